@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jusikbot/collector/internal/backtest"
+	"github.com/jusikbot/collector/internal/config"
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/store"
+	"github.com/jusikbot/collector/internal/summary"
+)
+
+const watchlistPath = "config/watchlist.json"
+
+// lookbackBuffer mirrors internal/backtest's own lookbackWindowDays: fetch
+// enough history before the run's From date that day-1's indicators (52-week
+// high/low, 200D MA) aren't computed on a truncated window.
+const lookbackBuffer = 380 * 24 * time.Hour
+
+func main() {
+	configPath := flag.String("config", "config/backtest.json", "path to the backtest run config (YAML or JSON)")
+	strategyName := flag.String("strategy", "breakout", "strategy to replay: breakout, ma-trend")
+	minFiftyTwoWeekPos := flag.Float64("min-52w-pos", 0.9, "FiftyTwoWeekPos (0-1) threshold: minimum required to enter for breakout, maximum before exiting for ma-trend")
+	outputPath := flag.String("output", "../data/backtest.json", "path to write the backtest Report as JSON")
+	flag.Parse()
+
+	if err := run(context.Background(), *configPath, *strategyName, *minFiftyTwoWeekPos, *outputPath); err != nil {
+		slog.Error("backtest failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, configPath, strategyName string, minFiftyTwoWeekPos float64, outputPath string) error {
+	env, err := config.LoadEnv()
+	if err != nil {
+		return fmt.Errorf("load environment config: %w", err)
+	}
+
+	runCfg, err := config.LoadBacktestRunConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("load backtest config: %w", err)
+	}
+
+	strategy, err := buildStrategy(strategyName, minFiftyTwoWeekPos)
+	if err != nil {
+		return err
+	}
+
+	watchlist, err := config.LoadWatchlist(watchlistPath)
+	if err != nil {
+		return fmt.Errorf("load watchlist: %w", err)
+	}
+	marketBySymbol := make(map[string]domain.Market, len(watchlist))
+	for _, e := range watchlist {
+		marketBySymbol[e.Symbol] = e.Market
+	}
+
+	pool, err := store.ConnectDB(ctx, env.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer pool.Close()
+	repo := store.NewRepository(pool)
+
+	inputs, err := loadSymbolInputs(ctx, repo, runCfg, marketBySymbol)
+	if err != nil {
+		return err
+	}
+
+	engine := backtest.NewEngine(backtest.Config{
+		FeeBps:         runCfg.FeeBps,
+		From:           runCfg.From,
+		InitialCapital: runCfg.InitialCapital,
+		MinSamples:     runCfg.MinSamples,
+		RiskFreeAnnual: runCfg.RiskFreeAnnual,
+		SlippageBps:    runCfg.SlippageBps,
+		To:             runCfg.To,
+	}, strategy)
+
+	report := engine.Run(inputs)
+
+	if err := writeReport(outputPath, report); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+
+	absOutputPath, _ := filepath.Abs(outputPath)
+	fmt.Printf("backtest complete: %d trades, win rate %.1f%%, CAGR %.2f%%, sharpe %.2f -> %s\n",
+		report.TotalTrades, report.WinRate, report.Performance.CAGR, report.Performance.Sharpe, absOutputPath)
+	return nil
+}
+
+// loadSymbolInputs fetches each configured symbol's price history plus its
+// market's benchmark history (cached per market, since several symbols in
+// cfg.Symbols commonly share a market) into the backtest.SymbolInput shape
+// Engine.Run expects.
+func loadSymbolInputs(ctx context.Context, repo *store.Repository, cfg domain.BacktestRunConfig, marketBySymbol map[string]domain.Market) ([]backtest.SymbolInput, error) {
+	fetchFrom := cfg.From.Add(-lookbackBuffer)
+	benchCache := make(map[domain.Market][]domain.DailyPrice)
+
+	inputs := make([]backtest.SymbolInput, 0, len(cfg.Symbols))
+	for _, symbol := range cfg.Symbols {
+		market, ok := marketBySymbol[symbol]
+		if !ok {
+			return nil, fmt.Errorf("symbol %s not found in watchlist", symbol)
+		}
+
+		prices, err := repo.FetchPriceHistory(ctx, symbol, fetchFrom, cfg.To)
+		if err != nil {
+			return nil, fmt.Errorf("fetch price history for %s: %w", symbol, err)
+		}
+
+		benchPrices, ok := benchCache[market]
+		if !ok {
+			benchSymbol := summary.BenchmarkSymbols[market]
+			benchPrices, err = repo.FetchPriceHistory(ctx, benchSymbol, fetchFrom, cfg.To)
+			if err != nil {
+				return nil, fmt.Errorf("fetch benchmark history for %s: %w", benchSymbol, err)
+			}
+			benchCache[market] = benchPrices
+		}
+
+		inputs = append(inputs, backtest.SymbolInput{
+			Symbol:      symbol,
+			Prices:      prices,
+			BenchPrices: benchPrices,
+			IsBenchmark: summary.BenchmarkSymbols[market] == symbol,
+		})
+	}
+
+	return inputs, nil
+}
+
+func buildStrategy(name string, minFiftyTwoWeekPos float64) (backtest.Strategy, error) {
+	switch name {
+	case "breakout":
+		return backtest.NewBreakoutStrategy(minFiftyTwoWeekPos), nil
+	case "ma-trend":
+		return backtest.NewMATrendStrategy(minFiftyTwoWeekPos), nil
+	default:
+		return nil, fmt.Errorf("unknown strategy %q (allowed: breakout, ma-trend)", name)
+	}
+}
+
+func writeReport(path string, report backtest.Report) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	defer f.Close()
+	return backtest.WriteJSON(f, report)
+}
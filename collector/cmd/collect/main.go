@@ -2,61 +2,156 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/jusikbot/collector/internal/collector"
 	"github.com/jusikbot/collector/internal/config"
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/influx"
+	"github.com/jusikbot/collector/internal/report"
+	"github.com/jusikbot/collector/internal/stats"
 	"github.com/jusikbot/collector/internal/store"
 	"github.com/jusikbot/collector/internal/summary"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 const (
+	holdingsPath      = "config/holdings.json"
+	retentionPath     = "config/retention.json"
 	summaryOutputPath = "../data/summary.md"
 	watchlistPath     = "config/watchlist.json"
 )
 
 func main() {
-	target, dryRun := parseFlags()
+	if len(os.Args) > 1 && os.Args[1] == "list" {
+		listSources()
+		return
+	}
+
+	sourceNames, format, dryRun, metricsAddr, reportDir, reportFormat := parseFlags()
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	if err := run(ctx, target, dryRun); err != nil {
-		slog.Error("collector failed", "error", err)
-		os.Exit(1)
+	var registry *prometheus.Registry
+	if metricsAddr != "" {
+		registry = prometheus.NewRegistry()
+		serveMetrics(metricsAddr, registry)
+	}
+
+	if err := run(ctx, sourceNames, format, dryRun, registry, reportDir, reportFormat); err != nil {
+		slog.Error("collector failed", "error", err, "failed_sources", collector.FailedSources(err))
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitCode maps a collection run's error to a sysexits-style process exit
+// code, so an alerting rule or supervisor can tell "credentials need fixing"
+// from "upstream hiccuped, safe to retry" without parsing the log message.
+// Anything not classified as auth/transient/rate-limited falls back to a
+// plain failure (1).
+func exitCode(err error) int {
+	if collector.HasKind(err, collector.KindAuth) {
+		return 2
+	}
+	if collector.HasKind(err, collector.KindTransient) || collector.HasKind(err, collector.KindRateLimited) {
+		return 75
+	}
+	return 1
+}
+
+// serveMetrics starts a /metrics HTTP server on addr in the background.
+// ListenAndServe failures are logged rather than fatal: a metrics endpoint
+// going down shouldn't abort a collection run already in progress.
+func serveMetrics(addr string, registry *prometheus.Registry) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			slog.Error("metrics server stopped", "error", err)
+		}
+	}()
+	slog.Info("metrics server starting", "addr", addr)
+}
+
+// listSources implements the `collector list` subcommand: print every
+// source registered with the default collector.Registry (see init in
+// registry.go) and what it needs to run, without touching the DB or network.
+func listSources() {
+	for _, d := range collector.Describe() {
+		scope := "unscoped"
+		if d.Watchlisted {
+			scope = "watchlist-scoped"
+		}
+		required := "none"
+		if len(d.RequiredEnv) > 0 {
+			required = strings.Join(d.RequiredEnv, ", ")
+		}
+		fmt.Printf("%-10s %-18s required env: %s\n", d.Name, scope, required)
 	}
 }
 
-func parseFlags() (string, bool) {
-	target := flag.String("target", "all", "collection target: tiingo, kis, fx, all")
+func parseFlags() ([]string, summary.Format, bool, string, string, string) {
+	target := flag.String("target", "all", "collection target: tiingo, kis, fx, retention, stream, orderbook, all")
+	sources := flag.String("sources", os.Getenv("COLLECTOR_SOURCES"), "comma-separated source names to run, e.g. kis,fx (overrides -target; defaults to $COLLECTOR_SOURCES)")
+	format := flag.String("format", "md", "summary output format: md, json, html, all")
 	dryRun := flag.Bool("dry-run", false, "validate configuration without collecting data")
+	metricsAddr := flag.String("metrics-addr", "", "address to serve Prometheus /metrics on, e.g. :9090 (disabled when empty)")
+	reportDir := flag.String("report-dir", "", "directory to write a per-symbol internal/stats performance report (JSON+CSV) after a run (disabled when empty)")
+	reportFormat := flag.String("report-format", "text", "collection run report format written to stdout: text, json")
 	flag.Parse()
 
-	validTargets := map[string]bool{
-		"all":    true,
-		"fx":     true,
-		"kis":    true,
-		"tiingo": true,
+	validFormats := map[string]bool{
+		"md":   true,
+		"json": true,
+		"html": true,
+		"all":  true,
+	}
+
+	if !validFormats[*format] {
+		fmt.Fprintf(os.Stderr, "invalid format %q (allowed: md, json, html, all)\n", *format)
+		os.Exit(1)
 	}
 
-	if !validTargets[*target] {
-		fmt.Fprintf(os.Stderr, "invalid target %q (allowed: tiingo, kis, fx, all)\n", *target)
+	validReportFormats := map[string]bool{"text": true, "json": true}
+	if !validReportFormats[*reportFormat] {
+		fmt.Fprintf(os.Stderr, "invalid report-format %q (allowed: text, json)\n", *reportFormat)
 		os.Exit(1)
 	}
 
-	return *target, *dryRun
+	return resolveSourceNames(*target, *sources), summary.Format(*format), *dryRun, *metricsAddr, *reportDir, *reportFormat
 }
 
-func run(ctx context.Context, target string, dryRun bool) error {
+// resolveSourceNames returns the ordered source names to build. An explicit
+// -sources (or $COLLECTOR_SOURCES) list wins over -target; unknown names are
+// not rejected here, since collector.Build reports them as a SkippedSource
+// rather than failing the whole run.
+func resolveSourceNames(target, sources string) []string {
+	if sources != "" {
+		parts := strings.Split(sources, ",")
+		names := make([]string, len(parts))
+		for i, p := range parts {
+			names[i] = strings.TrimSpace(p)
+		}
+		return names
+	}
+	return resolveTargets(target)
+}
+
+func run(ctx context.Context, sourceNames []string, format summary.Format, dryRun bool, registry *prometheus.Registry, reportDir string, reportFormat string) error {
 	started := time.Now()
-	slog.Info("collector starting", "dry_run", dryRun, "target", target)
+	slog.Info("collector starting", "dry_run", dryRun, "format", format, "sources", sourceNames)
 
 	env, err := config.LoadEnv()
 	if err != nil {
@@ -69,6 +164,13 @@ func run(ctx context.Context, target string, dryRun bool) error {
 	}
 	slog.Info("watchlist loaded", "entries", len(watchlist))
 
+	// Why holdings.json is optional: rebalancing is opt-in, so a deployment that
+	// hasn't configured a portfolio just gets an empty RebalanceOptions below.
+	holdings, err := config.LoadHoldings(holdingsPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("load holdings: %w", err)
+	}
+
 	if dryRun {
 		slog.Info("dry-run mode: skipping DB connection and collection")
 		return nil
@@ -84,50 +186,146 @@ func run(ctx context.Context, target string, dryRun bool) error {
 		return fmt.Errorf("run migrations: %w", err)
 	}
 
-	sc := &sourceCollector{
-		env:       env,
-		repo:      store.NewRepository(pool),
-		watchlist: watchlist,
+	// Why a separate *prometheus.Registry var rather than assigning registry
+	// directly into SourceDeps.Registry below: a nil *prometheus.Registry
+	// boxed into the prometheus.Registerer interface is a non-nil interface
+	// (it carries a concrete type), so sourceCollector's "registry == nil"
+	// check would never trip. Only set the field when there's a real registry.
+	deps := collector.SourceDeps{Pool: pool, TSDB: buildTSDBWriter(env)}
+	var repoOpts []store.Option
+	if registry != nil {
+		repoOpts = append(repoOpts, store.WithMetrics(registry))
+		deps.Registry = registry
+	}
+	repo := store.NewRepository(pool, repoOpts...)
+	deps.Repo = repo
+	sources, skipped, err := collector.Build(sourceNames, collector.SourceConfig{
+		Env:       env,
+		Watchlist: watchlist,
+	}, deps)
+	if err != nil {
+		return fmt.Errorf("build sources: %w", err)
+	}
+	for _, s := range skipped {
+		slog.Info("skipping source", "source", s.Name, "reason", s.Reason)
 	}
 
-	sources := sc.buildSources(target)
 	results := collector.CollectAll(ctx, sources)
 	collector.ReportResults(results, time.Since(started))
+	writeCollectionRunReport(ctx, repo, started, results, report.Format(reportFormat))
+
+	// sourceCollector is also used directly by resolveLotSizes below, which needs the
+	// same env/repo the registered sources were built with but isn't itself a Source.
+	sc := &sourceCollector{env: env, pool: pool, repo: repo, watchlist: watchlist}
 
 	// Intent: summary는 부가 출력이므로 실패해도 수집 exit code에 반영하지 않음.
+	rebalanceOpts := summary.DefaultRebalanceOptions()
+	rebalanceOpts.Holdings = holdings
+	if len(holdings) > 0 && env.KISAppKey != "" && env.KISAppSecret != "" {
+		rebalanceOpts.LotSizes = sc.resolveLotSizes(ctx, holdings)
+	}
+
 	absOutputPath, _ := filepath.Abs(summaryOutputPath)
-	if err := summary.GenerateSummary(ctx, sc.repo, watchlist, summaryOutputPath); err != nil {
+	if err := summary.GenerateSummary(ctx, sc.repo, watchlist, summaryOutputPath, format, env.KISAccountNo, summary.DefaultSummaryOptions(), rebalanceOpts, sc.repo); err != nil {
 		slog.Error("summary generation failed", "error", err, "path", absOutputPath)
 	} else {
 		slog.Info("summary generated", "path", absOutputPath)
 	}
 
+	if reportDir != "" {
+		writeStatsReports(ctx, repo, watchlist, reportDir)
+	}
+
 	return collector.AggregateErrors(results)
 }
 
-func resolveTargets(target string) []string {
-	if target == "all" {
-		return []string{"tiingo", "kis", "fx"}
+// writeCollectionRunReport renders this run's per-source counts to stdout
+// (in reportFormat) and persists them to the collection_runs table, so an
+// operator can query historical collection health instead of only reading
+// the latest run's logs. Like summary generation and writeStatsReports
+// below, both are convenience outputs: a failure here is logged, not
+// returned, and never changes the collection run's exit code.
+func writeCollectionRunReport(ctx context.Context, repo *store.Repository, started time.Time, results []collector.SourceResult, reportFormat report.Format) {
+	errSummary := ""
+	if err := collector.AggregateErrors(results); err != nil {
+		errSummary = err.Error()
+	}
+	run := report.BuildRun(started.Format(time.RFC3339), started, time.Now(), results, errSummary)
+
+	if err := (report.StdoutSink{Format: reportFormat, Out: os.Stdout}).Write(ctx, run); err != nil {
+		slog.Error("write collection run report to stdout failed", "error", err)
+	}
+	if err := (report.PostgresSink{Repo: repo}).Write(ctx, run); err != nil {
+		slog.Error("persist collection run failed", "error", err)
 	}
-	return []string{target}
 }
 
-func (c *sourceCollector) buildSources(target string) []collector.Source {
-	targets := resolveTargets(target)
-	sources := make([]collector.Source, 0, len(targets))
-
-	for _, t := range targets {
-		switch t {
-		case "tiingo":
-			sources = append(sources, collector.Source{Name: "tiingo", Collect: c.collectTiingo})
-		case "kis":
-			sources = append(sources, collector.Source{Name: "kis", Collect: c.collectKIS})
-		case "fx":
-			sources = append(sources, collector.Source{Name: "fx", Collect: c.collectFX})
-		default:
-			slog.Warn("unknown collection target, skipping", "target", t)
+// statsReportLookback bounds how much history writeStatsReports pulls per
+// symbol; five years is enough for a CAGR/Sharpe/drawdown report without
+// streaming a symbol's entire history on every run.
+const statsReportLookback = 5 * 365 * 24 * time.Hour
+
+// writeStatsReports emits a stats.PerformanceReport (JSON and CSV) per
+// watchlist symbol into dir. Failures are logged and skipped per-symbol
+// rather than aborting the run: like summary generation above, a report is
+// a convenience output and shouldn't fail collection's exit code.
+func writeStatsReports(ctx context.Context, repo *store.Repository, watchlist []domain.WatchlistEntry, dir string) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		slog.Error("create report dir failed", "error", err, "dir", dir)
+		return
+	}
+
+	now := time.Now()
+	for _, entry := range watchlist {
+		prices, err := repo.FetchPriceHistory(ctx, entry.Symbol, now.Add(-statsReportLookback), now)
+		if err != nil {
+			slog.Error("fetch price history for report failed", "error", err, "symbol", entry.Symbol)
+			continue
+		}
+
+		report := stats.ComputeReport(prices, 0)
+
+		if err := writeReportFile(filepath.Join(dir, entry.Symbol+".json"), func(f *os.File) error {
+			return stats.WriteJSON(f, report)
+		}); err != nil {
+			slog.Error("write json report failed", "error", err, "symbol", entry.Symbol)
+		}
+		if err := writeReportFile(filepath.Join(dir, entry.Symbol+".csv"), func(f *os.File) error {
+			return stats.WriteCSV(f, report)
+		}); err != nil {
+			slog.Error("write csv report failed", "error", err, "symbol", entry.Symbol)
 		}
 	}
+	slog.Info("stats reports written", "dir", dir, "symbols", len(watchlist))
+}
+
+func writeReportFile(path string, write func(*os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	defer f.Close()
+	return write(f)
+}
 
-	return sources
+// buildTSDBWriter returns an InfluxDB-backed store.TSDBWriter when
+// INFLUX_URL/INFLUX_TOKEN/INFLUX_BUCKET are all set, or nil otherwise, so
+// fanning out to a TSDB stays opt-in like the other source credentials.
+func buildTSDBWriter(env config.Env) store.TSDBWriter {
+	if env.InfluxURL == "" || env.InfluxToken == "" || env.InfluxBucket == "" {
+		return nil
+	}
+	return influx.NewWriter(env.InfluxURL, env.InfluxToken, env.InfluxBucket, nil, influx.DefaultRetryConfig)
+}
+
+// resolveTargets expands "all" to every data-collecting source. "retention" is a
+// maintenance job rather than a data source, and "stream"/"orderbook" each hold a
+// connection open for a bounded duration rather than returning promptly, so all three
+// are excluded from "all" and must be requested explicitly (e.g. via a separate cron
+// schedule).
+func resolveTargets(target string) []string {
+	if target == "all" {
+		return []string{"tiingo", "alpaca", "kis", "margin", "fx"}
+	}
+	return []string{target}
 }
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"github.com/jusikbot/collector/internal/collector"
+	"github.com/jusikbot/collector/internal/config"
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// init registers every built-in source with the default collector.Registry.
+// Adding another provider means writing one more collector.Register call
+// like these, not touching run or main's flag handling.
+func init() {
+	collector.Register(collector.SourceDef{
+		Filter:     krMarketFilter,
+		MissingEnv: kisMissingEnv,
+		Name:       "kis",
+		New:        newKISSource,
+		RequiredEnv: []string{
+			"KIS_APP_KEY",
+			"KIS_APP_SECRET",
+		},
+	})
+
+	collector.Register(collector.SourceDef{
+		MissingEnv: func(env config.Env) []string {
+			missing := kisMissingEnv(env)
+			if env.KISAccountNo == "" {
+				missing = append(missing, "KIS_ACCOUNT_NO")
+			}
+			return missing
+		},
+		Name: "margin",
+		New:  newMarginSource,
+		RequiredEnv: []string{
+			"KIS_APP_KEY",
+			"KIS_APP_SECRET",
+			"KIS_ACCOUNT_NO",
+		},
+	})
+
+	collector.Register(collector.SourceDef{
+		Filter:     krMarketFilter,
+		MissingEnv: kisMissingEnv,
+		Name:       "stream",
+		New:        newStreamSource,
+		RequiredEnv: []string{
+			"KIS_APP_KEY",
+			"KIS_APP_SECRET",
+		},
+	})
+
+	collector.Register(collector.SourceDef{
+		Filter:     krMarketFilter,
+		MissingEnv: kisMissingEnv,
+		Name:       "orderbook",
+		New:        newOrderBookSource,
+		RequiredEnv: []string{
+			"KIS_APP_KEY",
+			"KIS_APP_SECRET",
+		},
+	})
+
+	collector.Register(collector.SourceDef{
+		Filter: func(wl []domain.WatchlistEntry) []domain.WatchlistEntry {
+			return usEntriesBySource(wl, domain.USDataSourceTiingo)
+		},
+		MissingEnv: func(env config.Env) []string {
+			if env.TiingoAPIKey == "" {
+				return []string{"TIINGO_API_KEY"}
+			}
+			return nil
+		},
+		Name:        "tiingo",
+		New:         newTiingoSource,
+		RequiredEnv: []string{"TIINGO_API_KEY"},
+	})
+
+	collector.Register(collector.SourceDef{
+		Filter: func(wl []domain.WatchlistEntry) []domain.WatchlistEntry {
+			return usEntriesBySource(wl, domain.USDataSourceAlpaca)
+		},
+		MissingEnv: func(env config.Env) []string {
+			var missing []string
+			if env.AlpacaAPIKeyID == "" {
+				missing = append(missing, "ALPACA_API_KEY_ID")
+			}
+			if env.AlpacaAPISecretKey == "" {
+				missing = append(missing, "ALPACA_API_SECRET_KEY")
+			}
+			return missing
+		},
+		Name: "alpaca",
+		New:  newAlpacaSource,
+		RequiredEnv: []string{
+			"ALPACA_API_KEY_ID",
+			"ALPACA_API_SECRET_KEY",
+		},
+	})
+
+	collector.Register(collector.SourceDef{
+		Name:               "fx",
+		New:                newFXSource,
+		NotWatchlistScoped: true,
+	})
+
+	collector.Register(collector.SourceDef{
+		Name:               "retention",
+		New:                newRetentionSource,
+		NotWatchlistScoped: true,
+	})
+
+	collector.Register(collector.SourceDef{
+		Name:               "instruments",
+		New:                newInstrumentsSource,
+		NotWatchlistScoped: true,
+	})
+}
+
+func krMarketFilter(wl []domain.WatchlistEntry) []domain.WatchlistEntry {
+	return config.FilterByMarket(wl, domain.MarketKR)
+}
+
+func kisMissingEnv(env config.Env) []string {
+	var missing []string
+	if env.KISAppKey == "" {
+		missing = append(missing, "KIS_APP_KEY")
+	}
+	if env.KISAppSecret == "" {
+		missing = append(missing, "KIS_APP_SECRET")
+	}
+	return missing
+}
+
+// newSourceCollector builds the shared sourceCollector every factory below
+// wraps, threading the registry's per-source cfg/deps into its fields.
+func newSourceCollector(cfg collector.SourceConfig, deps collector.SourceDeps) *sourceCollector {
+	return &sourceCollector{
+		env:       cfg.Env,
+		pool:      deps.Pool,
+		registry:  deps.Registry,
+		repo:      deps.Repo,
+		tsdb:      deps.TSDB,
+		watchlist: cfg.Watchlist,
+	}
+}
+
+func newKISSource(cfg collector.SourceConfig, deps collector.SourceDeps) (collector.Source, error) {
+	sc := newSourceCollector(cfg, deps)
+	return collector.Source{Collect: sc.collectKIS, Name: "kis"}, nil
+}
+
+func newMarginSource(cfg collector.SourceConfig, deps collector.SourceDeps) (collector.Source, error) {
+	sc := newSourceCollector(cfg, deps)
+	return collector.Source{Collect: sc.collectMargin, Name: "margin"}, nil
+}
+
+func newStreamSource(cfg collector.SourceConfig, deps collector.SourceDeps) (collector.Source, error) {
+	sc := newSourceCollector(cfg, deps)
+	return collector.Source{Collect: sc.collectStream, Name: "stream"}, nil
+}
+
+func newOrderBookSource(cfg collector.SourceConfig, deps collector.SourceDeps) (collector.Source, error) {
+	sc := newSourceCollector(cfg, deps)
+	return collector.Source{Collect: sc.collectOrderBook, Name: "orderbook"}, nil
+}
+
+func newTiingoSource(cfg collector.SourceConfig, deps collector.SourceDeps) (collector.Source, error) {
+	sc := newSourceCollector(cfg, deps)
+	return collector.Source{Collect: sc.collectTiingo, Name: "tiingo"}, nil
+}
+
+func newAlpacaSource(cfg collector.SourceConfig, deps collector.SourceDeps) (collector.Source, error) {
+	sc := newSourceCollector(cfg, deps)
+	return collector.Source{Collect: sc.collectAlpaca, Name: "alpaca"}, nil
+}
+
+func newFXSource(cfg collector.SourceConfig, deps collector.SourceDeps) (collector.Source, error) {
+	sc := newSourceCollector(cfg, deps)
+	return collector.Source{Collect: sc.collectFX, Name: "fx"}, nil
+}
+
+func newRetentionSource(cfg collector.SourceConfig, deps collector.SourceDeps) (collector.Source, error) {
+	sc := newSourceCollector(cfg, deps)
+	return collector.Source{Collect: sc.collectRetention, Name: "retention"}, nil
+}
+
+func newInstrumentsSource(cfg collector.SourceConfig, deps collector.SourceDeps) (collector.Source, error) {
+	sc := newSourceCollector(cfg, deps)
+	return collector.Source{Collect: sc.collectInstruments, Name: "instruments"}, nil
+}
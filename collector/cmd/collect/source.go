@@ -7,6 +7,9 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jusikbot/collector/internal/alpaca"
+	coll "github.com/jusikbot/collector/internal/collector"
 	"github.com/jusikbot/collector/internal/config"
 	"github.com/jusikbot/collector/internal/domain"
 	"github.com/jusikbot/collector/internal/fx"
@@ -15,53 +18,105 @@ import (
 	"github.com/jusikbot/collector/internal/ratelimit"
 	"github.com/jusikbot/collector/internal/store"
 	"github.com/jusikbot/collector/internal/tiingo"
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/time/rate"
 )
 
 const (
+	alpacaBaseURL      = "https://data.alpaca.markets"
 	frankfurterBaseURL = "https://api.frankfurter.dev"
 	kisBaseURL         = "https://openapi.koreainvestment.com:9443"
+	kisWSURL           = "ws://ops.koreainvestment.com:21000"
 	tiingoBaseURL      = "https://api.tiingo.com"
 )
 
+// streamDuration bounds how long collectStream keeps its WebSocket subscription open.
+// Why bounded rather than indefinite: the collector process runs one batch pass per
+// invocation and then exits (see collector.CollectAll); an external scheduler invoking
+// `--target stream` on a short interval approximates continuous coverage without
+// requiring a long-running process.
+const streamDuration = 30 * time.Second
+
+// orderBookSampleDuration bounds how long collectOrderBook keeps sampling before
+// persisting and exiting, for the same reason streamDuration bounds collectStream.
+const orderBookSampleDuration = 30 * time.Second
+
 // Why 5s: Tiingo allows 50 req/hr burst with backoff.
 // 5s is conservative enough to avoid rate limiting under normal conditions.
+// Why Classify: honors a Retry-After header over the computed backoff when
+// Tiingo starts rate limiting (429/503).
 var tiingoRetryCfg = ratelimit.RetryConfig{
+	Classify:       ratelimit.HTTPRetryClassifier,
 	InitialBackoff: 5 * time.Second,
 	MaxAttempts:    3,
 	MaxBackoff:     60 * time.Second,
 }
 
 // Why 2s: Frankfurter has no rate limit, but retry with backoff for transient failures.
+// Why Classify: honors a Retry-After header over the computed backoff if Frankfurter
+// ever starts rate limiting (429/503).
 var fxRetryCfg = ratelimit.RetryConfig{
+	Classify:       ratelimit.HTTPRetryClassifier,
 	InitialBackoff: 2 * time.Second,
 	MaxAttempts:    3,
 	MaxBackoff:     30 * time.Second,
 }
 
 // Why 1s: KIS personal accounts allow ~20 req/sec, but conservative to avoid throttling.
+// Why Classify: KIS returns a Retry-After header on 429/503; honor it over the
+// computed backoff so retries converge on the server's own pacing.
 var kisRetryCfg = ratelimit.RetryConfig{
+	Classify:       ratelimit.HTTPRetryClassifier,
 	InitialBackoff: 2 * time.Second,
 	MaxAttempts:    3,
 	MaxBackoff:     30 * time.Second,
 }
 
+// Why 1s: Alpaca's basic tier allows 200 req/min; 1s backoff comfortably avoids throttling.
+// Why Classify: honors a Retry-After header over the computed backoff if Alpaca
+// starts rate limiting (429/503).
+var alpacaRetryCfg = ratelimit.RetryConfig{
+	Classify:       ratelimit.HTTPRetryClassifier,
+	InitialBackoff: 1 * time.Second,
+	MaxAttempts:    3,
+	MaxBackoff:     30 * time.Second,
+}
+
+// kisBreaker and tiingoBreaker short-circuit the rest of a batch once their
+// source trips on sustained failures, instead of burning MaxAttempts retries
+// per remaining symbol. fxBreakers holds one breaker per provider, since
+// fx.Collector fans a single fetch out to several providers concurrently.
+var (
+	kisBreaker    = ratelimit.NewCircuitBreaker(ratelimit.DefaultCircuitBreakerConfig())
+	tiingoBreaker = ratelimit.NewCircuitBreaker(ratelimit.DefaultCircuitBreakerConfig())
+	fxBreakers    = ratelimit.NewGroup(ratelimit.DefaultCircuitBreakerConfig())
+)
+
 type sourceCollector struct {
 	env       config.Env
+	pool      *pgxpool.Pool
+	registry  prometheus.Registerer
 	repo      *store.Repository
+	tsdb      store.TSDBWriter
 	watchlist []domain.WatchlistEntry
 }
 
-func (c *sourceCollector) collectKIS(ctx context.Context) error {
-	if c.env.KISAppKey == "" || c.env.KISAppSecret == "" {
-		return fmt.Errorf("KIS_APP_KEY and KIS_APP_SECRET are required")
-	}
-
-	krEntries := config.FilterByMarket(c.watchlist, domain.MarketKR)
-	if len(krEntries) == 0 {
-		slog.Info("no KR symbols in watchlist, skipping kis")
+// httpClientOpts returns the ClientOption needed to have every httpclient.Client
+// this collector builds record to c.registry, or nil when no registry was
+// configured (the --metrics-addr flag was not set).
+func (c *sourceCollector) httpClientOpts() []httpclient.ClientOption {
+	if c.registry == nil {
 		return nil
 	}
+	return []httpclient.ClientOption{httpclient.WithMetrics(c.registry)}
+}
+
+// collectKIS assumes c.watchlist has already been narrowed to KR-market
+// entries and c.env carries valid KIS credentials; both are guaranteed by
+// the kisSource registration in registry.go, which filters and checks
+// before ever constructing a sourceCollector for this method.
+func (c *sourceCollector) collectKIS(ctx context.Context) (coll.SourceStats, error) {
+	krEntries := c.watchlist
 
 	symbols := make([]string, len(krEntries))
 	for i, e := range krEntries {
@@ -70,12 +125,20 @@ func (c *sourceCollector) collectKIS(ctx context.Context) error {
 
 	gaps, err := c.repo.DetectGaps(ctx, symbols)
 	if err != nil {
-		return fmt.Errorf("detect gaps: %w", err)
+		return coll.SourceStats{}, fmt.Errorf("detect gaps: %w", err)
 	}
 
 	// Why credentials appear in both places: KIS triple auth requires appkey/appsecret
 	// in POST body for token issuance (TokenProvider) AND in GET headers for data APIs (httpclient).
-	tokenProvider := kis.NewTokenProvider(kisBaseURL, c.env.KISAppKey, c.env.KISAppSecret, nil)
+	// Why a nil-checked interface var: a non-nil *FileTokenStore wrapped directly in
+	// the TokenStore interface would make NewTokenProvider's nil check always false.
+	var tokenStore kis.TokenStore
+	if fileStore, err := kis.NewFileTokenStore(c.env.KISAppKey); err != nil {
+		slog.Warn("kis token persistence disabled", "error", err)
+	} else {
+		tokenStore = fileStore
+	}
+	tokenProvider := kis.NewTokenProvider(kisBaseURL, c.env.KISAppKey, c.env.KISAppSecret, nil, tokenStore)
 	httpClient := httpclient.NewClient(
 		kisBaseURL,
 		map[string]string{
@@ -84,27 +147,282 @@ func (c *sourceCollector) collectKIS(ctx context.Context) error {
 		},
 		nil,
 		0,
+		c.httpClientOpts()...,
 	)
 	kisClient := kis.NewClient(httpClient, tokenProvider)
 
 	// Why Every(56ms): ~18 req/sec matches KIS personal account limits (analysis.md).
 	limiter := rate.NewLimiter(rate.Every(56*time.Millisecond), 1)
-	collector := kis.NewCollector(kisClient, limiter, kisRetryCfg)
+	collector := kis.NewCollector(kisClient, limiter, kisRetryCfg, kisBreaker)
 
 	prices, collectErr := collector.CollectAll(ctx, krEntries, gaps)
 	return c.savePartialResults(ctx, prices, collectErr, "kis")
 }
 
-func (c *sourceCollector) collectTiingo(ctx context.Context) error {
-	if c.env.TiingoAPIKey == "" {
-		return fmt.Errorf("TIINGO_API_KEY is not set")
+// collectMargin assumes c.env carries valid KIS credentials and an account
+// number, guaranteed by the marginSource registration in registry.go.
+func (c *sourceCollector) collectMargin(ctx context.Context) (coll.SourceStats, error) {
+	accounts := []string{c.env.KISAccountNo}
+
+	gaps, err := c.repo.DetectMarginGaps(ctx, accounts)
+	if err != nil {
+		return coll.SourceStats{}, fmt.Errorf("detect margin gaps: %w", err)
 	}
 
-	usEntries := config.FilterByMarket(c.watchlist, domain.MarketUS)
-	if len(usEntries) == 0 {
-		slog.Info("no US symbols in watchlist, skipping tiingo")
-		return nil
+	var tokenStore kis.TokenStore
+	if fileStore, err := kis.NewFileTokenStore(c.env.KISAppKey); err != nil {
+		slog.Warn("kis token persistence disabled", "error", err)
+	} else {
+		tokenStore = fileStore
 	}
+	tokenProvider := kis.NewTokenProvider(kisBaseURL, c.env.KISAppKey, c.env.KISAppSecret, nil, tokenStore)
+	httpClient := httpclient.NewClient(
+		kisBaseURL,
+		map[string]string{
+			"appkey":    c.env.KISAppKey,
+			"appsecret": c.env.KISAppSecret,
+		},
+		nil,
+		0,
+		c.httpClientOpts()...,
+	)
+	marginClient := kis.NewMarginHistoryClient(httpClient, tokenProvider)
+
+	// Why Every(56ms): ~18 req/sec matches KIS personal account limits (analysis.md).
+	limiter := rate.NewLimiter(rate.Every(56*time.Millisecond), 1)
+	collector := kis.NewMarginCollector(marginClient, limiter, kisRetryCfg)
+
+	history, collectErr := collector.CollectAll(ctx, accounts, gaps)
+	return c.saveMarginHistory(ctx, history, collectErr)
+}
+
+// collectStream subscribes to real-time KIS ticks for the KR watchlist for
+// streamDuration, then persists whatever arrived. Unlike collectKIS, which backfills
+// daily bars, this captures intraday trades as they happen; the two share no state and
+// can run side by side.
+// collectStream assumes c.watchlist has already been narrowed to KR-market
+// entries and c.env carries valid KIS credentials, guaranteed by the
+// streamSource registration in registry.go.
+func (c *sourceCollector) collectStream(ctx context.Context) (coll.SourceStats, error) {
+	krEntries := c.watchlist
+	symbols := make([]string, len(krEntries))
+	for i, e := range krEntries {
+		symbols[i] = e.Symbol
+	}
+
+	var tokenStore kis.TokenStore
+	if fileStore, err := kis.NewFileTokenStore(c.env.KISAppKey); err != nil {
+		slog.Warn("kis token persistence disabled", "error", err)
+	} else {
+		tokenStore = fileStore
+	}
+	tokenProvider := kis.NewTokenProvider(kisBaseURL, c.env.KISAppKey, c.env.KISAppSecret, nil, tokenStore)
+	streamClient := kis.NewStreamingClient(kisWSURL, tokenProvider, nil)
+
+	if err := streamClient.Subscribe(ctx, symbols, kis.StreamKindTick); err != nil {
+		return coll.SourceStats{}, fmt.Errorf("subscribe ticks: %w", err)
+	}
+
+	streamCtx, cancel := context.WithTimeout(ctx, streamDuration)
+	defer cancel()
+
+	runDone := make(chan error, 1)
+	go func() { runDone <- streamClient.Run(streamCtx, kis.DefaultStreamRetryConfig()) }()
+
+	var ticks []domain.Tick
+collect:
+	for {
+		select {
+		case t := <-streamClient.Ticks():
+			ticks = append(ticks, t)
+		case <-streamCtx.Done():
+			break collect
+		}
+	}
+
+	if err := <-runDone; err != nil && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+		slog.Warn("streaming client stopped with error", "error", err)
+	}
+
+	if len(ticks) == 0 {
+		slog.Info("no ticks collected", "source", "stream")
+		return coll.SourceStats{}, nil
+	}
+
+	n, err := c.repo.UpsertTicks(ctx, ticks)
+	if err != nil {
+		return coll.SourceStats{RowsFetched: int64(len(ticks))}, fmt.Errorf("upsert ticks: %w", err)
+	}
+	slog.Info("ticks saved", "rows", n, "source", "stream")
+
+	return coll.SourceStats{RowsFetched: int64(len(ticks)), RowsInserted: n}, nil
+}
+
+// collectOrderBook periodically samples KIS order book depth for the KR watchlist
+// for orderBookSampleDuration, then persists whatever snapshots were collected.
+// Unlike collectStream, this polls the REST inquire-asking-price endpoint rather
+// than holding a WebSocket open; see kis.OrderBookSampler.
+// collectOrderBook assumes c.watchlist has already been narrowed to
+// KR-market entries and c.env carries valid KIS credentials, guaranteed by
+// the orderBookSource registration in registry.go.
+func (c *sourceCollector) collectOrderBook(ctx context.Context) (coll.SourceStats, error) {
+	krEntries := c.watchlist
+	symbols := make([]string, len(krEntries))
+	for i, e := range krEntries {
+		symbols[i] = e.Symbol
+	}
+
+	var tokenStore kis.TokenStore
+	if fileStore, err := kis.NewFileTokenStore(c.env.KISAppKey); err != nil {
+		slog.Warn("kis token persistence disabled", "error", err)
+	} else {
+		tokenStore = fileStore
+	}
+	tokenProvider := kis.NewTokenProvider(kisBaseURL, c.env.KISAppKey, c.env.KISAppSecret, nil, tokenStore)
+	httpClient := httpclient.NewClient(
+		kisBaseURL,
+		map[string]string{
+			"appkey":    c.env.KISAppKey,
+			"appsecret": c.env.KISAppSecret,
+		},
+		nil,
+		0,
+		c.httpClientOpts()...,
+	)
+	kisClient := kis.NewClient(httpClient, tokenProvider)
+	instrumentCache := kis.NewInstrumentCache(kisClient, 0)
+	symbols = c.excludeHaltedSymbols(ctx, instrumentCache, symbols)
+	if len(symbols) == 0 {
+		slog.Info("all KR symbols halted, skipping orderbook")
+		return coll.SourceStats{}, nil
+	}
+
+	// Why Every(56ms): ~18 req/sec matches KIS personal account limits (analysis.md).
+	limiter := rate.NewLimiter(rate.Every(56*time.Millisecond), 1)
+	sampler := kis.NewOrderBookSampler(kisClient, limiter, kisRetryCfg)
+
+	sampleCtx, cancel := context.WithTimeout(ctx, orderBookSampleDuration)
+	defer cancel()
+
+	snapshots, sampleErr := sampler.Sample(sampleCtx, symbols, c.env.OrderBookSampleInterval)
+	if sampleErr != nil && !errors.Is(sampleErr, context.DeadlineExceeded) && !errors.Is(sampleErr, context.Canceled) {
+		slog.Warn("order book sampling stopped with error", "error", sampleErr)
+	}
+
+	if len(snapshots) == 0 {
+		slog.Info("no order book snapshots collected", "source", "orderbook")
+		return coll.SourceStats{}, nil
+	}
+
+	n, err := c.repo.UpsertOrderBookSnapshots(ctx, snapshots)
+	if err != nil {
+		return coll.SourceStats{RowsFetched: int64(len(snapshots))}, fmt.Errorf("upsert order book snapshots: %w", err)
+	}
+	slog.Info("order book snapshots saved", "rows", n, "source", "orderbook")
+
+	return coll.SourceStats{RowsFetched: int64(len(snapshots)), RowsInserted: n}, nil
+}
+
+// collectRetention downsamples and prunes raw rows per config/retention.json,
+// per store.RunRetention. Unlike the other sources, it has no upstream API to
+// fail partway through, so it either fully succeeds or reports an error.
+func (c *sourceCollector) collectRetention(ctx context.Context) (coll.SourceStats, error) {
+	rules, err := config.LoadRetentionRules(retentionPath)
+	if err != nil {
+		return coll.SourceStats{}, fmt.Errorf("load retention rules: %w", err)
+	}
+
+	if err := store.RunRetention(ctx, c.pool, rules); err != nil {
+		return coll.SourceStats{}, fmt.Errorf("run retention: %w", err)
+	}
+	slog.Info("retention applied", "rules", len(rules))
+
+	return coll.SourceStats{}, nil
+}
+
+// excludeHaltedSymbols drops symbols whose cached domain.InstrumentInfo reports
+// TradingHalted, so collectOrderBook doesn't waste samples polling a symbol that
+// can't trade. A lookup failure is logged and the symbol is kept rather than
+// dropped, since a transient instrument-info error shouldn't block an otherwise
+// healthy symbol from being sampled.
+func (c *sourceCollector) excludeHaltedSymbols(ctx context.Context, cache *kis.InstrumentCache, symbols []string) []string {
+	active := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		info, err := cache.Get(ctx, symbol)
+		if err != nil {
+			slog.Warn("instrument info lookup failed, sampling anyway", "error", err, "symbol", symbol)
+			active = append(active, symbol)
+			continue
+		}
+		if info.TradingHalted {
+			slog.Info("symbol halted, excluding from orderbook sampling", "symbol", symbol)
+			continue
+		}
+		active = append(active, symbol)
+	}
+	return active
+}
+
+// resolveLotSizes looks up each KRW-denominated holding's exchange-mandated lot
+// size so summary.ComputeRebalance rounds suggested share counts to a
+// tradeable quantity instead of the generic default. Non-KRW holdings are left
+// out: the KIS product-info endpoint only covers KRX-listed symbols.
+func (c *sourceCollector) resolveLotSizes(ctx context.Context, holdings []domain.Holding) map[string]int64 {
+	var tokenStore kis.TokenStore
+	if fileStore, err := kis.NewFileTokenStore(c.env.KISAppKey); err != nil {
+		slog.Warn("kis token persistence disabled", "error", err)
+	} else {
+		tokenStore = fileStore
+	}
+	tokenProvider := kis.NewTokenProvider(kisBaseURL, c.env.KISAppKey, c.env.KISAppSecret, nil, tokenStore)
+	httpClient := httpclient.NewClient(
+		kisBaseURL,
+		map[string]string{
+			"appkey":    c.env.KISAppKey,
+			"appsecret": c.env.KISAppSecret,
+		},
+		nil,
+		0,
+		c.httpClientOpts()...,
+	)
+	cache := kis.NewInstrumentCache(kis.NewClient(httpClient, tokenProvider), 0)
+
+	lotSizes := make(map[string]int64)
+	for _, h := range holdings {
+		if h.Currency != "KRW" {
+			continue
+		}
+		info, err := cache.Get(ctx, h.Symbol)
+		if err != nil {
+			slog.Warn("instrument info lookup failed, using default lot size", "error", err, "symbol", h.Symbol)
+			continue
+		}
+		lotSizes[h.Symbol] = info.LotSize
+	}
+	return lotSizes
+}
+
+// usEntriesBySource returns US-market watchlist entries whose Source matches src,
+// defaulting unset entries to domain.USDataSourceTiingo.
+func usEntriesBySource(watchlist []domain.WatchlistEntry, src domain.USDataSource) []domain.WatchlistEntry {
+	var entries []domain.WatchlistEntry
+	for _, e := range config.FilterByMarket(watchlist, domain.MarketUS) {
+		source := e.Source
+		if source == "" {
+			source = domain.USDataSourceTiingo
+		}
+		if source == src {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// collectTiingo assumes c.watchlist has already been narrowed to
+// Tiingo-sourced US entries and c.env carries a valid API key, guaranteed by
+// the tiingoSource registration in registry.go.
+func (c *sourceCollector) collectTiingo(ctx context.Context) (coll.SourceStats, error) {
+	usEntries := c.watchlist
 
 	symbols := make([]string, len(usEntries))
 	for i, e := range usEntries {
@@ -113,7 +431,7 @@ func (c *sourceCollector) collectTiingo(ctx context.Context) error {
 
 	gaps, err := c.repo.DetectGaps(ctx, symbols)
 	if err != nil {
-		return fmt.Errorf("detect gaps: %w", err)
+		return coll.SourceStats{}, fmt.Errorf("detect gaps: %w", err)
 	}
 
 	httpClient := httpclient.NewClient(
@@ -121,41 +439,200 @@ func (c *sourceCollector) collectTiingo(ctx context.Context) error {
 		map[string]string{"Authorization": "Token " + c.env.TiingoAPIKey},
 		nil,
 		0,
+		c.httpClientOpts()...,
 	)
 	tiingoClient := tiingo.NewClient(httpClient)
 	limiter := rate.NewLimiter(rate.Every(3*time.Second), 1)
-	collector := tiingo.NewCollector(tiingoClient, limiter, tiingoRetryCfg)
+	collector := tiingo.NewCollector(tiingoClient, limiter, tiingoRetryCfg, tiingoBreaker)
+
+	result, collectErr := collector.CollectAll(ctx, usEntries, gaps)
+	for _, symErr := range result.Errors {
+		collectErr = errors.Join(collectErr, symErr)
+	}
+	return c.savePricesAndActions(ctx, result.Prices, result.Actions, collectErr, "tiingo")
+}
+
+// collectAlpaca assumes c.watchlist has already been narrowed to
+// Alpaca-sourced US entries and c.env carries valid credentials, guaranteed
+// by the alpacaSource registration in registry.go.
+func (c *sourceCollector) collectAlpaca(ctx context.Context) (coll.SourceStats, error) {
+	usEntries := c.watchlist
+
+	symbols := make([]string, len(usEntries))
+	for i, e := range usEntries {
+		symbols[i] = e.Symbol
+	}
+
+	gaps, err := c.repo.DetectGaps(ctx, symbols)
+	if err != nil {
+		return coll.SourceStats{}, fmt.Errorf("detect gaps: %w", err)
+	}
+
+	httpClient := httpclient.NewClient(
+		alpacaBaseURL,
+		map[string]string{
+			"APCA-API-KEY-ID":     c.env.AlpacaAPIKeyID,
+			"APCA-API-SECRET-KEY": c.env.AlpacaAPISecretKey,
+		},
+		nil,
+		0,
+		c.httpClientOpts()...,
+	)
+	alpacaClient := alpaca.NewClient(httpClient)
+	// Why Every(300ms): 200 req/min basic tier limit, comfortably under the cap.
+	limiter := rate.NewLimiter(rate.Every(300*time.Millisecond), 1)
+	collector := alpaca.NewCollector(alpacaClient, limiter, alpacaRetryCfg)
 
 	prices, collectErr := collector.CollectAll(ctx, usEntries, gaps)
-	return c.savePartialResults(ctx, prices, collectErr, "tiingo")
+	return c.savePartialResults(ctx, prices, collectErr, "alpaca")
 }
 
-func (c *sourceCollector) collectFX(ctx context.Context) error {
+func (c *sourceCollector) collectFX(ctx context.Context) (coll.SourceStats, error) {
 	gaps, err := c.repo.DetectFXGaps(ctx, []string{"USD/KRW"})
 	if err != nil {
-		return fmt.Errorf("detect fx gaps: %w", err)
+		return coll.SourceStats{}, fmt.Errorf("detect fx gaps: %w", err)
 	}
 
-	httpClient := httpclient.NewClient(frankfurterBaseURL, nil, nil, 0)
+	// Why Every(200ms): Frankfurter publishes no documented rate limit, so this is a
+	// conservative default; a 429's Retry-After pauses the bucket further (see
+	// httpclient.WithRateLimiter), which matters here because fetchAndReconcile fans
+	// out one goroutine per provider against this same shared Client.
+	fxLimiter := httpclient.NewTokenBucketLimiter(rate.NewLimiter(rate.Every(200*time.Millisecond), 1))
+	httpClient := httpclient.NewClient(frankfurterBaseURL, nil, nil, 0, append([]httpclient.ClientOption{httpclient.WithRateLimiter(fxLimiter)}, c.httpClientOpts()...)...)
 	fxClient := fx.NewClient(httpClient)
-	collector := fx.NewCollector(fxClient, fxRetryCfg)
+	// Why a single-element slice: Frankfurter is the only FX provider wired up today;
+	// fx.Collector reconciles across however many providers are passed, so a second one
+	// (e.g. KIS overseas-FX or ECB) can be appended here without further changes.
+	collector := fx.NewCollector([]fx.Provider{fxClient}, fxRetryCfg, fx.DefaultDisagreementThresholdBps, fxBreakers)
 
 	rates, err := collector.CollectFX(ctx, "USD", "KRW", gaps)
 	if err != nil {
-		return fmt.Errorf("collect fx: %w", err)
+		return coll.SourceStats{}, fmt.Errorf("collect fx: %w", err)
 	}
 
 	if len(rates) == 0 {
-		return nil
+		return coll.SourceStats{}, nil
 	}
 
 	n, err := c.repo.UpsertFXRates(ctx, rates)
 	if err != nil {
-		return fmt.Errorf("upsert fx rates: %w", err)
+		return coll.SourceStats{RowsFetched: int64(len(rates))}, fmt.Errorf("upsert fx rates: %w", err)
 	}
 	slog.Info("fx rates saved", "rows", n)
 
-	return nil
+	c.fanOutFXRatesToTSDB(ctx, rates)
+
+	return coll.SourceStats{RowsFetched: int64(len(rates)), RowsInserted: n}, nil
+}
+
+// collectInstruments refreshes each watchlist symbol's persisted
+// domain.Instrument contract spec: KIS for KR entries, Tiingo for US entries
+// routed to Tiingo. US entries routed to Alpaca are skipped and logged, since
+// no Alpaca instrument-metadata adapter exists yet. Unlike the price sources,
+// a single symbol's lookup failure doesn't fail the whole run: contract specs
+// change rarely, so a stale or missing row is far less harmful than skipping
+// every other symbol over one bad lookup.
+func (c *sourceCollector) collectInstruments(ctx context.Context) (coll.SourceStats, error) {
+	var instruments []domain.Instrument
+	var lookupErrs error
+
+	krEntries := config.FilterByMarket(c.watchlist, domain.MarketKR)
+	if len(krEntries) > 0 {
+		if c.env.KISAppKey == "" || c.env.KISAppSecret == "" {
+			slog.Warn("kis credentials missing, skipping KR instrument lookups", "count", len(krEntries))
+		} else {
+			kisClient := c.newKISInstrumentClient()
+			for _, e := range krEntries {
+				inst, err := kisClient.FetchInstrument(ctx, e.Symbol)
+				if err != nil {
+					lookupErrs = errors.Join(lookupErrs, fmt.Errorf("kis instrument %s: %w", e.Symbol, err))
+					continue
+				}
+				instruments = append(instruments, inst)
+			}
+		}
+	}
+
+	tiingoEntries := usEntriesBySource(c.watchlist, domain.USDataSourceTiingo)
+	if len(tiingoEntries) > 0 {
+		if c.env.TiingoAPIKey == "" {
+			slog.Warn("tiingo api key missing, skipping US instrument lookups", "count", len(tiingoEntries))
+		} else {
+			httpClient := httpclient.NewClient(
+				tiingoBaseURL,
+				map[string]string{"Authorization": "Token " + c.env.TiingoAPIKey},
+				nil,
+				0,
+				c.httpClientOpts()...,
+			)
+			tiingoClient := tiingo.NewClient(httpClient)
+			for _, e := range tiingoEntries {
+				inst, err := tiingoClient.FetchInstrument(ctx, e.Symbol)
+				if err != nil {
+					lookupErrs = errors.Join(lookupErrs, fmt.Errorf("tiingo instrument %s: %w", e.Symbol, err))
+					continue
+				}
+				instruments = append(instruments, inst)
+			}
+		}
+	}
+
+	if len(instruments) == 0 {
+		return coll.SourceStats{}, lookupErrs
+	}
+
+	n, err := c.repo.UpsertInstruments(ctx, instruments)
+	if err != nil {
+		return coll.SourceStats{RowsFetched: int64(len(instruments))}, errors.Join(lookupErrs, fmt.Errorf("upsert instruments: %w", err))
+	}
+	slog.Info("instruments saved", "rows", n)
+
+	return coll.SourceStats{RowsFetched: int64(len(instruments)), RowsInserted: n}, lookupErrs
+}
+
+// newKISInstrumentClient builds a KIS client for instrument lookups, sharing
+// the same token-persistence and credential wiring as collectKIS.
+func (c *sourceCollector) newKISInstrumentClient() *kis.Client {
+	var tokenStore kis.TokenStore
+	if fileStore, err := kis.NewFileTokenStore(c.env.KISAppKey); err != nil {
+		slog.Warn("kis token persistence disabled", "error", err)
+	} else {
+		tokenStore = fileStore
+	}
+	tokenProvider := kis.NewTokenProvider(kisBaseURL, c.env.KISAppKey, c.env.KISAppSecret, nil, tokenStore)
+	httpClient := httpclient.NewClient(
+		kisBaseURL,
+		map[string]string{
+			"appkey":    c.env.KISAppKey,
+			"appsecret": c.env.KISAppSecret,
+		},
+		nil,
+		0,
+		c.httpClientOpts()...,
+	)
+	return kis.NewClient(httpClient, tokenProvider)
+}
+
+// fanOutPricesToTSDB mirrors the summary-generation intent in run(): the TSDB
+// is a secondary sink, so a write failure there is logged and swallowed
+// rather than reflected in the collection run's exit code.
+func (c *sourceCollector) fanOutPricesToTSDB(ctx context.Context, prices []domain.DailyPrice, source string) {
+	if c.tsdb == nil || len(prices) == 0 {
+		return
+	}
+	if err := c.tsdb.WritePrices(ctx, prices); err != nil {
+		slog.Warn("tsdb price write failed", "error", err, "source", source)
+	}
+}
+
+// fanOutFXRatesToTSDB is the FX counterpart to fanOutPricesToTSDB.
+func (c *sourceCollector) fanOutFXRatesToTSDB(ctx context.Context, rates []domain.FXRate) {
+	if c.tsdb == nil || len(rates) == 0 {
+		return
+	}
+	if err := c.tsdb.WriteFXRates(ctx, rates); err != nil {
+		slog.Warn("tsdb fx rate write failed", "error", err)
+	}
 }
 
 // savePartialResults persists collected prices and joins any collection/upsert errors.
@@ -165,7 +642,8 @@ func (c *sourceCollector) savePartialResults(
 	prices []domain.DailyPrice,
 	collectErr error,
 	source string,
-) error {
+) (coll.SourceStats, error) {
+	stats := coll.SourceStats{RowsFetched: int64(len(prices)), AnomaliesFlagged: countAnomalies(prices)}
 	var upsertErr error
 	if len(prices) > 0 {
 		if collectErr != nil {
@@ -177,7 +655,120 @@ func (c *sourceCollector) savePartialResults(
 			upsertErr = fmt.Errorf("upsert %s prices: %w", source, err)
 		} else {
 			slog.Info("prices saved", "rows", n, "source", source)
+			stats.RowsInserted = n
+			stats.RowsSkipped = stats.RowsFetched - n
+			c.fanOutPricesToTSDB(ctx, prices, source)
+		}
+	}
+	return stats, errors.Join(collectErr, upsertErr)
+}
+
+// saveMarginHistory persists collected margin loans, interest, and repayments and
+// joins any collection/upsert errors. Why save before checking collectErr:
+// CollectAll returns partial results on failure.
+func (c *sourceCollector) saveMarginHistory(
+	ctx context.Context,
+	history kis.MarginHistory,
+	collectErr error,
+) (coll.SourceStats, error) {
+	var upsertErr error
+	stats := coll.SourceStats{
+		RowsFetched: int64(len(history.Loans) + len(history.Interest) + len(history.Repayments)),
+	}
+
+	if len(history.Loans) > 0 {
+		n, err := c.repo.UpsertMarginLoans(ctx, history.Loans)
+		if err != nil {
+			upsertErr = errors.Join(upsertErr, fmt.Errorf("upsert margin loans: %w", err))
+		} else {
+			slog.Info("margin loans saved", "rows", n)
+			stats.RowsInserted += n
+		}
+	}
+	if len(history.Interest) > 0 {
+		n, err := c.repo.UpsertMarginInterest(ctx, history.Interest)
+		if err != nil {
+			upsertErr = errors.Join(upsertErr, fmt.Errorf("upsert margin interest: %w", err))
+		} else {
+			slog.Info("margin interest saved", "rows", n)
+			stats.RowsInserted += n
+		}
+	}
+	if len(history.Repayments) > 0 {
+		n, err := c.repo.UpsertMarginRepayments(ctx, history.Repayments)
+		if err != nil {
+			upsertErr = errors.Join(upsertErr, fmt.Errorf("upsert margin repayments: %w", err))
+		} else {
+			slog.Info("margin repayments saved", "rows", n)
+			stats.RowsInserted += n
+		}
+	}
+	stats.RowsSkipped = stats.RowsFetched - stats.RowsInserted
+
+	return stats, errors.Join(collectErr, upsertErr)
+}
+
+// savePricesAndActions persists collected prices and the corporate actions observed
+// alongside them in a single transaction, joining any collection/upsert errors.
+// Why tiingo-only: only tiingo surfaces splitFactor/divCash (see tiingo.Collector.CollectAll).
+func (c *sourceCollector) savePricesAndActions(
+	ctx context.Context,
+	prices []domain.DailyPrice,
+	actions []domain.CorporateAction,
+	collectErr error,
+	source string,
+) (coll.SourceStats, error) {
+	stats := coll.SourceStats{RowsFetched: int64(len(prices)), AnomaliesFlagged: countAnomalies(prices)}
+	var upsertErr error
+	if len(prices) > 0 {
+		if collectErr != nil {
+			slog.Warn("saving partial results before reporting error",
+				"collected", len(prices), "error", collectErr, "source", source)
+		}
+		priceRows, actionRows, err := c.repo.UpsertPricesAndCorporateActions(ctx, prices, actions)
+		if err != nil {
+			upsertErr = fmt.Errorf("upsert %s prices and corporate actions: %w", source, err)
+		} else {
+			slog.Info("prices saved", "rows", priceRows, "source", source)
+			stats.RowsInserted = priceRows
+			stats.RowsSkipped = stats.RowsFetched - priceRows
+			if actionRows > 0 {
+				slog.Info("corporate actions saved", "rows", actionRows, "source", source)
+				c.recomputeAdjustedClose(ctx, actions, source)
+			}
+			c.fanOutPricesToTSDB(ctx, prices, source)
+		}
+	}
+	return stats, errors.Join(collectErr, upsertErr)
+}
+
+// countAnomalies counts prices already flagged IsAnomaly by a source's own
+// collection pass (see kis.collect, tiingo.collect, alpaca.collect), so the
+// resulting SourceStats.AnomaliesFlagged reflects what was actually detected
+// rather than re-deriving it here.
+func countAnomalies(prices []domain.DailyPrice) int64 {
+	var n int64
+	for _, p := range prices {
+		if p.IsAnomaly {
+			n++
+		}
+	}
+	return n
+}
+
+// recomputeAdjustedClose refreshes adj_close for every symbol touched by actions, so a
+// newly observed split or dividend is reflected in historical rows immediately rather
+// than waiting for tiingo to reissue them. Failures are logged, not returned: a stale
+// adj_close is a data-quality issue, not a reason to fail the whole collection run.
+func (c *sourceCollector) recomputeAdjustedClose(ctx context.Context, actions []domain.CorporateAction, source string) {
+	seen := make(map[string]bool, len(actions))
+	for _, a := range actions {
+		if seen[a.Symbol] {
+			continue
+		}
+		seen[a.Symbol] = true
+		if err := c.repo.RecomputeAdjustedClose(ctx, a.Symbol); err != nil {
+			slog.Error("recompute adjusted close failed", "symbol", a.Symbol, "source", source, "error", err)
 		}
 	}
-	return errors.Join(collectErr, upsertErr)
 }
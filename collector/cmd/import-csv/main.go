@@ -0,0 +1,87 @@
+// Command import-csv loads a single OHLCV or FX rate CSV export (e.g. a
+// Yahoo Finance or Investing.com download) into the prices/fx_rate tables,
+// so the summary/backtest pipelines can be bootstrapped or run offline
+// without the live KIS/Tiingo/Alpaca/Frankfurter integrations.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/jusikbot/collector/internal/config"
+	"github.com/jusikbot/collector/internal/csvsource"
+	"github.com/jusikbot/collector/internal/store"
+)
+
+func main() {
+	dataType := flag.String("type", "", "data type to import: price or fx")
+	filePath := flag.String("file", "", "path to the CSV file")
+	symbol := flag.String("symbol", "", "stock symbol (required for -type price)")
+	pair := flag.String("pair", "", "currency pair, e.g. USD/KRW (required for -type fx)")
+	dateFormat := flag.String("date-format", "2006-01-02", "time.Parse reference layout for the CSV's date column")
+	flag.Parse()
+
+	if err := run(context.Background(), *dataType, *filePath, *symbol, *pair, *dateFormat); err != nil {
+		slog.Error("import-csv failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, dataType, filePath, symbol, pair, dateFormat string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", filePath, err)
+	}
+	defer f.Close()
+
+	env, err := config.LoadEnv()
+	if err != nil {
+		return fmt.Errorf("load environment config: %w", err)
+	}
+	pool, err := store.ConnectDB(ctx, env.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer pool.Close()
+	repo := store.NewRepository(pool)
+
+	switch dataType {
+	case "price":
+		if symbol == "" {
+			return fmt.Errorf("-symbol is required for -type price")
+		}
+		reader := &csvsource.PriceCSVReader{Columns: csvsource.DefaultPriceColumns, DateFormat: dateFormat}
+		prices, err := reader.Read(f, symbol)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", filePath, err)
+		}
+		n, err := repo.UpsertPrices(ctx, prices)
+		if err != nil {
+			return fmt.Errorf("upsert prices: %w", err)
+		}
+		fmt.Printf("import-csv: upserted %d rows for %s from %s\n", n, symbol, filePath)
+
+	case "fx":
+		if pair == "" {
+			return fmt.Errorf("-pair is required for -type fx")
+		}
+		reader := &csvsource.FXCSVReader{Columns: csvsource.DefaultFXColumns, DateFormat: dateFormat}
+		rates, err := reader.Read(f, pair)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", filePath, err)
+		}
+		n, err := repo.UpsertFXRates(ctx, rates)
+		if err != nil {
+			return fmt.Errorf("upsert fx rates: %w", err)
+		}
+		fmt.Printf("import-csv: upserted %d rows for %s from %s\n", n, pair, filePath)
+
+	default:
+		return fmt.Errorf("unknown -type %q (allowed: price, fx)", dataType)
+	}
+
+	return nil
+}
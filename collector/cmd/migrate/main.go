@@ -2,15 +2,26 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/jusikbot/collector/internal/store"
 )
 
 func main() {
+	rollback := flag.Int("rollback", -1, "roll back migrations to this target version and exit (mutually exclusive with the up|down|status subcommands)")
+	flag.Parse()
+	args := flag.Args()
+
+	if *rollback < 0 && len(args) < 1 {
+		usage()
+		os.Exit(1)
+	}
+
 	databaseURL := os.Getenv("DATABASE_URL")
 	if databaseURL == "" {
 		log.Fatal("DATABASE_URL is required")
@@ -25,9 +36,71 @@ func main() {
 	}
 	defer pool.Close()
 
-	if err := store.RunMigrations(ctx, pool); err != nil {
-		log.Fatalf("migration failed: %v", err)
+	if *rollback >= 0 {
+		if err := store.RollbackMigrations(ctx, pool, *rollback); err != nil {
+			log.Fatalf("rollback failed: %v", err)
+		}
+		fmt.Printf("rolled back to version %d\n", *rollback)
+		return
 	}
 
-	fmt.Println("migrations applied successfully")
+	dir, err := store.MigrationsDir()
+	if err != nil {
+		log.Fatalf("load migrations: %v", err)
+	}
+
+	switch args[0] {
+	case "up":
+		if err := store.Migrate(ctx, pool, dir); err != nil {
+			log.Fatalf("migration failed: %v", err)
+		}
+		fmt.Println("migrations applied successfully")
+	case "down":
+		target, err := parseDownTarget(args[1:])
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if err := store.MigrateDown(ctx, pool, dir, target); err != nil {
+			log.Fatalf("rollback failed: %v", err)
+		}
+		fmt.Printf("rolled back to version %d\n", target)
+	case "status":
+		statuses, err := store.Status(ctx, pool, dir)
+		if err != nil {
+			log.Fatalf("status failed: %v", err)
+		}
+		printStatus(statuses)
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+// parseDownTarget reads the optional target version argument for `migrate
+// down`, defaulting to 0 (roll back every migration) when omitted.
+func parseDownTarget(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+	target, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid target version %q: %w", args[0], err)
+	}
+	return target, nil
+}
+
+func printStatus(statuses []store.MigrationStatus) {
+	for _, s := range statuses {
+		state := "pending"
+		appliedAt := ""
+		if s.Applied {
+			state = "applied"
+			appliedAt = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%06d  %-40s  %-8s  %s\n", s.Version, s.Name, state, appliedAt)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate [-rollback=N] up|down [target]|status")
 }
@@ -0,0 +1,238 @@
+package alpaca
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/httpclient"
+	"github.com/jusikbot/collector/internal/ratelimit"
+)
+
+const sourceName = "alpaca"
+
+// ErrTickerInvalid signals that the requested symbol does not exist on Alpaca.
+var ErrTickerInvalid = errors.New("ticker not found on alpaca")
+
+// ErrMarketUnsupported signals that FetchBenchmark was asked for a market Alpaca
+// doesn't serve: Alpaca is a US equities data provider, so only MarketUS resolves.
+var ErrMarketUnsupported = errors.New("alpaca: market not supported")
+
+// benchmarkSymbols maps a market to its benchmark symbol. Mirrors
+// summary.BenchmarkSymbols[domain.MarketUS]; duplicated here rather than
+// imported to keep this package independent of internal/summary.
+var benchmarkSymbols = map[domain.Market]string{
+	domain.MarketUS: "QQQ",
+}
+
+// alpacaBar represents a single row from the Alpaca /v2/stocks/{symbol}/bars API.
+type alpacaBar struct {
+	Close  float64 `json:"c"`
+	High   float64 `json:"h"`
+	Low    float64 `json:"l"`
+	Open   float64 `json:"o"`
+	Time   string  `json:"t"`
+	Volume float64 `json:"v"`
+}
+
+// barsResponse is the envelope Alpaca wraps bars in.
+type barsResponse struct {
+	Bars []alpacaBar `json:"bars"`
+}
+
+// corporateActionsResponse is the envelope Alpaca's /v1/corporate-actions
+// endpoint wraps splits and dividends in.
+type corporateActionsResponse struct {
+	CorporateActions struct {
+		CashDividends []alpacaCashDividend `json:"cash_dividends"`
+		ForwardSplits []alpacaSplit        `json:"forward_splits"`
+		ReverseSplits []alpacaSplit        `json:"reverse_splits"`
+	} `json:"corporate_actions"`
+}
+
+type alpacaCashDividend struct {
+	ExDate string  `json:"ex_date"`
+	Rate   float64 `json:"rate"`
+	Symbol string  `json:"symbol"`
+}
+
+type alpacaSplit struct {
+	ExDate  string  `json:"ex_date"`
+	NewRate float64 `json:"new_rate"`
+	OldRate float64 `json:"old_rate"`
+	Symbol  string  `json:"symbol"`
+}
+
+// Client wraps an httpclient.Client configured for the Alpaca data API.
+// Implements domain.MarketDataFetcher.
+type Client struct {
+	http *httpclient.Client
+}
+
+// NewClient creates an Alpaca API client.
+// The httpClient must be pre-configured with base URL and
+// APCA-API-KEY-ID / APCA-API-SECRET-KEY headers.
+func NewClient(httpClient *httpclient.Client) *Client {
+	return &Client{http: httpClient}
+}
+
+// FetchDailyPrices fetches daily bars for symbol and converts them to
+// domain.DailyPrice, without anomaly marking (see Collector.CollectAll for that).
+// Implements domain.StockDataFetcher.
+func (c *Client) FetchDailyPrices(ctx context.Context, symbol string, from, to time.Time) ([]domain.DailyPrice, error) {
+	raw, err := c.fetchBars(ctx, symbol, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make([]domain.DailyPrice, 0, len(raw))
+	for i, b := range raw {
+		p, err := toDailyPrice(b, symbol)
+		if err != nil {
+			return nil, fmt.Errorf("row %d date %q: %w", i, b.Time, err)
+		}
+		prices = append(prices, p)
+	}
+
+	return prices, nil
+}
+
+// fetchBars calls the Alpaca daily bars API and returns the raw parsed response.
+func (c *Client) fetchBars(ctx context.Context, symbol string, from, to time.Time) ([]alpacaBar, error) {
+	path := fmt.Sprintf("/v2/stocks/%s/bars", symbol)
+
+	body, _, err := c.http.Get(ctx, path,
+		httpclient.WithQueryParam("timeframe", "1Day"),
+		httpclient.WithQueryParam("start", from.Format("2006-01-02")),
+		httpclient.WithQueryParam("end", to.Format("2006-01-02")),
+		httpclient.WithQueryParam("adjustment", "split"),
+	)
+	if err != nil {
+		var apiErr *httpclient.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == 422 {
+			return nil, fmt.Errorf("symbol %s: %w", symbol, ErrTickerInvalid)
+		}
+		return nil, fmt.Errorf("fetch bars for %s: %w", symbol, err)
+	}
+
+	var parsed barsResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("parse alpaca response for %s: %w", symbol, err)
+	}
+
+	return parsed.Bars, nil
+}
+
+// FetchBenchmark fetches daily prices for market's benchmark symbol.
+// Implements domain.MarketDataFetcher. Only MarketUS is supported; any other
+// market returns ErrMarketUnsupported since Alpaca only serves US equities.
+func (c *Client) FetchBenchmark(ctx context.Context, market domain.Market, from, to time.Time) ([]domain.DailyPrice, error) {
+	symbol, ok := benchmarkSymbols[market]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMarketUnsupported, market)
+	}
+	return c.FetchDailyPrices(ctx, symbol, from, to)
+}
+
+// FetchSplitsAndDividends fetches splits and cash dividends for symbol within
+// the date range and converts them to domain.CorporateAction.
+// Implements domain.MarketDataFetcher.
+func (c *Client) FetchSplitsAndDividends(ctx context.Context, symbol string, from, to time.Time) ([]domain.CorporateAction, error) {
+	path := "/v1/corporate-actions"
+
+	body, _, err := c.http.Get(ctx, path,
+		httpclient.WithQueryParam("symbols", symbol),
+		httpclient.WithQueryParam("types", "cash_dividend,forward_split,reverse_split"),
+		httpclient.WithQueryParam("start", from.Format("2006-01-02")),
+		httpclient.WithQueryParam("end", to.Format("2006-01-02")),
+	)
+	if err != nil {
+		var apiErr *httpclient.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == 422 {
+			return nil, fmt.Errorf("symbol %s: %w", symbol, ErrTickerInvalid)
+		}
+		return nil, fmt.Errorf("fetch corporate actions for %s: %w", symbol, err)
+	}
+
+	var parsed corporateActionsResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("parse alpaca corporate actions for %s: %w", symbol, err)
+	}
+
+	return toCorporateActions(parsed, symbol)
+}
+
+// IsRetryable determines whether an error from the Alpaca client warrants retry.
+// Retryable: rate limiting (HTTP 429), server errors (5xx).
+// Non-retryable: invalid ticker (422), parse errors.
+func IsRetryable(err error) bool {
+	return ratelimit.DefaultIsRetryable(err)
+}
+
+// toCorporateActions converts a parsed corporateActionsResponse into
+// domain.CorporateAction rows. A split's ratio is NewRate/OldRate (e.g. a 4-for-1
+// forward split reports NewRate=4, OldRate=1, giving a SplitRatio of 4.0),
+// matching the multiplicative factor validate.CrossValidateAdjClose expects.
+func toCorporateActions(resp corporateActionsResponse, symbol string) ([]domain.CorporateAction, error) {
+	actions := make([]domain.CorporateAction, 0,
+		len(resp.CorporateActions.CashDividends)+len(resp.CorporateActions.ForwardSplits)+len(resp.CorporateActions.ReverseSplits))
+
+	for _, d := range resp.CorporateActions.CashDividends {
+		exDate, err := time.Parse("2006-01-02", d.ExDate)
+		if err != nil {
+			return nil, fmt.Errorf("parse dividend ex_date %q: %w", d.ExDate, err)
+		}
+		actions = append(actions, domain.CorporateAction{
+			CashAmount: d.Rate,
+			Currency:   "USD",
+			ExDate:     exDate,
+			Kind:       domain.CorporateActionDividend,
+			Symbol:     symbol,
+		})
+	}
+
+	for _, splits := range [][]alpacaSplit{resp.CorporateActions.ForwardSplits, resp.CorporateActions.ReverseSplits} {
+		for _, s := range splits {
+			exDate, err := time.Parse("2006-01-02", s.ExDate)
+			if err != nil {
+				return nil, fmt.Errorf("parse split ex_date %q: %w", s.ExDate, err)
+			}
+			ratio := 1.0
+			if s.OldRate != 0 {
+				ratio = s.NewRate / s.OldRate
+			}
+			actions = append(actions, domain.CorporateAction{
+				ExDate:     exDate,
+				Kind:       domain.CorporateActionSplit,
+				SplitRatio: ratio,
+				Symbol:     symbol,
+			})
+		}
+	}
+
+	return actions, nil
+}
+
+func toDailyPrice(b alpacaBar, symbol string) (domain.DailyPrice, error) {
+	date, err := time.Parse(time.RFC3339, b.Time)
+	if err != nil {
+		return domain.DailyPrice{}, fmt.Errorf("parse date %q: %w", b.Time, err)
+	}
+
+	return domain.DailyPrice{
+		// Why AdjClose == Close: the adjustment=split query param already returns
+		// split-adjusted OHLC; Alpaca's basic bars endpoint has no separate raw/adjusted pair.
+		AdjClose: b.Close,
+		Close:    b.Close,
+		Date:     date,
+		High:     b.High,
+		Low:      b.Low,
+		Open:     b.Open,
+		Source:   sourceName,
+		Symbol:   symbol,
+		Volume:   int64(b.Volume),
+	}, nil
+}
@@ -0,0 +1,245 @@
+package alpaca
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/httpclient"
+)
+
+func newTestClient(srv *httptest.Server) *Client {
+	hc := httpclient.NewClient(
+		srv.URL,
+		map[string]string{"APCA-API-KEY-ID": "key", "APCA-API-SECRET-KEY": "secret"},
+		srv.Client(),
+		0,
+	)
+	return NewClient(hc)
+}
+
+func TestFetchBars(t *testing.T) {
+	t.Run("normal JSON response", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v2/stocks/AAPL/bars" {
+				t.Errorf("path = %q, want /v2/stocks/AAPL/bars", r.URL.Path)
+			}
+			if got := r.URL.Query().Get("start"); got != "2024-01-01" {
+				t.Errorf("start = %q, want 2024-01-01", got)
+			}
+			if got := r.Header.Get("APCA-API-KEY-ID"); got != "key" {
+				t.Errorf("APCA-API-KEY-ID = %q, want key", got)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"bars":[
+				{"c":150.25,"h":151.0,"l":149.5,"o":150.0,"t":"2024-01-15T05:00:00Z","v":55000000},
+				{"c":155.50,"h":156.0,"l":154.0,"o":155.0,"t":"2024-01-16T05:00:00Z","v":48000000}
+			]}`))
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+		bars, err := client.fetchBars(context.Background(), "AAPL", from, to)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(bars) != 2 {
+			t.Fatalf("len(bars) = %d, want 2", len(bars))
+		}
+		if bars[0].Close != 150.25 {
+			t.Errorf("bars[0].Close = %v, want 150.25", bars[0].Close)
+		}
+	})
+
+	t.Run("422 returns ErrTickerInvalid", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		_, err := client.fetchBars(context.Background(), "BADTICKER", time.Now(), time.Now())
+		if !errors.Is(err, ErrTickerInvalid) {
+			t.Errorf("err = %v, want ErrTickerInvalid", err)
+		}
+	})
+}
+
+func TestFetchDailyPrices(t *testing.T) {
+	t.Run("converts bars to domain prices", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"bars":[
+				{"c":150.25,"h":151.0,"l":149.5,"o":150.0,"t":"2024-01-15T05:00:00Z","v":55000000}
+			]}`))
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+		prices, err := client.FetchDailyPrices(context.Background(), "AAPL", from, to)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(prices) != 1 {
+			t.Fatalf("len(prices) = %d, want 1", len(prices))
+		}
+		if prices[0].Symbol != "AAPL" {
+			t.Errorf("Symbol = %q, want AAPL", prices[0].Symbol)
+		}
+		if prices[0].Source != sourceName {
+			t.Errorf("Source = %q, want %q", prices[0].Source, sourceName)
+		}
+	})
+
+	t.Run("propagates ErrTickerInvalid", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		_, err := client.FetchDailyPrices(context.Background(), "BADTICKER", time.Now(), time.Now())
+		if !errors.Is(err, ErrTickerInvalid) {
+			t.Errorf("err = %v, want ErrTickerInvalid", err)
+		}
+	})
+}
+
+func TestFetchBenchmark(t *testing.T) {
+	t.Run("MarketUS resolves to QQQ", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v2/stocks/QQQ/bars" {
+				t.Errorf("path = %q, want /v2/stocks/QQQ/bars", r.URL.Path)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"bars":[{"c":400.0,"h":401.0,"l":399.0,"o":400.0,"t":"2024-01-15T05:00:00Z","v":1000000}]}`))
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+		prices, err := client.FetchBenchmark(context.Background(), domain.MarketUS, from, to)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(prices) != 1 || prices[0].Symbol != "QQQ" {
+			t.Errorf("prices = %+v, want one QQQ row", prices)
+		}
+	})
+
+	t.Run("unsupported market returns ErrMarketUnsupported", func(t *testing.T) {
+		client := newTestClient(httptest.NewServer(nil))
+		_, err := client.FetchBenchmark(context.Background(), domain.MarketKR, time.Now(), time.Now())
+		if !errors.Is(err, ErrMarketUnsupported) {
+			t.Errorf("err = %v, want ErrMarketUnsupported", err)
+		}
+	})
+}
+
+func TestFetchSplitsAndDividends(t *testing.T) {
+	t.Run("converts dividends and splits to domain.CorporateAction", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/corporate-actions" {
+				t.Errorf("path = %q, want /v1/corporate-actions", r.URL.Path)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"corporate_actions":{
+				"cash_dividends":[{"ex_date":"2024-02-09","rate":0.24,"symbol":"AAPL"}],
+				"forward_splits":[{"ex_date":"2024-06-10","new_rate":4,"old_rate":1,"symbol":"AAPL"}],
+				"reverse_splits":[]
+			}}`))
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+		actions, err := client.FetchSplitsAndDividends(context.Background(), "AAPL", from, to)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(actions) != 2 {
+			t.Fatalf("len(actions) = %d, want 2", len(actions))
+		}
+
+		dividend := actions[0]
+		if dividend.Kind != domain.CorporateActionDividend || dividend.CashAmount != 0.24 {
+			t.Errorf("dividend = %+v, want Kind=dividend CashAmount=0.24", dividend)
+		}
+
+		split := actions[1]
+		if split.Kind != domain.CorporateActionSplit || split.SplitRatio != 4.0 {
+			t.Errorf("split = %+v, want Kind=split SplitRatio=4.0", split)
+		}
+	})
+
+	t.Run("422 returns ErrTickerInvalid", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		_, err := client.FetchSplitsAndDividends(context.Background(), "BADTICKER", time.Now(), time.Now())
+		if !errors.Is(err, ErrTickerInvalid) {
+			t.Errorf("err = %v, want ErrTickerInvalid", err)
+		}
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limited", httpclient.ErrRateLimited, true},
+		{"server error", &httpclient.APIError{IsRetryable: true, StatusCode: 500}, true},
+		{"invalid ticker", &httpclient.APIError{IsRetryable: false, StatusCode: 422}, false},
+		{"other error", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToDailyPrice(t *testing.T) {
+	b := alpacaBar{Close: 150.25, High: 151.0, Low: 149.5, Open: 150.0, Time: "2024-01-15T05:00:00Z", Volume: 55000000}
+
+	p, err := toDailyPrice(b, "AAPL")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Symbol != "AAPL" {
+		t.Errorf("Symbol = %q, want AAPL", p.Symbol)
+	}
+	if p.AdjClose != p.Close {
+		t.Errorf("AdjClose = %v, want equal to Close %v", p.AdjClose, p.Close)
+	}
+	if p.Source != sourceName {
+		t.Errorf("Source = %q, want %q", p.Source, sourceName)
+	}
+
+	if _, err := toDailyPrice(alpacaBar{Time: "not-a-date"}, "AAPL"); err == nil {
+		t.Error("expected error for invalid date")
+	}
+}
@@ -0,0 +1,29 @@
+package alpaca
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeStartDate(t *testing.T) {
+	to := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("no gap uses lookback", func(t *testing.T) {
+		got := computeStartDate(to, map[string]time.Time{}, "AAPL")
+		want := to.AddDate(0, 0, -defaultLookbackDays)
+		if !got.Equal(want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("gap newer than lookback uses gap+1", func(t *testing.T) {
+		lastDate := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+		gaps := map[string]time.Time{"AAPL": lastDate}
+
+		got := computeStartDate(to, gaps, "AAPL")
+		want := lastDate.AddDate(0, 0, 1)
+		if !got.Equal(want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
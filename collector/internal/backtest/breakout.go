@@ -0,0 +1,45 @@
+package backtest
+
+import (
+	"time"
+
+	"github.com/jusikbot/collector/internal/summary"
+)
+
+// BreakoutStrategy enters a symbol on a golden cross (50D MA crossing above
+// 200D MA, summary.DetectMACross's "GC") confirmed by a positive 20-day
+// return relative to its market benchmark, and exits on a dead cross ("DC").
+// MinFiftyTwoWeekPos additionally gates entries to symbols actually near
+// their 52-week high, so a golden cross deep inside a multi-year range
+// doesn't by itself trigger a breakout entry.
+type BreakoutStrategy struct {
+	// MinFiftyTwoWeekPos is the minimum FiftyTwoWeekPos (0-1) an entry
+	// requires; e.g. 0.9 restricts entries to within the top 10% of the
+	// 52-week range.
+	MinFiftyTwoWeekPos float64
+}
+
+// NewBreakoutStrategy returns a BreakoutStrategy gated at minFiftyTwoWeekPos.
+func NewBreakoutStrategy(minFiftyTwoWeekPos float64) *BreakoutStrategy {
+	return &BreakoutStrategy{MinFiftyTwoWeekPos: minFiftyTwoWeekPos}
+}
+
+func (s *BreakoutStrategy) OnBar(day time.Time, ind summary.SymbolIndicators, holding bool) Action {
+	if holding {
+		if ind.MACross != nil && *ind.MACross == "DC" {
+			return ActionSell
+		}
+		return ActionHold
+	}
+
+	if ind.MACross == nil || *ind.MACross != "GC" {
+		return ActionHold
+	}
+	if ind.RelativeBench20D == nil || *ind.RelativeBench20D <= 0 {
+		return ActionHold
+	}
+	if ind.FiftyTwoWeekPos == nil || *ind.FiftyTwoWeekPos < s.MinFiftyTwoWeekPos {
+		return ActionHold
+	}
+	return ActionBuy
+}
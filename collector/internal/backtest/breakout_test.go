@@ -0,0 +1,69 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/summary"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestBreakoutStrategy_OnBar(t *testing.T) {
+	day := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	s := NewBreakoutStrategy(0.9)
+
+	t.Run("enters on golden cross with positive relative strength and near the high", func(t *testing.T) {
+		ind := summary.SymbolIndicators{
+			MACross:          ptr("GC"),
+			RelativeBench20D: ptr(1.5),
+			FiftyTwoWeekPos:  ptr(0.95),
+		}
+		if got := s.OnBar(day, ind, false); got != ActionBuy {
+			t.Errorf("OnBar() = %q, want %q", got, ActionBuy)
+		}
+	})
+
+	t.Run("holds on golden cross without positive relative strength", func(t *testing.T) {
+		ind := summary.SymbolIndicators{
+			MACross:          ptr("GC"),
+			RelativeBench20D: ptr(-0.5),
+			FiftyTwoWeekPos:  ptr(0.95),
+		}
+		if got := s.OnBar(day, ind, false); got != ActionHold {
+			t.Errorf("OnBar() = %q, want %q", got, ActionHold)
+		}
+	})
+
+	t.Run("holds on golden cross too far below the 52-week high", func(t *testing.T) {
+		ind := summary.SymbolIndicators{
+			MACross:          ptr("GC"),
+			RelativeBench20D: ptr(1.5),
+			FiftyTwoWeekPos:  ptr(0.5),
+		}
+		if got := s.OnBar(day, ind, false); got != ActionHold {
+			t.Errorf("OnBar() = %q, want %q", got, ActionHold)
+		}
+	})
+
+	t.Run("holds when not already holding and no cross detected", func(t *testing.T) {
+		ind := summary.SymbolIndicators{}
+		if got := s.OnBar(day, ind, false); got != ActionHold {
+			t.Errorf("OnBar() = %q, want %q", got, ActionHold)
+		}
+	})
+
+	t.Run("exits an open position on dead cross", func(t *testing.T) {
+		ind := summary.SymbolIndicators{MACross: ptr("DC")}
+		if got := s.OnBar(day, ind, true); got != ActionSell {
+			t.Errorf("OnBar() = %q, want %q", got, ActionSell)
+		}
+	})
+
+	t.Run("keeps holding an open position absent a dead cross", func(t *testing.T) {
+		ind := summary.SymbolIndicators{MACross: ptr("GC")}
+		if got := s.OnBar(day, ind, true); got != ActionHold {
+			t.Errorf("OnBar() = %q, want %q", got, ActionHold)
+		}
+	})
+}
@@ -0,0 +1,212 @@
+package backtest
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/summary"
+)
+
+// lookbackWindowDays bounds how much trailing history Engine feeds
+// summary.ComputeSymbolIndicators for each simulated day. Why 380: same
+// reasoning as summary's own priceHistoryLookbackDays (252 trading days/year
+// plus ~128 calendar gap days covers a full 52-week high/low and 200D MA with
+// headroom); kept as its own constant here since that one is unexported.
+const lookbackWindowDays = 380
+
+// Config holds the parameters for one Engine.Run.
+type Config struct {
+	// FeeBps is charged on both the entry and exit leg of a trade, in basis
+	// points of the traded notional (e.g. 10 for 0.10%).
+	FeeBps float64
+	// From and To bound the simulated date range (inclusive). Each
+	// SymbolInput's Prices should extend at least lookbackWindowDays before
+	// From so day-1's indicators aren't computed on a truncated window.
+	From, To time.Time
+	// InitialCapital is split evenly across the symbols passed to Run; each
+	// symbol's allocation trades independently of the others.
+	InitialCapital float64
+	// MinSamples and RiskFreeAnnual are passed straight through to
+	// summary.ComputeSymbolIndicators; see SummaryOptions for their meaning.
+	MinSamples     int
+	RiskFreeAnnual float64
+	// SlippageBps adds to FeeBps as an extra simulated cost on both legs of a
+	// trade, modeling the gap between a signal's close price and the
+	// achievable fill price.
+	SlippageBps float64
+}
+
+// costRate returns the combined fee+slippage cost as a fraction of notional
+// (e.g. 0.001 for 10bps).
+func (c Config) costRate() float64 {
+	return (c.FeeBps + c.SlippageBps) / 10000
+}
+
+// SymbolInput is one symbol's price history and the benchmark history for its
+// market, both expected to extend at least lookbackWindowDays before
+// Config.From. IsBenchmark matches summary.ComputeSymbolIndicators' own flag:
+// set it for a symbol that is itself the market benchmark, so
+// RelativeBench20D isn't computed against itself.
+type SymbolInput struct {
+	BenchPrices []domain.DailyPrice
+	IsBenchmark bool
+	Prices      []domain.DailyPrice
+	Symbol      string
+}
+
+// Trade is one completed round trip (buy then sell) in a symbol.
+type Trade struct {
+	EntryDate  time.Time
+	EntryPrice float64
+	ExitDate   time.Time
+	ExitPrice  float64
+	PnL        float64
+	PnLPct     float64
+	Symbol     string
+}
+
+// Engine replays a Strategy day-by-day over historical prices and simulates
+// the trades it emits.
+type Engine struct {
+	cfg      Config
+	strategy Strategy
+}
+
+// NewEngine returns an Engine that replays strategy under cfg.
+func NewEngine(cfg Config, strategy Strategy) *Engine {
+	return &Engine{cfg: cfg, strategy: strategy}
+}
+
+// Run replays cfg over every symbol in inputs, combining each symbol's
+// independently-simulated equity curve (seeded with an equal split of
+// cfg.InitialCapital) into a single portfolio Report.
+func (e *Engine) Run(inputs []SymbolInput) Report {
+	if len(inputs) == 0 {
+		return Report{}
+	}
+
+	allocation := e.cfg.InitialCapital / float64(len(inputs))
+
+	var allTrades []Trade
+	curves := make([][]domain.DailyPrice, 0, len(inputs))
+	for _, in := range inputs {
+		trades, curve := e.runSymbol(in, allocation)
+		allTrades = append(allTrades, trades...)
+		curves = append(curves, curve)
+	}
+
+	return buildReport(combineEquityCurves(curves), allTrades, e.cfg.RiskFreeAnnual)
+}
+
+// runSymbol walks in.Prices within [cfg.From, cfg.To], feeding the strategy a
+// trailing window of indicators for each day and simulating a single
+// long-only position sized off allocation. It returns the symbol's completed
+// trades and its simulated daily account value as a synthetic price series
+// (AdjClose/Close both set to that day's cash+position value).
+func (e *Engine) runSymbol(in SymbolInput, allocation float64) ([]Trade, []domain.DailyPrice) {
+	cash := allocation
+	var shares, costBasis float64
+	var entryDate time.Time
+	var entryPrice float64
+	holding := false
+
+	var trades []Trade
+	curve := make([]domain.DailyPrice, 0, len(in.Prices))
+	rate := e.cfg.costRate()
+
+	for _, p := range in.Prices {
+		if p.Date.Before(e.cfg.From) || p.Date.After(e.cfg.To) {
+			continue
+		}
+
+		window := trailingWindow(in.Prices, p.Date, lookbackWindowDays)
+		benchWindow := trailingWindow(in.BenchPrices, p.Date, lookbackWindowDays)
+		// 0: backtest replays raw price history with no Instrument lookup, so
+		// the 52-week high/low are left unrounded (see ComputeSymbolIndicators).
+		ind := summary.ComputeSymbolIndicators(window, benchWindow, in.IsBenchmark, e.cfg.MinSamples, e.cfg.RiskFreeAnnual, 0)
+
+		switch action := e.strategy.OnBar(p.Date, ind, holding); {
+		case action == ActionBuy && !holding:
+			fillPrice := p.AdjClose * (1 + rate)
+			shares = cash / fillPrice
+			costBasis = cash
+			cash = 0
+			holding = true
+			entryDate = p.Date
+			entryPrice = fillPrice
+		case action == ActionSell && holding:
+			fillPrice := p.AdjClose * (1 - rate)
+			proceeds := shares * fillPrice
+			trades = append(trades, closeTrade(in.Symbol, entryDate, entryPrice, p.Date, fillPrice, costBasis, proceeds))
+			cash = proceeds
+			shares = 0
+			holding = false
+		}
+
+		value := cash + shares*p.AdjClose
+		curve = append(curve, domain.DailyPrice{AdjClose: value, Close: value, Date: p.Date})
+	}
+
+	return trades, curve
+}
+
+func closeTrade(symbol string, entryDate time.Time, entryPrice float64, exitDate time.Time, exitPrice, costBasis, proceeds float64) Trade {
+	pnl := proceeds - costBasis
+	var pnlPct float64
+	if costBasis != 0 {
+		pnlPct = pnl / costBasis * 100
+	}
+	return Trade{
+		EntryDate:  entryDate,
+		EntryPrice: entryPrice,
+		ExitDate:   exitDate,
+		ExitPrice:  exitPrice,
+		PnL:        pnl,
+		PnLPct:     pnlPct,
+		Symbol:     symbol,
+	}
+}
+
+// trailingWindow returns the prefix of prices (sorted ascending by Date, as
+// every store.Repository/kis.Client result is) dated on or before asOf,
+// trimmed to at most windowDays trailing entries. Looking up by date rather
+// than a shared index lets a symbol's own series and its benchmark's series
+// window independently, even when the two don't share a trading calendar.
+func trailingWindow(prices []domain.DailyPrice, asOf time.Time, windowDays int) []domain.DailyPrice {
+	cut := sort.Search(len(prices), func(i int) bool { return prices[i].Date.After(asOf) })
+	start := 0
+	if cut-windowDays > 0 {
+		start = cut - windowDays
+	}
+	return prices[start:cut]
+}
+
+// combineEquityCurves sums each symbol's independently-simulated daily value
+// by date into one portfolio equity curve — additive rather than
+// fetcher.mergeByRank's override-by-rank, since every curve contributes value
+// simultaneously rather than competing to fill the same date. Symbols whose
+// trading calendars don't line up on a given date will make that date's total
+// dip by the missing symbol's value for that one day; this is an accepted
+// simplification for a first-pass multi-symbol backtest.
+func combineEquityCurves(curves [][]domain.DailyPrice) []domain.DailyPrice {
+	totals := make(map[time.Time]float64)
+	var dates []time.Time
+	for _, curve := range curves {
+		for _, p := range curve {
+			if _, ok := totals[p.Date]; !ok {
+				dates = append(dates, p.Date)
+			}
+			totals[p.Date] += p.AdjClose
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	combined := make([]domain.DailyPrice, 0, len(dates))
+	for _, d := range dates {
+		value := totals[d]
+		combined = append(combined, domain.DailyPrice{AdjClose: value, Close: value, Date: d})
+	}
+	return combined
+}
@@ -0,0 +1,163 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/summary"
+)
+
+func dailyPrices(adjCloses []float64, start time.Time) []domain.DailyPrice {
+	prices := make([]domain.DailyPrice, len(adjCloses))
+	for i, c := range adjCloses {
+		prices[i] = domain.DailyPrice{
+			AdjClose: c,
+			Close:    c,
+			Date:     start.AddDate(0, 0, i),
+		}
+	}
+	return prices
+}
+
+// scriptedStrategy buys/sells on exact calendar dates, so engine tests can
+// drive Engine.Run without depending on summary's indicator math.
+type scriptedStrategy struct {
+	buyOn  map[string]bool
+	sellOn map[string]bool
+}
+
+func (s scriptedStrategy) OnBar(day time.Time, ind summary.SymbolIndicators, holding bool) Action {
+	key := day.Format("2006-01-02")
+	switch {
+	case !holding && s.buyOn[key]:
+		return ActionBuy
+	case holding && s.sellOn[key]:
+		return ActionSell
+	default:
+		return ActionHold
+	}
+}
+
+func TestEngine_Run_SimulatesTrade(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	closes := []float64{100, 100, 100, 100, 100, 110, 110, 110, 110, 110}
+	prices := dailyPrices(closes, start)
+
+	strategy := scriptedStrategy{
+		buyOn:  map[string]bool{"2024-01-03": true},
+		sellOn: map[string]bool{"2024-01-06": true},
+	}
+
+	cfg := Config{
+		From:           start,
+		To:             start.AddDate(0, 0, 9),
+		InitialCapital: 1000,
+		MinSamples:     1,
+	}
+	engine := NewEngine(cfg, strategy)
+
+	report := engine.Run([]SymbolInput{{Symbol: "TEST", Prices: prices}})
+
+	if report.TotalTrades != 1 {
+		t.Fatalf("TotalTrades = %d, want 1", report.TotalTrades)
+	}
+	trade := report.Trades[0]
+	if trade.EntryPrice != 100 {
+		t.Errorf("EntryPrice = %v, want 100", trade.EntryPrice)
+	}
+	if trade.ExitPrice != 110 {
+		t.Errorf("ExitPrice = %v, want 110", trade.ExitPrice)
+	}
+	if trade.PnL <= 0 {
+		t.Errorf("PnL = %v, want > 0", trade.PnL)
+	}
+	if report.WinningTrades != 1 {
+		t.Errorf("WinningTrades = %d, want 1", report.WinningTrades)
+	}
+	if report.WinRate != 100 {
+		t.Errorf("WinRate = %v, want 100", report.WinRate)
+	}
+}
+
+func TestEngine_Run_AppliesFeeAndSlippage(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	closes := []float64{100, 100, 100, 110}
+	prices := dailyPrices(closes, start)
+
+	strategy := scriptedStrategy{
+		buyOn:  map[string]bool{"2024-01-01": true},
+		sellOn: map[string]bool{"2024-01-04": true},
+	}
+
+	cfg := Config{
+		From:           start,
+		To:             start.AddDate(0, 0, 3),
+		InitialCapital: 1000,
+		MinSamples:     1,
+		FeeBps:         50,
+		SlippageBps:    50,
+	}
+	engine := NewEngine(cfg, strategy)
+
+	report := engine.Run([]SymbolInput{{Symbol: "TEST", Prices: prices}})
+
+	if len(report.Trades) != 1 {
+		t.Fatalf("len(Trades) = %d, want 1", len(report.Trades))
+	}
+	trade := report.Trades[0]
+	if trade.EntryPrice != 101 {
+		t.Errorf("EntryPrice = %v, want 101 (100 * 1.01)", trade.EntryPrice)
+	}
+	if trade.ExitPrice != 108.9 {
+		t.Errorf("ExitPrice = %v, want 108.9 (110 * 0.99)", trade.ExitPrice)
+	}
+}
+
+func TestTrailingWindow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	prices := dailyPrices([]float64{1, 2, 3, 4, 5}, start)
+
+	t.Run("returns the full prefix when shorter than windowDays", func(t *testing.T) {
+		window := trailingWindow(prices, start.AddDate(0, 0, 2), 10)
+		if len(window) != 3 {
+			t.Fatalf("len = %d, want 3", len(window))
+		}
+	})
+
+	t.Run("trims to windowDays trailing entries", func(t *testing.T) {
+		window := trailingWindow(prices, start.AddDate(0, 0, 4), 2)
+		if len(window) != 2 {
+			t.Fatalf("len = %d, want 2", len(window))
+		}
+		if window[0].AdjClose != 4 {
+			t.Errorf("window[0].AdjClose = %v, want 4", window[0].AdjClose)
+		}
+	})
+
+	t.Run("excludes dates after asOf", func(t *testing.T) {
+		window := trailingWindow(prices, start.AddDate(0, 0, 1), 10)
+		if len(window) != 2 {
+			t.Fatalf("len = %d, want 2", len(window))
+		}
+	})
+}
+
+func TestCombineEquityCurves(t *testing.T) {
+	d1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d2 := d1.AddDate(0, 0, 1)
+	curveA := []domain.DailyPrice{{Date: d1, AdjClose: 100}, {Date: d2, AdjClose: 110}}
+	curveB := []domain.DailyPrice{{Date: d1, AdjClose: 200}, {Date: d2, AdjClose: 190}}
+
+	combined := combineEquityCurves([][]domain.DailyPrice{curveA, curveB})
+
+	if len(combined) != 2 {
+		t.Fatalf("len = %d, want 2", len(combined))
+	}
+	if combined[0].AdjClose != 300 {
+		t.Errorf("combined[0].AdjClose = %v, want 300", combined[0].AdjClose)
+	}
+	if combined[1].AdjClose != 300 {
+		t.Errorf("combined[1].AdjClose = %v, want 300", combined[1].AdjClose)
+	}
+}
@@ -0,0 +1,38 @@
+package backtest
+
+import (
+	"time"
+
+	"github.com/jusikbot/collector/internal/summary"
+)
+
+// MATrendStrategy enters a symbol once its close trades above its 200D MA
+// (MADivergence200D positive) and exits once it trades deep enough into its
+// 52-week range that further upside is presumed limited (FiftyTwoWeekPos
+// above MaxFiftyTwoWeekPos) — a simpler long-only trend rule than
+// BreakoutStrategy's golden-cross/relative-strength gating.
+type MATrendStrategy struct {
+	// MaxFiftyTwoWeekPos is the FiftyTwoWeekPos (0-1) above which an open
+	// position is exited, e.g. 0.95 to take profit within the top 5% of the
+	// 52-week range.
+	MaxFiftyTwoWeekPos float64
+}
+
+// NewMATrendStrategy returns an MATrendStrategy exiting at maxFiftyTwoWeekPos.
+func NewMATrendStrategy(maxFiftyTwoWeekPos float64) *MATrendStrategy {
+	return &MATrendStrategy{MaxFiftyTwoWeekPos: maxFiftyTwoWeekPos}
+}
+
+func (s *MATrendStrategy) OnBar(day time.Time, ind summary.SymbolIndicators, holding bool) Action {
+	if holding {
+		if ind.FiftyTwoWeekPos != nil && *ind.FiftyTwoWeekPos > s.MaxFiftyTwoWeekPos {
+			return ActionSell
+		}
+		return ActionHold
+	}
+
+	if ind.MADivergence200D == nil || *ind.MADivergence200D <= 0 {
+		return ActionHold
+	}
+	return ActionBuy
+}
@@ -0,0 +1,55 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/summary"
+)
+
+func TestMATrendStrategy_OnBar(t *testing.T) {
+	day := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	s := NewMATrendStrategy(0.95)
+
+	t.Run("enters once close trades above its 200D MA", func(t *testing.T) {
+		ind := summary.SymbolIndicators{MADivergence200D: ptr(2.5)}
+		if got := s.OnBar(day, ind, false); got != ActionBuy {
+			t.Errorf("OnBar() = %q, want %q", got, ActionBuy)
+		}
+	})
+
+	t.Run("holds while still below its 200D MA", func(t *testing.T) {
+		ind := summary.SymbolIndicators{MADivergence200D: ptr(-1.0)}
+		if got := s.OnBar(day, ind, false); got != ActionHold {
+			t.Errorf("OnBar() = %q, want %q", got, ActionHold)
+		}
+	})
+
+	t.Run("holds when not already holding and MADivergence200D is nil", func(t *testing.T) {
+		ind := summary.SymbolIndicators{}
+		if got := s.OnBar(day, ind, false); got != ActionHold {
+			t.Errorf("OnBar() = %q, want %q", got, ActionHold)
+		}
+	})
+
+	t.Run("exits an open position once deep in the 52-week range", func(t *testing.T) {
+		ind := summary.SymbolIndicators{FiftyTwoWeekPos: ptr(0.97)}
+		if got := s.OnBar(day, ind, true); got != ActionSell {
+			t.Errorf("OnBar() = %q, want %q", got, ActionSell)
+		}
+	})
+
+	t.Run("keeps holding below the exit threshold", func(t *testing.T) {
+		ind := summary.SymbolIndicators{FiftyTwoWeekPos: ptr(0.7)}
+		if got := s.OnBar(day, ind, true); got != ActionHold {
+			t.Errorf("OnBar() = %q, want %q", got, ActionHold)
+		}
+	})
+
+	t.Run("keeps holding when FiftyTwoWeekPos is nil", func(t *testing.T) {
+		ind := summary.SymbolIndicators{}
+		if got := s.OnBar(day, ind, true); got != ActionHold {
+			t.Errorf("OnBar() = %q, want %q", got, ActionHold)
+		}
+	})
+}
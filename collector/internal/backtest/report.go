@@ -0,0 +1,76 @@
+package backtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/stats"
+)
+
+// Report is the full result of one Engine.Run: equity-curve-level
+// performance stats (from internal/stats, computed over the simulated
+// portfolio's daily value) plus the trade-level stats internal/stats has no
+// notion of. WinRate is a percentage expressed as e.g. 23.5, not 0.235;
+// AverageWin, AverageLoss and Expectancy are per-trade amounts in the same
+// currency units as Trade.PnL.
+type Report struct {
+	Performance stats.PerformanceReport
+
+	AverageLoss   float64
+	AverageWin    float64
+	Expectancy    float64
+	ProfitFactor  float64
+	TotalTrades   int
+	Trades        []Trade
+	WinningTrades int
+	WinRate       float64
+}
+
+// buildReport computes Performance from curve via stats.ComputeReport, and
+// the trade-level stats from trades.
+func buildReport(curve []domain.DailyPrice, trades []Trade, riskFreeAnnual float64) Report {
+	report := Report{
+		Performance: stats.ComputeReport(curve, riskFreeAnnual),
+		TotalTrades: len(trades),
+		Trades:      trades,
+	}
+
+	var grossWin, grossLoss float64
+	for _, t := range trades {
+		switch {
+		case t.PnL > 0:
+			report.WinningTrades++
+			grossWin += t.PnL
+		case t.PnL < 0:
+			grossLoss += -t.PnL
+		}
+	}
+
+	if len(trades) > 0 {
+		report.WinRate = float64(report.WinningTrades) / float64(len(trades)) * 100
+	}
+	if report.WinningTrades > 0 {
+		report.AverageWin = grossWin / float64(report.WinningTrades)
+	}
+	if losingTrades := len(trades) - report.WinningTrades; losingTrades > 0 {
+		report.AverageLoss = -grossLoss / float64(losingTrades)
+	}
+	if grossLoss != 0 {
+		report.ProfitFactor = grossWin / grossLoss
+	}
+	report.Expectancy = report.WinRate/100*report.AverageWin + (1-report.WinRate/100)*report.AverageLoss
+
+	return report
+}
+
+// WriteJSON marshals report as indented JSON to w.
+func WriteJSON(w io.Writer, report Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("encode backtest report json: %w", err)
+	}
+	return nil
+}
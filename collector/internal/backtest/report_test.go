@@ -0,0 +1,72 @@
+package backtest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func TestBuildReport_TradeStats(t *testing.T) {
+	trades := []Trade{
+		{Symbol: "A", PnL: 100},
+		{Symbol: "A", PnL: -50},
+		{Symbol: "A", PnL: 200},
+		{Symbol: "A", PnL: -50},
+	}
+	curve := dailyPrices([]float64{1000, 1010, 1005, 1025, 1020}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	report := buildReport(curve, trades, 0)
+
+	if report.TotalTrades != 4 {
+		t.Errorf("TotalTrades = %d, want 4", report.TotalTrades)
+	}
+	if report.WinningTrades != 2 {
+		t.Errorf("WinningTrades = %d, want 2", report.WinningTrades)
+	}
+	if report.WinRate != 50 {
+		t.Errorf("WinRate = %v, want 50", report.WinRate)
+	}
+	if report.AverageWin != 150 {
+		t.Errorf("AverageWin = %v, want 150", report.AverageWin)
+	}
+	if report.AverageLoss != -50 {
+		t.Errorf("AverageLoss = %v, want -50", report.AverageLoss)
+	}
+	if report.ProfitFactor != 3 {
+		t.Errorf("ProfitFactor = %v, want 3", report.ProfitFactor)
+	}
+	wantExpectancy := 0.5*150 + 0.5*-50
+	if report.Expectancy != wantExpectancy {
+		t.Errorf("Expectancy = %v, want %v", report.Expectancy, wantExpectancy)
+	}
+}
+
+func TestBuildReport_NoTrades(t *testing.T) {
+	curve := dailyPrices([]float64{1000, 1000}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	report := buildReport(curve, nil, 0)
+
+	if report.TotalTrades != 0 {
+		t.Errorf("TotalTrades = %d, want 0", report.TotalTrades)
+	}
+	if report.WinRate != 0 {
+		t.Errorf("WinRate = %v, want 0", report.WinRate)
+	}
+	if report.ProfitFactor != 0 {
+		t.Errorf("ProfitFactor = %v, want 0", report.ProfitFactor)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	report := buildReport([]domain.DailyPrice{}, nil, 0)
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\"WinRate\"") {
+		t.Errorf("output = %q, want it to contain WinRate field", buf.String())
+	}
+}
@@ -0,0 +1,30 @@
+// Package backtest replays summary.ComputeSymbolIndicators day-by-day against
+// a Strategy and simulates the resulting trades, producing a Report that
+// reuses internal/stats for equity-curve-level statistics (CAGR, Sharpe,
+// Sortino, MaxDrawdown) and adds its own trade-level statistics (win rate,
+// profit factor, expectancy) that internal/stats has no notion of.
+package backtest
+
+import (
+	"time"
+
+	"github.com/jusikbot/collector/internal/summary"
+)
+
+// Action is the trading decision a Strategy returns for one symbol on one
+// simulated bar.
+type Action string
+
+const (
+	ActionBuy  Action = "buy"
+	ActionSell Action = "sell"
+	ActionHold Action = "hold"
+)
+
+// Strategy decides whether to buy, sell, or hold a symbol given its
+// indicators as of day. holding reports whether Engine currently has an open
+// position in the symbol, so a Strategy can express entry/exit rules without
+// tracking its own per-symbol state.
+type Strategy interface {
+	OnBar(day time.Time, ind summary.SymbolIndicators, holding bool) Action
+}
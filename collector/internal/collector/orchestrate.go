@@ -5,13 +5,20 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/jusikbot/collector/internal/alpaca"
+	"github.com/jusikbot/collector/internal/httpclient"
+	"github.com/jusikbot/collector/internal/tiingo"
+	"github.com/jusikbot/collector/internal/yahoo"
 	"golang.org/x/sync/errgroup"
 )
 
-// SourceFunc collects data from a single source. Returns nil on success.
-type SourceFunc func(ctx context.Context) error
+// SourceFunc collects data from a single source, returning the rows/retries
+// it touched alongside a nil error on success.
+type SourceFunc func(ctx context.Context) (SourceStats, error)
 
 // Source pairs a name with its collection function.
 type Source struct {
@@ -19,11 +26,28 @@ type Source struct {
 	Name    string
 }
 
+// SourceStats measures one source's contribution to a collection run, so an
+// operator reading the run's report can tell "fetched 500 rows, inserted 10"
+// (healthy, mostly already up to date) apart from "fetched 0 rows" (the
+// upstream API returned nothing). RetriesAttempted and HTTPCallCount are
+// zero-valued by sources that don't thread ratelimit/httpclient counters
+// through yet; a source should only populate them once it actually tracks
+// that data (see cmd/collect/source.go), rather than faking a count.
+type SourceStats struct {
+	AnomaliesFlagged int64
+	HTTPCallCount    int64
+	RetriesAttempted int64
+	RowsFetched      int64
+	RowsInserted     int64
+	RowsSkipped      int64
+}
+
 // SourceResult captures the outcome of a single source collection.
 type SourceResult struct {
 	Elapsed time.Duration
 	Error   error
 	Source  string
+	Stats   SourceStats
 }
 
 func (r SourceResult) IsOK() bool {
@@ -42,8 +66,9 @@ func CollectAll(ctx context.Context, sources []Source) []SourceResult {
 		results[i].Source = src.Name
 		g.Go(func() error {
 			started := time.Now()
-			err := src.Collect(ctx)
+			stats, err := src.Collect(ctx)
 			results[i].Elapsed = time.Since(started)
+			results[i].Stats = stats
 			results[i].Error = err
 			return nil // Always nil: errors captured in results, not errgroup
 		})
@@ -64,9 +89,9 @@ func ReportResults(results []SourceResult, totalElapsed time.Duration) {
 
 	for _, r := range results {
 		if r.IsOK() {
-			slog.Info(formatSourceSummary(r.Source, true, r.Elapsed))
+			slog.Info(formatSourceSummary(r.Source, true, r.Elapsed), "rows_fetched", r.Stats.RowsFetched, "rows_inserted", r.Stats.RowsInserted)
 		} else {
-			slog.Error(formatSourceSummary(r.Source, false, r.Elapsed), "error", r.Error)
+			slog.Error(formatSourceSummary(r.Source, false, r.Elapsed), "error", r.Error, "rows_fetched", r.Stats.RowsFetched, "rows_inserted", r.Stats.RowsInserted)
 		}
 	}
 
@@ -76,16 +101,157 @@ func ReportResults(results []SourceResult, totalElapsed time.Duration) {
 	)
 }
 
-// AggregateErrors joins all source errors into a single error.
+// Kind coarsely classifies a failed source's error for programmatic
+// handling: choosing a process exit code, or deciding whether a supervisor
+// can usefully retry just the failed source rather than the whole batch.
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	// KindTransient covers a retryable upstream failure (5xx, timeout) that
+	// is likely to succeed on its own if retried later.
+	KindTransient
+	// KindAuth covers an expired or invalid credential (401/403) that won't
+	// resolve without operator intervention.
+	KindAuth
+	// KindNotFound covers a symbol the source doesn't recognize (e.g. a
+	// delisted ticker), which retrying won't fix.
+	KindNotFound
+	KindRateLimited
+	// KindFatal covers everything else: an unrecognized 4xx, a decode
+	// failure, or the run being canceled - none of which a supervisor
+	// should blindly retry.
+	KindFatal
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindTransient:
+		return "transient"
+	case KindAuth:
+		return "auth"
+	case KindNotFound:
+		return "not_found"
+	case KindRateLimited:
+		return "rate_limited"
+	case KindFatal:
+		return "fatal"
+	default:
+		return "unknown"
+	}
+}
+
+// SourceFailure pairs a failed source's name and error with its classified Kind.
+type SourceFailure struct {
+	Err    error
+	Kind   Kind
+	Source string
+}
+
+// CollectError is the structured form of a collection run's failures: the
+// per-source errors AggregateErrors used to flatten into one joined message,
+// now retained individually with a Kind classification so a caller can
+// choose an exit code or retry target via HasKind/FailedSources instead of
+// substring-matching Error().
+type CollectError struct {
+	Failures []SourceFailure
+}
+
+func (e *CollectError) Error() string {
+	msgs := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		msgs[i] = fmt.Sprintf("%s: %s", f.Source, f.Err)
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap exposes the per-source errors for errors.Is/As (Go 1.20+ multi-error).
+func (e *CollectError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = fmt.Errorf("%s: %w", f.Source, f.Err)
+	}
+	return errs
+}
+
+// HasKind reports whether err is (or wraps) a *CollectError with at least
+// one failure classified as kind.
+func HasKind(err error, kind Kind) bool {
+	var ce *CollectError
+	if !errors.As(err, &ce) {
+		return false
+	}
+	for _, f := range ce.Failures {
+		if f.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// FailedSources returns the names of every source recorded in err, in
+// result order, or nil if err is not a *CollectError.
+func FailedSources(err error) []string {
+	var ce *CollectError
+	if !errors.As(err, &ce) {
+		return nil
+	}
+	names := make([]string, len(ce.Failures))
+	for i, f := range ce.Failures {
+		names[i] = f.Source
+	}
+	return names
+}
+
+// classifyKind derives a Kind from a source error by checking it against the
+// sentinel/structured errors the source packages (httpclient, tiingo,
+// alpaca, yahoo) actually return. Anything unrecognized is KindFatal rather
+// than KindUnknown, so a caller that only checks for specific kinds still
+// treats an unclassified failure conservatively (no blind retry).
+func classifyKind(err error) Kind {
+	if errors.Is(err, context.Canceled) {
+		return KindFatal
+	}
+	if errors.Is(err, httpclient.ErrRateLimited) {
+		return KindRateLimited
+	}
+	if errors.Is(err, tiingo.ErrTickerInvalid) || errors.Is(err, alpaca.ErrTickerInvalid) || errors.Is(err, yahoo.ErrTickerInvalid) {
+		return KindNotFound
+	}
+
+	var apiErr *httpclient.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return KindAuth
+		case http.StatusNotFound:
+			return KindNotFound
+		}
+		if apiErr.IsRetryable {
+			return KindTransient
+		}
+		return KindFatal
+	}
+
+	if errors.Is(err, httpclient.ErrTimeout) {
+		return KindTransient
+	}
+	return KindFatal
+}
+
+// AggregateErrors collects every source's error into a *CollectError.
 // Returns nil when all sources succeeded.
 func AggregateErrors(results []SourceResult) error {
-	var errs []error
+	var failures []SourceFailure
 	for _, r := range results {
 		if r.Error != nil {
-			errs = append(errs, fmt.Errorf("%s: %w", r.Source, r.Error))
+			failures = append(failures, SourceFailure{Err: r.Error, Kind: classifyKind(r.Error), Source: r.Source})
 		}
 	}
-	return errors.Join(errs...)
+	if len(failures) == 0 {
+		return nil
+	}
+	return &CollectError{Failures: failures}
 }
 
 func formatSourceSummary(source string, isOK bool, elapsed time.Duration) string {
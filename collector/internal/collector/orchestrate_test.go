@@ -3,18 +3,22 @@ package collector_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/jusikbot/collector/internal/alpaca"
 	"github.com/jusikbot/collector/internal/collector"
+	"github.com/jusikbot/collector/internal/httpclient"
 )
 
 func TestCollectAll_AllSuccess(t *testing.T) {
 	sources := []collector.Source{
-		{Name: "source-a", Collect: func(_ context.Context) error { return nil }},
-		{Name: "source-b", Collect: func(_ context.Context) error { return nil }},
-		{Name: "source-c", Collect: func(_ context.Context) error { return nil }},
+		{Name: "source-a", Collect: func(_ context.Context) (collector.SourceStats, error) { return collector.SourceStats{}, nil }},
+		{Name: "source-b", Collect: func(_ context.Context) (collector.SourceStats, error) { return collector.SourceStats{}, nil }},
+		{Name: "source-c", Collect: func(_ context.Context) (collector.SourceStats, error) { return collector.SourceStats{}, nil }},
 	}
 
 	results := collector.CollectAll(context.Background(), sources)
@@ -35,9 +39,9 @@ func TestCollectAll_AllSuccess(t *testing.T) {
 func TestCollectAll_PartialFailure(t *testing.T) {
 	tokenErr := errors.New("token expired")
 	sources := []collector.Source{
-		{Name: "tiingo", Collect: func(_ context.Context) error { return nil }},
-		{Name: "kis", Collect: func(_ context.Context) error { return tokenErr }},
-		{Name: "fx", Collect: func(_ context.Context) error { return nil }},
+		{Name: "tiingo", Collect: func(_ context.Context) (collector.SourceStats, error) { return collector.SourceStats{}, nil }},
+		{Name: "kis", Collect: func(_ context.Context) (collector.SourceStats, error) { return collector.SourceStats{}, tokenErr }},
+		{Name: "fx", Collect: func(_ context.Context) (collector.SourceStats, error) { return collector.SourceStats{}, nil }},
 	}
 
 	results := collector.CollectAll(context.Background(), sources)
@@ -63,9 +67,18 @@ func TestCollectAll_PartialFailure(t *testing.T) {
 func TestCollectAll_ParallelExecution(t *testing.T) {
 	delay := 100 * time.Millisecond
 	sources := []collector.Source{
-		{Name: "a", Collect: func(_ context.Context) error { time.Sleep(delay); return nil }},
-		{Name: "b", Collect: func(_ context.Context) error { time.Sleep(delay); return nil }},
-		{Name: "c", Collect: func(_ context.Context) error { time.Sleep(delay); return nil }},
+		{Name: "a", Collect: func(_ context.Context) (collector.SourceStats, error) {
+			time.Sleep(delay)
+			return collector.SourceStats{}, nil
+		}},
+		{Name: "b", Collect: func(_ context.Context) (collector.SourceStats, error) {
+			time.Sleep(delay)
+			return collector.SourceStats{}, nil
+		}},
+		{Name: "c", Collect: func(_ context.Context) (collector.SourceStats, error) {
+			time.Sleep(delay)
+			return collector.SourceStats{}, nil
+		}},
 	}
 
 	started := time.Now()
@@ -89,8 +102,8 @@ func TestCollectAll_ContextCancelled(t *testing.T) {
 	cancel()
 
 	sources := []collector.Source{
-		{Name: "a", Collect: func(ctx context.Context) error { return ctx.Err() }},
-		{Name: "b", Collect: func(ctx context.Context) error { return ctx.Err() }},
+		{Name: "a", Collect: func(ctx context.Context) (collector.SourceStats, error) { return collector.SourceStats{}, ctx.Err() }},
+		{Name: "b", Collect: func(ctx context.Context) (collector.SourceStats, error) { return collector.SourceStats{}, ctx.Err() }},
 	}
 
 	results := collector.CollectAll(ctx, sources)
@@ -116,7 +129,7 @@ func TestCollectAll_PreservesSourceOrder(t *testing.T) {
 	for i, n := range names {
 		sources[i] = collector.Source{
 			Name:    n,
-			Collect: func(_ context.Context) error { return nil },
+			Collect: func(_ context.Context) (collector.SourceStats, error) { return collector.SourceStats{}, nil },
 		}
 	}
 
@@ -129,6 +142,24 @@ func TestCollectAll_PreservesSourceOrder(t *testing.T) {
 	}
 }
 
+func TestCollectAll_PropagatesStats(t *testing.T) {
+	sources := []collector.Source{
+		{Name: "tiingo", Collect: func(_ context.Context) (collector.SourceStats, error) {
+			return collector.SourceStats{RowsFetched: 100, RowsInserted: 90, RowsSkipped: 10, AnomaliesFlagged: 2}, nil
+		}},
+	}
+
+	results := collector.CollectAll(context.Background(), sources)
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	stats := results[0].Stats
+	if stats.RowsFetched != 100 || stats.RowsInserted != 90 || stats.RowsSkipped != 10 || stats.AnomaliesFlagged != 2 {
+		t.Errorf("stats = %+v, want rows fetched=100 inserted=90 skipped=10 anomalies=2", stats)
+	}
+}
+
 func TestAggregateErrors_NoErrors(t *testing.T) {
 	results := []collector.SourceResult{
 		{Source: "a"},
@@ -166,3 +197,75 @@ func TestAggregateErrors_Empty(t *testing.T) {
 		t.Errorf("expected nil for nil input, got %v", err)
 	}
 }
+
+func TestAggregateErrors_ClassifiesKinds(t *testing.T) {
+	results := []collector.SourceResult{
+		{Source: "tiingo", Error: fmt.Errorf("rate limited: %w", httpclient.ErrRateLimited)},
+		{Source: "kis", Error: &httpclient.APIError{StatusCode: 401, URL: "/token"}},
+		{Source: "alpaca", Error: fmt.Errorf("symbol XYZ: %w", alpaca.ErrTickerInvalid)},
+		{Source: "fx", Error: &httpclient.APIError{IsRetryable: true, StatusCode: 503, URL: "/rates"}},
+		{Source: "retention", Error: errors.New("disk full")},
+	}
+
+	err := collector.AggregateErrors(results)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	var ce *collector.CollectError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected *collector.CollectError, got %T", err)
+	}
+	if len(ce.Failures) != 5 {
+		t.Fatalf("len(Failures) = %d, want 5", len(ce.Failures))
+	}
+
+	wantKinds := map[string]collector.Kind{
+		"tiingo":    collector.KindRateLimited,
+		"kis":       collector.KindAuth,
+		"alpaca":    collector.KindNotFound,
+		"fx":        collector.KindTransient,
+		"retention": collector.KindFatal,
+	}
+	for _, f := range ce.Failures {
+		if got, want := f.Kind, wantKinds[f.Source]; got != want {
+			t.Errorf("%s: Kind = %v, want %v", f.Source, got, want)
+		}
+	}
+
+	for kind, wantHas := range map[collector.Kind]bool{
+		collector.KindRateLimited: true,
+		collector.KindAuth:        true,
+		collector.KindNotFound:    true,
+		collector.KindTransient:   true,
+		collector.KindFatal:       true,
+		collector.KindUnknown:     false,
+	} {
+		if got := collector.HasKind(err, kind); got != wantHas {
+			t.Errorf("HasKind(%v) = %v, want %v", kind, got, wantHas)
+		}
+	}
+
+	wantSources := []string{"tiingo", "kis", "alpaca", "fx", "retention"}
+	if got := collector.FailedSources(err); !reflect.DeepEqual(got, wantSources) {
+		t.Errorf("FailedSources = %v, want %v", got, wantSources)
+	}
+}
+
+func TestAggregateErrors_UnwrapMultiError(t *testing.T) {
+	sentinel := errors.New("token expired")
+	results := []collector.SourceResult{
+		{Source: "kis", Error: sentinel},
+	}
+
+	err := collector.AggregateErrors(results)
+	if !errors.Is(err, sentinel) {
+		t.Error("expected errors.Is to find the wrapped sentinel via Unwrap() []error")
+	}
+}
+
+func TestFailedSources_NonCollectError(t *testing.T) {
+	if got := collector.FailedSources(errors.New("plain error")); got != nil {
+		t.Errorf("FailedSources = %v, want nil", got)
+	}
+}
@@ -0,0 +1,179 @@
+package collector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jusikbot/collector/internal/config"
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/store"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SourceConfig is the run-level configuration available to a source factory:
+// the loaded environment and a watchlist. Registry.Build narrows Watchlist to
+// whatever SourceDef.Filter selects before calling New, so a factory never
+// has to re-derive its own market/source slice.
+type SourceConfig struct {
+	Env       config.Env
+	Watchlist []domain.WatchlistEntry
+}
+
+// SourceDeps holds the shared infrastructure a source factory may need: the
+// DB pool, the repository built on top of it, the optional TSDB fan-out
+// sink, and the optional metrics registry (nil disables httpclient
+// instrumentation, matching a source not opting into WithMetrics).
+type SourceDeps struct {
+	Pool     *pgxpool.Pool
+	Registry prometheus.Registerer
+	Repo     *store.Repository
+	TSDB     store.TSDBWriter
+}
+
+// SourceDef registers a source with a Registry. Adding another provider
+// (e.g. a Korean FX source or a crypto feed) means writing one SourceDef and
+// calling Register from an init(), rather than editing CollectAll's call
+// site or a switch statement.
+type SourceDef struct {
+	// Filter narrows a run's watchlist to the entries this source collects
+	// for, e.g. KR-market symbols for kis. Nil means the source collects the
+	// full watchlist unnarrowed (e.g. margin) — it does NOT mean the source is
+	// unscoped by watchlist at all; see NotWatchlistScoped for that.
+	Filter func(watchlist []domain.WatchlistEntry) []domain.WatchlistEntry
+
+	// MissingEnv returns the required environment variable names that are
+	// unset on env, in declaration order. Nil, or a func returning an empty
+	// result, means the source has everything it needs to run.
+	MissingEnv func(env config.Env) []string
+
+	Name string
+	New  func(cfg SourceConfig, deps SourceDeps) (Source, error)
+
+	// NotWatchlistScoped marks a source that doesn't consume a run's
+	// watchlist at all (e.g. fx, retention, instruments), as opposed to a nil
+	// Filter, which just means "uses the full watchlist, no narrowing".
+	NotWatchlistScoped bool
+
+	// RequiredEnv documents the variable names MissingEnv checks, for
+	// Describe's human-readable output.
+	RequiredEnv []string
+}
+
+// SkippedSource records why a registered source was not built for a run,
+// replacing an ad hoc slog.Info buried inside the source's own Collect func.
+type SkippedSource struct {
+	Name   string
+	Reason string
+}
+
+// SourceDescription is the read-only view of a SourceDef returned by Describe.
+type SourceDescription struct {
+	Name        string
+	RequiredEnv []string
+	Watchlisted bool
+}
+
+// Registry holds every SourceDef registered via Register, in registration order.
+type Registry struct {
+	defs []SourceDef
+}
+
+// defaultRegistry is populated by each source's init(), mirroring
+// database/sql's driver registry.
+var defaultRegistry = &Registry{}
+
+// Register adds def to the default registry. Intended to be called from an
+// init() function; panics on a duplicate name, since that can only mean two
+// sources were registered under the same name at build time.
+func Register(def SourceDef) {
+	defaultRegistry.Register(def)
+}
+
+// Build resolves names against the default registry. See Registry.Build.
+func Build(names []string, cfg SourceConfig, deps SourceDeps) ([]Source, []SkippedSource, error) {
+	return defaultRegistry.Build(names, cfg, deps)
+}
+
+// Describe returns every source registered with the default registry. See
+// Registry.Describe.
+func Describe() []SourceDescription {
+	return defaultRegistry.Describe()
+}
+
+func (r *Registry) Register(def SourceDef) {
+	for _, existing := range r.defs {
+		if existing.Name == def.Name {
+			panic(fmt.Sprintf("collector: source %q already registered", def.Name))
+		}
+	}
+	r.defs = append(r.defs, def)
+}
+
+// Build resolves names, in order, against r. A name not found in r is
+// reported as a SkippedSource ("unknown source") rather than an error. A
+// source whose MissingEnv reports unset variables, or whose Filter narrows
+// cfg.Watchlist to nothing, is likewise skipped with a reason instead of
+// being built and left to silently no-op.
+func (r *Registry) Build(names []string, cfg SourceConfig, deps SourceDeps) ([]Source, []SkippedSource, error) {
+	var sources []Source
+	var skipped []SkippedSource
+
+	for _, name := range names {
+		def, ok := r.find(name)
+		if !ok {
+			skipped = append(skipped, SkippedSource{Name: name, Reason: "unknown source"})
+			continue
+		}
+
+		if def.MissingEnv != nil {
+			if missing := def.MissingEnv(cfg.Env); len(missing) > 0 {
+				skipped = append(skipped, SkippedSource{
+					Name:   name,
+					Reason: fmt.Sprintf("missing required env: %s", strings.Join(missing, ", ")),
+				})
+				continue
+			}
+		}
+
+		sourceCfg := cfg
+		if def.Filter != nil {
+			sourceCfg.Watchlist = def.Filter(cfg.Watchlist)
+			if len(sourceCfg.Watchlist) == 0 {
+				skipped = append(skipped, SkippedSource{Name: name, Reason: "no matching watchlist entries"})
+				continue
+			}
+		}
+
+		src, err := def.New(sourceCfg, deps)
+		if err != nil {
+			return sources, skipped, fmt.Errorf("build source %s: %w", name, err)
+		}
+		sources = append(sources, src)
+	}
+
+	return sources, skipped, nil
+}
+
+func (r *Registry) find(name string) (SourceDef, bool) {
+	for _, d := range r.defs {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return SourceDef{}, false
+}
+
+// Describe returns every registered source's name, required env vars, and
+// whether it's scoped by watchlist, in registration order.
+func (r *Registry) Describe() []SourceDescription {
+	out := make([]SourceDescription, len(r.defs))
+	for i, d := range r.defs {
+		out[i] = SourceDescription{
+			Name:        d.Name,
+			RequiredEnv: d.RequiredEnv,
+			Watchlisted: !d.NotWatchlistScoped,
+		}
+	}
+	return out
+}
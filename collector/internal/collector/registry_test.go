@@ -0,0 +1,176 @@
+package collector_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jusikbot/collector/internal/collector"
+	"github.com/jusikbot/collector/internal/config"
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func newTestRegistry() *collector.Registry {
+	r := &collector.Registry{}
+
+	r.Register(collector.SourceDef{
+		MissingEnv: func(env config.Env) []string {
+			if env.TiingoAPIKey == "" {
+				return []string{"TIINGO_API_KEY"}
+			}
+			return nil
+		},
+		Name: "tiingo",
+		New: func(cfg collector.SourceConfig, deps collector.SourceDeps) (collector.Source, error) {
+			return collector.Source{Name: "tiingo", Collect: func(context.Context) (collector.SourceStats, error) { return collector.SourceStats{}, nil }}, nil
+		},
+		RequiredEnv: []string{"TIINGO_API_KEY"},
+	})
+
+	r.Register(collector.SourceDef{
+		Filter: func(wl []domain.WatchlistEntry) []domain.WatchlistEntry {
+			var kr []domain.WatchlistEntry
+			for _, e := range wl {
+				if e.Market == domain.MarketKR {
+					kr = append(kr, e)
+				}
+			}
+			return kr
+		},
+		Name: "kis",
+		New: func(cfg collector.SourceConfig, deps collector.SourceDeps) (collector.Source, error) {
+			return collector.Source{Name: "kis", Collect: func(context.Context) (collector.SourceStats, error) { return collector.SourceStats{}, nil }}, nil
+		},
+	})
+
+	r.Register(collector.SourceDef{
+		Name: "fx",
+		New: func(cfg collector.SourceConfig, deps collector.SourceDeps) (collector.Source, error) {
+			return collector.Source{Name: "fx", Collect: func(context.Context) (collector.SourceStats, error) { return collector.SourceStats{}, nil }}, nil
+		},
+		NotWatchlistScoped: true,
+	})
+
+	return r
+}
+
+func TestRegistry_Build(t *testing.T) {
+	r := newTestRegistry()
+	watchlist := []domain.WatchlistEntry{
+		{Market: domain.MarketKR, Symbol: "005930"},
+		{Market: domain.MarketUS, Symbol: "AAPL"},
+	}
+
+	t.Run("builds a ready source", func(t *testing.T) {
+		sources, skipped, err := r.Build([]string{"fx"}, collector.SourceConfig{Watchlist: watchlist}, collector.SourceDeps{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(skipped) != 0 {
+			t.Errorf("skipped = %v, want none", skipped)
+		}
+		if len(sources) != 1 || sources[0].Name != "fx" {
+			t.Fatalf("sources = %v, want one fx source", sources)
+		}
+	})
+
+	t.Run("skips a source with missing required env", func(t *testing.T) {
+		sources, skipped, err := r.Build([]string{"tiingo"}, collector.SourceConfig{Env: config.Env{}, Watchlist: watchlist}, collector.SourceDeps{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(sources) != 0 {
+			t.Errorf("sources = %v, want none", sources)
+		}
+		if len(skipped) != 1 || skipped[0].Name != "tiingo" || skipped[0].Reason != "missing required env: TIINGO_API_KEY" {
+			t.Fatalf("skipped = %+v, want one tiingo skip citing TIINGO_API_KEY", skipped)
+		}
+	})
+
+	t.Run("builds a source once its required env is present", func(t *testing.T) {
+		env := config.Env{TiingoAPIKey: "key"}
+		sources, skipped, err := r.Build([]string{"tiingo"}, collector.SourceConfig{Env: env, Watchlist: watchlist}, collector.SourceDeps{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(skipped) != 0 {
+			t.Errorf("skipped = %v, want none", skipped)
+		}
+		if len(sources) != 1 {
+			t.Fatalf("sources = %v, want one", sources)
+		}
+	})
+
+	t.Run("skips a source whose filter narrows the watchlist to nothing", func(t *testing.T) {
+		usOnly := []domain.WatchlistEntry{{Market: domain.MarketUS, Symbol: "AAPL"}}
+		sources, skipped, err := r.Build([]string{"kis"}, collector.SourceConfig{Watchlist: usOnly}, collector.SourceDeps{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(sources) != 0 {
+			t.Errorf("sources = %v, want none", sources)
+		}
+		if len(skipped) != 1 || skipped[0].Name != "kis" || skipped[0].Reason != "no matching watchlist entries" {
+			t.Fatalf("skipped = %+v, want one kis skip citing no matching watchlist entries", skipped)
+		}
+	})
+
+	t.Run("reports an unknown source name instead of erroring", func(t *testing.T) {
+		sources, skipped, err := r.Build([]string{"crypto"}, collector.SourceConfig{Watchlist: watchlist}, collector.SourceDeps{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(sources) != 0 {
+			t.Errorf("sources = %v, want none", sources)
+		}
+		if len(skipped) != 1 || skipped[0].Name != "crypto" || skipped[0].Reason != "unknown source" {
+			t.Fatalf("skipped = %+v, want one crypto skip citing unknown source", skipped)
+		}
+	})
+
+	t.Run("preserves requested order across multiple sources", func(t *testing.T) {
+		sources, _, err := r.Build([]string{"fx", "kis"}, collector.SourceConfig{Watchlist: watchlist}, collector.SourceDeps{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(sources) != 2 || sources[0].Name != "fx" || sources[1].Name != "kis" {
+			t.Fatalf("sources = %v, want [fx kis] in order", sources)
+		}
+	})
+}
+
+func TestRegistry_Describe(t *testing.T) {
+	r := newTestRegistry()
+	descriptions := r.Describe()
+
+	if len(descriptions) != 3 {
+		t.Fatalf("len(descriptions) = %d, want 3", len(descriptions))
+	}
+
+	want := map[string]bool{"tiingo": true, "kis": true, "fx": false}
+	for _, d := range descriptions {
+		if d.Watchlisted != want[d.Name] {
+			t.Errorf("%s.Watchlisted = %v, want %v", d.Name, d.Watchlisted, want[d.Name])
+		}
+	}
+	if descriptions[0].Name != "tiingo" || len(descriptions[0].RequiredEnv) != 1 {
+		t.Errorf("descriptions[0] = %+v, want tiingo with 1 required env var", descriptions[0])
+	}
+}
+
+func TestRegistry_RegisterDuplicateNamePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Register to panic on a duplicate name")
+		}
+	}()
+
+	r := &collector.Registry{}
+	def := collector.SourceDef{
+		Name: "dup",
+		New: func(collector.SourceConfig, collector.SourceDeps) (collector.Source, error) {
+			return collector.Source{}, nil
+		},
+	}
+	r.Register(def)
+	r.Register(def)
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+type backtestFileConfig struct {
+	FeeBps         float64  `json:"fee_bps" yaml:"fee_bps"`
+	From           string   `json:"from" yaml:"from"`
+	InitialCapital float64  `json:"initial_capital" yaml:"initial_capital"`
+	MinSamples     int      `json:"min_samples" yaml:"min_samples"`
+	RiskFreeAnnual float64  `json:"risk_free_annual" yaml:"risk_free_annual"`
+	SlippageBps    float64  `json:"slippage_bps" yaml:"slippage_bps"`
+	Symbols        []string `json:"symbols" yaml:"symbols"`
+	To             string   `json:"to" yaml:"to"`
+}
+
+// LoadBacktestRunConfig reads a backtest run config file at path, parsing it
+// as YAML when the extension is .yaml or .yml and JSON otherwise, mirroring
+// LoadWatchlist's dual-format handling.
+func LoadBacktestRunConfig(path string) (domain.BacktestRunConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return domain.BacktestRunConfig{}, fmt.Errorf("reading backtest config: %w", err)
+	}
+
+	var raw backtestFileConfig
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return domain.BacktestRunConfig{}, fmt.Errorf("parsing backtest config YAML: %w", err)
+		}
+	default:
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return domain.BacktestRunConfig{}, fmt.Errorf("parsing backtest config JSON: %w", err)
+		}
+	}
+
+	return toBacktestRunConfig(raw)
+}
+
+func toBacktestRunConfig(raw backtestFileConfig) (domain.BacktestRunConfig, error) {
+	if len(raw.Symbols) == 0 {
+		return domain.BacktestRunConfig{}, errors.New("symbols must not be empty")
+	}
+	if raw.InitialCapital <= 0 {
+		return domain.BacktestRunConfig{}, fmt.Errorf("initial_capital %v must be positive", raw.InitialCapital)
+	}
+
+	from, err := time.Parse("2006-01-02", raw.From)
+	if err != nil {
+		return domain.BacktestRunConfig{}, fmt.Errorf("from %q: %w", raw.From, err)
+	}
+	to, err := time.Parse("2006-01-02", raw.To)
+	if err != nil {
+		return domain.BacktestRunConfig{}, fmt.Errorf("to %q: %w", raw.To, err)
+	}
+	if !to.After(from) {
+		return domain.BacktestRunConfig{}, fmt.Errorf("to %q must be after from %q", raw.To, raw.From)
+	}
+
+	// Why default 20: matches summary.DefaultSummaryOptions' MinSamples.
+	minSamples := raw.MinSamples
+	if minSamples == 0 {
+		minSamples = 20
+	}
+
+	return domain.BacktestRunConfig{
+		FeeBps:         raw.FeeBps,
+		From:           from,
+		InitialCapital: raw.InitialCapital,
+		MinSamples:     minSamples,
+		RiskFreeAnnual: raw.RiskFreeAnnual,
+		SlippageBps:    raw.SlippageBps,
+		Symbols:        raw.Symbols,
+		To:             to,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"testing"
+)
+
+func writeTestBacktestConfig(t *testing.T, content string) string {
+	t.Helper()
+	return writeTestWatchlistFile(t, "backtest.json", content)
+}
+
+func TestLoadBacktestRunConfig_Valid(t *testing.T) {
+	path := writeTestBacktestConfig(t, `{
+		"symbols": ["NVDA", "QQQ"],
+		"from": "2023-01-01",
+		"to": "2024-01-01",
+		"initial_capital": 10000,
+		"fee_bps": 10,
+		"slippage_bps": 5,
+		"risk_free_annual": 0.04
+	}`)
+
+	cfg, err := LoadBacktestRunConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Symbols) != 2 {
+		t.Fatalf("len(Symbols) = %d, want 2", len(cfg.Symbols))
+	}
+	if cfg.InitialCapital != 10000 {
+		t.Errorf("InitialCapital = %v, want 10000", cfg.InitialCapital)
+	}
+	if cfg.MinSamples != 20 {
+		t.Errorf("MinSamples = %d, want default 20", cfg.MinSamples)
+	}
+	if cfg.From.After(cfg.To) {
+		t.Errorf("From %v must not be after To %v", cfg.From, cfg.To)
+	}
+}
+
+func TestLoadBacktestRunConfig_YAML(t *testing.T) {
+	path := writeTestWatchlistFile(t, "backtest.yaml", `
+symbols:
+  - NVDA
+from: "2023-01-01"
+to: "2024-01-01"
+initial_capital: 5000
+`)
+
+	cfg, err := LoadBacktestRunConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Symbols) != 1 || cfg.Symbols[0] != "NVDA" {
+		t.Errorf("Symbols = %v, want [NVDA]", cfg.Symbols)
+	}
+}
+
+func TestLoadBacktestRunConfig_EmptySymbols(t *testing.T) {
+	path := writeTestBacktestConfig(t, `{"symbols": [], "from": "2023-01-01", "to": "2024-01-01", "initial_capital": 1000}`)
+
+	if _, err := LoadBacktestRunConfig(path); err == nil {
+		t.Fatal("expected error for empty symbols, got nil")
+	}
+}
+
+func TestLoadBacktestRunConfig_InvalidCapital(t *testing.T) {
+	path := writeTestBacktestConfig(t, `{"symbols": ["NVDA"], "from": "2023-01-01", "to": "2024-01-01", "initial_capital": 0}`)
+
+	if _, err := LoadBacktestRunConfig(path); err == nil {
+		t.Fatal("expected error for non-positive initial_capital, got nil")
+	}
+}
+
+func TestLoadBacktestRunConfig_ToNotAfterFrom(t *testing.T) {
+	path := writeTestBacktestConfig(t, `{"symbols": ["NVDA"], "from": "2024-01-01", "to": "2023-01-01", "initial_capital": 1000}`)
+
+	if _, err := LoadBacktestRunConfig(path); err == nil {
+		t.Fatal("expected error for to before from, got nil")
+	}
+}
+
+func TestLoadBacktestRunConfig_InvalidDate(t *testing.T) {
+	path := writeTestBacktestConfig(t, `{"symbols": ["NVDA"], "from": "not-a-date", "to": "2024-01-01", "initial_capital": 1000}`)
+
+	if _, err := LoadBacktestRunConfig(path); err == nil {
+		t.Fatal("expected error for invalid from date, got nil")
+	}
+}
+
+func TestLoadBacktestRunConfig_FileNotFound(t *testing.T) {
+	if _, err := LoadBacktestRunConfig("/nonexistent/backtest.json"); err == nil {
+		t.Fatal("expected error for missing file, got nil")
+	}
+}
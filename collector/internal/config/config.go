@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	env "github.com/caarlos0/env/v11"
 )
 
@@ -9,10 +11,17 @@ import (
 // Why API keys are not required: each source validates its own keys at collection time,
 // allowing `--target tiingo` to work without KIS keys and vice versa.
 type Env struct {
-	DatabaseURL  string `env:"DATABASE_URL,required,notEmpty"`
-	KISAppKey    string `env:"KIS_APP_KEY"`
-	KISAppSecret string `env:"KIS_APP_SECRET"`
-	TiingoAPIKey string `env:"TIINGO_API_KEY"`
+	AlpacaAPIKeyID          string        `env:"ALPACA_API_KEY_ID"`
+	AlpacaAPISecretKey      string        `env:"ALPACA_API_SECRET_KEY"`
+	DatabaseURL             string        `env:"DATABASE_URL,required,notEmpty"`
+	InfluxBucket            string        `env:"INFLUX_BUCKET"`
+	InfluxToken             string        `env:"INFLUX_TOKEN"`
+	InfluxURL               string        `env:"INFLUX_URL"`
+	KISAccountNo            string        `env:"KIS_ACCOUNT_NO"`
+	KISAppKey               string        `env:"KIS_APP_KEY"`
+	KISAppSecret            string        `env:"KIS_APP_SECRET"`
+	OrderBookSampleInterval time.Duration `env:"ORDER_BOOK_SAMPLE_INTERVAL" envDefault:"5s"`
+	TiingoAPIKey            string        `env:"TIINGO_API_KEY"`
 }
 
 func LoadEnv() (Env, error) {
@@ -9,6 +9,10 @@ func TestLoadEnv_AllRequired(t *testing.T) {
 	t.Setenv("TIINGO_API_KEY", "test-tiingo-key")
 	t.Setenv("KIS_APP_KEY", "test-kis-key")
 	t.Setenv("KIS_APP_SECRET", "test-kis-secret")
+	t.Setenv("KIS_ACCOUNT_NO", "1234567801")
+	t.Setenv("INFLUX_URL", "http://localhost:8086")
+	t.Setenv("INFLUX_TOKEN", "test-influx-token")
+	t.Setenv("INFLUX_BUCKET", "jusikbot")
 
 	cfg, err := LoadEnv()
 	if err != nil {
@@ -27,6 +31,18 @@ func TestLoadEnv_AllRequired(t *testing.T) {
 	if cfg.KISAppSecret != "test-kis-secret" {
 		t.Errorf("KISAppSecret = %q, want %q", cfg.KISAppSecret, "test-kis-secret")
 	}
+	if cfg.KISAccountNo != "1234567801" {
+		t.Errorf("KISAccountNo = %q, want %q", cfg.KISAccountNo, "1234567801")
+	}
+	if cfg.InfluxURL != "http://localhost:8086" {
+		t.Errorf("InfluxURL = %q, want %q", cfg.InfluxURL, "http://localhost:8086")
+	}
+	if cfg.InfluxToken != "test-influx-token" {
+		t.Errorf("InfluxToken = %q, want %q", cfg.InfluxToken, "test-influx-token")
+	}
+	if cfg.InfluxBucket != "jusikbot" {
+		t.Errorf("InfluxBucket = %q, want %q", cfg.InfluxBucket, "jusikbot")
+	}
 }
 
 func TestLoadEnv_MissingDatabaseURL(t *testing.T) {
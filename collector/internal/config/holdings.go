@@ -0,0 +1,55 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+type holdingFileEntry struct {
+	Currency string  `json:"currency"`
+	Quantity float64 `json:"quantity"`
+	Symbol   string  `json:"symbol"`
+}
+
+// LoadHoldings reads the portfolio holdings used for rebalancing suggestions.
+// Unlike LoadWatchlist, an empty array is valid (rebalancing stays disabled);
+// callers that want holdings.json to be optional should check errors.Is(err,
+// os.ErrNotExist) themselves.
+func LoadHoldings(path string) ([]domain.Holding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading holdings: %w", err)
+	}
+
+	var raw []holdingFileEntry
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing holdings JSON: %w", err)
+	}
+
+	entries := make([]domain.Holding, 0, len(raw))
+	for i, r := range raw {
+		entry, err := toHolding(r)
+		if err != nil {
+			return nil, fmt.Errorf("holding entry [%d]: %w", i, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func toHolding(raw holdingFileEntry) (domain.Holding, error) {
+	if raw.Symbol == "" {
+		return domain.Holding{}, errors.New("symbol is required")
+	}
+
+	return domain.Holding{
+		Currency: raw.Currency,
+		Quantity: raw.Quantity,
+		Symbol:   raw.Symbol,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestHoldings(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "holdings.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("writing test holdings: %v", err)
+	}
+	return path
+}
+
+func TestLoadHoldings_Valid(t *testing.T) {
+	path := writeTestHoldings(t, `[
+		{"symbol": "NVDA", "quantity": 10, "currency": "USD"},
+		{"symbol": "069500", "quantity": 5, "currency": "KRW"}
+	]`)
+
+	entries, err := LoadHoldings(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+
+	got := entries[0]
+	if got.Symbol != "NVDA" {
+		t.Errorf("Symbol = %q, want %q", got.Symbol, "NVDA")
+	}
+	if got.Quantity != 10 {
+		t.Errorf("Quantity = %v, want 10", got.Quantity)
+	}
+	if got.Currency != "USD" {
+		t.Errorf("Currency = %q, want %q", got.Currency, "USD")
+	}
+}
+
+func TestLoadHoldings_EmptyArray(t *testing.T) {
+	path := writeTestHoldings(t, `[]`)
+
+	entries, err := LoadHoldings(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("got %d entries, want 0", len(entries))
+	}
+}
+
+func TestLoadHoldings_InvalidJSON(t *testing.T) {
+	path := writeTestHoldings(t, `not json`)
+
+	_, err := LoadHoldings(path)
+	if err == nil {
+		t.Fatal("expected error for invalid JSON, got nil")
+	}
+}
+
+func TestLoadHoldings_FileNotFound(t *testing.T) {
+	_, err := LoadHoldings("/nonexistent/holdings.json")
+	if err == nil {
+		t.Fatal("expected error for missing file, got nil")
+	}
+}
+
+func TestLoadHoldings_MissingSymbol(t *testing.T) {
+	path := writeTestHoldings(t, `[
+		{"quantity": 10, "currency": "USD"}
+	]`)
+
+	_, err := LoadHoldings(path)
+	if err == nil {
+		t.Fatal("expected error for missing symbol, got nil")
+	}
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+type retentionFileRule struct {
+	DownsampleTo    string `json:"downsample_to"`
+	KeepDownsampled string `json:"keep_downsampled"`
+	KeepRaw         string `json:"keep_raw"`
+	Table           string `json:"table"`
+}
+
+var (
+	validRetentionTables = map[string]bool{
+		"fx_rate":       true,
+		"price_history": true,
+	}
+	validDownsampleTargets = map[string]bool{
+		"weekly": true,
+	}
+)
+
+// LoadRetentionRules reads retention/downsampling rules from path (e.g.
+// config/retention.json), mirroring LoadWatchlist's validate-then-convert shape.
+func LoadRetentionRules(path string) ([]domain.RetentionRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading retention rules: %w", err)
+	}
+
+	var raw []retentionFileRule
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing retention JSON: %w", err)
+	}
+
+	rules := make([]domain.RetentionRule, 0, len(raw))
+	for i, r := range raw {
+		rule, err := toRetentionRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("retention rule [%d]: %w", i, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func toRetentionRule(raw retentionFileRule) (domain.RetentionRule, error) {
+	if !validRetentionTables[raw.Table] {
+		return domain.RetentionRule{}, fmt.Errorf("invalid table %q (allowed: price_history, fx_rate)", raw.Table)
+	}
+	if !validDownsampleTargets[raw.DownsampleTo] {
+		return domain.RetentionRule{}, fmt.Errorf("invalid downsample_to %q (allowed: weekly)", raw.DownsampleTo)
+	}
+
+	keepRawDays, err := parseRetentionDays(raw.KeepRaw)
+	if err != nil {
+		return domain.RetentionRule{}, fmt.Errorf("keep_raw: %w", err)
+	}
+	keepDownsampledDays, err := parseRetentionDays(raw.KeepDownsampled)
+	if err != nil {
+		return domain.RetentionRule{}, fmt.Errorf("keep_downsampled: %w", err)
+	}
+
+	return domain.RetentionRule{
+		DownsampleTo:        raw.DownsampleTo,
+		KeepDownsampledDays: keepDownsampledDays,
+		KeepRawDays:         keepRawDays,
+		Table:               raw.Table,
+	}, nil
+}
+
+// parseRetentionDays parses a "<n><unit>" duration where unit is d (day),
+// w (week, 7 days), or y (year, 365 days), returning the equivalent number of
+// days. Calendar-aware units aren't worth the complexity here: retention
+// windows are measured in years, where leap-day drift is noise.
+func parseRetentionDays(s string) (int, error) {
+	if len(s) < 2 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	unit := s[len(s)-1]
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+
+	switch unit {
+	case 'd':
+		return n, nil
+	case 'w':
+		return n * 7, nil
+	case 'y':
+		return n * 365, nil
+	default:
+		return 0, fmt.Errorf("invalid duration unit %q in %q (allowed: d, w, y)", string(unit), s)
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestRetention(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "retention.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("writing test retention rules: %v", err)
+	}
+	return path
+}
+
+func TestLoadRetentionRules_Valid(t *testing.T) {
+	path := writeTestRetention(t, `[
+		{"table": "price_history", "keep_raw": "2y", "downsample_to": "weekly", "keep_downsampled": "20y"},
+		{"table": "fx_rate", "keep_raw": "1y", "downsample_to": "weekly", "keep_downsampled": "10y"}
+	]`)
+
+	rules, err := LoadRetentionRules(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("got %d rules, want 2", len(rules))
+	}
+
+	got := rules[0]
+	if got.Table != "price_history" {
+		t.Errorf("Table = %q, want %q", got.Table, "price_history")
+	}
+	if got.DownsampleTo != "weekly" {
+		t.Errorf("DownsampleTo = %q, want %q", got.DownsampleTo, "weekly")
+	}
+	if got.KeepRawDays != 730 {
+		t.Errorf("KeepRawDays = %d, want 730", got.KeepRawDays)
+	}
+	if got.KeepDownsampledDays != 7300 {
+		t.Errorf("KeepDownsampledDays = %d, want 7300", got.KeepDownsampledDays)
+	}
+}
+
+func TestLoadRetentionRules_InvalidTable(t *testing.T) {
+	path := writeTestRetention(t, `[
+		{"table": "corporate_action", "keep_raw": "2y", "downsample_to": "weekly", "keep_downsampled": "20y"}
+	]`)
+
+	_, err := LoadRetentionRules(path)
+	if err == nil {
+		t.Fatal("expected error for invalid table, got nil")
+	}
+}
+
+func TestLoadRetentionRules_InvalidDownsampleTo(t *testing.T) {
+	path := writeTestRetention(t, `[
+		{"table": "price_history", "keep_raw": "2y", "downsample_to": "daily", "keep_downsampled": "20y"}
+	]`)
+
+	_, err := LoadRetentionRules(path)
+	if err == nil {
+		t.Fatal("expected error for invalid downsample_to, got nil")
+	}
+}
+
+func TestLoadRetentionRules_InvalidDuration(t *testing.T) {
+	path := writeTestRetention(t, `[
+		{"table": "price_history", "keep_raw": "two years", "downsample_to": "weekly", "keep_downsampled": "20y"}
+	]`)
+
+	_, err := LoadRetentionRules(path)
+	if err == nil {
+		t.Fatal("expected error for invalid duration, got nil")
+	}
+}
+
+func TestLoadRetentionRules_FileNotFound(t *testing.T) {
+	_, err := LoadRetentionRules("/nonexistent/retention.json")
+	if err == nil {
+		t.Fatal("expected error for missing file, got nil")
+	}
+}
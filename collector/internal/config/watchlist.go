@@ -5,37 +5,58 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/jusikbot/collector/internal/domain"
+	"gopkg.in/yaml.v3"
 )
 
 type watchlistFileEntry struct {
-	Market string `json:"market"`
-	Name   string `json:"name"`
-	Symbol string `json:"symbol"`
-	Type   string `json:"type"`
+	Aliases      map[string]string `json:"aliases" yaml:"aliases"`
+	Currency     string            `json:"currency" yaml:"currency"`
+	Enabled      *bool             `json:"enabled" yaml:"enabled"`
+	ISIN         string            `json:"isin" yaml:"isin"`
+	LotSize      int64             `json:"lot_size" yaml:"lot_size"`
+	Market       string            `json:"market" yaml:"market"`
+	Name         string            `json:"name" yaml:"name"`
+	PriceTick    float64           `json:"price_tick" yaml:"price_tick"`
+	Source       string            `json:"source" yaml:"source"`
+	Symbol       string            `json:"symbol" yaml:"symbol"`
+	TargetWeight float64           `json:"target_weight" yaml:"target_weight"`
+	Type         string            `json:"type" yaml:"type"`
 }
 
 var (
 	validMarkets = map[string]domain.Market{
 		"US": domain.MarketUS,
 		"KR": domain.MarketKR,
+		"JP": domain.MarketJP,
+		"HK": domain.MarketHK,
 	}
 	validSecurityTypes = map[string]domain.SecurityType{
 		"stock": domain.SecurityTypeStock,
 		"etf":   domain.SecurityTypeETF,
 	}
+	validUSDataSources = map[string]domain.USDataSource{
+		"":       domain.USDataSourceTiingo,
+		"alpaca": domain.USDataSourceAlpaca,
+		"tiingo": domain.USDataSourceTiingo,
+	}
 )
 
+// LoadWatchlist reads a watchlist file at path, parsing it as YAML when the
+// extension is .yaml or .yml and JSON otherwise.
 func LoadWatchlist(path string) ([]domain.WatchlistEntry, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading watchlist: %w", err)
 	}
 
-	var raw []watchlistFileEntry
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return nil, fmt.Errorf("parsing watchlist JSON: %w", err)
+	raw, err := unmarshalWatchlist(path, data)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(raw) == 0 {
@@ -54,6 +75,23 @@ func LoadWatchlist(path string) ([]domain.WatchlistEntry, error) {
 	return entries, nil
 }
 
+func unmarshalWatchlist(path string, data []byte) ([]watchlistFileEntry, error) {
+	var raw []watchlistFileEntry
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("parsing watchlist YAML: %w", err)
+		}
+	default:
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("parsing watchlist JSON: %w", err)
+		}
+	}
+
+	return raw, nil
+}
+
 func FilterByMarket(entries []domain.WatchlistEntry, market domain.Market) []domain.WatchlistEntry {
 	var filtered []domain.WatchlistEntry
 	for _, e := range entries {
@@ -64,6 +102,19 @@ func FilterByMarket(entries []domain.WatchlistEntry, market domain.Market) []dom
 	return filtered
 }
 
+// FilterByEnabled returns only the entries with Enabled set, so a
+// temporarily-paused symbol (e.g. delisted, awaiting a data fix) can stay in
+// the watchlist file without being fed to collection.
+func FilterByEnabled(entries []domain.WatchlistEntry) []domain.WatchlistEntry {
+	var filtered []domain.WatchlistEntry
+	for _, e := range entries {
+		if e.Enabled {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
 func toWatchlistEntry(raw watchlistFileEntry) (domain.WatchlistEntry, error) {
 	if raw.Symbol == "" {
 		return domain.WatchlistEntry{}, errors.New("symbol is required")
@@ -71,7 +122,7 @@ func toWatchlistEntry(raw watchlistFileEntry) (domain.WatchlistEntry, error) {
 
 	market, ok := validMarkets[raw.Market]
 	if !ok {
-		return domain.WatchlistEntry{}, fmt.Errorf("invalid market %q (allowed: US, KR)", raw.Market)
+		return domain.WatchlistEntry{}, fmt.Errorf("invalid market %q (allowed: US, KR, JP, HK)", raw.Market)
 	}
 
 	secType, ok := validSecurityTypes[raw.Type]
@@ -79,10 +130,85 @@ func toWatchlistEntry(raw watchlistFileEntry) (domain.WatchlistEntry, error) {
 		return domain.WatchlistEntry{}, fmt.Errorf("invalid type %q (allowed: stock, etf)", raw.Type)
 	}
 
+	source, ok := validUSDataSources[raw.Source]
+	if !ok {
+		return domain.WatchlistEntry{}, fmt.Errorf("invalid source %q (allowed: tiingo, alpaca)", raw.Source)
+	}
+
+	if raw.PriceTick < 0 {
+		return domain.WatchlistEntry{}, fmt.Errorf("price_tick %v must not be negative", raw.PriceTick)
+	}
+	if raw.LotSize < 0 {
+		return domain.WatchlistEntry{}, fmt.Errorf("lot_size %d must not be negative", raw.LotSize)
+	}
+	if raw.ISIN != "" {
+		if err := validateISIN(raw.ISIN); err != nil {
+			return domain.WatchlistEntry{}, fmt.Errorf("isin %q: %w", raw.ISIN, err)
+		}
+	}
+
+	enabled := true
+	if raw.Enabled != nil {
+		enabled = *raw.Enabled
+	}
+
 	return domain.WatchlistEntry{
-		Market: market,
-		Name:   raw.Name,
-		Symbol: raw.Symbol,
-		Type:   secType,
+		Aliases:      raw.Aliases,
+		Currency:     raw.Currency,
+		Enabled:      enabled,
+		ISIN:         raw.ISIN,
+		LotSize:      raw.LotSize,
+		Market:       market,
+		Name:         raw.Name,
+		PriceTick:    raw.PriceTick,
+		Source:       source,
+		Symbol:       raw.Symbol,
+		TargetWeight: raw.TargetWeight,
+		Type:         secType,
 	}, nil
 }
+
+// validateISIN checks that isin is 12 characters (2-letter country code, 9
+// alphanumeric chars, 1 check digit) and that its check digit matches the ISO
+// 6166 Luhn-mod-10 algorithm (letters expanded to digits: A=10 ... Z=35).
+func validateISIN(isin string) error {
+	if len(isin) != 12 {
+		return fmt.Errorf("must be 12 characters, got %d", len(isin))
+	}
+
+	var digits strings.Builder
+	for _, r := range strings.ToUpper(isin) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			digits.WriteString(strconv.Itoa(int(r-'A') + 10))
+		default:
+			return fmt.Errorf("contains invalid character %q", r)
+		}
+	}
+
+	if luhnChecksum(digits.String()) != 0 {
+		return errors.New("check digit is invalid")
+	}
+	return nil
+}
+
+// luhnChecksum returns the Luhn checksum of digits (a string of ASCII
+// digits), processed right to left; a valid check digit yields 0 mod 10.
+func luhnChecksum(digits string) int {
+	var sum int
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum % 10
+}
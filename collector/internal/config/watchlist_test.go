@@ -9,9 +9,14 @@ import (
 )
 
 func writeTestWatchlist(t *testing.T, content string) string {
+	t.Helper()
+	return writeTestWatchlistFile(t, "watchlist.json", content)
+}
+
+func writeTestWatchlistFile(t *testing.T, filename, content string) string {
 	t.Helper()
 	dir := t.TempDir()
-	path := filepath.Join(dir, "watchlist.json")
+	path := filepath.Join(dir, filename)
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		t.Fatalf("writing test watchlist: %v", err)
 	}
@@ -47,6 +52,27 @@ func TestLoadWatchlist_Valid(t *testing.T) {
 	}
 }
 
+func TestLoadWatchlist_JPAndHKMarkets(t *testing.T) {
+	path := writeTestWatchlist(t, `[
+		{"symbol": "7203", "name": "Toyota", "market": "JP", "type": "stock"},
+		{"symbol": "0700", "name": "Tencent", "market": "HK", "type": "stock"}
+	]`)
+
+	entries, err := LoadWatchlist(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Market != domain.MarketJP {
+		t.Errorf("Market = %q, want %q", entries[0].Market, domain.MarketJP)
+	}
+	if entries[1].Market != domain.MarketHK {
+		t.Errorf("Market = %q, want %q", entries[1].Market, domain.MarketHK)
+	}
+}
+
 func TestLoadWatchlist_InvalidJSON(t *testing.T) {
 	path := writeTestWatchlist(t, `not json`)
 
@@ -85,7 +111,7 @@ func TestLoadWatchlist_MissingSymbol(t *testing.T) {
 
 func TestLoadWatchlist_InvalidMarket(t *testing.T) {
 	path := writeTestWatchlist(t, `[
-		{"symbol": "TSLA", "name": "Tesla", "market": "JP", "type": "stock"}
+		{"symbol": "TSLA", "name": "Tesla", "market": "XX", "type": "stock"}
 	]`)
 
 	_, err := LoadWatchlist(path)
@@ -139,6 +165,125 @@ func TestFilterByMarket_KR(t *testing.T) {
 	}
 }
 
+func TestLoadWatchlist_YAML(t *testing.T) {
+	path := writeTestWatchlistFile(t, "watchlist.yaml", `
+- symbol: NVDA
+  name: NVIDIA
+  market: US
+  type: stock
+  aliases:
+    kis: "005930"
+  currency: USD
+  price_tick: 0.01
+  lot_size: 1
+  isin: US67066G1040
+`)
+
+	entries, err := LoadWatchlist(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+
+	got := entries[0]
+	if got.Currency != "USD" {
+		t.Errorf("Currency = %q, want %q", got.Currency, "USD")
+	}
+	if got.PriceTick != 0.01 {
+		t.Errorf("PriceTick = %v, want 0.01", got.PriceTick)
+	}
+	if got.LotSize != 1 {
+		t.Errorf("LotSize = %d, want 1", got.LotSize)
+	}
+	if got.Aliases["kis"] != "005930" {
+		t.Errorf("Aliases[kis] = %q, want %q", got.Aliases["kis"], "005930")
+	}
+	if !got.Enabled {
+		t.Error("Enabled = false, want true (default when unset)")
+	}
+}
+
+func TestLoadWatchlist_EnabledDefaultsTrue(t *testing.T) {
+	path := writeTestWatchlist(t, `[{"symbol": "NVDA", "name": "NVIDIA", "market": "US", "type": "stock"}]`)
+
+	entries, err := LoadWatchlist(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !entries[0].Enabled {
+		t.Error("Enabled = false, want true when omitted from the file")
+	}
+}
+
+func TestLoadWatchlist_EnabledExplicitFalse(t *testing.T) {
+	path := writeTestWatchlist(t, `[{"symbol": "NVDA", "name": "NVIDIA", "market": "US", "type": "stock", "enabled": false}]`)
+
+	entries, err := LoadWatchlist(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries[0].Enabled {
+		t.Error("Enabled = true, want false")
+	}
+}
+
+func TestLoadWatchlist_InvalidISIN(t *testing.T) {
+	path := writeTestWatchlist(t, `[{"symbol": "NVDA", "name": "NVIDIA", "market": "US", "type": "stock", "isin": "US67066G1041"}]`)
+
+	_, err := LoadWatchlist(path)
+	if err == nil {
+		t.Fatal("expected error for invalid ISIN check digit, got nil")
+	}
+}
+
+func TestLoadWatchlist_ValidISIN(t *testing.T) {
+	path := writeTestWatchlist(t, `[{"symbol": "NVDA", "name": "NVIDIA", "market": "US", "type": "stock", "isin": "US67066G1040"}]`)
+
+	entries, err := LoadWatchlist(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries[0].ISIN != "US67066G1040" {
+		t.Errorf("ISIN = %q, want %q", entries[0].ISIN, "US67066G1040")
+	}
+}
+
+func TestLoadWatchlist_NegativePriceTick(t *testing.T) {
+	path := writeTestWatchlist(t, `[{"symbol": "NVDA", "name": "NVIDIA", "market": "US", "type": "stock", "price_tick": -0.01}]`)
+
+	_, err := LoadWatchlist(path)
+	if err == nil {
+		t.Fatal("expected error for negative price_tick, got nil")
+	}
+}
+
+func TestLoadWatchlist_NegativeLotSize(t *testing.T) {
+	path := writeTestWatchlist(t, `[{"symbol": "NVDA", "name": "NVIDIA", "market": "US", "type": "stock", "lot_size": -1}]`)
+
+	_, err := LoadWatchlist(path)
+	if err == nil {
+		t.Fatal("expected error for negative lot_size, got nil")
+	}
+}
+
+func TestFilterByEnabled(t *testing.T) {
+	entries := []domain.WatchlistEntry{
+		{Symbol: "NVDA", Enabled: true},
+		{Symbol: "TSLA", Enabled: false},
+		{Symbol: "AAPL", Enabled: true},
+	}
+
+	enabled := FilterByEnabled(entries)
+	if len(enabled) != 2 {
+		t.Fatalf("got %d entries, want 2", len(enabled))
+	}
+	if enabled[0].Symbol != "NVDA" || enabled[1].Symbol != "AAPL" {
+		t.Errorf("enabled = %+v, want NVDA, AAPL", enabled)
+	}
+}
+
 func TestFilterByMarket_NoMatch(t *testing.T) {
 	entries := []domain.WatchlistEntry{
 		{Symbol: "NVDA", Market: domain.MarketUS},
@@ -0,0 +1,98 @@
+package csvsource
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// FXColumns maps domain.FXRate fields to CSV header names.
+type FXColumns struct {
+	Date string
+	Rate string
+}
+
+// DefaultFXColumns matches a plain "Date,Rate" export.
+var DefaultFXColumns = FXColumns{
+	Date: "Date",
+	Rate: "Rate",
+}
+
+// FXCSVReader parses a single-pair FX rate CSV into domain.FXRate rows.
+type FXCSVReader struct {
+	// Columns maps each FXRate field to its CSV header name. Zero value
+	// uses DefaultFXColumns.
+	Columns FXColumns
+	// DateFormat is a time.Parse reference layout. Zero value uses "2006-01-02".
+	DateFormat string
+}
+
+// NewFXCSVReader returns an FXCSVReader configured for DefaultFXColumns and
+// an ISO date format.
+func NewFXCSVReader() *FXCSVReader {
+	return &FXCSVReader{Columns: DefaultFXColumns, DateFormat: defaultDateFormat}
+}
+
+// Read parses src as a header row plus one row per day, returning FXRate rows
+// for pair (e.g. "USD/KRW"). Source and Sources are both set to the "csv"
+// sentinel source name, matching a single-provider collection run's convention
+// of Sources == []string{Source}. FetchedAt is left zero: it's set server-side
+// by store.Repository.UpsertFXRates.
+func (r *FXCSVReader) Read(src io.Reader, pair string) ([]domain.FXRate, error) {
+	columns := r.Columns
+	if columns == (FXColumns{}) {
+		columns = DefaultFXColumns
+	}
+	dateFormat := r.DateFormat
+	if dateFormat == "" {
+		dateFormat = defaultDateFormat
+	}
+
+	cr := csv.NewReader(src)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	dateIdx, err := columnIndex(header, columns.Date)
+	if err != nil {
+		return nil, err
+	}
+	rateIdx, err := columnIndex(header, columns.Rate)
+	if err != nil {
+		return nil, err
+	}
+
+	var rates []domain.FXRate
+	for rowNum := 2; ; rowNum++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv row %d: %w", rowNum, err)
+		}
+
+		date, err := time.Parse(dateFormat, record[dateIdx])
+		if err != nil {
+			return nil, fmt.Errorf("row %d: parse date %q: %w", rowNum, record[dateIdx], err)
+		}
+		rate, err := parseFloat(record, rateIdx)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: parse rate: %w", rowNum, err)
+		}
+
+		rates = append(rates, domain.FXRate{
+			Date:    date,
+			Pair:    pair,
+			Rate:    rate,
+			Source:  sourceName,
+			Sources: []string{sourceName},
+		})
+	}
+
+	return rates, nil
+}
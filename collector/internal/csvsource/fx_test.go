@@ -0,0 +1,61 @@
+package csvsource
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFXCSVReader_Read(t *testing.T) {
+	csvData := "Date,Rate\n2024-01-02,1320.5\n2024-01-03,1318.2\n"
+
+	r := NewFXCSVReader()
+	rates, err := r.Read(strings.NewReader(csvData), "USD/KRW")
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(rates) != 2 {
+		t.Fatalf("len(rates) = %d, want 2", len(rates))
+	}
+
+	first := rates[0]
+	if !first.Date.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Date = %v, want 2024-01-02", first.Date)
+	}
+	if first.Rate != 1320.5 {
+		t.Errorf("Rate = %v, want 1320.5", first.Rate)
+	}
+	if first.Pair != "USD/KRW" {
+		t.Errorf("Pair = %q, want USD/KRW", first.Pair)
+	}
+	if first.Source != sourceName {
+		t.Errorf("Source = %q, want %q", first.Source, sourceName)
+	}
+	if len(first.Sources) != 1 || first.Sources[0] != sourceName {
+		t.Errorf("Sources = %v, want [%q]", first.Sources, sourceName)
+	}
+}
+
+func TestFXCSVReader_Read_CustomColumns(t *testing.T) {
+	csvData := "day,fx_rate\n2024-01-02,1320.5\n"
+
+	r := &FXCSVReader{Columns: FXColumns{Date: "day", Rate: "fx_rate"}}
+	rates, err := r.Read(strings.NewReader(csvData), "USD/KRW")
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if rates[0].Rate != 1320.5 {
+		t.Errorf("Rate = %v, want 1320.5", rates[0].Rate)
+	}
+}
+
+func TestFXCSVReader_Read_MissingColumn(t *testing.T) {
+	csvData := "Date\n2024-01-02\n"
+
+	r := NewFXCSVReader()
+	_, err := r.Read(strings.NewReader(csvData), "USD/KRW")
+	if !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("err = %v, want ErrColumnNotFound", err)
+	}
+}
@@ -0,0 +1,194 @@
+// Package csvsource reads domain.DailyPrice and domain.FXRate rows out of
+// plain CSV files, so the summary/backtest pipelines can be bootstrapped or
+// run entirely offline from a Yahoo/Investing.com export instead of the
+// live KIS/Tiingo/Alpaca/Frankfurter integrations.
+package csvsource
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+const sourceName = "csv"
+
+// defaultDateFormat is used when a reader's DateFormat is left zero.
+const defaultDateFormat = "2006-01-02"
+
+// ErrColumnNotFound signals that a configured column name is absent from the
+// CSV's header row.
+var ErrColumnNotFound = errors.New("column not found in csv header")
+
+// PriceColumns maps domain.DailyPrice fields to CSV header names, so a
+// PriceCSVReader can be pointed at exports with different header conventions
+// without a config file per source.
+type PriceColumns struct {
+	Date     string
+	Open     string
+	High     string
+	Low      string
+	Close    string
+	AdjClose string
+	Volume   string
+}
+
+// DefaultPriceColumns matches Yahoo Finance's historical-data CSV export header.
+var DefaultPriceColumns = PriceColumns{
+	Date:     "Date",
+	Open:     "Open",
+	High:     "High",
+	Low:      "Low",
+	Close:    "Close",
+	AdjClose: "Adj Close",
+	Volume:   "Volume",
+}
+
+// PriceCSVReader parses an OHLCV CSV into domain.DailyPrice rows.
+type PriceCSVReader struct {
+	// Columns maps each DailyPrice field to its CSV header name. Zero value
+	// uses DefaultPriceColumns.
+	Columns PriceColumns
+	// DateFormat is a time.Parse reference layout. Zero value uses "2006-01-02".
+	DateFormat string
+}
+
+// NewPriceCSVReader returns a PriceCSVReader configured for Yahoo Finance's
+// export format (DefaultPriceColumns, ISO date).
+func NewPriceCSVReader() *PriceCSVReader {
+	return &PriceCSVReader{Columns: DefaultPriceColumns, DateFormat: defaultDateFormat}
+}
+
+// Read parses src as a header row plus one row per trading day, returning
+// DailyPrice rows for symbol. AdjClose falls back to Close when r.Columns.AdjClose
+// is empty or absent from the header, matching exports (e.g. Investing.com) that
+// don't carry a separate adjusted-close column. FetchedAt and IsAnomaly are left
+// zero-valued: FetchedAt is set server-side by store.Repository.UpsertPrices, and
+// anomaly detection runs downstream in internal/validate.
+func (r *PriceCSVReader) Read(src io.Reader, symbol string) ([]domain.DailyPrice, error) {
+	columns := r.Columns
+	if columns == (PriceColumns{}) {
+		columns = DefaultPriceColumns
+	}
+	dateFormat := r.DateFormat
+	if dateFormat == "" {
+		dateFormat = defaultDateFormat
+	}
+
+	cr := csv.NewReader(src)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	dateIdx, err := columnIndex(header, columns.Date)
+	if err != nil {
+		return nil, err
+	}
+	openIdx, err := columnIndex(header, columns.Open)
+	if err != nil {
+		return nil, err
+	}
+	highIdx, err := columnIndex(header, columns.High)
+	if err != nil {
+		return nil, err
+	}
+	lowIdx, err := columnIndex(header, columns.Low)
+	if err != nil {
+		return nil, err
+	}
+	closeIdx, err := columnIndex(header, columns.Close)
+	if err != nil {
+		return nil, err
+	}
+	volumeIdx, err := columnIndex(header, columns.Volume)
+	if err != nil {
+		return nil, err
+	}
+	adjCloseIdx := -1
+	if columns.AdjClose != "" {
+		if idx, err := columnIndex(header, columns.AdjClose); err == nil {
+			adjCloseIdx = idx
+		}
+	}
+
+	var prices []domain.DailyPrice
+	for rowNum := 2; ; rowNum++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv row %d: %w", rowNum, err)
+		}
+
+		date, err := time.Parse(dateFormat, record[dateIdx])
+		if err != nil {
+			return nil, fmt.Errorf("row %d: parse date %q: %w", rowNum, record[dateIdx], err)
+		}
+		open, err := parseFloat(record, openIdx)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: parse open: %w", rowNum, err)
+		}
+		high, err := parseFloat(record, highIdx)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: parse high: %w", rowNum, err)
+		}
+		low, err := parseFloat(record, lowIdx)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: parse low: %w", rowNum, err)
+		}
+		closePrice, err := parseFloat(record, closeIdx)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: parse close: %w", rowNum, err)
+		}
+		volume, err := parseInt(record, volumeIdx)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: parse volume: %w", rowNum, err)
+		}
+		adjClose := closePrice
+		if adjCloseIdx >= 0 {
+			adjClose, err = parseFloat(record, adjCloseIdx)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: parse adj close: %w", rowNum, err)
+			}
+		}
+
+		prices = append(prices, domain.DailyPrice{
+			AdjClose: adjClose,
+			Close:    closePrice,
+			Date:     date,
+			High:     high,
+			Low:      low,
+			Open:     open,
+			Source:   sourceName,
+			Symbol:   symbol,
+			Volume:   volume,
+		})
+	}
+
+	return prices, nil
+}
+
+// columnIndex returns name's position in header, or ErrColumnNotFound.
+func columnIndex(header []string, name string) (int, error) {
+	for i, h := range header {
+		if strings.TrimSpace(h) == name {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("%q: %w", name, ErrColumnNotFound)
+}
+
+func parseFloat(record []string, idx int) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(record[idx]), 64)
+}
+
+func parseInt(record []string, idx int) (int64, error) {
+	return strconv.ParseInt(strings.TrimSpace(record[idx]), 10, 64)
+}
@@ -0,0 +1,98 @@
+package csvsource
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPriceCSVReader_Read(t *testing.T) {
+	csvData := "Date,Open,High,Low,Close,Adj Close,Volume\n" +
+		"2024-01-02,185.1,186.9,183.2,186.5,186.2,40000000\n" +
+		"2024-01-03,186.8,188.0,185.5,187.9,187.6,35000000\n"
+
+	r := NewPriceCSVReader()
+	prices, err := r.Read(strings.NewReader(csvData), "AAPL")
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(prices) != 2 {
+		t.Fatalf("len(prices) = %d, want 2", len(prices))
+	}
+
+	first := prices[0]
+	if !first.Date.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Date = %v, want 2024-01-02", first.Date)
+	}
+	if first.Open != 185.1 || first.High != 186.9 || first.Low != 183.2 || first.Close != 186.5 || first.AdjClose != 186.2 {
+		t.Errorf("prices[0] = %+v, unexpected OHLC values", first)
+	}
+	if first.Volume != 40000000 {
+		t.Errorf("Volume = %d, want 40000000", first.Volume)
+	}
+	if first.Symbol != "AAPL" {
+		t.Errorf("Symbol = %q, want AAPL", first.Symbol)
+	}
+	if first.Source != sourceName {
+		t.Errorf("Source = %q, want %q", first.Source, sourceName)
+	}
+}
+
+func TestPriceCSVReader_Read_MissingAdjCloseFallsBackToClose(t *testing.T) {
+	csvData := "Date,Open,High,Low,Close,Volume\n" +
+		"2024-01-02,185.1,186.9,183.2,186.5,40000000\n"
+
+	r := NewPriceCSVReader()
+	prices, err := r.Read(strings.NewReader(csvData), "AAPL")
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got, want := prices[0].AdjClose, prices[0].Close; got != want {
+		t.Errorf("AdjClose = %v, want fallback to Close %v", got, want)
+	}
+}
+
+func TestPriceCSVReader_Read_CustomColumnsAndDateFormat(t *testing.T) {
+	csvData := "date,open,high,low,close,volume\n" +
+		"01/02/2024,185.1,186.9,183.2,186.5,40000000\n"
+
+	r := &PriceCSVReader{
+		Columns: PriceColumns{
+			Date:   "date",
+			Open:   "open",
+			High:   "high",
+			Low:    "low",
+			Close:  "close",
+			Volume: "volume",
+		},
+		DateFormat: "01/02/2006",
+	}
+	prices, err := r.Read(strings.NewReader(csvData), "AAPL")
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !prices[0].Date.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Date = %v, want 2024-01-02", prices[0].Date)
+	}
+}
+
+func TestPriceCSVReader_Read_MissingColumn(t *testing.T) {
+	csvData := "Date,Open,High,Low,Volume\n2024-01-02,185.1,186.9,183.2,40000000\n"
+
+	r := NewPriceCSVReader()
+	_, err := r.Read(strings.NewReader(csvData), "AAPL")
+	if !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("err = %v, want ErrColumnNotFound", err)
+	}
+}
+
+func TestPriceCSVReader_Read_BadRow(t *testing.T) {
+	csvData := "Date,Open,High,Low,Close,Adj Close,Volume\n" +
+		"2024-01-02,not-a-number,186.9,183.2,186.5,186.2,40000000\n"
+
+	r := NewPriceCSVReader()
+	if _, err := r.Read(strings.NewReader(csvData), "AAPL"); err == nil {
+		t.Error("expected error for unparseable open value")
+	}
+}
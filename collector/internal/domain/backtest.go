@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// BacktestRunConfig is the run-level configuration for a backtest: which
+// symbols to replay, over what date range, and the simulated trading costs
+// and capital. Loaded via config.LoadBacktestRunConfig.
+type BacktestRunConfig struct {
+	FeeBps         float64
+	From           time.Time
+	InitialCapital float64
+	MinSamples     int
+	RiskFreeAnnual float64
+	SlippageBps    float64
+	Symbols        []string
+	To             time.Time
+}
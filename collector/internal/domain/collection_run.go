@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// CollectionRun records the outcome of one invocation of cmd/collect, so an
+// operator can query historical collection health instead of only seeing
+// the latest run's stdout/log lines. RunID is caller-assigned (cmd/collect
+// uses StartedAt formatted as RFC3339) rather than a DB-generated ID, so a
+// run can be logged/reported before the DB insert happens.
+type CollectionRun struct {
+	ErrorSummary string
+	FinishedAt   time.Time
+	RunID        string
+	Sources      []CollectionRunSource
+	StartedAt    time.Time
+}
+
+// CollectionRunSource is one source's row-level contribution within a
+// CollectionRun. Fields mirror collector.SourceStats plus the pass/fail
+// outcome and elapsed time collector.SourceResult already tracks in memory.
+type CollectionRunSource struct {
+	AnomaliesFlagged int64
+	Elapsed          time.Duration
+	Error            string
+	HTTPCallCount    int64
+	OK               bool
+	RetriesAttempted int64
+	RowsFetched      int64
+	RowsInserted     int64
+	RowsSkipped      int64
+	Source           string
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// CorporateActionKind distinguishes stock splits, cash dividends, and spinoffs.
+type CorporateActionKind string
+
+const (
+	CorporateActionSplit    CorporateActionKind = "split"
+	CorporateActionDividend CorporateActionKind = "dividend"
+	CorporateActionSpinoff  CorporateActionKind = "spinoff"
+)
+
+// CorporateAction records a split, dividend, or spinoff event observed alongside a daily price row.
+// Why persisted separately from DailyPrice: splitFactor/divCash are source-specific fields
+// that would otherwise be discarded once converted to the shared DailyPrice shape.
+type CorporateAction struct {
+	CashAmount float64
+	Currency   string
+	ExDate     time.Time
+	Kind       CorporateActionKind
+	SplitRatio float64
+	Symbol     string
+}
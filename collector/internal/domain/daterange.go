@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// DateRange is an inclusive span of consecutive missing trading days, as
+// returned by store.Repository.DetectMissingRanges / DetectMissingFXRanges.
+type DateRange struct {
+	End   time.Time
+	Start time.Time
+}
@@ -10,3 +10,24 @@ import (
 type StockDataFetcher interface {
 	FetchDailyPrices(ctx context.Context, symbol string, from time.Time, to time.Time) ([]DailyPrice, error)
 }
+
+// MarketDataFetcher extends StockDataFetcher with the splits/dividends and
+// benchmark data a per-market backend can serve, letting fetcher.Registry
+// dispatch a WatchlistEntry's Market to a richer implementation than the
+// bare price-only contract. Why not fold this into StockDataFetcher: kis.Client
+// and fetcher.ChainFetcher are still useful as plain price sources, so the
+// richer contract stays additive rather than forcing every StockDataFetcher to
+// grow methods it can't serve.
+type MarketDataFetcher interface {
+	StockDataFetcher
+
+	// FetchBenchmark fetches daily prices for market's benchmark symbol (e.g.
+	// QQQ for MarketUS), for use as the RelativeBench baseline in summary indicators.
+	FetchBenchmark(ctx context.Context, market Market, from time.Time, to time.Time) ([]DailyPrice, error)
+
+	// FetchSplitsAndDividends fetches the corporate actions for symbol within
+	// the date range, so callers can cross-validate adj_close anomalies the
+	// same way Tiingo's raw splitFactor/divCash fields already do (see
+	// validate.CorporateActionsFromDomain).
+	FetchSplitsAndDividends(ctx context.Context, symbol string, from time.Time, to time.Time) ([]CorporateAction, error)
+}
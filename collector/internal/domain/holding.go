@@ -0,0 +1,11 @@
+package domain
+
+// Holding is a current portfolio position used to compute rebalancing
+// suggestions against a WatchlistEntry's TargetWeight.
+type Holding struct {
+	// Currency is the holding's pricing currency (e.g. "USD", "KRW"). A non-KRW
+	// holding needs an FX rate to normalize its market value.
+	Currency string
+	Quantity float64
+	Symbol   string
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// SettlementType identifies a market's settlement cycle convention, used to
+// reason about when a trade against an Instrument actually settles.
+type SettlementType string
+
+const (
+	SettlementTPlus1 SettlementType = "T+1"
+	SettlementTPlus2 SettlementType = "T+2"
+)
+
+// Instrument is a symbol's exchange-level contract specification: the
+// currency it quotes in, its minimum price/amount increments, and (for
+// futures) its delivery and contract type. Sourced once per symbol from a
+// venue's product-metadata endpoint (see kis.Client.FetchInstrument,
+// tiingo.Client.FetchInstrument) and persisted via InstrumentRepository,
+// since contract specs change rarely enough that every summary/rebalance run
+// refetching them would be wasted API calls. Contrast InstrumentInfo, which
+// tracks KIS-specific intraday-volatile state (trading halts, daily price
+// limits) refreshed via InstrumentCache instead of persisted to the DB.
+type Instrument struct {
+	AmountTickSize float64
+	// ContractMultiplier is the notional value represented by one unit of
+	// the instrument; 1 for ordinary equities/ETFs.
+	ContractMultiplier float64
+	// ContractType distinguishes a futures contract's settlement style (e.g.
+	// "physical", "cash"). Empty for equities/ETFs.
+	ContractType string
+	// Delivery is a futures contract's delivery/expiry date. Zero for
+	// equities/ETFs.
+	Delivery       time.Time
+	Exchange       string
+	PriceTickSize  float64
+	QuoteCurrency  string
+	SettlementType SettlementType
+	Symbol         string
+}
+
+// InstrumentRepository persists and retrieves Instrument contract specs.
+// Satisfied by store.Repository.
+type InstrumentRepository interface {
+	// FetchInstrument returns symbol's persisted Instrument. The bool return
+	// is false (with a zero Instrument and nil error) when no row exists yet,
+	// the same "not found is not an error" convention store.Repository uses
+	// elsewhere for optional per-symbol state.
+	FetchInstrument(ctx context.Context, symbol string) (Instrument, bool, error)
+
+	// UpsertInstruments inserts or updates instruments, keyed by Symbol, and
+	// returns the number of rows affected.
+	UpsertInstruments(ctx context.Context, instruments []Instrument) (int64, error)
+}
+
+// InstrumentInfo holds per-symbol trading parameters that rarely change intraday:
+// the minimum price increment, the minimum tradable unit, the daily price limits,
+// and the shares outstanding. Sourced from KIS's product-info lookup and cached,
+// since collection and rebalancing runs would otherwise refetch the same values
+// on every invocation.
+type InstrumentInfo struct {
+	ListedShares    int64
+	LotSize         int64
+	LowerLimitPrice float64
+	// MarketCode identifies the listing board (e.g. "KOSPI", "KOSDAQ"), which
+	// determines which trading session calendar applies to Symbol.
+	MarketCode string
+	Symbol     string
+	TickSize   float64
+	// TradingHalted mirrors KIS's per-symbol halt flag (거래정지), independent of
+	// the board's regular session hours.
+	TradingHalted   bool
+	UpperLimitPrice float64
+}
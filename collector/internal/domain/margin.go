@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// MarginLoanStatus distinguishes an outstanding loan from one that has been
+// fully repaid.
+type MarginLoanStatus string
+
+const (
+	MarginLoanOpen   MarginLoanStatus = "open"
+	MarginLoanClosed MarginLoanStatus = "closed"
+)
+
+// MarginLoan records a single margin (신용) loan issuance against an account.
+type MarginLoan struct {
+	AccountNo string
+	IssuedAt  time.Time
+	LoanID    string
+	Principal float64
+	Status    MarginLoanStatus
+	Symbol    string
+}
+
+// MarginInterest records one day's accrued interest on an account's outstanding
+// margin balance.
+type MarginInterest struct {
+	AccountNo string
+	Amount    float64
+	Date      time.Time
+	Rate      float64
+}
+
+// MarginRepayment records a principal/interest repayment applied against a
+// margin loan.
+type MarginRepayment struct {
+	AccountNo string
+	Amount    float64
+	Date      time.Time
+	LoanID    string
+}
@@ -0,0 +1,192 @@
+package domain
+
+import "time"
+
+// TradingHours describes a market's regular session window in its own local
+// timezone.
+type TradingHours struct {
+	// Location is the exchange's IANA timezone, e.g. "America/New_York".
+	Location string
+	// Open and Close are "15:04"-formatted local session boundaries.
+	Open  string
+	Close string
+}
+
+// MarketAdapter exposes the exchange-specific rules validate.IsPriceAnomaly
+// (and future per-market collectors) need, so adding a new exchange means
+// registering an adapter in MarketAdapters rather than adding another branch
+// to a market switch statement scattered across packages.
+type MarketAdapter interface {
+	// PriceLimit returns the maximum single-day percentage price move (as a
+	// fraction, e.g. 0.30 for 30%) this exchange tolerates for secType before
+	// validate.IsPriceAnomaly flags it.
+	PriceLimit(secType SecurityType) float64
+	// TradingHours returns the exchange's regular session window.
+	TradingHours() TradingHours
+	// HolidayCalendar returns this exchange's fixed-date holiday observances
+	// for year, in local exchange time. Like store.DefaultTradingCalendar,
+	// this only covers fixed-date observances; moving holidays (lunar new
+	// year, Golden Week, US Thanksgiving) aren't modeled.
+	HolidayCalendar(year int) []time.Time
+	// SymbolFormat documents the shape of this exchange's local ticker codes
+	// (e.g. "6-digit numeric" for KRX), for sources that need to validate or
+	// construct a symbol.
+	SymbolFormat() string
+}
+
+// usMarketAdapter covers both US exchanges this package registers (NASDAQ,
+// NYSE): same currency, timezone, holiday calendar, and price-limit
+// thresholds, just labeled separately so each can be looked up by its own
+// registry key.
+type usMarketAdapter struct {
+	exchange string
+}
+
+func (a usMarketAdapter) PriceLimit(secType SecurityType) float64 {
+	if secType == SecurityTypeETF {
+		return 0.15
+	}
+	return 0.50
+}
+
+func (a usMarketAdapter) TradingHours() TradingHours {
+	return TradingHours{Location: "America/New_York", Open: "09:30", Close: "16:00"}
+}
+
+func (a usMarketAdapter) HolidayCalendar(year int) []time.Time {
+	loc := mustLoadLocation("America/New_York")
+	return []time.Time{
+		time.Date(year, time.January, 1, 0, 0, 0, 0, loc),
+		time.Date(year, time.July, 4, 0, 0, 0, 0, loc),
+		time.Date(year, time.December, 25, 0, 0, 0, 0, loc),
+	}
+}
+
+func (a usMarketAdapter) SymbolFormat() string {
+	return a.exchange + ": 1-5 letter alphanumeric ticker (e.g. \"AAPL\", \"BRK-B\")"
+}
+
+// NewUSNasdaqAdapter returns the MarketAdapter for NASDAQ-listed symbols.
+func NewUSNasdaqAdapter() MarketAdapter { return usMarketAdapter{exchange: "NASDAQ"} }
+
+// NewUSNYSEAdapter returns the MarketAdapter for NYSE-listed symbols.
+func NewUSNYSEAdapter() MarketAdapter { return usMarketAdapter{exchange: "NYSE"} }
+
+// krMarketAdapter models KRX, where the exchange-wide 30% daily price limit
+// band applies uniformly to stocks and ETFs alike.
+type krMarketAdapter struct{}
+
+func (krMarketAdapter) PriceLimit(SecurityType) float64 { return 0.30 }
+
+func (krMarketAdapter) TradingHours() TradingHours {
+	return TradingHours{Location: "Asia/Seoul", Open: "09:00", Close: "15:30"}
+}
+
+func (krMarketAdapter) HolidayCalendar(year int) []time.Time {
+	loc := mustLoadLocation("Asia/Seoul")
+	return []time.Time{
+		time.Date(year, time.January, 1, 0, 0, 0, 0, loc),
+		time.Date(year, time.October, 3, 0, 0, 0, 0, loc), // National Foundation Day
+		time.Date(year, time.December, 25, 0, 0, 0, 0, loc),
+	}
+}
+
+func (krMarketAdapter) SymbolFormat() string {
+	return "KRX: 6-digit numeric code (e.g. \"005930\")"
+}
+
+// NewKRKRXAdapter returns the MarketAdapter for KRX-listed symbols.
+func NewKRKRXAdapter() MarketAdapter { return krMarketAdapter{} }
+
+// jpMarketAdapter models the Tokyo Stock Exchange. PriceLimit is a
+// conservative flat approximation: TSE's real limit is a reference-price-
+// dependent band table, not a flat percentage; 20% is wide enough to avoid
+// flagging a normal trading day without a full band table.
+type jpMarketAdapter struct{}
+
+func (jpMarketAdapter) PriceLimit(SecurityType) float64 { return 0.20 }
+
+func (jpMarketAdapter) TradingHours() TradingHours {
+	return TradingHours{Location: "Asia/Tokyo", Open: "09:00", Close: "15:00"}
+}
+
+func (jpMarketAdapter) HolidayCalendar(year int) []time.Time {
+	loc := mustLoadLocation("Asia/Tokyo")
+	return []time.Time{
+		time.Date(year, time.January, 1, 0, 0, 0, 0, loc),
+		time.Date(year, time.December, 31, 0, 0, 0, 0, loc),
+	}
+}
+
+func (jpMarketAdapter) SymbolFormat() string {
+	return "TSE: 4-digit numeric code (e.g. \"7203\")"
+}
+
+// NewJPTSEAdapter returns the MarketAdapter for TSE-listed symbols.
+func NewJPTSEAdapter() MarketAdapter { return jpMarketAdapter{} }
+
+// hkMarketAdapter models HKEX, which has no exchange-mandated daily price
+// limit; PriceLimit is a conservative fallback threshold so
+// validate.IsPriceAnomaly still has something to compare against, the same
+// role usMarketAdapter's 50% stock limit plays for US equities.
+type hkMarketAdapter struct{}
+
+func (hkMarketAdapter) PriceLimit(SecurityType) float64 { return 0.50 }
+
+func (hkMarketAdapter) TradingHours() TradingHours {
+	return TradingHours{Location: "Asia/Hong_Kong", Open: "09:30", Close: "16:00"}
+}
+
+func (hkMarketAdapter) HolidayCalendar(year int) []time.Time {
+	loc := mustLoadLocation("Asia/Hong_Kong")
+	return []time.Time{
+		time.Date(year, time.January, 1, 0, 0, 0, 0, loc),
+		time.Date(year, time.December, 25, 0, 0, 0, 0, loc),
+	}
+}
+
+func (hkMarketAdapter) SymbolFormat() string {
+	return "HKEX: 4-or-5-digit numeric code (e.g. \"0700\")"
+}
+
+// NewHKHKEXAdapter returns the MarketAdapter for HKEX-listed symbols.
+func NewHKHKEXAdapter() MarketAdapter { return hkMarketAdapter{} }
+
+// MarketAdapters maps registration keys to their MarketAdapter. Built once
+// since every adapter here is stateless.
+var MarketAdapters = map[string]MarketAdapter{
+	"us_nasdaq": NewUSNasdaqAdapter(),
+	"us_nyse":   NewUSNYSEAdapter(),
+	"kr_krx":    NewKRKRXAdapter(),
+	"jp_tse":    NewJPTSEAdapter(),
+	"hk_hkex":   NewHKHKEXAdapter(),
+}
+
+// MarketAdapterFor resolves a WatchlistEntry's coarse Market to its default
+// exchange adapter, for callers that only track country-level Market rather
+// than a specific exchange. US resolves to NASDAQ: WatchlistEntry carries no
+// NASDAQ-vs-NYSE distinction today, and the two share identical
+// price-limit/trading-hours/holiday behavior in this package, so the choice
+// between them is arbitrary.
+func MarketAdapterFor(market Market) MarketAdapter {
+	switch market {
+	case MarketKR:
+		return MarketAdapters["kr_krx"]
+	case MarketJP:
+		return MarketAdapters["jp_tse"]
+	case MarketHK:
+		return MarketAdapters["hk_hkex"]
+	default:
+		return MarketAdapters["us_nasdaq"]
+	}
+}
+
+// mustLoadLocation loads name, falling back to UTC if the timezone database
+// is unavailable, matching stream.NewUSMarketCalendar/NewKRMarketCalendar.
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,20 @@
+package domain
+
+// Period selects the bar granularity a fetcher requests from its upstream API
+// (e.g. KIS's FID_PERIOD_DIV_CODE).
+type Period string
+
+const (
+	PeriodDaily   Period = "D"
+	PeriodWeekly  Period = "W"
+	PeriodMonthly Period = "M"
+)
+
+// AdjustmentMode selects whether a fetch returns split/dividend-adjusted or raw
+// exchange-reported prices (e.g. KIS's FID_ORG_ADJ_PRC).
+type AdjustmentMode string
+
+const (
+	AdjAdjusted AdjustmentMode = "0"
+	AdjOriginal AdjustmentMode = "1"
+)
@@ -19,11 +19,22 @@ type DailyPrice struct {
 	Volume   int64
 }
 
-// FXRate stores a single-day foreign exchange rate (e.g. USD/KRW).
+// FXRate stores a single-day foreign exchange rate (e.g. USD/KRW). Source is the
+// provider whose rate was used; Sources lists every provider that reported a rate
+// for this date, so a single-provider collection run still sets Sources to
+// []string{Source}.
 type FXRate struct {
 	Date      time.Time
 	FetchedAt time.Time
+	IsAnomaly bool
 	Pair      string
 	Rate      float64
 	Source    string
+	Sources   []string
+}
+
+// Pair identifies a currency pair for multi-pair FX collection, e.g. {Base: "USD", Quote: "KRW"}.
+type Pair struct {
+	Base  string
+	Quote string
 }
@@ -0,0 +1,13 @@
+package domain
+
+// RetentionRule configures how long raw rows are kept in a source table
+// before being rolled up into a downsampled aggregate and deleted, and how
+// long that aggregate itself is kept. Mirrors InfluxDB's retention-policy +
+// continuous-query model (EXTERNAL DOC 4, 7, 11), adapted to plain Postgres
+// tables since the collector has no TSDB retention engine of its own.
+type RetentionRule struct {
+	DownsampleTo        string
+	KeepDownsampledDays int
+	KeepRawDays         int
+	Table               string
+}
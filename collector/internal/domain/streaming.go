@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// Tick is a single real-time trade execution event from a market-data stream.
+type Tick struct {
+	Price     float64
+	Symbol    string
+	Timestamp time.Time
+	Volume    int64
+}
+
+// OrderBookLevel is one price/quantity pair on one side of an order book.
+type OrderBookLevel struct {
+	Price    float64
+	Quantity int64
+}
+
+// OrderBookSnapshot is a point-in-time view of bid/ask depth for one symbol.
+// Bids and Asks are ordered best-to-worst (Bids[0] is the highest bid,
+// Asks[0] the lowest ask).
+type OrderBookSnapshot struct {
+	Asks      []OrderBookLevel
+	Bids      []OrderBookLevel
+	Symbol    string
+	Timestamp time.Time
+}
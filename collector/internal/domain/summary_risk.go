@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// SummaryRiskMetric records one symbol's risk/return indicators (CAGR, max
+// drawdown, Sharpe, Sortino, Calmar) as computed during a single summary run,
+// so historical risk profiles can be queried without recomputing them from
+// price history. Fields mirror summary.SymbolIndicators' risk/return subset;
+// nil pointers mean the stat was gated out by too little price history.
+type SummaryRiskMetric struct {
+	AnnualizedVolatility  *float64
+	AsOf                  time.Time
+	CAGR                  *float64
+	Calmar                *float64
+	MaxDrawdown           *float64
+	MaxDrawdownPeakDate   *time.Time
+	MaxDrawdownTroughDate *time.Time
+	Sharpe                *float64
+	Sortino               *float64
+	Symbol                string
+}
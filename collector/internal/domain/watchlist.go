@@ -6,6 +6,8 @@ type Market string
 const (
 	MarketUS Market = "US"
 	MarketKR Market = "KR"
+	MarketJP Market = "JP"
+	MarketHK Market = "HK"
 )
 
 // SecurityType distinguishes individual stocks from ETFs.
@@ -17,10 +19,42 @@ const (
 	SecurityTypeETF   SecurityType = "etf"
 )
 
-// WatchlistEntry represents a single tracked symbol loaded from watchlist.json.
+// USDataSource selects which US-market data provider serves a given symbol.
+type USDataSource string
+
+const (
+	USDataSourceTiingo USDataSource = "tiingo"
+	USDataSourceAlpaca USDataSource = "alpaca"
+)
+
+// WatchlistEntry represents a single tracked symbol loaded from watchlist.json
+// (or watchlist.yaml).
 type WatchlistEntry struct {
 	Market Market
 	Name   string
+	// Source selects the US data provider for this entry. Ignored for KR entries,
+	// which always use KIS. Defaults to USDataSourceTiingo when unset.
+	Source USDataSource
 	Symbol string
-	Type   SecurityType
+	// TargetWeight is this symbol's target portfolio weight (0.0-1.0) for
+	// rebalancing. Zero means the symbol doesn't participate in rebalancing.
+	TargetWeight float64
+	Type         SecurityType
+
+	// Aliases maps a data source name (e.g. "tiingo", "kis") to the
+	// source-specific symbol for this entry, for sources whose local code
+	// differs from Symbol (e.g. Tiingo's "BRK-B" vs. a KIS local code). A
+	// source absent from Aliases is fetched using Symbol as-is.
+	Aliases map[string]string
+	// Currency is the instrument's trading currency (e.g. "USD", "KRW").
+	Currency string
+	// Enabled gates whether this entry participates in collection; defaults
+	// to true when unset in the source file. See FilterByEnabled.
+	Enabled bool
+	// ISIN is the instrument's ISO 6166 identifier, if known.
+	ISIN string
+	// LotSize is the minimum tradeable unit, in shares.
+	LotSize int64
+	// PriceTick is the minimum price increment the instrument trades in.
+	PriceTick float64
 }
@@ -0,0 +1,136 @@
+// Package fetcher provides ChainFetcher, which composes several
+// domain.StockDataFetcher implementations (e.g. tiingo, alpaca, yahoo) into one,
+// so a throttled or down provider doesn't stall collection for a symbol another
+// provider can still serve. It also provides Registry, which dispatches a
+// domain.Market to the richer domain.MarketDataFetcher that serves it.
+//
+// Why this doesn't wire providers per market today: a KR-side chain (Naver, DART)
+// would need adapter packages this repo doesn't have yet, and kis.Client already
+// implements domain.StockDataFetcher directly, so it has no need for a chain of
+// its own. This package is the US-side building block (alpaca + yahoo behind
+// tiingo); wiring it into cmd/collect's source registry is left for a follow-up
+// once a market actually needs the fallback.
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/httpclient"
+)
+
+// Provider pairs a domain.StockDataFetcher with the metadata ChainFetcher needs to
+// order and fall back between providers. InvalidTickerErr is this provider's own
+// "symbol not found" sentinel (e.g. tiingo.ErrTickerInvalid), checked via errors.Is
+// so ChainFetcher can fall back to the next provider without importing every
+// concrete provider package; leave it nil if the provider has none.
+type Provider struct {
+	Fetcher          domain.StockDataFetcher
+	InvalidTickerErr error
+	Name             string
+	Rank             int
+}
+
+// ChainFetcher tries a priority-ordered set of domain.StockDataFetcher providers
+// for a symbol: providers are queried in ascending Rank order, and on a date where
+// more than one provider reports data, the lowest-Rank provider's row wins. A
+// provider reporting an invalid ticker, rate limiting, or a timeout doesn't abort
+// the chain — it's treated as "try the next provider"; any other error is returned
+// immediately, since it's assumed to affect every provider equally (e.g. a
+// malformed symbol). Implements domain.StockDataFetcher.
+type ChainFetcher struct {
+	providers []Provider
+}
+
+// NewChainFetcher builds a ChainFetcher, sorting providers into ascending Rank
+// order so callers can register them in any order.
+func NewChainFetcher(providers ...Provider) *ChainFetcher {
+	sorted := append([]Provider(nil), providers...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Rank < sorted[j].Rank })
+	return &ChainFetcher{providers: sorted}
+}
+
+// FetchDailyPrices queries every provider in Rank order, merging the successful
+// results by date so a lower-ranked provider can still fill in dates none of the
+// higher-ranked providers covered. Returns an error as soon as one provider fails
+// with an error that doesn't trigger fallback, or when every provider was skipped
+// via fallback.
+func (f *ChainFetcher) FetchDailyPrices(ctx context.Context, symbol string, from, to time.Time) ([]domain.DailyPrice, error) {
+	return f.FetchDailyPricesWithAliases(ctx, symbol, nil, from, to)
+}
+
+// FetchDailyPricesWithAliases behaves like FetchDailyPrices, but resolves each
+// provider's symbol via aliases (keyed by Provider.Name) before falling back to
+// symbol. This lets a single logical domain.WatchlistEntry (whose Aliases field
+// feeds aliases here) resolve to source-specific symbols — e.g. Tiingo's
+// "BRK-B" vs. a KIS local code — without a duplicate watchlist row per source.
+func (f *ChainFetcher) FetchDailyPricesWithAliases(ctx context.Context, symbol string, aliases map[string]string, from, to time.Time) ([]domain.DailyPrice, error) {
+	var perProvider [][]domain.DailyPrice
+	var fallbackErrs error
+
+	for _, p := range f.providers {
+		providerSymbol := symbol
+		if alias, ok := aliases[p.Name]; ok && alias != "" {
+			providerSymbol = alias
+		}
+
+		prices, err := p.Fetcher.FetchDailyPrices(ctx, providerSymbol, from, to)
+		if err != nil {
+			if !shouldFallback(err, p.InvalidTickerErr) {
+				return nil, fmt.Errorf("%s: %w", p.Name, err)
+			}
+			slog.Warn("provider unavailable, falling back", "error", err, "provider", p.Name, "symbol", symbol)
+			fallbackErrs = errors.Join(fallbackErrs, fmt.Errorf("%s: %w", p.Name, err))
+			continue
+		}
+		perProvider = append(perProvider, prices)
+	}
+
+	if len(perProvider) == 0 {
+		return nil, fmt.Errorf("all providers exhausted for %s: %w", symbol, fallbackErrs)
+	}
+
+	return mergeByRank(perProvider), nil
+}
+
+// shouldFallback reports whether err should move the chain to the next provider
+// rather than abort it outright.
+func shouldFallback(err error, invalidTickerErr error) bool {
+	if invalidTickerErr != nil && errors.Is(err, invalidTickerErr) {
+		return true
+	}
+	return errors.Is(err, httpclient.ErrRateLimited) ||
+		errors.Is(err, httpclient.ErrTimeout) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// mergeByRank merges one []domain.DailyPrice per successful provider, in Rank
+// order (perProvider[0] is the highest-ranked provider that succeeded), into a
+// single series keyed by date. The highest-ranked provider to report a date wins;
+// lower-ranked providers only fill in dates none of the higher-ranked ones covered.
+func mergeByRank(perProvider [][]domain.DailyPrice) []domain.DailyPrice {
+	byDate := make(map[time.Time]domain.DailyPrice)
+	var dates []time.Time
+
+	for i := len(perProvider) - 1; i >= 0; i-- {
+		for _, p := range perProvider[i] {
+			if _, ok := byDate[p.Date]; !ok {
+				dates = append(dates, p.Date)
+			}
+			byDate[p.Date] = p
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	merged := make([]domain.DailyPrice, 0, len(dates))
+	for _, d := range dates {
+		merged = append(merged, byDate[d])
+	}
+	return merged
+}
@@ -0,0 +1,164 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/httpclient"
+)
+
+// stubFetcher is a domain.StockDataFetcher backed by a fixed result or error,
+// analogous to fx's stubProvider.
+type stubFetcher struct {
+	err       error
+	prices    []domain.DailyPrice
+	gotSymbol string
+}
+
+func (s *stubFetcher) FetchDailyPrices(ctx context.Context, symbol string, from, to time.Time) ([]domain.DailyPrice, error) {
+	s.gotSymbol = symbol
+	return s.prices, s.err
+}
+
+func priceOn(date time.Time, source string, close float64) domain.DailyPrice {
+	return domain.DailyPrice{Close: close, Date: date, Source: source}
+}
+
+func TestChainFetcher_FetchDailyPrices(t *testing.T) {
+	day1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("highest-rank provider wins outright", func(t *testing.T) {
+		primary := Provider{Fetcher: &stubFetcher{prices: []domain.DailyPrice{priceOn(day1, "alpaca", 100)}}, Name: "alpaca", Rank: 0}
+		secondary := Provider{Fetcher: &stubFetcher{prices: []domain.DailyPrice{priceOn(day1, "yahoo", 200)}}, Name: "yahoo", Rank: 1}
+
+		c := NewChainFetcher(secondary, primary)
+		prices, err := c.FetchDailyPrices(context.Background(), "AAPL", day1, day1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(prices) != 1 || prices[0].Source != "alpaca" {
+			t.Errorf("prices = %+v, want one row from alpaca", prices)
+		}
+	})
+
+	t.Run("falls back to next provider on ticker invalid", func(t *testing.T) {
+		errTickerInvalid := errors.New("ticker not found on alpaca")
+		primary := Provider{
+			Fetcher:          &stubFetcher{err: errTickerInvalid},
+			InvalidTickerErr: errTickerInvalid,
+			Name:             "alpaca",
+			Rank:             0,
+		}
+		secondary := Provider{Fetcher: &stubFetcher{prices: []domain.DailyPrice{priceOn(day1, "yahoo", 200)}}, Name: "yahoo", Rank: 1}
+
+		c := NewChainFetcher(primary, secondary)
+		prices, err := c.FetchDailyPrices(context.Background(), "AAPL", day1, day1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(prices) != 1 || prices[0].Source != "yahoo" {
+			t.Errorf("prices = %+v, want one row from yahoo", prices)
+		}
+	})
+
+	t.Run("falls back on rate limit", func(t *testing.T) {
+		primary := Provider{Fetcher: &stubFetcher{err: httpclient.ErrRateLimited}, Name: "alpaca", Rank: 0}
+		secondary := Provider{Fetcher: &stubFetcher{prices: []domain.DailyPrice{priceOn(day1, "yahoo", 200)}}, Name: "yahoo", Rank: 1}
+
+		c := NewChainFetcher(primary, secondary)
+		prices, err := c.FetchDailyPrices(context.Background(), "AAPL", day1, day1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(prices) != 1 || prices[0].Source != "yahoo" {
+			t.Errorf("prices = %+v, want one row from yahoo", prices)
+		}
+	})
+
+	t.Run("non-fallback error aborts the chain", func(t *testing.T) {
+		primary := Provider{Fetcher: &stubFetcher{err: errors.New("auth failure")}, Name: "alpaca", Rank: 0}
+		secondary := Provider{Fetcher: &stubFetcher{prices: []domain.DailyPrice{priceOn(day1, "yahoo", 200)}}, Name: "yahoo", Rank: 1}
+
+		c := NewChainFetcher(primary, secondary)
+		_, err := c.FetchDailyPrices(context.Background(), "AAPL", day1, day1)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+
+	t.Run("lower-rank provider fills in dates the primary is missing", func(t *testing.T) {
+		primary := Provider{Fetcher: &stubFetcher{prices: []domain.DailyPrice{priceOn(day1, "alpaca", 100)}}, Name: "alpaca", Rank: 0}
+		secondary := Provider{Fetcher: &stubFetcher{prices: []domain.DailyPrice{priceOn(day1, "yahoo", 200), priceOn(day2, "yahoo", 210)}}, Name: "yahoo", Rank: 1}
+
+		c := NewChainFetcher(primary, secondary)
+		prices, err := c.FetchDailyPrices(context.Background(), "AAPL", day1, day2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(prices) != 2 {
+			t.Fatalf("len(prices) = %d, want 2", len(prices))
+		}
+		if prices[0].Source != "alpaca" || prices[0].Close != 100 {
+			t.Errorf("prices[0] = %+v, want alpaca/100", prices[0])
+		}
+		if prices[1].Source != "yahoo" || prices[1].Close != 210 {
+			t.Errorf("prices[1] = %+v, want yahoo/210", prices[1])
+		}
+	})
+
+	t.Run("all providers exhausted returns joined error", func(t *testing.T) {
+		primary := Provider{Fetcher: &stubFetcher{err: httpclient.ErrRateLimited}, Name: "alpaca", Rank: 0}
+		secondary := Provider{Fetcher: &stubFetcher{err: httpclient.ErrTimeout}, Name: "yahoo", Rank: 1}
+
+		c := NewChainFetcher(primary, secondary)
+		_, err := c.FetchDailyPrices(context.Background(), "AAPL", day1, day1)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !errors.Is(err, httpclient.ErrRateLimited) || !errors.Is(err, httpclient.ErrTimeout) {
+			t.Errorf("err = %v, want to wrap both provider errors", err)
+		}
+	})
+}
+
+func TestChainFetcher_FetchDailyPricesWithAliases(t *testing.T) {
+	day1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("resolves a per-provider alias", func(t *testing.T) {
+		tiingo := &stubFetcher{prices: []domain.DailyPrice{priceOn(day1, "tiingo", 100)}}
+		yahoo := &stubFetcher{prices: []domain.DailyPrice{priceOn(day1, "yahoo", 100)}}
+
+		c := NewChainFetcher(
+			Provider{Fetcher: tiingo, Name: "tiingo", Rank: 0},
+			Provider{Fetcher: yahoo, Name: "yahoo", Rank: 1},
+		)
+
+		_, err := c.FetchDailyPricesWithAliases(context.Background(), "BRK.B", map[string]string{"tiingo": "BRK-B"}, day1, day1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tiingo.gotSymbol != "BRK-B" {
+			t.Errorf("tiingo.gotSymbol = %q, want %q", tiingo.gotSymbol, "BRK-B")
+		}
+		if yahoo.gotSymbol != "BRK.B" {
+			t.Errorf("yahoo.gotSymbol = %q, want %q (no alias, falls back to symbol)", yahoo.gotSymbol, "BRK.B")
+		}
+	})
+
+	t.Run("FetchDailyPrices uses the symbol as-is with no aliases", func(t *testing.T) {
+		tiingo := &stubFetcher{prices: []domain.DailyPrice{priceOn(day1, "tiingo", 100)}}
+		c := NewChainFetcher(Provider{Fetcher: tiingo, Name: "tiingo", Rank: 0})
+
+		_, err := c.FetchDailyPrices(context.Background(), "AAPL", day1, day1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tiingo.gotSymbol != "AAPL" {
+			t.Errorf("tiingo.gotSymbol = %q, want %q", tiingo.gotSymbol, "AAPL")
+		}
+	})
+}
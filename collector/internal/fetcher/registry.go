@@ -0,0 +1,41 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// ErrMarketNotRegistered is returned by Registry.Get when no
+// domain.MarketDataFetcher has been registered for the requested market.
+var ErrMarketNotRegistered = errors.New("fetcher: no MarketDataFetcher registered for market")
+
+// Registry dispatches a domain.Market to the domain.MarketDataFetcher that
+// serves it, so cmd/collect can route each domain.WatchlistEntry to the right
+// backend (e.g. MarketUS -> alpaca, MarketKR -> kis) without a growing
+// switch statement.
+type Registry struct {
+	fetchers map[domain.Market]domain.MarketDataFetcher
+}
+
+// NewRegistry builds an empty Registry; call Register to populate it.
+func NewRegistry() *Registry {
+	return &Registry{fetchers: make(map[domain.Market]domain.MarketDataFetcher)}
+}
+
+// Register associates market with fetcher, overwriting any prior registration
+// for the same market.
+func (r *Registry) Register(market domain.Market, fetcher domain.MarketDataFetcher) {
+	r.fetchers[market] = fetcher
+}
+
+// Get returns the domain.MarketDataFetcher registered for market, or
+// ErrMarketNotRegistered if none was registered.
+func (r *Registry) Get(market domain.Market) (domain.MarketDataFetcher, error) {
+	fetcher, ok := r.fetchers[market]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMarketNotRegistered, market)
+	}
+	return fetcher, nil
+}
@@ -0,0 +1,66 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// stubMarketFetcher is a domain.MarketDataFetcher backed by fixed results,
+// analogous to stubFetcher in chain_test.go.
+type stubMarketFetcher struct{}
+
+func (s *stubMarketFetcher) FetchDailyPrices(ctx context.Context, symbol string, from, to time.Time) ([]domain.DailyPrice, error) {
+	return nil, nil
+}
+
+func (s *stubMarketFetcher) FetchBenchmark(ctx context.Context, market domain.Market, from, to time.Time) ([]domain.DailyPrice, error) {
+	return nil, nil
+}
+
+func (s *stubMarketFetcher) FetchSplitsAndDividends(ctx context.Context, symbol string, from, to time.Time) ([]domain.CorporateAction, error) {
+	return nil, nil
+}
+
+func TestRegistry_Get(t *testing.T) {
+	t.Run("returns the registered fetcher for a market", func(t *testing.T) {
+		us := &stubMarketFetcher{}
+		r := NewRegistry()
+		r.Register(domain.MarketUS, us)
+
+		got, err := r.Get(domain.MarketUS)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != us {
+			t.Error("Get returned a different fetcher than registered")
+		}
+	})
+
+	t.Run("unregistered market returns ErrMarketNotRegistered", func(t *testing.T) {
+		r := NewRegistry()
+		_, err := r.Get(domain.MarketKR)
+		if !errors.Is(err, ErrMarketNotRegistered) {
+			t.Errorf("err = %v, want ErrMarketNotRegistered", err)
+		}
+	})
+
+	t.Run("a later Register overwrites the earlier one for the same market", func(t *testing.T) {
+		first := &stubMarketFetcher{}
+		second := &stubMarketFetcher{}
+		r := NewRegistry()
+		r.Register(domain.MarketUS, first)
+		r.Register(domain.MarketUS, second)
+
+		got, err := r.Get(domain.MarketUS)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != second {
+			t.Error("Get returned the first-registered fetcher, want the overwriting one")
+		}
+	})
+}
@@ -10,10 +10,42 @@ import (
 
 	"github.com/jusikbot/collector/internal/domain"
 	"github.com/jusikbot/collector/internal/httpclient"
+	"github.com/jusikbot/collector/internal/ratelimit"
 )
 
 const sourceName = "frankfurter"
 
+// ErrCurrencyMissing indicates the target currency was absent from
+// Frankfurter's response, e.g. because the free tier doesn't publish that
+// cross-rate directly. FetchRates returns it so WithTriangulation can detect
+// when to fall back to FetchRatesVia.
+var ErrCurrencyMissing = errors.New("target currency missing in frankfurter response")
+
+// Provider abstracts a single FX rate source so Collector can reconcile rates
+// across several of them. Client implements it for Frankfurter; additional
+// providers (e.g. an ECB or KIS overseas-FX client) plug in the same way.
+type Provider interface {
+	// Name identifies this provider in domain.FXRate.Source/Sources.
+	Name() string
+	FetchRates(ctx context.Context, base, target string, from, to time.Time, opts ...FetchRatesOption) ([]domain.FXRate, error)
+}
+
+// FetchRatesOption configures a single FetchRates call.
+type FetchRatesOption func(*fetchRatesConfig)
+
+type fetchRatesConfig struct {
+	triangulateVia string
+}
+
+// WithTriangulation makes FetchRates fall back to FetchRatesVia(via) when the
+// direct base/target pair comes back with ErrCurrencyMissing. Typical via is
+// "USD" or "EUR".
+func WithTriangulation(via string) FetchRatesOption {
+	return func(c *fetchRatesConfig) {
+		c.triangulateVia = via
+	}
+}
+
 type frankfurterResponse struct {
 	Amount    float64                          `json:"amount"`
 	Base      string                           `json:"base"`
@@ -33,8 +65,29 @@ func NewClient(httpClient *httpclient.Client) *Client {
 	return &Client{http: httpClient}
 }
 
-// FetchRates returns rates sorted by date ascending.
-func (c *Client) FetchRates(ctx context.Context, base, target string, from, to time.Time) ([]domain.FXRate, error) {
+// Name identifies this provider as "frankfurter" in reconciled FXRate rows.
+func (c *Client) Name() string {
+	return sourceName
+}
+
+// FetchRates returns rates sorted by date ascending. With WithTriangulation(via),
+// a direct fetch that fails with ErrCurrencyMissing falls back to
+// FetchRatesVia(via) instead of returning the error.
+func (c *Client) FetchRates(ctx context.Context, base, target string, from, to time.Time, opts ...FetchRatesOption) ([]domain.FXRate, error) {
+	var cfg fetchRatesConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	rates, err := c.fetchDirectRates(ctx, base, target, from, to)
+	if err != nil && cfg.triangulateVia != "" && errors.Is(err, ErrCurrencyMissing) {
+		return c.FetchRatesVia(ctx, base, target, cfg.triangulateVia, from, to)
+	}
+	return rates, err
+}
+
+// fetchDirectRates fetches the direct base/target series from Frankfurter.
+func (c *Client) fetchDirectRates(ctx context.Context, base, target string, from, to time.Time) ([]domain.FXRate, error) {
 	path := fmt.Sprintf("/v1/%s..%s", from.Format("2006-01-02"), to.Format("2006-01-02"))
 
 	body, _, err := c.http.Get(ctx, path,
@@ -56,7 +109,7 @@ func (c *Client) FetchRates(ctx context.Context, base, target string, from, to t
 	for dateStr, currencies := range resp.Rates {
 		rate, ok := currencies[target]
 		if !ok {
-			return nil, fmt.Errorf("target currency %s missing in rates for %s", target, dateStr)
+			return nil, fmt.Errorf("%s for %s: %w", target, dateStr, ErrCurrencyMissing)
 		}
 
 		date, err := time.Parse("2006-01-02", dateStr)
@@ -79,12 +132,68 @@ func (c *Client) FetchRates(ctx context.Context, base, target string, from, to t
 	return rates, nil
 }
 
+// FetchRatesVia triangulates base/target through an intermediate currency via,
+// fetching base/via and via/target in parallel, joining them on date, and
+// emitting Rate = (base/via rate) * (via/target rate) for every date present
+// in both legs. Use this directly, or let FetchRates fall back to it
+// automatically via WithTriangulation.
+func (c *Client) FetchRatesVia(ctx context.Context, base, target, via string, from, to time.Time) ([]domain.FXRate, error) {
+	type legResult struct {
+		rates []domain.FXRate
+		err   error
+	}
+
+	baseViaCh := make(chan legResult, 1)
+	viaTargetCh := make(chan legResult, 1)
+
+	go func() {
+		rates, err := c.fetchDirectRates(ctx, base, via, from, to)
+		baseViaCh <- legResult{rates: rates, err: err}
+	}()
+	go func() {
+		rates, err := c.fetchDirectRates(ctx, via, target, from, to)
+		viaTargetCh <- legResult{rates: rates, err: err}
+	}()
+
+	baseVia, viaTarget := <-baseViaCh, <-viaTargetCh
+	if baseVia.err != nil {
+		return nil, fmt.Errorf("fetch %s/%s leg for triangulation via %s: %w", base, via, via, baseVia.err)
+	}
+	if viaTarget.err != nil {
+		return nil, fmt.Errorf("fetch %s/%s leg for triangulation via %s: %w", via, target, via, viaTarget.err)
+	}
+
+	viaTargetByDate := make(map[string]float64, len(viaTarget.rates))
+	for _, r := range viaTarget.rates {
+		viaTargetByDate[r.Date.Format("2006-01-02")] = r.Rate
+	}
+
+	pair := base + "/" + target
+	source := "frankfurter:triangulated:" + via
+	rates := make([]domain.FXRate, 0, len(baseVia.rates))
+	for _, r := range baseVia.rates {
+		viaRate, ok := viaTargetByDate[r.Date.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+
+		rates = append(rates, domain.FXRate{
+			Date:   r.Date,
+			Pair:   pair,
+			Rate:   r.Rate * viaRate,
+			Source: source,
+		})
+	}
+
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].Date.Before(rates[j].Date)
+	})
+
+	return rates, nil
+}
+
 // IsRetryable classifies errors for retry decisions.
 // Why not retry 4xx: client errors indicate permanent failures (bad request, not found).
 func IsRetryable(err error) bool {
-	var apiErr *httpclient.APIError
-	if errors.As(err, &apiErr) {
-		return apiErr.IsRetryable
-	}
-	return false
+	return ratelimit.DefaultIsRetryable(err)
 }
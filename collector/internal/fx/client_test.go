@@ -3,6 +3,7 @@ package fx
 import (
 	"context"
 	"errors"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -16,6 +17,16 @@ func newTestClient(srv *httptest.Server) *Client {
 	return NewClient(hc)
 }
 
+// assertAlmostEqual compares two float64s within tolerance, to tolerate the
+// one-ULP drift between a compile-time-folded constant expression and the
+// same product computed at runtime from two already-rounded float64 legs.
+func assertAlmostEqual(t *testing.T, got, want, tolerance float64, label string) {
+	t.Helper()
+	if math.Abs(got-want) > tolerance {
+		t.Errorf("%s = %v, want %v", label, got, want)
+	}
+}
+
 func TestFetchRates(t *testing.T) {
 	t.Run("normal JSON response", func(t *testing.T) {
 		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -125,6 +136,36 @@ func TestFetchRates(t *testing.T) {
 		}
 	})
 
+	t.Run("rate limited 429 surfaces Retry-After", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+		_, err := client.FetchRates(context.Background(), "USD", "KRW", from, to)
+		if err == nil {
+			t.Fatal("expected error for 429 response")
+		}
+		var apiErr *httpclient.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("expected APIError, got: %T", err)
+		}
+		if apiErr.StatusCode != 429 {
+			t.Errorf("StatusCode = %d, want 429", apiErr.StatusCode)
+		}
+		if apiErr.RetryAfter != 2*time.Second {
+			t.Errorf("RetryAfter = %v, want 2s", apiErr.RetryAfter)
+		}
+		if !IsRetryable(err) {
+			t.Error("429 with Retry-After should still be IsRetryable")
+		}
+	})
+
 	t.Run("bad request 400", func(t *testing.T) {
 		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusBadRequest)
@@ -165,6 +206,76 @@ func TestFetchRates(t *testing.T) {
 		if err == nil {
 			t.Fatal("expected error when target currency missing from rates")
 		}
+		if !errors.Is(err, ErrCurrencyMissing) {
+			t.Errorf("expected ErrCurrencyMissing, got: %v", err)
+		}
+	})
+
+	t.Run("triangulates via an intermediate currency when the direct pair is unavailable", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			base := r.URL.Query().Get("from")
+			target := r.URL.Query().Get("to")
+			w.Header().Set("Content-Type", "application/json")
+
+			switch {
+			case base == "USD" && target == "KRW":
+				// Direct pair unsupported: target currency absent from rates.
+				w.Write([]byte(`{
+					"amount": 1,
+					"base": "USD",
+					"start_date": "2025-01-01",
+					"end_date": "2025-01-02",
+					"rates": {
+						"2025-01-02": {}
+					}
+				}`))
+			case base == "USD" && target == "EUR":
+				w.Write([]byte(`{
+					"amount": 1,
+					"base": "USD",
+					"start_date": "2025-01-01",
+					"end_date": "2025-01-02",
+					"rates": {
+						"2025-01-02": {"EUR": 0.92}
+					}
+				}`))
+			case base == "EUR" && target == "KRW":
+				w.Write([]byte(`{
+					"amount": 1,
+					"base": "EUR",
+					"start_date": "2025-01-01",
+					"end_date": "2025-01-02",
+					"rates": {
+						"2025-01-02": {"KRW": 1594.20}
+					}
+				}`))
+			default:
+				t.Errorf("unexpected leg requested: %s/%s", base, target)
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+		rates, err := client.FetchRates(context.Background(), "USD", "KRW", from, to, WithTriangulation("EUR"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rates) != 1 {
+			t.Fatalf("len(rates) = %d, want 1", len(rates))
+		}
+
+		want := 0.92 * 1594.20
+		assertAlmostEqual(t, rates[0].Rate, want, 1e-9, "rates[0].Rate")
+		if rates[0].Pair != "USD/KRW" {
+			t.Errorf("rates[0].Pair = %q, want USD/KRW", rates[0].Pair)
+		}
+		if rates[0].Source != "frankfurter:triangulated:EUR" {
+			t.Errorf("rates[0].Source = %q, want frankfurter:triangulated:EUR", rates[0].Source)
+		}
 	})
 
 	t.Run("malformed JSON response", func(t *testing.T) {
@@ -222,6 +333,65 @@ func TestFetchRates(t *testing.T) {
 	})
 }
 
+func TestFetchRatesVia(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		base := r.URL.Query().Get("from")
+		target := r.URL.Query().Get("to")
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case base == "USD" && target == "EUR":
+			w.Write([]byte(`{
+				"amount": 1,
+				"base": "USD",
+				"start_date": "2025-01-01",
+				"end_date": "2025-01-02",
+				"rates": {
+					"2025-01-01": {"EUR": 0.90},
+					"2025-01-02": {"EUR": 0.92}
+				}
+			}`))
+		case base == "EUR" && target == "KRW":
+			w.Write([]byte(`{
+				"amount": 1,
+				"base": "EUR",
+				"start_date": "2025-01-01",
+				"end_date": "2025-01-02",
+				"rates": {
+					"2025-01-02": {"KRW": 1594.20}
+				}
+			}`))
+		default:
+			t.Errorf("unexpected leg requested: %s/%s", base, target)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	rates, err := client.FetchRatesVia(context.Background(), "USD", "KRW", "EUR", from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 2025-01-01 is dropped: it has no EUR/KRW leg to join against.
+	if len(rates) != 1 {
+		t.Fatalf("len(rates) = %d, want 1", len(rates))
+	}
+
+	want := 0.92 * 1594.20
+	assertAlmostEqual(t, rates[0].Rate, want, 1e-9, "rates[0].Rate")
+	if rates[0].Pair != "USD/KRW" {
+		t.Errorf("rates[0].Pair = %q, want USD/KRW", rates[0].Pair)
+	}
+	if rates[0].Source != "frankfurter:triangulated:EUR" {
+		t.Errorf("rates[0].Source = %q, want frankfurter:triangulated:EUR", rates[0].Source)
+	}
+}
+
 func TestIsRetryable(t *testing.T) {
 	tests := []struct {
 		name string
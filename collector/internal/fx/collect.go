@@ -2,34 +2,51 @@ package fx
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/jusikbot/collector/internal/domain"
 	"github.com/jusikbot/collector/internal/ratelimit"
 )
 
-const defaultLookbackDays = 365
+const (
+	defaultChunkDays    = 365
+	defaultLookbackDays = 365
+	maxConcurrentPairs  = 4
+)
 
-// Collector orchestrates FX rate collection with retry support.
+// Collector orchestrates FX rate collection with retry support. providers[0] is
+// primary: its rate wins per date whenever reported; later providers only fill
+// gaps and cross-check the primary (see reconcileRates).
 type Collector struct {
-	client   *Client
-	retryCfg ratelimit.RetryConfig
+	// breakers holds one CircuitBreaker per provider (keyed by Provider.Name), so
+	// a fully-down provider short-circuits its own retries without affecting the
+	// others still being fanned out to in fetchAndReconcile.
+	breakers                 *ratelimit.Group
+	disagreementThresholdBps float64
+	providers                []Provider
+	retryCfg                 ratelimit.RetryConfig
 
 	// Why injectable: enables deterministic testing without time-dependent flakiness.
 	now func() time.Time
 }
 
-func NewCollector(client *Client, retryCfg ratelimit.RetryConfig) *Collector {
+func NewCollector(providers []Provider, retryCfg ratelimit.RetryConfig, disagreementThresholdBps float64, breakers *ratelimit.Group) *Collector {
 	return &Collector{
-		client:   client,
-		now:      time.Now,
-		retryCfg: retryCfg,
+		breakers:                 breakers,
+		disagreementThresholdBps: disagreementThresholdBps,
+		now:                      time.Now,
+		providers:                providers,
+		retryCfg:                 retryCfg,
 	}
 }
 
-// CollectFX returns nil when data is already up to date.
+// CollectFX is a thin wrapper draining CollectFXStream into a materialized slice,
+// kept for callers that want the full range in one shot. Returns nil when data is
+// already up to date.
 func (c *Collector) CollectFX(
 	ctx context.Context,
 	base, target string,
@@ -39,36 +56,186 @@ func (c *Collector) CollectFX(
 		return nil, ctx.Err()
 	}
 
+	out := make(chan domain.FXRate)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.CollectFXStream(ctx, base, target, gaps, out)
+	}()
+
+	var rates []domain.FXRate
+	for r := range out {
+		rates = append(rates, r)
+	}
+
+	if err := <-errCh; err != nil {
+		return nil, err
+	}
+
+	if len(rates) > 0 {
+		slog.Info("fx collected", "pair", base+"/"+target, "rows", len(rates))
+	}
+
+	return rates, nil
+}
+
+// CollectFXStream chunks the gap-adjusted [from, to] range into windows of
+// defaultChunkDays and emits rates on out as each chunk resolves, closing out when
+// done (on success, error, or context cancellation) so callers can always range
+// over it. This lets a multi-year backfill overlap rate-limited fetches with
+// downstream persistence instead of blocking on one materialized response.
+func (c *Collector) CollectFXStream(
+	ctx context.Context,
+	base, target string,
+	gaps map[string]time.Time,
+	out chan<- domain.FXRate,
+) error {
+	defer close(out)
+
 	pair := base + "/" + target
 	to := c.now().Truncate(24 * time.Hour)
 	from := computeStartDate(to, gaps, pair)
 
 	if !from.Before(to) {
 		slog.Info("fx already up to date", "pair", pair)
-		return nil, nil
+		return nil
 	}
 
-	rates, err := ratelimit.WithRetry(ctx, c.retryCfg, IsRetryable,
-		func(ctx context.Context) ([]domain.FXRate, error) {
-			return c.client.FetchRates(ctx, base, target, from, to)
-		},
-	)
-	if err != nil {
-		return nil, fmt.Errorf("collect fx %s: %w", pair, err)
+	for chunkStart := from; chunkStart.Before(to); chunkStart = chunkStart.AddDate(0, 0, defaultChunkDays) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		chunkEnd := chunkStart.AddDate(0, 0, defaultChunkDays)
+		if chunkEnd.After(to) {
+			chunkEnd = to
+		}
+
+		rates, err := c.fetchAndReconcile(ctx, pair, base, target, chunkStart, chunkEnd)
+		if err != nil {
+			return fmt.Errorf("collect fx %s chunk %s..%s: %w",
+				pair, chunkStart.Format("2006-01-02"), chunkEnd.Format("2006-01-02"), err)
+		}
+
+		for _, r := range rates {
+			select {
+			case out <- r:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
 	}
 
-	slog.Info("fx collected", "pair", pair, "rows", len(rates))
-	return rates, nil
+	return nil
+}
+
+// fetchAndReconcile fetches [from, to] from every provider concurrently, retrying
+// each independently, and reconciles the results into one series via
+// reconcileRates. A provider that errors out is logged and excluded; only when
+// every provider fails does this return an error.
+func (c *Collector) fetchAndReconcile(ctx context.Context, pair, base, target string, from, to time.Time) ([]domain.FXRate, error) {
+	rateSets := make([][]domain.FXRate, len(c.providers))
+	errs := make([]error, len(c.providers))
+
+	var wg sync.WaitGroup
+	for i, p := range c.providers {
+		wg.Add(1)
+		go func(i int, p Provider) {
+			defer wg.Done()
+			rates, err := ratelimit.WithRetryCB(ctx, c.breakers.Get(p.Name()), c.retryCfg, IsRetryable,
+				func(ctx context.Context) ([]domain.FXRate, error) {
+					return p.FetchRates(ctx, base, target, from, to)
+				},
+			)
+			rateSets[i], errs[i] = rates, err
+		}(i, p)
+	}
+	wg.Wait()
+
+	providerNames := make([]string, len(c.providers))
+	var failed int
+	for i, p := range c.providers {
+		providerNames[i] = p.Name()
+		if errs[i] != nil {
+			slog.Warn("fx provider fetch failed", "error", errs[i], "pair", pair, "provider", providerNames[i])
+			failed++
+		}
+	}
+	if failed == len(c.providers) {
+		return nil, fmt.Errorf("all fx providers failed for %s: %w", pair, errors.Join(errs...))
+	}
+
+	return reconcileRates(pair, providerNames, rateSets, c.disagreementThresholdBps), nil
+}
+
+// CollectFXMulti fans out CollectFXStream across pairs with a bounded worker pool
+// (maxConcurrentPairs), sharing retryCfg across all of them. Per-pair ordering by
+// date is preserved on the merged output channel since each pair's results are
+// forwarded by a single dedicated goroutine; ordering across different pairs is not
+// guaranteed. A failure on one pair is reported on the error channel without
+// stopping collection of the others. Both channels are closed once every pair has
+// finished or ctx is cancelled.
+func (c *Collector) CollectFXMulti(
+	ctx context.Context,
+	pairs []domain.Pair,
+	gaps map[string]time.Time,
+) (<-chan domain.FXRate, <-chan error) {
+	out := make(chan domain.FXRate)
+	errs := make(chan error, len(pairs))
+
+	go func() {
+		defer close(out)
+		defer close(errs)
+
+		sem := make(chan struct{}, maxConcurrentPairs)
+		var wg sync.WaitGroup
+
+		for _, pair := range pairs {
+			if ctx.Err() != nil {
+				break
+			}
+
+			sem <- struct{}{}
+			wg.Add(1)
+
+			go func(p domain.Pair) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				pairOut := make(chan domain.FXRate)
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if err := c.CollectFXStream(ctx, p.Base, p.Quote, gaps, pairOut); err != nil {
+						select {
+						case errs <- fmt.Errorf("collect fx %s/%s: %w", p.Base, p.Quote, err):
+						case <-ctx.Done():
+						}
+					}
+				}()
+
+				for r := range pairOut {
+					select {
+					case out <- r:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}(pair)
+		}
+
+		wg.Wait()
+	}()
+
+	return out, errs
 }
 
 func computeStartDate(to time.Time, gaps map[string]time.Time, pair string) time.Time {
-	from := to.AddDate(0, 0, -defaultLookbackDays)
 	if lastDate, ok := gaps[pair]; ok {
 		// Why +1 day: last recorded date is already in DB, start from next day.
-		candidate := lastDate.AddDate(0, 0, 1)
-		if candidate.After(from) {
-			from = candidate
-		}
+		// Always honor the gap, even one older than defaultLookbackDays: a
+		// multi-year-old gap is exactly the long-backfill case this is meant to
+		// resume, not something to clamp away.
+		return lastDate.AddDate(0, 0, 1)
 	}
-	return from
+	return to.AddDate(0, 0, -defaultLookbackDays)
 }
@@ -2,6 +2,7 @@ package fx
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -29,11 +30,11 @@ func TestCollectFX(t *testing.T) {
 		defer srv.Close()
 
 		fxClient := newTestClient(srv)
-		collector := NewCollector(fxClient, ratelimit.RetryConfig{
+		collector := NewCollector([]Provider{fxClient}, ratelimit.RetryConfig{
 			InitialBackoff: time.Millisecond,
 			MaxAttempts:    1,
 			MaxBackoff:     time.Millisecond,
-		})
+		}, DefaultDisagreementThresholdBps, ratelimit.NewGroup(ratelimit.DefaultCircuitBreakerConfig()))
 		collector.now = func() time.Time {
 			return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 		}
@@ -70,11 +71,11 @@ func TestCollectFX(t *testing.T) {
 		defer srv.Close()
 
 		fxClient := newTestClient(srv)
-		collector := NewCollector(fxClient, ratelimit.RetryConfig{
+		collector := NewCollector([]Provider{fxClient}, ratelimit.RetryConfig{
 			InitialBackoff: time.Millisecond,
 			MaxAttempts:    1,
 			MaxBackoff:     time.Millisecond,
-		})
+		}, DefaultDisagreementThresholdBps, ratelimit.NewGroup(ratelimit.DefaultCircuitBreakerConfig()))
 
 		// Simulate gap: last data was 2025-01-10, today is 2025-01-15
 		collector.now = func() time.Time {
@@ -105,11 +106,11 @@ func TestCollectFX(t *testing.T) {
 		defer srv.Close()
 
 		fxClient := newTestClient(srv)
-		collector := NewCollector(fxClient, ratelimit.RetryConfig{
+		collector := NewCollector([]Provider{fxClient}, ratelimit.RetryConfig{
 			InitialBackoff: time.Millisecond,
 			MaxAttempts:    1,
 			MaxBackoff:     time.Millisecond,
-		})
+		}, DefaultDisagreementThresholdBps, ratelimit.NewGroup(ratelimit.DefaultCircuitBreakerConfig()))
 
 		today := time.Now().Truncate(24 * time.Hour)
 		collector.now = func() time.Time { return today }
@@ -135,11 +136,11 @@ func TestCollectFX(t *testing.T) {
 		defer srv.Close()
 
 		fxClient := newTestClient(srv)
-		collector := NewCollector(fxClient, ratelimit.RetryConfig{
+		collector := NewCollector([]Provider{fxClient}, ratelimit.RetryConfig{
 			InitialBackoff: time.Millisecond,
 			MaxAttempts:    1,
 			MaxBackoff:     time.Millisecond,
-		})
+		}, DefaultDisagreementThresholdBps, ratelimit.NewGroup(ratelimit.DefaultCircuitBreakerConfig()))
 
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
@@ -166,11 +167,11 @@ func TestCollectFX(t *testing.T) {
 		defer srv.Close()
 
 		fxClient := newTestClient(srv)
-		collector := NewCollector(fxClient, ratelimit.RetryConfig{
+		collector := NewCollector([]Provider{fxClient}, ratelimit.RetryConfig{
 			InitialBackoff: time.Millisecond,
 			MaxAttempts:    1,
 			MaxBackoff:     time.Millisecond,
-		})
+		}, DefaultDisagreementThresholdBps, ratelimit.NewGroup(ratelimit.DefaultCircuitBreakerConfig()))
 
 		rates, err := collector.CollectFX(context.Background(), "USD", "KRW", make(map[string]time.Time))
 		if err != nil {
@@ -201,3 +202,57 @@ func TestCollectFX(t *testing.T) {
 		}
 	})
 }
+
+func TestFetchAndReconcile_BreakerIsolatedPerProvider(t *testing.T) {
+	primary := &stubProvider{name: "frankfurter", rates: []domain.FXRate{rateOn(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), 1380.00)}}
+	secondary := &failingProvider{name: "ecb"}
+
+	breakers := ratelimit.NewGroup(ratelimit.CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		OpenTimeout:         time.Hour,
+	})
+	c := NewCollector([]Provider{primary, secondary}, ratelimit.RetryConfig{
+		InitialBackoff: time.Millisecond,
+		MaxAttempts:    1,
+		MaxBackoff:     time.Millisecond,
+	}, DefaultDisagreementThresholdBps, breakers)
+
+	day := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	if _, err := c.fetchAndReconcile(context.Background(), "USD/KRW", "USD", "KRW", day, day); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if breakers.Get("ecb").State() != ratelimit.StateOpen {
+		t.Error("ecb breaker state = not Open, want Open after its sole provider call failed")
+	}
+	if breakers.Get("frankfurter").State() != ratelimit.StateClosed {
+		t.Error("frankfurter breaker state = not Closed, want unaffected by ecb's trip")
+	}
+
+	// A second call must short-circuit ecb via ErrCircuitOpen without invoking it again,
+	// while frankfurter keeps serving normally.
+	secondary.calls = 0
+	if _, err := c.fetchAndReconcile(context.Background(), "USD/KRW", "USD", "KRW", day, day); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secondary.calls != 0 {
+		t.Errorf("ecb FetchRates calls = %d, want 0 (breaker should short-circuit it)", secondary.calls)
+	}
+}
+
+// failingProvider always returns errFetchFailed, for exercising breaker trips.
+type failingProvider struct {
+	calls int
+	name  string
+}
+
+var errFetchFailed = errors.New("fetch failed")
+
+func (p *failingProvider) Name() string {
+	return p.name
+}
+
+func (p *failingProvider) FetchRates(ctx context.Context, base, target string, from, to time.Time, opts ...FetchRatesOption) ([]domain.FXRate, error) {
+	p.calls++
+	return nil, errFetchFailed
+}
@@ -0,0 +1,130 @@
+package fx
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/validate"
+)
+
+// DefaultDisagreementThresholdBps is the default per-date reconciliation threshold:
+// two providers disagreeing by more than this many basis points is logged as a warning.
+const DefaultDisagreementThresholdBps = 50.0
+
+// reconcileRates merges one []domain.FXRate slice per provider (providerNames[i]
+// sourced results[i]) into a single series, one row per date. providerNames[0] is
+// primary: its rate wins when present; later providers fill in only for dates the
+// primary is missing. Every provider that reported a value for a date is recorded
+// in Sources, and any two providers disagreeing by more than thresholdBps is logged.
+func reconcileRates(pair string, providerNames []string, results [][]domain.FXRate, thresholdBps float64) []domain.FXRate {
+	byDate := make(map[time.Time]map[string]float64)
+	var dates []time.Time
+
+	for i, providerRates := range results {
+		for _, r := range providerRates {
+			values, ok := byDate[r.Date]
+			if !ok {
+				values = make(map[string]float64)
+				byDate[r.Date] = values
+				dates = append(dates, r.Date)
+			}
+			values[providerNames[i]] = r.Rate
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	merged := make([]domain.FXRate, 0, len(dates))
+	for _, date := range dates {
+		values := byDate[date]
+		warnOnDisagreement(pair, date, values, thresholdBps)
+
+		source, rate := pickPrimary(providerNames, values)
+		merged = append(merged, domain.FXRate{
+			Date:    date,
+			Pair:    pair,
+			Rate:    rate,
+			Source:  source,
+			Sources: contributingSources(providerNames, values),
+		})
+	}
+
+	flagFXAnomalies(merged)
+
+	return merged
+}
+
+// flagFXAnomalies marks each entry in merged (ascending by Date) whose rate
+// jumped more than validate.FXRateAnomalyThreshold from the previous entry,
+// the same day-over-day check markAnomalies applies to equity prices.
+func flagFXAnomalies(merged []domain.FXRate) {
+	for i := 1; i < len(merged); i++ {
+		if validate.IsFXRateAnomaly(merged[i].Rate, merged[i-1].Rate) {
+			merged[i].IsAnomaly = true
+		}
+	}
+}
+
+// pickPrimary returns the first provider (in providerNames order) that reported a
+// value for this date, falling back from the primary to secondaries as needed.
+func pickPrimary(providerNames []string, values map[string]float64) (source string, rate float64) {
+	for _, name := range providerNames {
+		if v, ok := values[name]; ok {
+			return name, v
+		}
+	}
+	return "", 0
+}
+
+// contributingSources lists, in providerNames order, every provider that reported
+// a value for this date.
+func contributingSources(providerNames []string, values map[string]float64) []string {
+	sources := make([]string, 0, len(values))
+	for _, name := range providerNames {
+		if _, ok := values[name]; ok {
+			sources = append(sources, name)
+		}
+	}
+	return sources
+}
+
+// warnOnDisagreement logs every pair of providers whose rates for date differ by
+// more than thresholdBps, expressed as basis points of their average.
+func warnOnDisagreement(pair string, date time.Time, values map[string]float64, thresholdBps float64) {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i := 0; i < len(names); i++ {
+		for j := i + 1; j < len(names); j++ {
+			a, b := values[names[i]], values[names[j]]
+			deltaBps := bpsDelta(a, b)
+			if deltaBps > thresholdBps {
+				slog.Warn("fx providers disagree",
+					"date", date.Format("2006-01-02"),
+					"delta_bps", fmt.Sprintf("%.1f", deltaBps),
+					"pair", pair,
+					names[i], a,
+					names[j], b,
+				)
+			}
+		}
+	}
+}
+
+func bpsDelta(a, b float64) float64 {
+	mean := (a + b) / 2
+	if mean == 0 {
+		return 0
+	}
+	delta := a - b
+	if delta < 0 {
+		delta = -delta
+	}
+	return delta / mean * 10000
+}
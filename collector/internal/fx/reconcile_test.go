@@ -0,0 +1,186 @@
+package fx
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/ratelimit"
+)
+
+// stubProvider is a Provider backed by a fixed set of rates, analogous to
+// stubPriceReader in the price-history tests.
+type stubProvider struct {
+	name  string
+	rates []domain.FXRate
+}
+
+func (p *stubProvider) Name() string {
+	return p.name
+}
+
+func (p *stubProvider) FetchRates(ctx context.Context, base, target string, from, to time.Time, opts ...FetchRatesOption) ([]domain.FXRate, error) {
+	return p.rates, nil
+}
+
+func rateOn(date time.Time, rate float64) domain.FXRate {
+	return domain.FXRate{Date: date, Rate: rate}
+}
+
+func sourcesOf(rates []domain.FXRate) map[string][]string {
+	out := make(map[string][]string, len(rates))
+	for _, r := range rates {
+		sorted := append([]string(nil), r.Sources...)
+		sort.Strings(sorted)
+		out[r.Date.Format("2006-01-02")] = sorted
+	}
+	return out
+}
+
+func TestReconcileRates(t *testing.T) {
+	day1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("primary only", func(t *testing.T) {
+		results := [][]domain.FXRate{
+			{rateOn(day1, 1380.00), rateOn(day2, 1381.00)},
+			{},
+		}
+
+		merged := reconcileRates("USD/KRW", []string{"frankfurter", "ecb"}, results, DefaultDisagreementThresholdBps)
+
+		if len(merged) != 2 {
+			t.Fatalf("len(merged) = %d, want 2", len(merged))
+		}
+		for _, r := range merged {
+			if r.Source != "frankfurter" {
+				t.Errorf("rate for %s: Source = %q, want frankfurter", r.Date.Format("2006-01-02"), r.Source)
+			}
+			if len(r.Sources) != 1 || r.Sources[0] != "frankfurter" {
+				t.Errorf("rate for %s: Sources = %v, want [frankfurter]", r.Date.Format("2006-01-02"), r.Sources)
+			}
+		}
+	})
+
+	t.Run("primary missing falls back to secondary", func(t *testing.T) {
+		results := [][]domain.FXRate{
+			{rateOn(day1, 1380.00)},
+			{rateOn(day1, 1380.10), rateOn(day2, 1381.50)},
+		}
+
+		merged := reconcileRates("USD/KRW", []string{"frankfurter", "ecb"}, results, DefaultDisagreementThresholdBps)
+
+		if len(merged) != 2 {
+			t.Fatalf("len(merged) = %d, want 2", len(merged))
+		}
+
+		byDate := make(map[string]domain.FXRate, len(merged))
+		for _, r := range merged {
+			byDate[r.Date.Format("2006-01-02")] = r
+		}
+
+		day1Rate := byDate["2025-01-01"]
+		if day1Rate.Source != "frankfurter" || day1Rate.Rate != 1380.00 {
+			t.Errorf("day1: Source = %q, Rate = %v, want frankfurter/1380.00", day1Rate.Source, day1Rate.Rate)
+		}
+
+		day2Rate := byDate["2025-01-02"]
+		if day2Rate.Source != "ecb" || day2Rate.Rate != 1381.50 {
+			t.Errorf("day2: Source = %q, Rate = %v, want ecb/1381.50", day2Rate.Source, day2Rate.Rate)
+		}
+	})
+
+	t.Run("disagreement within threshold does not affect the pick", func(t *testing.T) {
+		results := [][]domain.FXRate{
+			{rateOn(day1, 1380.00)},
+			{rateOn(day1, 1380.50)},
+		}
+
+		merged := reconcileRates("USD/KRW", []string{"frankfurter", "ecb"}, results, DefaultDisagreementThresholdBps)
+
+		if len(merged) != 1 {
+			t.Fatalf("len(merged) = %d, want 1", len(merged))
+		}
+		if merged[0].Source != "frankfurter" || merged[0].Rate != 1380.00 {
+			t.Errorf("Source = %q, Rate = %v, want frankfurter/1380.00", merged[0].Source, merged[0].Rate)
+		}
+
+		sources := sourcesOf(merged)["2025-01-01"]
+		if len(sources) != 2 || sources[0] != "ecb" || sources[1] != "frankfurter" {
+			t.Errorf("Sources = %v, want [ecb frankfurter]", sources)
+		}
+	})
+
+	t.Run("disagreement exceeding threshold still picks primary", func(t *testing.T) {
+		results := [][]domain.FXRate{
+			{rateOn(day1, 1380.00)},
+			{rateOn(day1, 1450.00)},
+		}
+
+		merged := reconcileRates("USD/KRW", []string{"frankfurter", "ecb"}, results, DefaultDisagreementThresholdBps)
+
+		if len(merged) != 1 {
+			t.Fatalf("len(merged) = %d, want 1", len(merged))
+		}
+		if merged[0].Source != "frankfurter" || merged[0].Rate != 1380.00 {
+			t.Errorf("Source = %q, Rate = %v, want frankfurter/1380.00", merged[0].Source, merged[0].Rate)
+		}
+
+		sources := sourcesOf(merged)["2025-01-01"]
+		if len(sources) != 2 {
+			t.Errorf("Sources = %v, want both providers recorded despite disagreement", sources)
+		}
+	})
+
+	t.Run("day-over-day jump past threshold is flagged anomalous", func(t *testing.T) {
+		day3 := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+		results := [][]domain.FXRate{
+			{rateOn(day1, 1380.00), rateOn(day2, 1480.00), rateOn(day3, 1481.00)},
+			{},
+		}
+
+		merged := reconcileRates("USD/KRW", []string{"frankfurter", "ecb"}, results, DefaultDisagreementThresholdBps)
+
+		if len(merged) != 3 {
+			t.Fatalf("len(merged) = %d, want 3", len(merged))
+		}
+		if merged[0].IsAnomaly {
+			t.Error("day1: IsAnomaly = true, want false (no previous day to compare)")
+		}
+		if !merged[1].IsAnomaly {
+			t.Error("day2: IsAnomaly = false, want true (+7.2% exceeds FXRateAnomalyThreshold)")
+		}
+		if merged[2].IsAnomaly {
+			t.Error("day3: IsAnomaly = true, want false (+0.07% over day2 stays within threshold)")
+		}
+	})
+}
+
+func TestFetchAndReconcile(t *testing.T) {
+	day1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	primary := &stubProvider{name: "frankfurter", rates: []domain.FXRate{rateOn(day1, 1380.00)}}
+	secondary := &stubProvider{name: "ecb", rates: []domain.FXRate{rateOn(day1, 1380.20)}}
+
+	c := NewCollector([]Provider{primary, secondary}, ratelimit.RetryConfig{
+		InitialBackoff: time.Millisecond,
+		MaxAttempts:    1,
+		MaxBackoff:     time.Millisecond,
+	}, DefaultDisagreementThresholdBps, ratelimit.NewGroup(ratelimit.DefaultCircuitBreakerConfig()))
+
+	rates, err := c.fetchAndReconcile(context.Background(), "USD/KRW", "USD", "KRW", day1, day1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rates) != 1 {
+		t.Fatalf("len(rates) = %d, want 1", len(rates))
+	}
+	if rates[0].Source != "frankfurter" {
+		t.Errorf("Source = %q, want frankfurter", rates[0].Source)
+	}
+	if len(rates[0].Sources) != 2 {
+		t.Errorf("Sources = %v, want both providers", rates[0].Sources)
+	}
+}
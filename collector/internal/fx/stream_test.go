@@ -0,0 +1,207 @@
+package fx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/ratelimit"
+)
+
+func TestCollectFXStream_ChunkBoundaries(t *testing.T) {
+	var paths []string
+	var mu sync.Mutex
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"amount":1,"base":"USD","rates":{"2024-06-01":{"KRW":1300.0}}}`))
+	}))
+	defer srv.Close()
+
+	fxClient := newTestClient(srv)
+	collector := NewCollector([]Provider{fxClient}, ratelimit.RetryConfig{
+		InitialBackoff: time.Millisecond,
+		MaxAttempts:    1,
+		MaxBackoff:     time.Millisecond,
+	}, DefaultDisagreementThresholdBps, ratelimit.NewGroup(ratelimit.DefaultCircuitBreakerConfig()))
+	collector.now = func() time.Time {
+		return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	gaps := map[string]time.Time{
+		"USD/KRW": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	out := make(chan domain.FXRate)
+	var rates []domain.FXRate
+	done := make(chan error, 1)
+	go func() {
+		done <- collector.CollectFXStream(context.Background(), "USD", "KRW", gaps, out)
+	}()
+	for r := range out {
+		rates = append(rates, r)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// ~2 years at 365-day chunks spans at least 2 chunk requests.
+	if len(paths) < 2 {
+		t.Fatalf("len(paths) = %d, want >= 2 chunk requests", len(paths))
+	}
+	if len(rates) != len(paths) {
+		t.Errorf("len(rates) = %d, want %d (one rate per chunk response)", len(rates), len(paths))
+	}
+}
+
+func TestCollectFXStream_MidStreamCancellation(t *testing.T) {
+	callCount := 0
+	var mu sync.Mutex
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		callCount++
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"amount":1,"base":"USD","rates":{"2024-06-01":{"KRW":1300.0}}}`))
+	}))
+	defer srv.Close()
+
+	fxClient := newTestClient(srv)
+	collector := NewCollector([]Provider{fxClient}, ratelimit.RetryConfig{
+		InitialBackoff: time.Millisecond,
+		MaxAttempts:    1,
+		MaxBackoff:     time.Millisecond,
+	}, DefaultDisagreementThresholdBps, ratelimit.NewGroup(ratelimit.DefaultCircuitBreakerConfig()))
+	collector.now = func() time.Time {
+		return time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	gaps := map[string]time.Time{
+		"USD/KRW": time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := make(chan domain.FXRate)
+	done := make(chan error, 1)
+	go func() {
+		done <- collector.CollectFXStream(ctx, "USD", "KRW", gaps, out)
+	}()
+
+	<-out // receive first chunk's rate
+	cancel()
+
+	// Drain remaining sends (if any) until the channel closes.
+	for range out {
+	}
+
+	if err := <-done; err == nil {
+		t.Fatal("expected error after mid-stream cancellation")
+	}
+}
+
+func TestCollectFXMulti_PartialFailureDoesNotPoisonOthers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// "from" carries the base currency for this client (see Client.FetchRates).
+		if r.URL.Query().Get("from") == "JPY" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"amount":1,"base":"USD","rates":{"2025-06-01":{"KRW":1300.0}}}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	collector := NewCollector([]Provider{client}, ratelimit.RetryConfig{
+		InitialBackoff: time.Millisecond,
+		MaxAttempts:    1,
+		MaxBackoff:     time.Millisecond,
+	}, DefaultDisagreementThresholdBps, ratelimit.NewGroup(ratelimit.DefaultCircuitBreakerConfig()))
+	collector.now = func() time.Time {
+		return time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	}
+
+	pairs := []domain.Pair{
+		{Base: "USD", Quote: "KRW"},
+		{Base: "EUR", Quote: "KRW"},
+		{Base: "JPY", Quote: "KRW"},
+	}
+	gaps := map[string]time.Time{
+		"USD/KRW": time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		"EUR/KRW": time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		"JPY/KRW": time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	out, errs := collector.CollectFXMulti(context.Background(), pairs, gaps)
+
+	var received int
+	for range out {
+		received++
+	}
+	var errCount int
+	for range errs {
+		errCount++
+	}
+
+	// USD and EUR succeed (one chunk each); JPY fails. The failure must not
+	// prevent the other two pairs' rates or stop the error channel from closing.
+	if received != 2 {
+		t.Errorf("received = %d, want 2 (USD and EUR rates)", received)
+	}
+	if errCount != 1 {
+		t.Errorf("errCount = %d, want 1 (JPY failure)", errCount)
+	}
+}
+
+// TestCollectFXMulti_CancelDoesNotPanic cancels ctx while every pair is still
+// failing, so every per-pair fetch goroutine races to send on errs right as
+// wg.Wait() returns and the multi-collect goroutine closes it. Run with -race
+// and -count > 1: a goroutine not tracked by wg would let close(errs) happen
+// while it's still blocked sending, panicking with "send on closed channel".
+func TestCollectFXMulti_CancelDoesNotPanic(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	collector := NewCollector([]Provider{client}, ratelimit.RetryConfig{
+		InitialBackoff: time.Millisecond,
+		MaxAttempts:    3,
+		MaxBackoff:     time.Millisecond,
+	}, DefaultDisagreementThresholdBps, ratelimit.NewGroup(ratelimit.DefaultCircuitBreakerConfig()))
+	collector.now = func() time.Time {
+		return time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	}
+
+	pairs := make([]domain.Pair, 8)
+	gaps := make(map[string]time.Time)
+	for i := range pairs {
+		quote := string(rune('A' + i))
+		pairs[i] = domain.Pair{Base: "USD", Quote: quote}
+		gaps["USD/"+quote] = time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out, errs := collector.CollectFXMulti(ctx, pairs, gaps)
+
+	// Cancel mid-flight, while several per-pair fetches are concurrently
+	// retrying and about to send on errs.
+	time.Sleep(2 * time.Millisecond)
+	cancel()
+
+	for range out {
+	}
+	for range errs {
+	}
+}
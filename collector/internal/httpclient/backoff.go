@@ -0,0 +1,27 @@
+package httpclient
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff computes exponential backoff with full jitter for long-running
+// reconnect loops (see internal/stream). Unlike ratelimit.RetryConfig, which
+// governs per-request retry/stop decisions via Classify, Backoff is a minimal,
+// dependency-free helper with no notion of error classification or attempt
+// limits — callers decide when to stop retrying.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+// Next returns the delay before reconnect attempt number attempt (0-based):
+// a random duration in [0, min(Max, Initial*2^attempt)).
+func (b Backoff) Next(attempt int) time.Duration {
+	ceiling := float64(b.Initial) * math.Pow(2, float64(min(attempt, 62)))
+	if ceiling > float64(b.Max) {
+		ceiling = float64(b.Max)
+	}
+	return time.Duration(rand.Float64() * ceiling)
+}
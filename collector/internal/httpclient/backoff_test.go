@@ -0,0 +1,50 @@
+package httpclient
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff_Next(t *testing.T) {
+	b := Backoff{Initial: time.Second, Max: 30 * time.Second}
+
+	t.Run("never exceeds Max", func(t *testing.T) {
+		for attempt := 0; attempt < 10; attempt++ {
+			if got := b.Next(attempt); got > b.Max {
+				t.Errorf("Next(%d) = %v, want <= %v", attempt, got, b.Max)
+			}
+		}
+	})
+
+	t.Run("never negative", func(t *testing.T) {
+		for attempt := 0; attempt < 10; attempt++ {
+			if got := b.Next(attempt); got < 0 {
+				t.Errorf("Next(%d) = %v, want >= 0", attempt, got)
+			}
+		}
+	})
+
+	t.Run("ceiling grows with attempt until capped", func(t *testing.T) {
+		var maxSeen time.Duration
+		for i := 0; i < 200; i++ {
+			if got := b.Next(0); got > maxSeen {
+				maxSeen = got
+			}
+		}
+		var maxSeenLater time.Duration
+		for i := 0; i < 200; i++ {
+			if got := b.Next(4); got > maxSeenLater {
+				maxSeenLater = got
+			}
+		}
+		if maxSeenLater <= maxSeen {
+			t.Errorf("observed max at attempt 4 (%v) should exceed attempt 0 (%v) over enough samples", maxSeenLater, maxSeen)
+		}
+	})
+
+	t.Run("huge attempt does not overflow", func(t *testing.T) {
+		if got := b.Next(1000); got > b.Max || got < 0 {
+			t.Errorf("Next(1000) = %v, want in [0, %v]", got, b.Max)
+		}
+	})
+}
@@ -1,13 +1,19 @@
 package httpclient
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
+
+	"github.com/jusikbot/collector/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
@@ -18,40 +24,97 @@ const (
 // ErrBodyTooLarge signals a response body exceeding the configured limit.
 var ErrBodyTooLarge = errors.New("response body too large")
 
+// defaultSensitiveHeaders are stripped from a redirected request whenever the
+// redirect target's host differs from the original, regardless of
+// Client.SensitiveHeaders. http.Header lookups are case-insensitive, so the
+// canonical forms below match "appKey", "APPKEY", etc.
+var defaultSensitiveHeaders = []string{"Authorization", "appkey", "appsecret"}
+
 // Client wraps net/http with base URL, default headers, and safety limits.
 type Client struct {
 	baseURL     string
 	headers     map[string]string
 	httpClient  *http.Client
 	maxBodySize int64
+
+	// FollowRedirects is the default max number of 3xx hops to follow before
+	// returning ErrTooManyRedirects. Zero (the default) disables following:
+	// a 3xx response is returned as an *APIError, matching historical
+	// behavior. Override per request with WithFollowRedirects.
+	FollowRedirects int
+
+	// SensitiveHeaders lists additional header names (beyond
+	// defaultSensitiveHeaders) to strip from a redirected request when the
+	// target host differs from the original, e.g. Alpaca's
+	// "APCA-API-KEY-ID"/"APCA-API-SECRET-KEY".
+	SensitiveHeaders []string
+
+	rateLimiter RateLimiter
+
+	metrics *metrics.HTTPMetrics
+}
+
+// ClientOption customizes a Client at construction time, analogous to
+// RequestOption for a single request.
+type ClientOption func(*Client)
+
+// WithRateLimiter makes c wait on rl before every outgoing request attempt,
+// including each redirect hop. When rl also implements RetryAfterLimiter, a
+// 429 response's parsed Retry-After is fed back into it via PauseFor, so
+// concurrent goroutines sharing c all honor the server's cooldown window
+// instead of each hammering it again on their own retry.
+func WithRateLimiter(rl RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter = rl
+	}
+}
+
+// WithMetrics registers the round-trip instrumentation (see
+// metrics.HTTPMetrics) against reg and has c record to it. Without this
+// option c records nothing, matching historical behavior.
+func WithMetrics(reg prometheus.Registerer) ClientOption {
+	return func(c *Client) {
+		c.metrics = metrics.NewHTTPMetrics(reg)
+	}
 }
 
 // NewClient creates a Client. Pass nil httpClient for defaults.
 // Why explicit *http.Client: enables test doubles via httptest.
-func NewClient(baseURL string, headers map[string]string, httpClient *http.Client, maxBodySize int64) *Client {
+// Why CheckRedirect is overridden: Client.do implements its own redirect
+// policy (see FollowRedirects); net/http's default of silently following up
+// to 10 redirects would otherwise run first and hide 3xx responses from it.
+func NewClient(baseURL string, headers map[string]string, httpClient *http.Client, maxBodySize int64, opts ...ClientOption) *Client {
 	if httpClient == nil {
 		httpClient = &http.Client{Timeout: defaultTimeout}
 	}
+	httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
 	if maxBodySize <= 0 {
 		maxBodySize = defaultMaxBodySize
 	}
 	if headers == nil {
 		headers = make(map[string]string)
 	}
-	return &Client{
+	c := &Client{
 		baseURL:     baseURL,
 		headers:     headers,
 		httpClient:  httpClient,
 		maxBodySize: maxBodySize,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // RequestOption customizes a single request.
 type RequestOption func(*requestConfig)
 
 type requestConfig struct {
-	headers     map[string]string
-	queryParams map[string]string
+	headers      map[string]string
+	queryParams  map[string]string
+	maxRedirects *int
 }
 
 func WithHeader(key, value string) RequestOption {
@@ -66,9 +129,75 @@ func WithQueryParam(key, value string) RequestOption {
 	}
 }
 
+// WithFollowRedirects overrides Client.FollowRedirects for a single request.
+func WithFollowRedirects(max int) RequestOption {
+	return func(c *requestConfig) {
+		c.maxRedirects = &max
+	}
+}
+
 // Get sends an HTTP GET and returns body bytes, status code, and error.
 // 2xx → (body, status, nil), 4xx → permanent error, 5xx → retryable error.
 func (c *Client) Get(ctx context.Context, path string, opts ...RequestOption) ([]byte, int, error) {
+	return c.do(ctx, http.MethodGet, path, nil, opts...)
+}
+
+// Post sends an HTTP POST with body and returns body bytes, status code, and
+// error, following the same 2xx/4xx/5xx convention as Get. body may be nil,
+// an io.Reader (sent as-is), or any other value (JSON-encoded with a
+// Content-Type: application/json header).
+func (c *Client) Post(ctx context.Context, path string, body any, opts ...RequestOption) ([]byte, int, error) {
+	reader, jsonEncoded, err := encodeBody(body)
+	if err != nil {
+		return nil, 0, err
+	}
+	if jsonEncoded {
+		opts = append([]RequestOption{WithHeader("Content-Type", "application/json")}, opts...)
+	}
+	return c.do(ctx, http.MethodPost, path, reader, opts...)
+}
+
+// Put sends an HTTP PUT with body. See Post for body encoding rules.
+func (c *Client) Put(ctx context.Context, path string, body any, opts ...RequestOption) ([]byte, int, error) {
+	reader, jsonEncoded, err := encodeBody(body)
+	if err != nil {
+		return nil, 0, err
+	}
+	if jsonEncoded {
+		opts = append([]RequestOption{WithHeader("Content-Type", "application/json")}, opts...)
+	}
+	return c.do(ctx, http.MethodPut, path, reader, opts...)
+}
+
+// Delete sends an HTTP DELETE and returns body bytes, status code, and error.
+func (c *Client) Delete(ctx context.Context, path string, opts ...RequestOption) ([]byte, int, error) {
+	return c.do(ctx, http.MethodDelete, path, nil, opts...)
+}
+
+// encodeBody normalizes a Post/Put body into an io.Reader. A nil body or an
+// already-io.Reader body is passed through unchanged; anything else is
+// JSON-encoded, in which case the second return value is true so callers can
+// set a matching Content-Type.
+func encodeBody(body any) (io.Reader, bool, error) {
+	if body == nil {
+		return nil, false, nil
+	}
+	if reader, ok := body.(io.Reader); ok {
+		return reader, false, nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, false, fmt.Errorf("marshal request body: %w", err)
+	}
+	return bytes.NewReader(data), true, nil
+}
+
+// do sends an HTTP request and returns body bytes, status code, and error.
+// 2xx → (body, status, nil), 4xx → permanent error, 5xx → retryable error.
+// A 3xx is followed as a redirect (see followRedirect) when FollowRedirects is
+// positive, either via the Client default or a per-request WithFollowRedirects;
+// otherwise it falls through to the same APIError path as any other non-2xx.
+func (c *Client) do(ctx context.Context, method, path string, body io.Reader, opts ...RequestOption) (respBody []byte, status int, err error) {
 	cfg := &requestConfig{
 		headers:     make(map[string]string),
 		queryParams: make(map[string]string),
@@ -77,12 +206,17 @@ func (c *Client) Get(ctx context.Context, path string, opts ...RequestOption) ([
 		opt(cfg)
 	}
 
+	maxRedirects := c.FollowRedirects
+	if cfg.maxRedirects != nil {
+		maxRedirects = *cfg.maxRedirects
+	}
+
 	reqURL, err := c.buildURL(path, cfg.queryParams)
 	if err != nil {
 		return nil, 0, fmt.Errorf("build request URL: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
 	if err != nil {
 		return nil, 0, fmt.Errorf("create request: %w", err)
 	}
@@ -93,41 +227,184 @@ func (c *Client) Get(ctx context.Context, path string, opts ...RequestOption) ([
 	for k, v := range cfg.headers {
 		req.Header.Set(k, v)
 	}
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-			return nil, 0, fmt.Errorf("request to %s: %w", reqURL, ErrTimeout)
+	host := req.URL.Host
+	if c.metrics != nil {
+		start := time.Now()
+		defer func() {
+			c.metrics.RequestDuration.WithLabelValues(host, statusClass(status)).Observe(time.Since(start).Seconds())
+			switch {
+			case errors.Is(err, ErrRateLimited):
+				c.metrics.RateLimited.WithLabelValues(host).Inc()
+			case errors.Is(err, ErrTimeout):
+				c.metrics.Timeouts.WithLabelValues(host).Inc()
+			case errors.Is(err, ErrBodyTooLarge):
+				c.metrics.BodyTooLarge.WithLabelValues(host).Inc()
+			}
+		}()
+	}
+
+	for hop := 0; ; hop++ {
+		if hop > 0 && c.metrics != nil {
+			c.metrics.RetryAttempts.WithLabelValues(host).Inc()
+		}
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.Wait(ctx); err != nil {
+				return nil, 0, fmt.Errorf("rate limiter wait for %s: %w", req.URL, err)
+			}
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, 0, fmt.Errorf("request to %s: %w", req.URL, ErrTimeout)
+			}
+			if ctx.Err() != nil {
+				return nil, 0, fmt.Errorf("request to %s: %w", req.URL, ctx.Err())
+			}
+			return nil, 0, fmt.Errorf("request to %s: %w", req.URL, err)
+		}
+
+		if isRedirectStatus(resp.StatusCode) && maxRedirects > 0 {
+			resp.Body.Close()
+
+			if hop >= maxRedirects {
+				return nil, resp.StatusCode, fmt.Errorf("request to %s: %w", req.URL, ErrTooManyRedirects)
+			}
+
+			nextReq, err := c.followRedirect(req, resp)
+			if err != nil {
+				return nil, resp.StatusCode, err
+			}
+			req = nextReq
+			continue
+		}
+
+		defer resp.Body.Close()
+
+		respBody, err := decodeBody(resp, c.maxBodySize)
+		if err != nil {
+			return nil, resp.StatusCode, fmt.Errorf("read response body from %s: %w", req.URL, err)
+		}
+
+		if int64(len(respBody)) > c.maxBodySize {
+			return nil, resp.StatusCode, fmt.Errorf("response from %s (%d bytes): %w", req.URL, len(respBody), ErrBodyTooLarge)
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return respBody, resp.StatusCode, nil
+		}
+
+		apiErr := &APIError{
+			Body:        truncateBody(respBody),
+			Header:      resp.Header,
+			IsRetryable: resp.StatusCode == 429 || resp.StatusCode >= 500,
+			Method:      method,
+			StatusCode:  resp.StatusCode,
+			URL:         req.URL.String(),
 		}
-		if ctx.Err() != nil {
-			return nil, 0, fmt.Errorf("request to %s: %w", reqURL, ctx.Err())
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			if d, ok := parseRetryAfter(resp.Header); ok {
+				apiErr.RetryAfter = d
+				if rl, ok := c.rateLimiter.(RetryAfterLimiter); ok {
+					rl.PauseFor(d)
+				}
+			}
 		}
-		return nil, 0, fmt.Errorf("request to %s: %w", reqURL, err)
+
+		return nil, resp.StatusCode, apiErr
+	}
+}
+
+// statusClass buckets an HTTP status code into "2xx", "4xx", etc. for the
+// request-duration histogram; a zero status (request never got a response,
+// e.g. ErrTimeout) is reported as "none".
+func statusClass(code int) string {
+	if code <= 0 {
+		return "none"
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
+
+// isRedirectStatus reports whether code is one of the standard HTTP redirect
+// statuses this client is willing to follow.
+func isRedirectStatus(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	default:
+		return false
+	}
+}
+
+// followRedirect builds the request for the next hop from a 3xx response.
+// Credential-bearing headers (defaultSensitiveHeaders plus
+// Client.SensitiveHeaders) are stripped whenever the redirect target's host
+// differs from prevReq's, so a misbehaving redirect cannot leak KIS/Tiingo
+// tokens to an unrelated host. If prevReq had a body that net/http could not
+// capture a replay function for (prevReq.GetBody == nil), the redirect is not
+// followed and ErrInvalidLocation is returned rather than silently dropping
+// the body.
+func (c *Client) followRedirect(prevReq *http.Request, resp *http.Response) (*http.Request, error) {
+	loc := resp.Header.Get("Location")
+	if loc == "" {
+		return nil, fmt.Errorf("request to %s: %w", prevReq.URL, ErrInvalidLocation)
 	}
-	defer resp.Body.Close()
 
-	// Why +1: detect overflow without separate HEAD request.
-	limited := io.LimitReader(resp.Body, c.maxBodySize+1)
-	body, err := io.ReadAll(limited)
+	locURL, err := url.Parse(loc)
 	if err != nil {
-		return nil, resp.StatusCode, fmt.Errorf("read response body from %s: %w", reqURL, err)
+		return nil, fmt.Errorf("request to %s: %w", prevReq.URL, ErrInvalidLocation)
 	}
+	resolved := prevReq.URL.ResolveReference(locURL)
 
-	if int64(len(body)) > c.maxBodySize {
-		return nil, resp.StatusCode, fmt.Errorf("response from %s (%d bytes): %w", reqURL, len(body), ErrBodyTooLarge)
+	var nextBody io.Reader
+	if prevReq.GetBody != nil {
+		rc, err := prevReq.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("replay request body for redirect to %s: %w", resolved, err)
+		}
+		nextBody = rc
+	} else if prevReq.Body != nil {
+		return nil, fmt.Errorf("request to %s: %w", prevReq.URL, ErrInvalidLocation)
 	}
 
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return body, resp.StatusCode, nil
+	nextReq, err := http.NewRequestWithContext(prevReq.Context(), prevReq.Method, resolved.String(), nextBody)
+	if err != nil {
+		return nil, fmt.Errorf("build redirect request to %s: %w", resolved, err)
+	}
+	nextReq.GetBody = prevReq.GetBody
+	nextReq.Header = prevReq.Header.Clone()
+
+	if resolved.Host != prevReq.URL.Host {
+		for _, h := range defaultSensitiveHeaders {
+			nextReq.Header.Del(h)
+		}
+		for _, h := range c.SensitiveHeaders {
+			nextReq.Header.Del(h)
+		}
 	}
 
-	apiErr := &APIError{
-		Body:        truncateBody(body),
-		IsRetryable: resp.StatusCode == 429 || resp.StatusCode >= 500,
-		StatusCode:  resp.StatusCode,
-		URL:         reqURL,
+	return nextReq, nil
+}
+
+// DoJSON decodes the body returned by a Client method (Get, Post, Put,
+// Delete) into T. Intended to wrap a call directly, e.g.:
+//
+//	result, status, err := httpclient.DoJSON[PriceResponse](client.Get(ctx, path))
+func DoJSON[T any](body []byte, status int, err error) (T, int, error) {
+	var result T
+	if err != nil {
+		return result, status, err
+	}
+	if unmarshalErr := json.Unmarshal(body, &result); unmarshalErr != nil {
+		return result, status, fmt.Errorf("decode JSON response: %w", unmarshalErr)
 	}
-	return nil, resp.StatusCode, apiErr
+	return result, status, nil
 }
 
 func (c *Client) buildURL(path string, queryParams map[string]string) (string, error) {
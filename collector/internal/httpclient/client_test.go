@@ -2,12 +2,18 @@ package httpclient
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"golang.org/x/time/rate"
 )
 
 func TestGet_Success(t *testing.T) {
@@ -140,6 +146,115 @@ func TestGet_429_RateLimited(t *testing.T) {
 	if !apiErr.IsRetryable {
 		t.Error("429 should be retryable")
 	}
+	if apiErr.RetryAfter != 0 {
+		t.Errorf("RetryAfter = %v, want 0 (no header sent)", apiErr.RetryAfter)
+	}
+}
+
+func TestGet_429_RetryAfterSeconds(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+	_, _, err := client.Get(context.Background(), "/limited")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatal("error should be *APIError")
+	}
+	if apiErr.RetryAfter != 5*time.Second {
+		t.Errorf("RetryAfter = %v, want 5s", apiErr.RetryAfter)
+	}
+}
+
+func TestGet_429_RetryAfterHTTPDate(t *testing.T) {
+	target := time.Now().Add(3 * time.Second).UTC()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", target.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+	_, _, err := client.Get(context.Background(), "/limited")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatal("error should be *APIError")
+	}
+	if apiErr.RetryAfter <= 0 || apiErr.RetryAfter > 4*time.Second {
+		t.Errorf("RetryAfter = %v, want ~3s", apiErr.RetryAfter)
+	}
+}
+
+func TestGet_WithRateLimiter_WaitsBeforeEachRequest(t *testing.T) {
+	var calls []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, time.Now())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	limiter := NewTokenBucketLimiter(rate.NewLimiter(rate.Every(50*time.Millisecond), 1))
+	client := NewClient(srv.URL, nil, srv.Client(), 0, WithRateLimiter(limiter))
+
+	for i := 0; i < 3; i++ {
+		if _, _, err := client.Get(context.Background(), "/ok"); err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+	}
+
+	if len(calls) != 3 {
+		t.Fatalf("got %d calls, want 3", len(calls))
+	}
+	if gap := calls[2].Sub(calls[0]); gap < 90*time.Millisecond {
+		t.Errorf("elapsed across 3 calls = %v, want >= ~100ms given the rate limit", gap)
+	}
+}
+
+func TestGet_WithRateLimiter_PausesOnRetryAfter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	limiter := NewTokenBucketLimiter(rate.NewLimiter(rate.Every(10*time.Millisecond), 1))
+	client := NewClient(srv.URL, nil, srv.Client(), 0, WithRateLimiter(limiter))
+
+	if _, _, err := client.Get(context.Background(), "/limited"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("first request error = %v, want ErrRateLimited", err)
+	}
+
+	// A second caller sharing limiter should now be blocked by the 5s pause
+	// the first 429's Retry-After fed back in, not just the original 10ms
+	// token-bucket rate — assert this with a short deadline rather than
+	// sleeping out the full pause.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Error("Wait during pause succeeded, want an error given the 5s Retry-After pause vs. a 20ms deadline")
+	}
+}
+
+func TestTokenBucketLimiter_Stats(t *testing.T) {
+	limiter := NewTokenBucketLimiter(rate.NewLimiter(rate.Every(10*time.Millisecond), 5))
+
+	stats := limiter.Stats()
+	if stats.Burst != 5 {
+		t.Errorf("Burst = %d, want 5", stats.Burst)
+	}
+	if stats.TokensLeft != 5 {
+		t.Errorf("TokensLeft = %v, want 5 (unused bucket starts full)", stats.TokensLeft)
+	}
+
+	limiter.PauseFor(50 * time.Millisecond)
+	if got := limiter.Stats().Limit; got != 0 {
+		t.Errorf("Limit during pause = %v, want 0", got)
+	}
 }
 
 func TestGet_5xx_RetryableError(t *testing.T) {
@@ -236,6 +351,306 @@ func TestGet_BodySizeExceeded(t *testing.T) {
 	}
 }
 
+func TestPost_JSONEncodedBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", got)
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request body: %v", err)
+		}
+		if body["symbol"] != "NVDA" {
+			t.Errorf("symbol = %q, want NVDA", body["symbol"])
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"order-1"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+	body, status, err := client.Post(context.Background(), "/orders", map[string]string{"symbol": "NVDA"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != http.StatusCreated {
+		t.Errorf("status = %d, want 201", status)
+	}
+	if string(body) != `{"id":"order-1"}` {
+		t.Errorf("body = %q", string(body))
+	}
+}
+
+func TestPost_ReaderBodyPassedThroughUnencoded(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Content-Type"); got != "" {
+			t.Errorf("Content-Type = %q, want unset for a raw reader body", got)
+		}
+		raw, _ := io.ReadAll(r.Body)
+		if string(raw) != "raw-payload" {
+			t.Errorf("body = %q, want raw-payload", string(raw))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+	_, _, err := client.Post(context.Background(), "/raw", strings.NewReader("raw-payload"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPut_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("method = %s, want PUT", r.Method)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+	_, status, err := client.Put(context.Background(), "/orders/1", map[string]int{"quantity": 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != http.StatusOK {
+		t.Errorf("status = %d, want 200", status)
+	}
+}
+
+func TestDelete_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("method = %s, want DELETE", r.Method)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+	_, status, err := client.Delete(context.Background(), "/orders/1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", status)
+	}
+}
+
+func TestPost_4xx_ErrorIncludesMethod(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("bad order"))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+	_, _, err := client.Post(context.Background(), "/orders", map[string]string{"symbol": "NVDA"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatal("error should be *APIError")
+	}
+	if apiErr.Method != http.MethodPost {
+		t.Errorf("Method = %q, want POST", apiErr.Method)
+	}
+	if !strings.Contains(apiErr.Error(), "POST") {
+		t.Errorf("Error() = %q, want it to mention POST", apiErr.Error())
+	}
+}
+
+type doJSONPayload struct {
+	Symbol string `json:"symbol"`
+}
+
+func TestDoJSON_DecodesSuccessfulResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"symbol":"NVDA"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+	payload, status, err := DoJSON[doJSONPayload](client.Get(context.Background(), "/instrument"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != http.StatusOK {
+		t.Errorf("status = %d, want 200", status)
+	}
+	if payload.Symbol != "NVDA" {
+		t.Errorf("Symbol = %q, want NVDA", payload.Symbol)
+	}
+}
+
+func TestDoJSON_PropagatesRequestError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+	_, _, err := DoJSON[doJSONPayload](client.Get(context.Background(), "/instrument"))
+	if err == nil {
+		t.Fatal("expected error to propagate from the underlying request")
+	}
+}
+
+func TestGet_FollowRedirects_SameHostKeepsHeaders(t *testing.T) {
+	var finalAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		finalAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, map[string]string{"Authorization": "Bearer token"}, srv.Client(), 0)
+	client.FollowRedirects = 3
+
+	body, status, err := client.Get(context.Background(), "/old")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != http.StatusOK {
+		t.Errorf("status = %d, want 200", status)
+	}
+	if string(body) != "ok" {
+		t.Errorf("body = %q, want ok", string(body))
+	}
+	if finalAuth != "Bearer token" {
+		t.Errorf("Authorization on redirected request = %q, want preserved for a same-host redirect", finalAuth)
+	}
+}
+
+func TestGet_FollowRedirects_CrossHostStripsSensitiveHeaders(t *testing.T) {
+	var gotAuth, gotAppKey, gotCustom string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotAppKey = r.Header.Get("appkey")
+		gotCustom = r.Header.Get("X-Custom-Secret")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+"/elsewhere", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, map[string]string{
+		"Authorization":   "Bearer token",
+		"appkey":          "secret-key",
+		"X-Custom-Secret": "also-secret",
+	}, srv.Client(), 0)
+	client.FollowRedirects = 3
+	client.SensitiveHeaders = []string{"X-Custom-Secret"}
+
+	_, status, err := client.Get(context.Background(), "/start")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != http.StatusOK {
+		t.Errorf("status = %d, want 200", status)
+	}
+	if gotAuth != "" {
+		t.Errorf("Authorization leaked cross-host: %q", gotAuth)
+	}
+	if gotAppKey != "" {
+		t.Errorf("appkey leaked cross-host: %q", gotAppKey)
+	}
+	if gotCustom != "" {
+		t.Errorf("X-Custom-Secret leaked cross-host: %q", gotCustom)
+	}
+}
+
+func TestGet_FollowRedirects_MissingLocation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusFound)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+	client.FollowRedirects = 3
+
+	_, _, err := client.Get(context.Background(), "/no-location")
+	if !errors.Is(err, ErrInvalidLocation) {
+		t.Errorf("error = %v, want ErrInvalidLocation", err)
+	}
+}
+
+func TestGet_FollowRedirects_ExceedsMaxHops(t *testing.T) {
+	var hops int
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hops++
+		http.Redirect(w, r, srv.URL+"/next", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+	client.FollowRedirects = 2
+
+	_, _, err := client.Get(context.Background(), "/loop")
+	if !errors.Is(err, ErrTooManyRedirects) {
+		t.Errorf("error = %v, want ErrTooManyRedirects", err)
+	}
+}
+
+func TestGet_FollowRedirects_DisabledByDefaultReturnsAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+
+	_, status, err := client.Get(context.Background(), "/old")
+	if status != http.StatusMovedPermanently {
+		t.Errorf("status = %d, want 301", status)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatal("error should be *APIError when FollowRedirects is unset")
+	}
+}
+
+func TestGet_FollowRedirects_PerRequestOverride(t *testing.T) {
+	var followed bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		followed = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+
+	_, status, err := client.Get(context.Background(), "/old", WithFollowRedirects(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != http.StatusOK {
+		t.Errorf("status = %d, want 200", status)
+	}
+	if !followed {
+		t.Error("redirect was not followed despite per-request WithFollowRedirects")
+	}
+}
+
 func TestGet_BodyWithinLimit(t *testing.T) {
 	exactBody := strings.Repeat("x", 512)
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -254,3 +669,35 @@ func TestGet_BodyWithinLimit(t *testing.T) {
 		t.Errorf("body length = %d, want 512", len(body))
 	}
 }
+
+func TestDo_RecordsMetrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	reg := prometheus.NewRegistry()
+	client := NewClient(srv.URL, nil, srv.Client(), 0, WithMetrics(reg))
+
+	if _, _, err := client.Get(context.Background(), "/limited"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := testutil.ToFloat64(client.metrics.RateLimited.WithLabelValues(srv.Listener.Addr().String())); got != 1 {
+		t.Errorf("RateLimited = %v, want 1", got)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	var sawDuration bool
+	for _, f := range families {
+		if f.GetName() == "collector_httpclient_request_duration_seconds" {
+			sawDuration = true
+		}
+	}
+	if !sawDuration {
+		t.Error("request duration histogram was not registered/recorded")
+	}
+}
@@ -0,0 +1,65 @@
+package httpclient
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrDecodeFailed signals a response whose Content-Encoding could not be
+// decompressed (e.g. a truncated or corrupt gzip/deflate stream).
+var ErrDecodeFailed = errors.New("decode response body")
+
+// acceptEncoding is sent on every request unless the caller already set their
+// own Accept-Encoding header. Why send it explicitly: Go's http.Transport
+// transparently gzip-decodes when no Accept-Encoding header is present at
+// all, but it doesn't understand deflate, and setting our own header (to
+// advertise both) opts out of that implicit handling — so decodeBody below
+// has to do the decompression itself either way.
+const acceptEncoding = "gzip, deflate"
+
+// decodeBody reads resp.Body, transparently decompressing it per
+// Content-Encoding, and enforces maxBodySize against the decompressed size so
+// a compression bomb can't bypass the limit that applies to a plain response.
+func decodeBody(resp *http.Response, maxBodySize int64) ([]byte, error) {
+	var reader io.Reader = resp.Body
+	var compressed bool
+
+	switch encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))); encoding {
+	case "gzip":
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrDecodeFailed, err)
+		}
+		defer gz.Close()
+		reader = gz
+		compressed = true
+	case "deflate":
+		fl := flate.NewReader(reader)
+		defer fl.Close()
+		reader = fl
+		compressed = true
+	case "", "identity":
+		// No decompression needed.
+	default:
+		// Unrecognized encoding (e.g. br, zstd): pass the body through as-is
+		// rather than failing the request outright; any resulting garbage
+		// surfaces downstream (JSON decode, ErrRateLimited's '[' check, etc.)
+		// the same way an unexpected body always has.
+	}
+
+	// Why +1: detect overflow without a separate HEAD request.
+	limited := io.LimitReader(reader, maxBodySize+1)
+	body, err := io.ReadAll(limited)
+	if err != nil {
+		if compressed {
+			return nil, fmt.Errorf("%w: %v", ErrDecodeFailed, err)
+		}
+		return nil, err
+	}
+	return body, nil
+}
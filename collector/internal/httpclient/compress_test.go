@@ -0,0 +1,137 @@
+package httpclient
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(s)); err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func deflateBytes(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	fl, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		t.Fatalf("flate writer: %v", err)
+	}
+	if _, err := fl.Write([]byte(s)); err != nil {
+		t.Fatalf("flate write: %v", err)
+	}
+	if err := fl.Close(); err != nil {
+		t.Fatalf("flate close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestGet_TransparentlyDecodesGzip(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != acceptEncoding {
+			t.Errorf("Accept-Encoding = %q, want %q", r.Header.Get("Accept-Encoding"), acceptEncoding)
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusOK)
+		w.Write(gzipBytes(t, `{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+
+	body, status, err := client.Get(context.Background(), "/test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != 200 {
+		t.Errorf("status = %d, want 200", status)
+	}
+	if string(body) != `{"ok":true}` {
+		t.Errorf("body = %q, want decoded JSON", string(body))
+	}
+}
+
+func TestGet_TransparentlyDecodesDeflate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "deflate")
+		w.WriteHeader(http.StatusOK)
+		w.Write(deflateBytes(t, `{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+
+	body, _, err := client.Get(context.Background(), "/test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != `{"ok":true}` {
+		t.Errorf("body = %q, want decoded JSON", string(body))
+	}
+}
+
+func TestGet_CustomAcceptEncodingIsNotOverridden(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept-Encoding"); got != "identity" {
+			t.Errorf("Accept-Encoding = %q, want %q", got, "identity")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+
+	_, _, err := client.Get(context.Background(), "/test", WithHeader("Accept-Encoding", "identity"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGet_MalformedGzipReturnsErrDecodeFailed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("not actually gzip"))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 0)
+
+	_, _, err := client.Get(context.Background(), "/test")
+	if !errors.Is(err, ErrDecodeFailed) {
+		t.Fatalf("err = %v, want ErrDecodeFailed", err)
+	}
+	if Classify(err) != KindDecode {
+		t.Errorf("Classify(err) = %v, want KindDecode", Classify(err))
+	}
+}
+
+func TestGet_EnforcesMaxBodySizeAfterDecompression(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusOK)
+		w.Write(gzipBytes(t, "aaaaaaaaaa"))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, nil, srv.Client(), 5)
+
+	_, _, err := client.Get(context.Background(), "/test")
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Fatalf("err = %v, want ErrBodyTooLarge", err)
+	}
+}
@@ -3,6 +3,9 @@ package httpclient
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"time"
 	"unicode/utf8"
 )
 
@@ -11,22 +14,42 @@ const maxErrorBodyLength = 512
 var (
 	ErrRateLimited = errors.New("rate limited")
 	ErrTimeout     = errors.New("request timed out")
+
+	// ErrInvalidLocation signals a 3xx response whose Location header is
+	// missing or fails to parse as a URL reference.
+	ErrInvalidLocation = errors.New("redirect response missing or malformed Location header")
+	// ErrTooManyRedirects signals that following redirects exceeded the
+	// configured hop budget (see Client.FollowRedirects / WithFollowRedirects).
+	ErrTooManyRedirects = errors.New("too many redirects")
 )
 
 // APIError represents an HTTP response indicating failure.
 // Why Body is string: truncated for safe log inclusion, not raw bytes.
 type APIError struct {
 	Body        string
+	Header      http.Header
 	IsRetryable bool
-	StatusCode  int
-	URL         string
+	// Method is the HTTP method of the failed request. Left empty by callers
+	// predating Post/Put/Delete, in which case Error() assumes GET.
+	Method string
+	// RetryAfter is the duration parsed from a 429 response's Retry-After
+	// header (delta-seconds or HTTP-date per RFC 7231 §7.1.3), or zero when
+	// the header is absent or malformed. See WithRateLimiter, which
+	// feeds this back into the configured RateLimiter.
+	RetryAfter time.Duration
+	StatusCode int
+	URL        string
 }
 
 func (e *APIError) Error() string {
+	method := e.Method
+	if method == "" {
+		method = http.MethodGet
+	}
 	if e.Body != "" {
-		return fmt.Sprintf("HTTP %d GET %s: %s", e.StatusCode, e.URL, e.Body)
+		return fmt.Sprintf("HTTP %d %s %s: %s", e.StatusCode, method, e.URL, e.Body)
 	}
-	return fmt.Sprintf("HTTP %d GET %s", e.StatusCode, e.URL)
+	return fmt.Sprintf("HTTP %d %s %s", e.StatusCode, method, e.URL)
 }
 
 // Unwrap returns sentinel errors for errors.Is matching.
@@ -38,6 +61,119 @@ func (e *APIError) Unwrap() error {
 	return nil
 }
 
+// parseRetryAfter parses a Retry-After header as either delta-seconds or an
+// HTTP-date, per RFC 7231 §7.1.3. Returns ok=false when absent or malformed.
+// Why duplicated from ratelimit.parseRetryAfter: ratelimit imports httpclient
+// for APIError, so the reverse import isn't available here.
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	if header == nil {
+		return 0, false
+	}
+
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+
+	d := time.Until(t)
+	if d < 0 {
+		return 0, true
+	}
+	return d, true
+}
+
+// ErrorKind categorizes a failure from Client's methods for callers that want
+// coarser handling than inspecting *APIError.StatusCode directly (e.g.
+// choosing a log level, or deciding whether to trip a circuit breaker).
+type ErrorKind int
+
+const (
+	KindUnknown ErrorKind = iota
+	// KindNetwork covers transport-level failures (DNS, connection refused,
+	// TLS) that never produced an HTTP response.
+	KindNetwork
+	KindTimeout
+	KindRateLimited
+	// KindClientError is a non-429 4xx response.
+	KindClientError
+	KindServerError
+	KindDecode
+	KindBodyTooLarge
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindNetwork:
+		return "network"
+	case KindTimeout:
+		return "timeout"
+	case KindRateLimited:
+		return "rate_limited"
+	case KindClientError:
+		return "client_error"
+	case KindServerError:
+		return "server_error"
+	case KindDecode:
+		return "decode"
+	case KindBodyTooLarge:
+		return "body_too_large"
+	default:
+		return "unknown"
+	}
+}
+
+// Kind categorizes e by status code: 429 → KindRateLimited, 5xx →
+// KindServerError, any other 4xx → KindClientError.
+func (e *APIError) Kind() ErrorKind {
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests:
+		return KindRateLimited
+	case e.StatusCode >= 500:
+		return KindServerError
+	case e.StatusCode >= 400:
+		return KindClientError
+	default:
+		return KindUnknown
+	}
+}
+
+// Classify categorizes an error returned by one of Client's methods. It
+// recognizes ErrTimeout, ErrBodyTooLarge, ErrDecodeFailed and *APIError
+// directly; anything else is reported as KindNetwork. That includes
+// transport-level errors from the underlying http.Client, but also
+// ErrTooManyRedirects, ErrInvalidLocation and request-construction errors.
+func Classify(err error) ErrorKind {
+	if err == nil {
+		return KindUnknown
+	}
+	if errors.Is(err, ErrTimeout) {
+		return KindTimeout
+	}
+	if errors.Is(err, ErrBodyTooLarge) {
+		return KindBodyTooLarge
+	}
+	if errors.Is(err, ErrDecodeFailed) {
+		return KindDecode
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Kind()
+	}
+	return KindNetwork
+}
+
 func truncateBody(body []byte) string {
 	if len(body) <= maxErrorBodyLength {
 		return string(body)
@@ -23,6 +23,11 @@ func TestAPIError_Error(t *testing.T) {
 			err:     &APIError{StatusCode: 404, URL: "https://api.example.com/data"},
 			wantSub: "HTTP 404 GET https://api.example.com/data",
 		},
+		{
+			name:    "non-GET method",
+			err:     &APIError{Method: "POST", StatusCode: 400, URL: "https://api.example.com/orders", Body: "bad order"},
+			wantSub: "HTTP 400 POST https://api.example.com/orders: bad order",
+		},
 	}
 
 	for _, tt := range tests {
@@ -68,6 +73,50 @@ func TestAPIError_ErrorsAs(t *testing.T) {
 	}
 }
 
+func TestAPIError_Kind(t *testing.T) {
+	tests := []struct {
+		status int
+		want   ErrorKind
+	}{
+		{429, KindRateLimited},
+		{500, KindServerError},
+		{503, KindServerError},
+		{404, KindClientError},
+		{400, KindClientError},
+		{200, KindUnknown},
+	}
+	for _, tt := range tests {
+		apiErr := &APIError{StatusCode: tt.status}
+		if got := apiErr.Kind(); got != tt.want {
+			t.Errorf("Kind() for status %d = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{"nil", nil, KindUnknown},
+		{"timeout", fmt.Errorf("request to x: %w", ErrTimeout), KindTimeout},
+		{"body too large", fmt.Errorf("response from x: %w", ErrBodyTooLarge), KindBodyTooLarge},
+		{"decode failed", fmt.Errorf("read response body from x: %w", ErrDecodeFailed), KindDecode},
+		{"rate limited api error", &APIError{StatusCode: 429}, KindRateLimited},
+		{"server error api error", &APIError{StatusCode: 502}, KindServerError},
+		{"client error api error", &APIError{StatusCode: 404}, KindClientError},
+		{"network", errors.New("dial tcp: connection refused"), KindNetwork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("Classify(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestTruncateBody(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,69 @@
+package httpclient
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter is the token-bucket interface a Client waits on before every
+// outgoing request attempt when configured via WithRateLimiter. *rate.Limiter
+// (golang.org/x/time/rate) satisfies it directly.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// RetryAfterLimiter is implemented by a RateLimiter that can act on a
+// server-supplied Retry-After cooldown, e.g. pausing a token bucket so every
+// goroutine sharing the same Client backs off together instead of each
+// independently rediscovering the 429. A RateLimiter that doesn't implement
+// it is simply not notified.
+type RetryAfterLimiter interface {
+	RateLimiter
+	PauseFor(d time.Duration)
+}
+
+// TokenBucketLimiter adapts a *rate.Limiter into a RetryAfterLimiter by
+// dropping its rate to zero for the Retry-After duration, then restoring it —
+// the same technique ratelimit.FetchWithRateLimit's limiter pausing uses internally,
+// exposed here for callers that want that behavior from a Client alone,
+// without pulling in ratelimit's retry/classification machinery (e.g. the fx
+// client, whose provider fetches run concurrently against one shared Client).
+type TokenBucketLimiter struct {
+	*rate.Limiter
+}
+
+// NewTokenBucketLimiter wraps rl as a RetryAfterLimiter.
+func NewTokenBucketLimiter(rl *rate.Limiter) *TokenBucketLimiter {
+	return &TokenBucketLimiter{Limiter: rl}
+}
+
+func (l *TokenBucketLimiter) PauseFor(d time.Duration) {
+	prevLimit := l.Limit()
+	l.SetLimitAt(time.Now(), 0)
+	time.AfterFunc(d, func() {
+		l.SetLimitAt(time.Now(), prevLimit)
+	})
+}
+
+// RateLimitStats is a point-in-time snapshot of a TokenBucketLimiter's
+// throttle state, for a health/debug endpoint or a log line — a cheaper
+// alternative to scraping Prometheus (see WithMetrics) when a caller just
+// wants to see whether a client is currently being held back.
+type RateLimitStats struct {
+	Burst      int
+	Limit      rate.Limit
+	TokensLeft float64
+}
+
+// Stats reports l's current rate, burst, and available tokens as of now.
+// A TokensLeft near zero means the next Wait will block; a Limit of zero
+// means l is paused (see PauseFor).
+func (l *TokenBucketLimiter) Stats() RateLimitStats {
+	return RateLimitStats{
+		Burst:      l.Burst(),
+		Limit:      l.Limit(),
+		TokensLeft: l.TokensAt(time.Now()),
+	}
+}
@@ -0,0 +1,258 @@
+// Package replay provides a record/replay test harness for collectors built on
+// httpclient.Client. A Recorder wraps the real transport and captures every
+// request/response pair it sees; a Player later serves those same pairs from
+// disk as an http.RoundTripper, so tests can exercise retry, circuit-breaker,
+// and rate-limiter behavior deterministically without a bespoke
+// httptest.Server per scenario or live API keys.
+package replay
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// UpdateVectors mirrors summary's -update-golden flag: run
+// `go test -update-vectors` against a Recorder-backed test to refresh a
+// source's fixtures from the live API instead of replaying them.
+var UpdateVectors = flag.Bool("update-vectors", false, "record live API vectors instead of replaying them")
+
+// vectorVersion is bumped whenever the Vector JSON shape changes, so a Player
+// can reject fixtures recorded against an older, incompatible layout.
+const vectorVersion = 1
+
+// defaultSensitiveHeaders mirrors httpclient.defaultSensitiveHeaders: these
+// are never persisted into a fixture file, since fixtures are meant to be
+// committed to source control.
+var defaultSensitiveHeaders = []string{"Authorization", "appkey", "appsecret"}
+
+// Vector is one recorded request/response pair.
+type Vector struct {
+	Version  int            `json:"version"`
+	Request  VectorRequest  `json:"request"`
+	Response VectorResponse `json:"response"`
+}
+
+// VectorRequest identifies a request well enough for Player to match a
+// replayed call back to it, without pinning down incidental details like
+// header order or the Host header on the synthetic base URL used in tests.
+type VectorRequest struct {
+	Method   string              `json:"method"`
+	Path     string              `json:"path"`
+	Query    string              `json:"query,omitempty"`
+	Headers  map[string][]string `json:"headers,omitempty"`
+	BodyHash string              `json:"body_hash,omitempty"`
+}
+
+// VectorResponse is the stored response for a VectorRequest.
+type VectorResponse struct {
+	Status  int                 `json:"status"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    string              `json:"body"`
+}
+
+// Path returns the on-disk location of a test case's vector file:
+// testdata/vectors/<source>/<caseID>.json.
+func Path(source, caseID string) string {
+	return filepath.Join("testdata", "vectors", source, caseID+".json")
+}
+
+// ReadVectors loads the vectors previously written to path by WriteVectors.
+func ReadVectors(path string) ([]Vector, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read vector file %s: %w", path, err)
+	}
+	var vectors []Vector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		return nil, fmt.Errorf("parse vector file %s: %w", path, err)
+	}
+	for _, v := range vectors {
+		if v.Version != vectorVersion {
+			return nil, fmt.Errorf("vector file %s: version %d, want %d", path, v.Version, vectorVersion)
+		}
+	}
+	return vectors, nil
+}
+
+// WriteVectors persists vectors to path as indented JSON, creating parent
+// directories as needed.
+func WriteVectors(path string, vectors []Vector) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create vector dir: %w", err)
+	}
+	data, err := json.MarshalIndent(vectors, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal vectors: %w", err)
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write vector file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Recorder wraps a real http.RoundTripper and captures every request/response
+// pair it sees. Headers in SensitiveHeaders, plus the fixed
+// Authorization/appkey/appsecret set, are stripped before a pair is recorded
+// so fixture files never carry live credentials.
+type Recorder struct {
+	SensitiveHeaders []string
+	Transport        http.RoundTripper
+
+	vectors []Vector
+}
+
+// NewRecorder creates a Recorder that delegates to transport. A nil transport
+// defaults to http.DefaultTransport.
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{Transport: transport}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read request body for recording: %w", err)
+	}
+
+	resp, err := r.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := drainBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body for recording: %w", err)
+	}
+
+	r.vectors = append(r.vectors, Vector{
+		Version: vectorVersion,
+		Request: VectorRequest{
+			Method:   req.Method,
+			Path:     req.URL.Path,
+			Query:    req.URL.Query().Encode(),
+			Headers:  stripSensitive(req.Header, r.SensitiveHeaders),
+			BodyHash: hashBody(reqBody),
+		},
+		Response: VectorResponse{
+			Status:  resp.StatusCode,
+			Headers: map[string][]string(resp.Header),
+			Body:    string(respBody),
+		},
+	})
+
+	return resp, nil
+}
+
+// Vectors returns the pairs recorded so far, in request order.
+func (r *Recorder) Vectors() []Vector {
+	return r.vectors
+}
+
+// Save writes the recorded vectors for a test case to
+// testdata/vectors/<source>/<caseID>.json.
+func (r *Recorder) Save(source, caseID string) error {
+	return WriteVectors(Path(source, caseID), r.vectors)
+}
+
+// ErrNoMatchingVector signals that a Player received a request with no stored
+// vector matching its method, path, query, and body hash.
+var ErrNoMatchingVector = errors.New("replay: no matching vector for request")
+
+// Player serves stored vectors as an http.RoundTripper. Vectors are consumed
+// on first match, in file order, so a fixture may contain repeated
+// method+path+query+body signatures (e.g. a 500 followed by a 200) to drive a
+// caller's retry loop.
+type Player struct {
+	vectors []Vector
+}
+
+// NewPlayerFromVectors builds a Player directly from an in-memory vector
+// slice, for tests of the harness itself.
+func NewPlayerFromVectors(vectors []Vector) *Player {
+	return &Player{vectors: append([]Vector(nil), vectors...)}
+}
+
+// NewPlayer loads the vector file for (source, caseID) and returns a Player
+// serving it.
+func NewPlayer(source, caseID string) (*Player, error) {
+	vectors, err := ReadVectors(Path(source, caseID))
+	if err != nil {
+		return nil, err
+	}
+	return NewPlayerFromVectors(vectors), nil
+}
+
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read request body for replay: %w", err)
+	}
+
+	query := req.URL.Query().Encode()
+	bodyHash := hashBody(reqBody)
+
+	for i, v := range p.vectors {
+		if v.Request.Method != req.Method || v.Request.Path != req.URL.Path ||
+			v.Request.Query != query || v.Request.BodyHash != bodyHash {
+			continue
+		}
+		p.vectors = append(p.vectors[:i], p.vectors[i+1:]...)
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewReader([]byte(v.Response.Body))),
+			Header:     http.Header(v.Response.Headers),
+			Proto:      "HTTP/1.1",
+			Request:    req,
+			Status:     http.StatusText(v.Response.Status),
+			StatusCode: v.Response.Status,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s %s?%s body=%s: %w", req.Method, req.URL.Path, query, bodyHash, ErrNoMatchingVector)
+}
+
+// drainBody reads *body fully and replaces it with a fresh reader over the
+// same bytes, so the caller (and the real transport, for a Recorder) can
+// still consume it after this returns.
+func drainBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	if err != nil {
+		return nil, err
+	}
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+func hashBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func stripSensitive(h http.Header, extra []string) map[string][]string {
+	clone := h.Clone()
+	for _, name := range defaultSensitiveHeaders {
+		clone.Del(name)
+	}
+	for _, name := range extra {
+		clone.Del(name)
+	}
+	return map[string][]string(clone)
+}
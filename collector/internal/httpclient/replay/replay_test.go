@@ -0,0 +1,135 @@
+package replay
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecorderThenPlayer_RoundTrips(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("server saw Authorization = %q, want Bearer secret", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	rec := NewRecorder(srv.Client().Transport)
+	client := &http.Client{Transport: rec}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v1/prices?symbol=AAPL", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("recorded request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	vectors := rec.Vectors()
+	if len(vectors) != 1 {
+		t.Fatalf("len(vectors) = %d, want 1", len(vectors))
+	}
+	if _, ok := vectors[0].Request.Headers["Authorization"]; ok {
+		t.Error("Authorization header was recorded, want stripped")
+	}
+	if vectors[0].Response.Body != `{"ok":true}` {
+		t.Errorf("response body = %q, want {\"ok\":true}", vectors[0].Response.Body)
+	}
+
+	player := NewPlayerFromVectors(vectors)
+	replayClient := &http.Client{Transport: player}
+
+	replayReq, _ := http.NewRequest(http.MethodGet, "https://example.invalid/v1/prices?symbol=AAPL", nil)
+	replayResp, err := replayClient.Do(replayReq)
+	if err != nil {
+		t.Fatalf("replayed request failed: %v", err)
+	}
+	defer replayResp.Body.Close()
+
+	var buf bytes.Buffer
+	buf.ReadFrom(replayResp.Body)
+	if buf.String() != `{"ok":true}` {
+		t.Errorf("replayed body = %q, want {\"ok\":true}", buf.String())
+	}
+}
+
+func TestPlayer_UnmatchedRequestFailsLoudly(t *testing.T) {
+	player := NewPlayerFromVectors(nil)
+	client := &http.Client{Transport: player}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.invalid/v1/prices", nil)
+	_, err := client.Do(req)
+	if err == nil {
+		t.Fatal("expected an error for an unmatched request")
+	}
+	if !errors.Is(err, ErrNoMatchingVector) {
+		t.Errorf("error = %v, want ErrNoMatchingVector", err)
+	}
+}
+
+func TestPlayer_ConsumesVectorsInOrderForRepeatedSignatures(t *testing.T) {
+	vectors := []Vector{
+		{
+			Version: vectorVersion,
+			Request: VectorRequest{Method: http.MethodGet, Path: "/v1/prices"},
+			Response: VectorResponse{
+				Status: http.StatusInternalServerError,
+				Body:   "server error",
+			},
+		},
+		{
+			Version: vectorVersion,
+			Request: VectorRequest{Method: http.MethodGet, Path: "/v1/prices"},
+			Response: VectorResponse{
+				Status: http.StatusOK,
+				Body:   `{"ok":true}`,
+			},
+		},
+	}
+	player := NewPlayerFromVectors(vectors)
+	client := &http.Client{Transport: player}
+
+	for _, wantStatus := range []int{http.StatusInternalServerError, http.StatusOK} {
+		req, _ := http.NewRequest(http.MethodGet, "https://example.invalid/v1/prices", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != wantStatus {
+			t.Errorf("status = %d, want %d", resp.StatusCode, wantStatus)
+		}
+	}
+}
+
+func TestWriteVectorsThenReadVectors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "case.json")
+
+	want := []Vector{{
+		Version: vectorVersion,
+		Request: VectorRequest{Method: http.MethodGet, Path: "/v1/prices", Query: "symbol=AAPL"},
+		Response: VectorResponse{
+			Status: http.StatusOK,
+			Body:   `{"ok":true}`,
+		},
+	}}
+
+	if err := WriteVectors(path, want); err != nil {
+		t.Fatalf("WriteVectors failed: %v", err)
+	}
+
+	got, err := ReadVectors(path)
+	if err != nil {
+		t.Fatalf("ReadVectors failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Request.Query != "symbol=AAPL" || got[0].Response.Body != `{"ok":true}` {
+		t.Errorf("ReadVectors = %+v, want round-trip of %+v", got, want)
+	}
+}
@@ -0,0 +1,179 @@
+// Package influx implements store.TSDBWriter against InfluxDB v2's line
+// protocol write endpoint.
+package influx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/ratelimit"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	writePath      = "/api/v2/write"
+)
+
+// DefaultRetryConfig backs off on 429/5xx write failures; tuned similarly to
+// the collectors' own retry configs (cmd/collect/source.go) since a TSDB
+// write sits in the same request budget as the collection run.
+var DefaultRetryConfig = ratelimit.RetryConfig{
+	InitialBackoff: 500 * time.Millisecond,
+	MaxAttempts:    3,
+	MaxBackoff:     10 * time.Second,
+}
+
+// WriteError represents a non-2xx response from InfluxDB's write endpoint.
+type WriteError struct {
+	Body       string
+	StatusCode int
+}
+
+func (e *WriteError) Error() string {
+	return fmt.Sprintf("HTTP %d POST influx write: %s", e.StatusCode, e.Body)
+}
+
+// isRetryable reports whether err warrants another attempt: 429/5xx responses,
+// or any non-WriteError (network failure, timeout) since those are transient.
+func isRetryable(err error) bool {
+	var writeErr *WriteError
+	if !errors.As(err, &writeErr) {
+		return true
+	}
+	return writeErr.StatusCode == http.StatusTooManyRequests || writeErr.StatusCode >= 500
+}
+
+// Writer implements store.TSDBWriter against an InfluxDB v2 bucket. Each
+// WritePrices/WriteFXRates call batches its rows into a single write request.
+// It POSTs through a plain net/http.Client rather than httpclient.Client, and
+// retries via ratelimit.WithRetry with isRetryable.
+type Writer struct {
+	bucket     string
+	httpClient *http.Client
+	retryCfg   ratelimit.RetryConfig
+	token      string
+	url        string
+}
+
+// NewWriter creates a Writer. Pass nil httpClient for defaults.
+func NewWriter(baseURL, token, bucket string, httpClient *http.Client, retryCfg ratelimit.RetryConfig) *Writer {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Writer{
+		bucket:     bucket,
+		httpClient: httpClient,
+		retryCfg:   retryCfg,
+		token:      token,
+		url:        baseURL,
+	}
+}
+
+func (w *Writer) WritePrices(ctx context.Context, prices []domain.DailyPrice) error {
+	if len(prices) == 0 {
+		return nil
+	}
+	lines := make([]string, len(prices))
+	for i, p := range prices {
+		lines[i] = priceLine(p)
+	}
+	return w.write(ctx, lines)
+}
+
+func (w *Writer) WriteFXRates(ctx context.Context, rates []domain.FXRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	lines := make([]string, len(rates))
+	for i, r := range rates {
+		lines[i] = fxLine(r)
+	}
+	return w.write(ctx, lines)
+}
+
+func (w *Writer) write(ctx context.Context, lines []string) error {
+	body := []byte(strings.Join(lines, "\n"))
+
+	_, err := ratelimit.WithRetry(ctx, w.retryCfg, isRetryable, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, w.writeOnce(ctx, body)
+	})
+	return err
+}
+
+func (w *Writer) writeOnce(ctx context.Context, body []byte) error {
+	reqURL, err := w.writeURL()
+	if err != nil {
+		return fmt.Errorf("build influx write url: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create influx write request: %w", err)
+	}
+	req.Header.Set("Authorization", "Token "+w.token)
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("influx write request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return &WriteError{Body: string(respBody), StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+func (w *Writer) writeURL() (string, error) {
+	base, err := url.Parse(strings.TrimRight(w.url, "/") + writePath)
+	if err != nil {
+		return "", fmt.Errorf("parse influx base url %q: %w", w.url, err)
+	}
+	q := base.Query()
+	q.Set("bucket", w.bucket)
+	q.Set("precision", "ns")
+	base.RawQuery = q.Encode()
+	return base.String(), nil
+}
+
+// priceLine serialises a DailyPrice as InfluxDB line protocol, e.g.
+// "price,symbol=NVDA,source=tiingo open=...,high=...,low=...,close=...,adj_close=...,volume=...i <date-ns>".
+func priceLine(p domain.DailyPrice) string {
+	return fmt.Sprintf(
+		"price,symbol=%s,source=%s open=%s,high=%s,low=%s,close=%s,adj_close=%s,volume=%di %d",
+		escapeTag(p.Symbol), escapeTag(p.Source),
+		formatFloat(p.Open), formatFloat(p.High), formatFloat(p.Low), formatFloat(p.Close), formatFloat(p.AdjClose),
+		p.Volume, p.Date.UnixNano(),
+	)
+}
+
+// fxLine serialises an FXRate as InfluxDB line protocol, e.g.
+// "fx,pair=USD/KRW,source=frankfurter rate=... <date-ns>".
+func fxLine(r domain.FXRate) string {
+	return fmt.Sprintf(
+		"fx,pair=%s,source=%s rate=%s %d",
+		escapeTag(r.Pair), escapeTag(r.Source), formatFloat(r.Rate), r.Date.UnixNano(),
+	)
+}
+
+// escapeTag escapes the characters line protocol reserves in tag keys/values:
+// commas, spaces, and equals signs.
+func escapeTag(v string) string {
+	r := strings.NewReplacer(",", `\,`, " ", `\ `, "=", `\=`)
+	return r.Replace(v)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,170 @@
+package influx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/ratelimit"
+)
+
+func TestWritePrices_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if r.URL.Path != writePath {
+			t.Errorf("path = %s, want %s", r.URL.Path, writePath)
+		}
+		if got, want := r.URL.Query().Get("bucket"), "jusikbot"; got != want {
+			t.Errorf("bucket = %q, want %q", got, want)
+		}
+		if r.Header.Get("Authorization") != "Token test-token" {
+			t.Errorf("missing auth header, got %q", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	w := NewWriter(srv.URL, "test-token", "jusikbot", srv.Client(), DefaultRetryConfig)
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	prices := []domain.DailyPrice{
+		{AdjClose: 875.28, Close: 875.28, Date: date, High: 880, Low: 860, Open: 870, Source: "tiingo", Symbol: "NVDA", Volume: 1000},
+	}
+
+	if err := w.WritePrices(context.Background(), prices); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestWritePrices_LineProtocolFormat(t *testing.T) {
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make([]byte, r.ContentLength)
+		r.Body.Read(body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	w := NewWriter(srv.URL, "test-token", "jusikbot", srv.Client(), DefaultRetryConfig)
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	prices := []domain.DailyPrice{
+		{AdjClose: 875.28, Close: 875.28, Date: date, High: 880, Low: 860, Open: 870, Source: "tiingo", Symbol: "NVDA", Volume: 1000},
+	}
+
+	if err := w.WritePrices(context.Background(), prices); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "price,symbol=NVDA,source=tiingo open=870,high=880,low=860,close=875.28,adj_close=875.28,volume=1000i " +
+		"1709251200000000000"
+	if gotBody != want {
+		t.Errorf("body = %q, want %q", gotBody, want)
+	}
+}
+
+func TestWriteFXRates_LineProtocolFormat(t *testing.T) {
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make([]byte, r.ContentLength)
+		r.Body.Read(body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	w := NewWriter(srv.URL, "test-token", "jusikbot", srv.Client(), DefaultRetryConfig)
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	rates := []domain.FXRate{
+		{Date: date, Pair: "USD/KRW", Rate: 1330.5, Source: "frankfurter"},
+	}
+
+	if err := w.WriteFXRates(context.Background(), rates); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "fx,pair=USD/KRW,source=frankfurter rate=1330.5 1709251200000000000"
+	if gotBody != want {
+		t.Errorf("body = %q, want %q", gotBody, want)
+	}
+}
+
+func TestWritePrices_Empty(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	w := NewWriter(srv.URL, "test-token", "jusikbot", srv.Client(), DefaultRetryConfig)
+
+	if err := w.WritePrices(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if called {
+		t.Error("expected no request for an empty batch")
+	}
+}
+
+func TestWritePrices_RetriesOn503ThenSucceeds(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	w := NewWriter(srv.URL, "test-token", "jusikbot", srv.Client(), ratelimit.RetryConfig{
+		InitialBackoff: time.Millisecond,
+		MaxAttempts:    3,
+		MaxBackoff:     5 * time.Millisecond,
+	})
+
+	prices := []domain.DailyPrice{{Date: time.Now(), Source: "tiingo", Symbol: "NVDA"}}
+	if err := w.WritePrices(context.Background(), prices); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts.Load() != 2 {
+		t.Errorf("attempts = %d, want 2", attempts.Load())
+	}
+}
+
+func TestWritePrices_StopsOn400(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("invalid line protocol"))
+	}))
+	defer srv.Close()
+
+	w := NewWriter(srv.URL, "test-token", "jusikbot", srv.Client(), ratelimit.RetryConfig{
+		InitialBackoff: time.Millisecond,
+		MaxAttempts:    3,
+		MaxBackoff:     5 * time.Millisecond,
+	})
+
+	prices := []domain.DailyPrice{{Date: time.Now(), Source: "tiingo", Symbol: "NVDA"}}
+	err := w.WritePrices(context.Background(), prices)
+	if err == nil {
+		t.Fatal("expected error for a 400 response")
+	}
+	if !strings.Contains(err.Error(), "invalid line protocol") {
+		t.Errorf("error = %v, want it to contain the response body", err)
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("attempts = %d, want 1 (400 should not retry)", attempts.Load())
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/jusikbot/collector/internal/domain"
 	"github.com/jusikbot/collector/internal/httpclient"
+	"github.com/jusikbot/collector/internal/ratelimit"
 )
 
 const (
@@ -51,17 +52,31 @@ func NewClient(httpClient *httpclient.Client, tokenProvider *TokenProvider) *Cli
 
 // FetchDailyPrices fetches all pages of daily prices for a symbol within the date range.
 // Returns data sorted ascending by date. Implements domain.StockDataFetcher.
+// A thin wrapper around FetchDailyPricesWithOptions, kept so Client still
+// satisfies StockDataFetcher's fixed signature.
 func (c *Client) FetchDailyPrices(ctx context.Context, symbol string, from, to time.Time) ([]domain.DailyPrice, error) {
+	return c.FetchDailyPricesWithOptions(ctx, symbol, WithDateRange(from, to))
+}
+
+// FetchDailyPricesWithOptions fetches all pages of daily prices for a symbol,
+// configured via FetchOptions such as WithDateRange, WithSince, WithPeriod,
+// WithAdjustment, and WithMaxPages. Returns data sorted ascending by date.
+func (c *Client) FetchDailyPricesWithOptions(ctx context.Context, symbol string, opts ...FetchOption) ([]domain.DailyPrice, error) {
+	cfg := newFetchConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	var allPrices []domain.DailyPrice
-	cursor := kisCursor{endDate: to, hasMore: true}
+	cursor := kisCursor{endDate: cfg.to, hasMore: true}
 
-	for page := range maxPages {
+	for page := range cfg.maxPages {
 		if !cursor.hasMore {
 			break
 		}
 
 		prevEndDate := cursor.endDate
-		prices, nextCursor, err := c.fetchDailyPricesPage(ctx, symbol, from, cursor.endDate)
+		prices, nextCursor, err := c.fetchDailyPricesPage(ctx, symbol, cfg.cursorFloor(), cursor.endDate, cfg.period, cfg.adjustment)
 		if err != nil {
 			return allPrices, fmt.Errorf("page %d for %s: %w", page, symbol, err)
 		}
@@ -91,7 +106,9 @@ func (c *Client) FetchDailyPrices(ctx context.Context, symbol string, from, to t
 func (c *Client) fetchDailyPricesPage(
 	ctx context.Context,
 	symbol string,
-	from, to time.Time,
+	floor, to time.Time,
+	period domain.Period,
+	adjustment domain.AdjustmentMode,
 ) ([]domain.DailyPrice, kisCursor, error) {
 	accessToken, err := c.token.Token(ctx)
 	if err != nil {
@@ -102,11 +119,11 @@ func (c *Client) fetchDailyPricesPage(
 		httpclient.WithHeader("authorization", "Bearer "+accessToken),
 		httpclient.WithHeader("tr_id", trIDDailyChart),
 		httpclient.WithQueryParam("FID_COND_MRKT_DIV_CODE", "J"),
-		httpclient.WithQueryParam("FID_INPUT_DATE_1", from.Format("20060102")),
+		httpclient.WithQueryParam("FID_INPUT_DATE_1", floor.Format("20060102")),
 		httpclient.WithQueryParam("FID_INPUT_DATE_2", to.Format("20060102")),
 		httpclient.WithQueryParam("FID_INPUT_ISCD", symbol),
-		httpclient.WithQueryParam("FID_ORG_ADJ_PRC", "0"),
-		httpclient.WithQueryParam("FID_PERIOD_DIV_CODE", "D"),
+		httpclient.WithQueryParam("FID_ORG_ADJ_PRC", string(adjustment)),
+		httpclient.WithQueryParam("FID_PERIOD_DIV_CODE", string(period)),
 	)
 	if err != nil {
 		return nil, kisCursor{}, err
@@ -126,14 +143,15 @@ func (c *Client) fetchDailyPricesPage(
 		return nil, kisCursor{}, err
 	}
 
-	nextCursor := buildNextCursor(prices, from)
+	nextCursor := buildNextCursor(prices, floor)
 	return prices, nextCursor, nil
 }
 
 // buildNextCursor determines if more pages are needed.
-// KIS returns data newest-first. If the oldest row's date is still after `from`,
-// there may be more data. Set endDate to one day before the oldest date.
-func buildNextCursor(prices []domain.DailyPrice, from time.Time) kisCursor {
+// KIS returns data newest-first. If the oldest row's date is still after floor
+// (from, or the tighter WithSince watermark), there may be more data. Set
+// endDate to one day before the oldest date.
+func buildNextCursor(prices []domain.DailyPrice, floor time.Time) kisCursor {
 	if len(prices) == 0 {
 		return kisCursor{hasMore: false}
 	}
@@ -145,7 +163,7 @@ func buildNextCursor(prices []domain.DailyPrice, from time.Time) kisCursor {
 		}
 	}
 
-	if oldestDate.After(from) {
+	if oldestDate.After(floor) {
 		return kisCursor{
 			endDate: oldestDate.AddDate(0, 0, -1),
 			hasMore: true,
@@ -172,12 +190,5 @@ func parseOutputRows(rows []kisOutputRow, symbol string) ([]domain.DailyPrice, e
 
 // IsRetryable determines whether an error from the KIS client warrants retry.
 func IsRetryable(err error) bool {
-	if errors.Is(err, httpclient.ErrRateLimited) {
-		return true
-	}
-	var apiErr *httpclient.APIError
-	if errors.As(err, &apiErr) {
-		return apiErr.IsRetryable
-	}
-	return false
+	return ratelimit.DefaultIsRetryable(err)
 }
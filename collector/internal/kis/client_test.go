@@ -18,7 +18,7 @@ func newStubTokenProvider(t *testing.T, token string) *TokenProvider {
 	t.Helper()
 	srv := httptest.NewServer(validTokenHandler(token, 86400))
 	t.Cleanup(srv.Close)
-	return NewTokenProvider(srv.URL, "test-key", "test-secret", srv.Client())
+	return NewTokenProvider(srv.URL, "test-key", "test-secret", httpclient.NewClient(srv.URL, nil, srv.Client(), 0), nil)
 }
 
 func newTestKISClient(t *testing.T, srv *httptest.Server, tokenProvider *TokenProvider) *Client {
@@ -16,13 +16,15 @@ const defaultLookbackDays = 365
 
 // Collector orchestrates sequential symbol collection with rate limiting and anomaly detection.
 type Collector struct {
+	breaker  *ratelimit.CircuitBreaker
 	client   *Client
 	limiter  *rate.Limiter
 	retryCfg ratelimit.RetryConfig
 }
 
-func NewCollector(client *Client, limiter *rate.Limiter, retryCfg ratelimit.RetryConfig) *Collector {
+func NewCollector(client *Client, limiter *rate.Limiter, retryCfg ratelimit.RetryConfig, breaker *ratelimit.CircuitBreaker) *Collector {
 	return &Collector{
+		breaker:  breaker,
 		client:   client,
 		limiter:  limiter,
 		retryCfg: retryCfg,
@@ -30,7 +32,9 @@ func NewCollector(client *Client, limiter *rate.Limiter, retryCfg ratelimit.Retr
 }
 
 // CollectAll fetches daily prices for all KR entries sequentially with rate limiting.
-// Returns partial results on error.
+// Returns partial results on error. The circuit breaker trips on sustained
+// failures so a fully down KIS endpoint fails fast (ErrCircuitOpen) instead of
+// burning MaxAttempts retries on a symbol that was never going to succeed.
 func (c *Collector) CollectAll(
 	ctx context.Context,
 	entries []domain.WatchlistEntry,
@@ -50,7 +54,7 @@ func (c *Collector) CollectAll(
 			continue
 		}
 
-		raw, err := ratelimit.FetchWithRateLimit(ctx, c.limiter, c.retryCfg, IsRetryable,
+		raw, err := ratelimit.FetchWithRateLimitCB(ctx, c.limiter, c.breaker, c.retryCfg, IsRetryable,
 			func(ctx context.Context) ([]domain.DailyPrice, error) {
 				return c.client.FetchDailyPrices(ctx, entry.Symbol, from, to)
 			},
@@ -72,7 +76,7 @@ func (c *Collector) CollectAll(
 // For KR market, 30% threshold matches KRX price limits, making IsPriceAnomaly sufficient.
 func markAnomalies(prices []domain.DailyPrice, entry domain.WatchlistEntry) []domain.DailyPrice {
 	for i := 1; i < len(prices); i++ {
-		if validate.IsPriceAnomaly(prices[i].AdjClose, prices[i-1].AdjClose, entry.Market, entry.Type) {
+		if validate.IsPriceAnomaly(prices[i].AdjClose, prices[i-1].AdjClose, domain.MarketAdapterFor(entry.Market), entry.Type) {
 			prices[i].IsAnomaly = true
 			slog.Warn("anomaly detected",
 				"change_pct", fmt.Sprintf("%.1f%%", (prices[i].AdjClose-prices[i-1].AdjClose)/prices[i-1].AdjClose*100),
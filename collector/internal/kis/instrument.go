@@ -0,0 +1,126 @@
+package kis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/httpclient"
+)
+
+const (
+	instrumentInfoPath = "/uapi/domestic-stock/v1/quotations/search-info"
+	trIDInstrumentInfo = "CTPF1604R"
+)
+
+// kisInstrumentInfoResponse represents the KIS product-info (상품기본조회) API response.
+type kisInstrumentInfoResponse struct {
+	MsgCode string               `json:"msg_cd"`
+	Msg     string               `json:"msg1"`
+	Output  kisInstrumentInfoRow `json:"output"`
+	RtCode  string               `json:"rt_cd"`
+}
+
+// kisInstrumentInfoRow represents the single-row output envelope from the KIS
+// product-info inquiry. Why all numeric fields are strings: same convention as
+// kisOutputRow in parse.go — KIS returns every numeric value as a JSON string.
+type kisInstrumentInfoRow struct {
+	HoUnitAmt   string `json:"ho_unit_amt"`    // tick size
+	LstgStqt    string `json:"lstg_stqt"`      // listed shares
+	MketIdCd    string `json:"mket_id_cd"`     // listing board code
+	OrdStopYn   string `json:"ord_stop_yn"`    // trading halt flag
+	StckDdprLmt string `json:"dprt_stck_ubpr"` // upper price limit
+	StckDdprLwr string `json:"dprt_stck_lwpr"` // lower price limit
+}
+
+// FetchInstrumentInfo fetches tick size, lot size, daily price limits, listed
+// shares, and the market/halt identifiers for symbol via the KIS product-info
+// endpoint. Callers wanting to avoid repeated lookups across runs should go
+// through InstrumentCache instead of calling this directly.
+func (c *Client) FetchInstrumentInfo(ctx context.Context, symbol string) (domain.InstrumentInfo, error) {
+	accessToken, err := c.token.Token(ctx)
+	if err != nil {
+		return domain.InstrumentInfo{}, fmt.Errorf("obtain token: %w", err)
+	}
+
+	body, _, err := c.http.Get(ctx, instrumentInfoPath,
+		httpclient.WithHeader("authorization", "Bearer "+accessToken),
+		httpclient.WithHeader("tr_id", trIDInstrumentInfo),
+		httpclient.WithQueryParam("PDNO", symbol),
+		httpclient.WithQueryParam("PRDT_TYPE_CD", "300"),
+	)
+	if err != nil {
+		return domain.InstrumentInfo{}, err
+	}
+
+	var resp kisInstrumentInfoResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return domain.InstrumentInfo{}, fmt.Errorf("parse KIS instrument info response for %s: %w", symbol, err)
+	}
+	if resp.RtCode != "0" {
+		return domain.InstrumentInfo{}, fmt.Errorf("KIS instrument info API error for %s (code=%s): %s", symbol, resp.MsgCode, resp.Msg)
+	}
+
+	return toInstrumentInfo(resp.Output, symbol)
+}
+
+func toInstrumentInfo(row kisInstrumentInfoRow, symbol string) (domain.InstrumentInfo, error) {
+	listedShares, err := parseInt64(row.LstgStqt)
+	if err != nil {
+		return domain.InstrumentInfo{}, fmt.Errorf("parse listed shares %q: %w", row.LstgStqt, err)
+	}
+
+	tickSize, err := parseFloat64(row.HoUnitAmt)
+	if err != nil {
+		return domain.InstrumentInfo{}, fmt.Errorf("parse tick size %q: %w", row.HoUnitAmt, err)
+	}
+
+	upperLimit, err := parseFloat64(row.StckDdprLmt)
+	if err != nil {
+		return domain.InstrumentInfo{}, fmt.Errorf("parse upper limit price %q: %w", row.StckDdprLmt, err)
+	}
+
+	lowerLimit, err := parseFloat64(row.StckDdprLwr)
+	if err != nil {
+		return domain.InstrumentInfo{}, fmt.Errorf("parse lower limit price %q: %w", row.StckDdprLwr, err)
+	}
+
+	return domain.InstrumentInfo{
+		// KIS doesn't return a KRX lot size for ordinary equities (it's always 1
+		// share); a dedicated field would only ever hold "1", so it's fixed here
+		// rather than parsed from a response field that doesn't exist.
+		ListedShares:    listedShares,
+		LotSize:         1,
+		LowerLimitPrice: lowerLimit,
+		MarketCode:      row.MketIdCd,
+		Symbol:          symbol,
+		TickSize:        tickSize,
+		TradingHalted:   row.OrdStopYn == "Y",
+		UpperLimitPrice: upperLimit,
+	}, nil
+}
+
+// FetchInstrument returns symbol's exchange-level contract specification,
+// derived from the same product-info lookup as FetchInstrumentInfo. Unlike
+// InstrumentInfo, the result is meant to be persisted via
+// domain.InstrumentRepository rather than refreshed on a TTL, since KRX
+// equity contract specs (currency, settlement cycle, multiplier) don't
+// change intraday the way trading halts and price limits do.
+func (c *Client) FetchInstrument(ctx context.Context, symbol string) (domain.Instrument, error) {
+	info, err := c.FetchInstrumentInfo(ctx, symbol)
+	if err != nil {
+		return domain.Instrument{}, err
+	}
+
+	return domain.Instrument{
+		AmountTickSize: float64(info.LotSize),
+		// Ordinary KRX equities/ETFs; 1 unit represents 1 share.
+		ContractMultiplier: 1,
+		Exchange:           "KRX",
+		PriceTickSize:      info.TickSize,
+		QuoteCurrency:      "KRW",
+		SettlementType:     domain.SettlementTPlus2,
+		Symbol:             symbol,
+	}, nil
+}
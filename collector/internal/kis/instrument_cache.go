@@ -0,0 +1,64 @@
+package kis
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// DefaultInstrumentCacheTTL is how long a cached domain.InstrumentInfo is
+// trusted before InstrumentCache refetches it. Tick size, lot size, and price
+// limits change rarely enough (typically only on corporate actions) that a
+// day's staleness is an acceptable tradeoff against hammering the API on every
+// summary/rebalance run.
+const DefaultInstrumentCacheTTL = 24 * time.Hour
+
+type instrumentCacheEntry struct {
+	fetchedAt time.Time
+	info      domain.InstrumentInfo
+}
+
+// InstrumentCache memoizes Client.FetchInstrumentInfo per symbol for ttl, the
+// same lazy-fetch-then-reuse shape TokenProvider applies to access tokens.
+// Why sync.Mutex over sync.RWMutex: same reasoning as TokenProvider — every
+// Get checks the entry's age, which may trigger a write on a miss or expiry.
+type InstrumentCache struct {
+	client  *Client
+	entries map[string]instrumentCacheEntry
+	mu      sync.Mutex
+	ttl     time.Duration
+}
+
+// NewInstrumentCache wraps client with a TTL cache. A zero ttl falls back to
+// DefaultInstrumentCacheTTL.
+func NewInstrumentCache(client *Client, ttl time.Duration) *InstrumentCache {
+	if ttl <= 0 {
+		ttl = DefaultInstrumentCacheTTL
+	}
+	return &InstrumentCache{
+		client:  client,
+		entries: make(map[string]instrumentCacheEntry),
+		ttl:     ttl,
+	}
+}
+
+// Get returns symbol's cached InstrumentInfo, fetching it via the wrapped
+// Client on a miss or expiry.
+func (c *InstrumentCache) Get(ctx context.Context, symbol string) (domain.InstrumentInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[symbol]; ok && time.Since(entry.fetchedAt) < c.ttl {
+		return entry.info, nil
+	}
+
+	info, err := c.client.FetchInstrumentInfo(ctx, symbol)
+	if err != nil {
+		return domain.InstrumentInfo{}, err
+	}
+
+	c.entries[symbol] = instrumentCacheEntry{fetchedAt: time.Now(), info: info}
+	return info, nil
+}
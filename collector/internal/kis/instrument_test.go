@@ -0,0 +1,214 @@
+package kis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func kisInstrumentInfoSuccessResponse(row kisInstrumentInfoRow) kisInstrumentInfoResponse {
+	return kisInstrumentInfoResponse{
+		MsgCode: "MCA00000",
+		Msg:     "정상처리 되었습니다.",
+		Output:  row,
+		RtCode:  "0",
+	}
+}
+
+func sampleInstrumentInfoRow() kisInstrumentInfoRow {
+	return kisInstrumentInfoRow{
+		HoUnitAmt:   "100",
+		LstgStqt:    "5969782550",
+		MketIdCd:    "KOSPI",
+		OrdStopYn:   "N",
+		StckDdprLmt: "93600",
+		StckDdprLwr: "50400",
+	}
+}
+
+func TestFetchInstrumentInfo(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		stubTP := newStubTokenProvider(t, "test-bearer-token")
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != instrumentInfoPath {
+				t.Errorf("path = %q, want %s", r.URL.Path, instrumentInfoPath)
+			}
+			if got := r.Header.Get("authorization"); got != "Bearer test-bearer-token" {
+				t.Errorf("authorization = %q, want Bearer test-bearer-token", got)
+			}
+			if got := r.Header.Get("tr_id"); got != trIDInstrumentInfo {
+				t.Errorf("tr_id = %q, want %s", got, trIDInstrumentInfo)
+			}
+			if got := r.URL.Query().Get("PDNO"); got != "005930" {
+				t.Errorf("PDNO = %q, want 005930", got)
+			}
+
+			resp := kisInstrumentInfoSuccessResponse(sampleInstrumentInfoRow())
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		}))
+		defer srv.Close()
+
+		client := newTestKISClient(t, srv, stubTP)
+		info, err := client.FetchInstrumentInfo(context.Background(), "005930")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if info.Symbol != "005930" {
+			t.Errorf("Symbol = %q, want 005930", info.Symbol)
+		}
+		if info.TickSize != 100 {
+			t.Errorf("TickSize = %v, want 100", info.TickSize)
+		}
+		if info.LotSize != 1 {
+			t.Errorf("LotSize = %v, want 1", info.LotSize)
+		}
+		if info.ListedShares != 5969782550 {
+			t.Errorf("ListedShares = %v, want 5969782550", info.ListedShares)
+		}
+		if info.UpperLimitPrice != 93600 || info.LowerLimitPrice != 50400 {
+			t.Errorf("price limits = (%v, %v), want (93600, 50400)", info.UpperLimitPrice, info.LowerLimitPrice)
+		}
+		if info.MarketCode != "KOSPI" {
+			t.Errorf("MarketCode = %q, want KOSPI", info.MarketCode)
+		}
+		if info.TradingHalted {
+			t.Error("TradingHalted = true, want false")
+		}
+	})
+
+	t.Run("KIS API error", func(t *testing.T) {
+		stubTP := newStubTokenProvider(t, "token")
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resp := kisInstrumentInfoResponse{
+				MsgCode: "EGW00123",
+				Msg:     "유효하지 않은 토큰입니다.",
+				RtCode:  "1",
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		}))
+		defer srv.Close()
+
+		client := newTestKISClient(t, srv, stubTP)
+		_, err := client.FetchInstrumentInfo(context.Background(), "005930")
+		if err == nil {
+			t.Fatal("expected error for KIS API error response")
+		}
+	})
+}
+
+func TestFetchInstrument(t *testing.T) {
+	stubTP := newStubTokenProvider(t, "test-bearer-token")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := kisInstrumentInfoSuccessResponse(sampleInstrumentInfoRow())
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	client := newTestKISClient(t, srv, stubTP)
+	inst, err := client.FetchInstrument(context.Background(), "005930")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if inst.Symbol != "005930" {
+		t.Errorf("Symbol = %q, want 005930", inst.Symbol)
+	}
+	if inst.Exchange != "KRX" {
+		t.Errorf("Exchange = %q, want KRX", inst.Exchange)
+	}
+	if inst.QuoteCurrency != "KRW" {
+		t.Errorf("QuoteCurrency = %q, want KRW", inst.QuoteCurrency)
+	}
+	if inst.PriceTickSize != 100 {
+		t.Errorf("PriceTickSize = %v, want 100", inst.PriceTickSize)
+	}
+	if inst.AmountTickSize != 1 {
+		t.Errorf("AmountTickSize = %v, want 1", inst.AmountTickSize)
+	}
+	if inst.ContractMultiplier != 1 {
+		t.Errorf("ContractMultiplier = %v, want 1", inst.ContractMultiplier)
+	}
+	if inst.SettlementType != domain.SettlementTPlus2 {
+		t.Errorf("SettlementType = %q, want T+2", inst.SettlementType)
+	}
+}
+
+func TestInstrumentCache_Get(t *testing.T) {
+	t.Run("caches across repeated lookups within the TTL", func(t *testing.T) {
+		stubTP := newStubTokenProvider(t, "test-bearer-token")
+
+		var calls int
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			resp := kisInstrumentInfoSuccessResponse(sampleInstrumentInfoRow())
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		}))
+		defer srv.Close()
+
+		client := newTestKISClient(t, srv, stubTP)
+		cache := NewInstrumentCache(client, time.Hour)
+
+		for range 5 {
+			if _, err := cache.Get(context.Background(), "005930"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if calls != 1 {
+			t.Errorf("upstream calls = %d, want 1", calls)
+		}
+	})
+
+	t.Run("refetches after the entry expires", func(t *testing.T) {
+		stubTP := newStubTokenProvider(t, "test-bearer-token")
+
+		var calls int
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			resp := kisInstrumentInfoSuccessResponse(sampleInstrumentInfoRow())
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		}))
+		defer srv.Close()
+
+		client := newTestKISClient(t, srv, stubTP)
+		cache := NewInstrumentCache(client, time.Nanosecond)
+
+		if _, err := cache.Get(context.Background(), "005930"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+		if _, err := cache.Get(context.Background(), "005930"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if calls != 2 {
+			t.Errorf("upstream calls = %d, want 2", calls)
+		}
+	})
+
+	t.Run("a zero ttl falls back to the default", func(t *testing.T) {
+		stubTP := newStubTokenProvider(t, "token")
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer srv.Close()
+
+		client := newTestKISClient(t, srv, stubTP)
+		cache := NewInstrumentCache(client, 0)
+		if cache.ttl != DefaultInstrumentCacheTTL {
+			t.Errorf("ttl = %v, want %v", cache.ttl, DefaultInstrumentCacheTTL)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package kis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/httpclient"
+)
+
+const (
+	marginInterestPath  = "/uapi/domestic-stock/v1/trading/inquire-credit-interest"
+	marginLoanPath      = "/uapi/domestic-stock/v1/trading/inquire-credit-loan"
+	marginRepaymentPath = "/uapi/domestic-stock/v1/trading/inquire-credit-repayment"
+	trIDMarginInterest  = "TTTC8910R"
+	trIDMarginLoan      = "TTTC8909R"
+	trIDMarginRepayment = "TTTC8911R"
+)
+
+// marginLoanResponse mirrors kisResponse's envelope around credit-loan rows.
+type marginLoanResponse struct {
+	MsgCode string          `json:"msg_cd"`
+	Msg     string          `json:"msg1"`
+	Output  []marginLoanRow `json:"output"`
+	RtCode  string          `json:"rt_cd"`
+}
+
+// marginLoanRow represents a single row from the KIS credit-loan inquiry.
+// Why all fields are strings: KIS API returns all numeric values as strings.
+type marginLoanRow struct {
+	CrdtLoanAmt string `json:"crdt_loan_amt"`
+	CrdtNo      string `json:"crdt_no"`
+	IijaDate    string `json:"iija_date"`
+	PdnoCode    string `json:"pdno"`
+	RedmAbleYn  string `json:"redm_able_yn"` // "Y" while outstanding, "N" once closed
+}
+
+type marginInterestResponse struct {
+	MsgCode string              `json:"msg_cd"`
+	Msg     string              `json:"msg1"`
+	Output  []marginInterestRow `json:"output"`
+	RtCode  string              `json:"rt_cd"`
+}
+
+type marginInterestRow struct {
+	BsopDate string `json:"bsop_date"`
+	IntrAmt  string `json:"intr_amt"`
+	IntrRate string `json:"intr_rate"`
+}
+
+type marginRepaymentResponse struct {
+	MsgCode string               `json:"msg_cd"`
+	Msg     string               `json:"msg1"`
+	Output  []marginRepaymentRow `json:"output"`
+	RtCode  string               `json:"rt_cd"`
+}
+
+type marginRepaymentRow struct {
+	CrdtNo   string `json:"crdt_no"`
+	RedmAmt  string `json:"redm_amt"`
+	RedmDate string `json:"redm_date"`
+}
+
+// MarginHistoryClient wraps an httpclient.Client configured for the KIS
+// domestic-margin (신용거래) endpoints: loan issuances, daily interest accruals,
+// and repayments.
+type MarginHistoryClient struct {
+	http  *httpclient.Client
+	token *TokenProvider
+}
+
+func NewMarginHistoryClient(httpClient *httpclient.Client, tokenProvider *TokenProvider) *MarginHistoryClient {
+	return &MarginHistoryClient{
+		http:  httpClient,
+		token: tokenProvider,
+	}
+}
+
+// FetchLoans returns margin loan issuances for accountNo within the date range.
+func (c *MarginHistoryClient) FetchLoans(ctx context.Context, accountNo string, from, to time.Time) ([]domain.MarginLoan, error) {
+	body, err := c.get(ctx, marginLoanPath, trIDMarginLoan, accountNo, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp marginLoanResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parse KIS margin loan response for %s: %w", accountNo, err)
+	}
+	if resp.RtCode != "0" {
+		return nil, fmt.Errorf("KIS margin loan API error for %s (code=%s): %s", accountNo, resp.MsgCode, resp.Msg)
+	}
+
+	loans := make([]domain.MarginLoan, 0, len(resp.Output))
+	for i, row := range resp.Output {
+		loan, err := toMarginLoan(row, accountNo)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+		loans = append(loans, loan)
+	}
+	return loans, nil
+}
+
+// FetchInterestAccruals returns daily margin interest charges for accountNo within the date range.
+func (c *MarginHistoryClient) FetchInterestAccruals(ctx context.Context, accountNo string, from, to time.Time) ([]domain.MarginInterest, error) {
+	body, err := c.get(ctx, marginInterestPath, trIDMarginInterest, accountNo, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp marginInterestResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parse KIS margin interest response for %s: %w", accountNo, err)
+	}
+	if resp.RtCode != "0" {
+		return nil, fmt.Errorf("KIS margin interest API error for %s (code=%s): %s", accountNo, resp.MsgCode, resp.Msg)
+	}
+
+	entries := make([]domain.MarginInterest, 0, len(resp.Output))
+	for i, row := range resp.Output {
+		entry, err := toMarginInterest(row, accountNo)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// FetchRepayments returns margin loan repayments for accountNo within the date range.
+func (c *MarginHistoryClient) FetchRepayments(ctx context.Context, accountNo string, from, to time.Time) ([]domain.MarginRepayment, error) {
+	body, err := c.get(ctx, marginRepaymentPath, trIDMarginRepayment, accountNo, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp marginRepaymentResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parse KIS margin repayment response for %s: %w", accountNo, err)
+	}
+	if resp.RtCode != "0" {
+		return nil, fmt.Errorf("KIS margin repayment API error for %s (code=%s): %s", accountNo, resp.MsgCode, resp.Msg)
+	}
+
+	repayments := make([]domain.MarginRepayment, 0, len(resp.Output))
+	for i, row := range resp.Output {
+		repayment, err := toMarginRepayment(row, accountNo)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+		repayments = append(repayments, repayment)
+	}
+	return repayments, nil
+}
+
+func (c *MarginHistoryClient) get(ctx context.Context, path, trID, accountNo string, from, to time.Time) ([]byte, error) {
+	accessToken, err := c.token.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("obtain token: %w", err)
+	}
+
+	cano, acntPrdtCd := splitAccountNo(accountNo)
+	body, _, err := c.http.Get(ctx, path,
+		httpclient.WithHeader("authorization", "Bearer "+accessToken),
+		httpclient.WithHeader("tr_id", trID),
+		httpclient.WithQueryParam("ACNT_PRDT_CD", acntPrdtCd),
+		httpclient.WithQueryParam("CANO", cano),
+		httpclient.WithQueryParam("INQR_STRT_DT", from.Format("20060102")),
+		httpclient.WithQueryParam("INQR_END_DT", to.Format("20060102")),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
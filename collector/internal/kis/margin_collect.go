@@ -0,0 +1,110 @@
+package kis
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/ratelimit"
+	"golang.org/x/time/rate"
+)
+
+// Why 90 days: margin loans are short-term (KIS caps them well under a year);
+// 90 days comfortably covers any loan/interest/repayment still worth reporting.
+const defaultMarginLookbackDays = 90
+
+// MarginHistory holds everything collected for one account in a single CollectAll call.
+type MarginHistory struct {
+	Interest   []domain.MarginInterest
+	Loans      []domain.MarginLoan
+	Repayments []domain.MarginRepayment
+}
+
+// MarginCollector orchestrates sequential per-account margin history collection
+// with the same rate limiting and incremental gap-based fetch pattern as Collector.
+type MarginCollector struct {
+	client   *MarginHistoryClient
+	limiter  *rate.Limiter
+	retryCfg ratelimit.RetryConfig
+}
+
+func NewMarginCollector(client *MarginHistoryClient, limiter *rate.Limiter, retryCfg ratelimit.RetryConfig) *MarginCollector {
+	return &MarginCollector{
+		client:   client,
+		limiter:  limiter,
+		retryCfg: retryCfg,
+	}
+}
+
+// CollectAll fetches loans, interest accruals, and repayments for all accounts
+// sequentially with rate limiting. gaps maps accountNo to the last recorded date
+// across all three margin tables. Returns partial results on error.
+func (c *MarginCollector) CollectAll(
+	ctx context.Context,
+	accounts []string,
+	gaps map[string]time.Time,
+) (MarginHistory, error) {
+	var history MarginHistory
+	to := time.Now().Truncate(24 * time.Hour)
+
+	for _, accountNo := range accounts {
+		if ctx.Err() != nil {
+			return history, ctx.Err()
+		}
+
+		from := computeMarginStartDate(to, gaps, accountNo)
+		if !from.Before(to) {
+			slog.Info("already up to date", "account", accountNo)
+			continue
+		}
+
+		loans, err := ratelimit.FetchWithRateLimit(ctx, c.limiter, c.retryCfg, IsRetryable,
+			func(ctx context.Context) ([]domain.MarginLoan, error) {
+				return c.client.FetchLoans(ctx, accountNo, from, to)
+			},
+		)
+		if err != nil {
+			return history, fmt.Errorf("collect margin loans for %s: %w", accountNo, err)
+		}
+
+		interest, err := ratelimit.FetchWithRateLimit(ctx, c.limiter, c.retryCfg, IsRetryable,
+			func(ctx context.Context) ([]domain.MarginInterest, error) {
+				return c.client.FetchInterestAccruals(ctx, accountNo, from, to)
+			},
+		)
+		if err != nil {
+			return history, fmt.Errorf("collect margin interest for %s: %w", accountNo, err)
+		}
+
+		repayments, err := ratelimit.FetchWithRateLimit(ctx, c.limiter, c.retryCfg, IsRetryable,
+			func(ctx context.Context) ([]domain.MarginRepayment, error) {
+				return c.client.FetchRepayments(ctx, accountNo, from, to)
+			},
+		)
+		if err != nil {
+			return history, fmt.Errorf("collect margin repayments for %s: %w", accountNo, err)
+		}
+
+		history.Loans = append(history.Loans, loans...)
+		history.Interest = append(history.Interest, interest...)
+		history.Repayments = append(history.Repayments, repayments...)
+		slog.Info("collected margin history",
+			"account", accountNo, "interest", len(interest), "loans", len(loans), "repayments", len(repayments))
+	}
+
+	return history, nil
+}
+
+func computeMarginStartDate(to time.Time, gaps map[string]time.Time, accountNo string) time.Time {
+	from := to.AddDate(0, 0, -defaultMarginLookbackDays)
+	if lastDate, ok := gaps[accountNo]; ok {
+		// Why +1 day: last recorded date is already in DB, start from next day.
+		candidate := lastDate.AddDate(0, 0, 1)
+		if candidate.After(from) {
+			from = candidate
+		}
+	}
+	return from
+}
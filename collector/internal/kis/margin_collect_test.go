@@ -0,0 +1,52 @@
+package kis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeMarginStartDate(t *testing.T) {
+	to := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("no gap uses lookback", func(t *testing.T) {
+		gaps := map[string]time.Time{}
+
+		got := computeMarginStartDate(to, gaps, "1234567801")
+		want := to.AddDate(0, 0, -defaultMarginLookbackDays)
+		if !got.Equal(want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("gap newer than lookback uses gap+1", func(t *testing.T) {
+		lastDate := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+		gaps := map[string]time.Time{"1234567801": lastDate}
+
+		got := computeMarginStartDate(to, gaps, "1234567801")
+		want := lastDate.AddDate(0, 0, 1)
+		if !got.Equal(want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("gap older than lookback uses lookback", func(t *testing.T) {
+		lastDate := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		gaps := map[string]time.Time{"1234567801": lastDate}
+
+		got := computeMarginStartDate(to, gaps, "1234567801")
+		want := to.AddDate(0, 0, -defaultMarginLookbackDays)
+		if !got.Equal(want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("gap for different account ignored", func(t *testing.T) {
+		gaps := map[string]time.Time{"9999999901": time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)}
+
+		got := computeMarginStartDate(to, gaps, "1234567801")
+		want := to.AddDate(0, 0, -defaultMarginLookbackDays)
+		if !got.Equal(want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
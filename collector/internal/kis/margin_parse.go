@@ -0,0 +1,89 @@
+package kis
+
+import (
+	"fmt"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// accountNoCANOLen is the length of the CANO (계좌번호) portion of a KIS
+// account number; the remaining digits are the ACNT_PRDT_CD (상품코드).
+const accountNoCANOLen = 8
+
+// splitAccountNo splits a KIS account number (e.g. "1234567801") into its
+// CANO and ACNT_PRDT_CD parts as required by the margin query parameters.
+func splitAccountNo(accountNo string) (cano, acntPrdtCd string) {
+	if len(accountNo) <= accountNoCANOLen {
+		return accountNo, ""
+	}
+	return accountNo[:accountNoCANOLen], accountNo[accountNoCANOLen:]
+}
+
+func toMarginLoan(row marginLoanRow, accountNo string) (domain.MarginLoan, error) {
+	issuedAt, err := parseDate(row.IijaDate)
+	if err != nil {
+		return domain.MarginLoan{}, fmt.Errorf("parse issued date %q: %w", row.IijaDate, err)
+	}
+
+	principal, err := parseFloat64(row.CrdtLoanAmt)
+	if err != nil {
+		return domain.MarginLoan{}, fmt.Errorf("parse principal %q: %w", row.CrdtLoanAmt, err)
+	}
+
+	status := domain.MarginLoanOpen
+	if row.RedmAbleYn == "N" {
+		status = domain.MarginLoanClosed
+	}
+
+	return domain.MarginLoan{
+		AccountNo: accountNo,
+		IssuedAt:  issuedAt,
+		LoanID:    row.CrdtNo,
+		Principal: principal,
+		Status:    status,
+		Symbol:    row.PdnoCode,
+	}, nil
+}
+
+func toMarginInterest(row marginInterestRow, accountNo string) (domain.MarginInterest, error) {
+	date, err := parseDate(row.BsopDate)
+	if err != nil {
+		return domain.MarginInterest{}, fmt.Errorf("parse date %q: %w", row.BsopDate, err)
+	}
+
+	amount, err := parseFloat64(row.IntrAmt)
+	if err != nil {
+		return domain.MarginInterest{}, fmt.Errorf("parse amount %q: %w", row.IntrAmt, err)
+	}
+
+	rate, err := parseFloat64(row.IntrRate)
+	if err != nil {
+		return domain.MarginInterest{}, fmt.Errorf("parse rate %q: %w", row.IntrRate, err)
+	}
+
+	return domain.MarginInterest{
+		AccountNo: accountNo,
+		Amount:    amount,
+		Date:      date,
+		Rate:      rate,
+	}, nil
+}
+
+func toMarginRepayment(row marginRepaymentRow, accountNo string) (domain.MarginRepayment, error) {
+	date, err := parseDate(row.RedmDate)
+	if err != nil {
+		return domain.MarginRepayment{}, fmt.Errorf("parse date %q: %w", row.RedmDate, err)
+	}
+
+	amount, err := parseFloat64(row.RedmAmt)
+	if err != nil {
+		return domain.MarginRepayment{}, fmt.Errorf("parse amount %q: %w", row.RedmAmt, err)
+	}
+
+	return domain.MarginRepayment{
+		AccountNo: accountNo,
+		Amount:    amount,
+		Date:      date,
+		LoanID:    row.CrdtNo,
+	}, nil
+}
@@ -0,0 +1,134 @@
+package kis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func TestSplitAccountNo(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantCANO       string
+		wantAcntPrdtCd string
+	}{
+		{"standard 10-digit account", "1234567801", "12345678", "01"},
+		{"exactly CANO length", "12345678", "12345678", ""},
+		{"shorter than CANO length", "1234", "1234", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cano, acntPrdtCd := splitAccountNo(tt.input)
+			if cano != tt.wantCANO {
+				t.Errorf("cano = %q, want %q", cano, tt.wantCANO)
+			}
+			if acntPrdtCd != tt.wantAcntPrdtCd {
+				t.Errorf("acntPrdtCd = %q, want %q", acntPrdtCd, tt.wantAcntPrdtCd)
+			}
+		})
+	}
+}
+
+func TestToMarginLoan(t *testing.T) {
+	t.Run("converts an outstanding loan", func(t *testing.T) {
+		row := marginLoanRow{
+			CrdtLoanAmt: "5000000",
+			CrdtNo:      "L1",
+			IijaDate:    "20240102",
+			PdnoCode:    "005930",
+			RedmAbleYn:  "Y",
+		}
+
+		got, err := toMarginLoan(row, "1234567801")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Status != domain.MarginLoanOpen {
+			t.Errorf("Status = %q, want open", got.Status)
+		}
+		if got.Principal != 5000000 {
+			t.Errorf("Principal = %v, want 5000000", got.Principal)
+		}
+		wantDate := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		if !got.IssuedAt.Equal(wantDate) {
+			t.Errorf("IssuedAt = %v, want %v", got.IssuedAt, wantDate)
+		}
+	})
+
+	t.Run("converts a closed loan", func(t *testing.T) {
+		row := marginLoanRow{CrdtLoanAmt: "0", CrdtNo: "L1", IijaDate: "20240102", RedmAbleYn: "N"}
+
+		got, err := toMarginLoan(row, "1234567801")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Status != domain.MarginLoanClosed {
+			t.Errorf("Status = %q, want closed", got.Status)
+		}
+	})
+
+	t.Run("rejects invalid date", func(t *testing.T) {
+		row := marginLoanRow{CrdtLoanAmt: "100", IijaDate: "not-a-date"}
+		if _, err := toMarginLoan(row, "1234567801"); err == nil {
+			t.Fatal("expected error for invalid date")
+		}
+	})
+
+	t.Run("rejects invalid principal", func(t *testing.T) {
+		row := marginLoanRow{CrdtLoanAmt: "abc", IijaDate: "20240102"}
+		if _, err := toMarginLoan(row, "1234567801"); err == nil {
+			t.Fatal("expected error for invalid principal")
+		}
+	})
+}
+
+func TestToMarginInterest(t *testing.T) {
+	t.Run("converts a normal row", func(t *testing.T) {
+		row := marginInterestRow{BsopDate: "20240102", IntrAmt: "1234.5", IntrRate: "0.07"}
+
+		got, err := toMarginInterest(row, "1234567801")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Amount != 1234.5 {
+			t.Errorf("Amount = %v, want 1234.5", got.Amount)
+		}
+		if got.Rate != 0.07 {
+			t.Errorf("Rate = %v, want 0.07", got.Rate)
+		}
+	})
+
+	t.Run("rejects invalid amount", func(t *testing.T) {
+		row := marginInterestRow{BsopDate: "20240102", IntrAmt: "abc", IntrRate: "0.07"}
+		if _, err := toMarginInterest(row, "1234567801"); err == nil {
+			t.Fatal("expected error for invalid amount")
+		}
+	})
+}
+
+func TestToMarginRepayment(t *testing.T) {
+	t.Run("converts a normal row", func(t *testing.T) {
+		row := marginRepaymentRow{CrdtNo: "L1", RedmAmt: "1000000", RedmDate: "20240110"}
+
+		got, err := toMarginRepayment(row, "1234567801")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Amount != 1000000 {
+			t.Errorf("Amount = %v, want 1000000", got.Amount)
+		}
+		if got.LoanID != "L1" {
+			t.Errorf("LoanID = %q, want L1", got.LoanID)
+		}
+	})
+
+	t.Run("rejects invalid date", func(t *testing.T) {
+		row := marginRepaymentRow{RedmAmt: "100", RedmDate: "not-a-date"}
+		if _, err := toMarginRepayment(row, "1234567801"); err == nil {
+			t.Fatal("expected error for invalid date")
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package kis
+
+import (
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// FetchOption configures a FetchDailyPricesWithOptions call.
+type FetchOption func(*fetchConfig)
+
+// fetchConfig holds the resolved settings for one FetchDailyPricesWithOptions
+// call. newFetchConfig's defaults plus WithDateRange are equivalent to the plain
+// FetchDailyPrices behavior (daily period, adjusted prices, maxPages pages).
+type fetchConfig struct {
+	adjustment domain.AdjustmentMode
+	from       time.Time
+	maxPages   int
+	period     domain.Period
+	since      *time.Time
+	to         time.Time
+}
+
+func newFetchConfig() fetchConfig {
+	return fetchConfig{
+		adjustment: domain.AdjAdjusted,
+		maxPages:   maxPages,
+		period:     domain.PeriodDaily,
+	}
+}
+
+// WithDateRange sets the inclusive date range to fetch.
+func WithDateRange(from, to time.Time) FetchOption {
+	return func(c *fetchConfig) {
+		c.from = from
+		c.to = to
+	}
+}
+
+// WithSince tightens pagination's stop condition to lastKnownDate instead of
+// the From side of WithDateRange, so buildNextCursor stops paging backward as
+// soon as it reaches data already on hand. Use this for incremental daily runs:
+// WithDateRange sets the nominal window while WithSince (typically the caller's
+// last recorded date for the symbol) avoids re-fetching pages already covered.
+func WithSince(lastKnownDate time.Time) FetchOption {
+	return func(c *fetchConfig) {
+		c.since = &lastKnownDate
+	}
+}
+
+// WithPeriod sets the bar granularity (maps to FID_PERIOD_DIV_CODE).
+func WithPeriod(period domain.Period) FetchOption {
+	return func(c *fetchConfig) {
+		c.period = period
+	}
+}
+
+// WithAdjustment sets whether KIS returns split-adjusted or raw exchange prices
+// (maps to FID_ORG_ADJ_PRC).
+func WithAdjustment(adjustment domain.AdjustmentMode) FetchOption {
+	return func(c *fetchConfig) {
+		c.adjustment = adjustment
+	}
+}
+
+// WithMaxPages overrides the default maxPages pagination cap.
+func WithMaxPages(n int) FetchOption {
+	return func(c *fetchConfig) {
+		c.maxPages = n
+	}
+}
+
+// cursorFloor returns the date buildNextCursor should stop paging at: since
+// when WithSince was given (it's expected to be on or after from), else from.
+func (c fetchConfig) cursorFloor() time.Time {
+	if c.since != nil {
+		return *c.since
+	}
+	return c.from
+}
@@ -0,0 +1,132 @@
+package kis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func TestFetchDailyPricesWithOptions(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("WithPeriod and WithAdjustment set the matching query params", func(t *testing.T) {
+		stubTP := newStubTokenProvider(t, "token")
+
+		var gotPeriod, gotAdjustment string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotPeriod = r.URL.Query().Get("FID_PERIOD_DIV_CODE")
+			gotAdjustment = r.URL.Query().Get("FID_ORG_ADJ_PRC")
+			resp := kisSuccessResponse(nil)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		}))
+		defer srv.Close()
+
+		client := newTestKISClient(t, srv, stubTP)
+		_, err := client.FetchDailyPricesWithOptions(context.Background(), "005930",
+			WithDateRange(from, to), WithPeriod(domain.PeriodWeekly), WithAdjustment(domain.AdjOriginal))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotPeriod != "W" {
+			t.Errorf("FID_PERIOD_DIV_CODE = %q, want W", gotPeriod)
+		}
+		if gotAdjustment != "1" {
+			t.Errorf("FID_ORG_ADJ_PRC = %q, want 1", gotAdjustment)
+		}
+	})
+
+	t.Run("WithSince stops pagination at the watermark instead of from", func(t *testing.T) {
+		stubTP := newStubTokenProvider(t, "token")
+
+		var pageCount int
+		var gotFloor string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pageCount++
+			if pageCount == 1 {
+				gotFloor = r.URL.Query().Get("FID_INPUT_DATE_1")
+			}
+			resp := kisSuccessResponse([]kisOutputRow{
+				{StckBsopDate: "20240801", StckOprc: "100", StckHgpr: "100", StckLwpr: "100", StckClpr: "100", AcmlVol: "1000"},
+			})
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		}))
+		defer srv.Close()
+
+		client := newTestKISClient(t, srv, stubTP)
+		since := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+		prices, err := client.FetchDailyPricesWithOptions(context.Background(), "005930",
+			WithDateRange(from, to), WithSince(since))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotFloor != "20240701" {
+			t.Errorf("FID_INPUT_DATE_1 = %q, want 20240701 (since overrides from)", gotFloor)
+		}
+		// Each page returns a single row on 2024-08-01, which is after `since`
+		// (2024-07-01), so buildNextCursor keeps paging until it stops on its
+		// own stale-cursor guard rather than reaching `from` (2024-01-01).
+		if pageCount != 2 {
+			t.Errorf("pageCount = %d, want 2 (stale cursor breaks after 2 identical pages)", pageCount)
+		}
+		if len(prices) != 2 {
+			t.Errorf("len(prices) = %d, want 2", len(prices))
+		}
+	})
+
+	t.Run("WithMaxPages caps pagination below the package default", func(t *testing.T) {
+		stubTP := newStubTokenProvider(t, "token")
+
+		var pageCount int
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pageCount++
+			date := to.AddDate(0, 0, -pageCount)
+			resp := kisSuccessResponse([]kisOutputRow{
+				{StckBsopDate: date.Format("20060102"), StckOprc: "100", StckHgpr: "100", StckLwpr: "100", StckClpr: "100", AcmlVol: "1000"},
+			})
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		}))
+		defer srv.Close()
+
+		client := newTestKISClient(t, srv, stubTP)
+		_, err := client.FetchDailyPricesWithOptions(context.Background(), "005930",
+			WithDateRange(from, to), WithMaxPages(1))
+		if !errors.Is(err, ErrMaxPagesReached) {
+			t.Errorf("err = %v, want ErrMaxPagesReached", err)
+		}
+		if pageCount != 1 {
+			t.Errorf("pageCount = %d, want 1 (capped by WithMaxPages)", pageCount)
+		}
+	})
+}
+
+func TestFetchConfig_CursorFloor(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("defaults to from when WithSince is unset", func(t *testing.T) {
+		cfg := newFetchConfig()
+		WithDateRange(from, from)(&cfg)
+		if !cfg.cursorFloor().Equal(from) {
+			t.Errorf("cursorFloor() = %v, want %v", cfg.cursorFloor(), from)
+		}
+	})
+
+	t.Run("uses since when WithSince is set", func(t *testing.T) {
+		cfg := newFetchConfig()
+		WithDateRange(from, from)(&cfg)
+		WithSince(since)(&cfg)
+		if !cfg.cursorFloor().Equal(since) {
+			t.Errorf("cursorFloor() = %v, want %v", cfg.cursorFloor(), since)
+		}
+	})
+}
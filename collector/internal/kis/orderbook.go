@@ -0,0 +1,131 @@
+package kis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/httpclient"
+)
+
+const (
+	orderBookPath = "/uapi/domestic-stock/v1/quotations/inquire-asking-price-exp-ccn"
+	trIDOrderBook = "FHKST01010200"
+)
+
+// kisOrderBookResponse represents the KIS order book (호가) API response.
+type kisOrderBookResponse struct {
+	MsgCode string            `json:"msg_cd"`
+	Msg     string            `json:"msg1"`
+	Output1 []kisOrderBookRow `json:"output1"`
+	RtCode  string            `json:"rt_cd"`
+}
+
+// kisOrderBookRow represents the single-row output1 envelope from the KIS
+// asking-price inquiry. Why fields are flattened per level instead of a slice:
+// KIS numbers each of its 10 levels into its own JSON field; orderbookLevels of
+// them are decoded here, matching the simplification parseOrderBook applies to
+// the streaming feed (see stream.go).
+type kisOrderBookRow struct {
+	AcceptTime    string `json:"aspr_acpt_tm"`
+	Askp1         string `json:"askp1"`
+	Askp2         string `json:"askp2"`
+	Askp3         string `json:"askp3"`
+	Askp4         string `json:"askp4"`
+	Askp5         string `json:"askp5"`
+	AskpRsqn1     string `json:"askp_rsqn1"`
+	AskpRsqn2     string `json:"askp_rsqn2"`
+	AskpRsqn3     string `json:"askp_rsqn3"`
+	AskpRsqn4     string `json:"askp_rsqn4"`
+	AskpRsqn5     string `json:"askp_rsqn5"`
+	Bidp1         string `json:"bidp1"`
+	Bidp2         string `json:"bidp2"`
+	Bidp3         string `json:"bidp3"`
+	Bidp4         string `json:"bidp4"`
+	Bidp5         string `json:"bidp5"`
+	BidpRsqn1     string `json:"bidp_rsqn1"`
+	BidpRsqn2     string `json:"bidp_rsqn2"`
+	BidpRsqn3     string `json:"bidp_rsqn3"`
+	BidpRsqn4     string `json:"bidp_rsqn4"`
+	BidpRsqn5     string `json:"bidp_rsqn5"`
+}
+
+// FetchOrderBook fetches the current bid/ask depth for symbol via the KIS
+// inquire-asking-price endpoint. Implements a point-in-time read; callers wanting
+// a regular cadence of snapshots should drive this through OrderBookSampler.
+func (c *Client) FetchOrderBook(ctx context.Context, symbol string) (domain.OrderBookSnapshot, error) {
+	accessToken, err := c.token.Token(ctx)
+	if err != nil {
+		return domain.OrderBookSnapshot{}, fmt.Errorf("obtain token: %w", err)
+	}
+
+	body, _, err := c.http.Get(ctx, orderBookPath,
+		httpclient.WithHeader("authorization", "Bearer "+accessToken),
+		httpclient.WithHeader("tr_id", trIDOrderBook),
+		httpclient.WithQueryParam("FID_COND_MRKT_DIV_CODE", "J"),
+		httpclient.WithQueryParam("FID_INPUT_ISCD", symbol),
+	)
+	if err != nil {
+		return domain.OrderBookSnapshot{}, err
+	}
+
+	var resp kisOrderBookResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return domain.OrderBookSnapshot{}, fmt.Errorf("parse KIS order book response for %s: %w", symbol, err)
+	}
+	if resp.RtCode != "0" {
+		return domain.OrderBookSnapshot{}, fmt.Errorf("KIS order book API error for %s (code=%s): %s", symbol, resp.MsgCode, resp.Msg)
+	}
+	if len(resp.Output1) == 0 {
+		return domain.OrderBookSnapshot{}, fmt.Errorf("KIS order book response for %s: empty output1", symbol)
+	}
+
+	return toOrderBookSnapshot(resp.Output1[0], symbol)
+}
+
+func toOrderBookSnapshot(row kisOrderBookRow, symbol string) (domain.OrderBookSnapshot, error) {
+	timestamp, err := parseStreamTime(row.AcceptTime)
+	if err != nil {
+		return domain.OrderBookSnapshot{}, fmt.Errorf("parse time %q: %w", row.AcceptTime, err)
+	}
+
+	asks, err := parseOrderBookPrices(
+		[]string{row.Askp1, row.Askp2, row.Askp3, row.Askp4, row.Askp5},
+		[]string{row.AskpRsqn1, row.AskpRsqn2, row.AskpRsqn3, row.AskpRsqn4, row.AskpRsqn5},
+	)
+	if err != nil {
+		return domain.OrderBookSnapshot{}, fmt.Errorf("parse asks: %w", err)
+	}
+
+	bids, err := parseOrderBookPrices(
+		[]string{row.Bidp1, row.Bidp2, row.Bidp3, row.Bidp4, row.Bidp5},
+		[]string{row.BidpRsqn1, row.BidpRsqn2, row.BidpRsqn3, row.BidpRsqn4, row.BidpRsqn5},
+	)
+	if err != nil {
+		return domain.OrderBookSnapshot{}, fmt.Errorf("parse bids: %w", err)
+	}
+
+	return domain.OrderBookSnapshot{
+		Asks:      asks,
+		Bids:      bids,
+		Symbol:    symbol,
+		Timestamp: timestamp,
+	}, nil
+}
+
+func parseOrderBookPrices(prices, quantities []string) ([]domain.OrderBookLevel, error) {
+	levels := make([]domain.OrderBookLevel, 0, len(prices))
+	for i, priceStr := range prices {
+		price, err := parseFloat64(priceStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse price %q: %w", priceStr, err)
+		}
+		quantity, err := parseInt64(quantities[i])
+		if err != nil {
+			return nil, fmt.Errorf("parse quantity %q: %w", quantities[i], err)
+		}
+		levels = append(levels, domain.OrderBookLevel{Price: price, Quantity: quantity})
+	}
+	return levels, nil
+}
@@ -0,0 +1,72 @@
+package kis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/ratelimit"
+	"golang.org/x/time/rate"
+)
+
+// OrderBookSampler polls FetchOrderBook for a set of symbols at a fixed interval,
+// rate-limited and retried the same way as Collector, so a bounded sampling
+// window produces a regular cadence of snapshots instead of a single read.
+type OrderBookSampler struct {
+	client   *Client
+	limiter  *rate.Limiter
+	retryCfg ratelimit.RetryConfig
+}
+
+func NewOrderBookSampler(client *Client, limiter *rate.Limiter, retryCfg ratelimit.RetryConfig) *OrderBookSampler {
+	return &OrderBookSampler{
+		client:   client,
+		limiter:  limiter,
+		retryCfg: retryCfg,
+	}
+}
+
+// Sample fetches the order book for every symbol once immediately, then again
+// every interval, until ctx is done. Returns whatever snapshots were collected
+// before ctx ended or an error interrupted sampling.
+func (s *OrderBookSampler) Sample(ctx context.Context, symbols []string, interval time.Duration) ([]domain.OrderBookSnapshot, error) {
+	var snapshots []domain.OrderBookSnapshot
+
+	sampleOnce := func() error {
+		for _, symbol := range symbols {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			snapshot, err := ratelimit.FetchWithRateLimit(ctx, s.limiter, s.retryCfg, IsRetryable,
+				func(ctx context.Context) (domain.OrderBookSnapshot, error) {
+					return s.client.FetchOrderBook(ctx, symbol)
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("sample order book for %s: %w", symbol, err)
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+		return nil
+	}
+
+	if err := sampleOnce(); err != nil {
+		return snapshots, err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return snapshots, nil
+		case <-ticker.C:
+			if err := sampleOnce(); err != nil {
+				return snapshots, err
+			}
+		}
+	}
+}
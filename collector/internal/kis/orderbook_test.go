@@ -0,0 +1,130 @@
+package kis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func kisOrderBookSuccessResponse(row kisOrderBookRow) kisOrderBookResponse {
+	return kisOrderBookResponse{
+		MsgCode: "MCA00000",
+		Msg:     "정상처리 되었습니다.",
+		Output1: []kisOrderBookRow{row},
+		RtCode:  "0",
+	}
+}
+
+func sampleOrderBookRow() kisOrderBookRow {
+	return kisOrderBookRow{
+		AcceptTime: "093000",
+		Askp1:      "72100", AskpRsqn1: "100",
+		Askp2:      "72200", AskpRsqn2: "200",
+		Askp3:      "72300", AskpRsqn3: "300",
+		Askp4:      "72400", AskpRsqn4: "400",
+		Askp5:      "72500", AskpRsqn5: "500",
+		Bidp1: "72000", BidpRsqn1: "150",
+		Bidp2: "71900", BidpRsqn2: "250",
+		Bidp3: "71800", BidpRsqn3: "350",
+		Bidp4: "71700", BidpRsqn4: "450",
+		Bidp5: "71600", BidpRsqn5: "550",
+	}
+}
+
+func TestFetchOrderBook(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		stubTP := newStubTokenProvider(t, "test-bearer-token")
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != orderBookPath {
+				t.Errorf("path = %q, want %s", r.URL.Path, orderBookPath)
+			}
+			if got := r.Header.Get("authorization"); got != "Bearer test-bearer-token" {
+				t.Errorf("authorization = %q, want Bearer test-bearer-token", got)
+			}
+			if got := r.Header.Get("tr_id"); got != trIDOrderBook {
+				t.Errorf("tr_id = %q, want %s", got, trIDOrderBook)
+			}
+			if got := r.URL.Query().Get("FID_INPUT_ISCD"); got != "005930" {
+				t.Errorf("FID_INPUT_ISCD = %q, want 005930", got)
+			}
+			if got := r.URL.Query().Get("FID_COND_MRKT_DIV_CODE"); got != "J" {
+				t.Errorf("FID_COND_MRKT_DIV_CODE = %q, want J", got)
+			}
+
+			resp := kisOrderBookSuccessResponse(sampleOrderBookRow())
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		}))
+		defer srv.Close()
+
+		client := newTestKISClient(t, srv, stubTP)
+		snapshot, err := client.FetchOrderBook(context.Background(), "005930")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if snapshot.Symbol != "005930" {
+			t.Errorf("Symbol = %q, want 005930", snapshot.Symbol)
+		}
+		if len(snapshot.Asks) != 5 {
+			t.Fatalf("len(Asks) = %d, want 5", len(snapshot.Asks))
+		}
+		if len(snapshot.Bids) != 5 {
+			t.Fatalf("len(Bids) = %d, want 5", len(snapshot.Bids))
+		}
+		if snapshot.Asks[0].Price != 72100 || snapshot.Asks[0].Quantity != 100 {
+			t.Errorf("Asks[0] = %+v, want {72100 100}", snapshot.Asks[0])
+		}
+		if snapshot.Bids[0].Price != 72000 || snapshot.Bids[0].Quantity != 150 {
+			t.Errorf("Bids[0] = %+v, want {72000 150}", snapshot.Bids[0])
+		}
+
+		now := time.Now().UTC()
+		wantTime := time.Date(now.Year(), now.Month(), now.Day(), 9, 30, 0, 0, time.UTC)
+		if !snapshot.Timestamp.Equal(wantTime) {
+			t.Errorf("Timestamp = %v, want %v", snapshot.Timestamp, wantTime)
+		}
+	})
+
+	t.Run("KIS API error", func(t *testing.T) {
+		stubTP := newStubTokenProvider(t, "token")
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resp := kisOrderBookResponse{
+				MsgCode: "EGW00123",
+				Msg:     "유효하지 않은 토큰입니다.",
+				RtCode:  "1",
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		}))
+		defer srv.Close()
+
+		client := newTestKISClient(t, srv, stubTP)
+		_, err := client.FetchOrderBook(context.Background(), "005930")
+		if err == nil {
+			t.Fatal("expected error for KIS API error response")
+		}
+	})
+
+	t.Run("empty output1", func(t *testing.T) {
+		stubTP := newStubTokenProvider(t, "token")
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resp := kisOrderBookResponse{MsgCode: "MCA00000", Msg: "정상처리 되었습니다.", RtCode: "0"}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		}))
+		defer srv.Close()
+
+		client := newTestKISClient(t, srv, stubTP)
+		_, err := client.FetchOrderBook(context.Background(), "005930")
+		if err == nil {
+			t.Fatal("expected error for empty output1")
+		}
+	})
+}
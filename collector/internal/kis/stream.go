@@ -0,0 +1,485 @@
+package kis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/ratelimit"
+)
+
+const (
+	// maxStreamSubscriptions mirrors KIS's documented per-connection limit on
+	// concurrent real-time registrations (tick + order book combined).
+	maxStreamSubscriptions = 40
+
+	orderbookTRID = "H0STASP0"
+	tickTRID      = "H0STCNT0"
+
+	trTypeSubscribe   = "1"
+	trTypeUnsubscribe = "2"
+
+	pingPongTRID = "PINGPONG"
+
+	tickChannelBuffer      = 1024
+	orderbookChannelBuffer = 1024
+)
+
+// ErrSubscriptionLimitReached is returned by Subscribe when adding the requested
+// symbols would exceed maxStreamSubscriptions on the connection.
+var ErrSubscriptionLimitReached = errors.New("kis: subscription limit reached")
+
+// StreamKind selects which real-time feed a symbol is subscribed to.
+type StreamKind string
+
+const (
+	StreamKindTick      StreamKind = "tick"
+	StreamKindOrderBook StreamKind = "orderbook"
+)
+
+func (k StreamKind) trID() string {
+	if k == StreamKindOrderBook {
+		return orderbookTRID
+	}
+	return tickTRID
+}
+
+// DefaultStreamRetryConfig returns reconnect backoff settings for StreamingClient.Run:
+// 1s initial backoff doubling up to 30s, retried effectively indefinitely for a
+// long-running streaming process.
+func DefaultStreamRetryConfig() ratelimit.RetryConfig {
+	return ratelimit.RetryConfig{
+		InitialBackoff: time.Second,
+		MaxAttempts:    1 << 30,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// subscribeFrame is the handshake message KIS expects to (un)register a symbol
+// on a real-time feed.
+type subscribeFrame struct {
+	Body   subscribeBody   `json:"body"`
+	Header subscribeHeader `json:"header"`
+}
+
+type subscribeHeader struct {
+	ApprovalKey string `json:"approval_key"`
+	ContentType string `json:"content-type"`
+	CustType    string `json:"custtype"`
+	TrType      string `json:"tr_type"`
+}
+
+type subscribeBody struct {
+	Input subscribeInput `json:"input"`
+}
+
+type subscribeInput struct {
+	TrID  string `json:"tr_id"`
+	TrKey string `json:"tr_key"`
+}
+
+// controlFrame is the shape of PINGPONG and subscribe-ack messages, which KIS
+// sends as plain JSON. Real-time tick/orderbook data instead arrives as
+// pipe-delimited text frames (see parseTick/parseOrderBook).
+type controlFrame struct {
+	Header struct {
+		TrID string `json:"tr_id"`
+	} `json:"header"`
+}
+
+// StreamingClient connects to KIS's real-time WebSocket feed and delivers tick and
+// order-book events over typed channels. Parallel to Client, which polls the REST
+// daily-chart endpoint instead.
+type StreamingClient struct {
+	dialer *websocket.Dialer
+	token  *TokenProvider
+	wsURL  string
+
+	orderbook chan domain.OrderBookSnapshot
+	ticks     chan domain.Tick
+
+	mu         sync.Mutex
+	conn       *websocket.Conn
+	subscribed map[string]StreamKind // "kind:symbol" -> kind, resent on reconnect
+
+	// writeMu serializes every write to conn: gorilla/websocket permits only
+	// one concurrent writer per connection, but handleControlFrame echoes
+	// PINGPONG frames from the read loop without holding mu, independently of
+	// Subscribe/Unsubscribe and attachAndResubscribe's writes under mu.
+	writeMu sync.Mutex
+}
+
+// NewStreamingClient creates a StreamingClient. wsURL is the KIS real-time
+// WebSocket endpoint (e.g. "wss://ops.koreainvestment.com:21000/tryitout").
+// Pass nil dialer for websocket.DefaultDialer.
+func NewStreamingClient(wsURL string, tokenProvider *TokenProvider, dialer *websocket.Dialer) *StreamingClient {
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	return &StreamingClient{
+		dialer:     dialer,
+		orderbook:  make(chan domain.OrderBookSnapshot, orderbookChannelBuffer),
+		subscribed: make(map[string]StreamKind),
+		ticks:      make(chan domain.Tick, tickChannelBuffer),
+		token:      tokenProvider,
+		wsURL:      wsURL,
+	}
+}
+
+// Ticks returns the channel of incoming trade execution events.
+func (c *StreamingClient) Ticks() <-chan domain.Tick {
+	return c.ticks
+}
+
+// Orderbook returns the channel of incoming order-book snapshots.
+func (c *StreamingClient) Orderbook() <-chan domain.OrderBookSnapshot {
+	return c.orderbook
+}
+
+// Subscribe registers symbols on the given feed. If a live connection is held,
+// the subscribe frame is sent immediately; otherwise the symbols are recorded
+// and sent once Run establishes a connection. Returns ErrSubscriptionLimitReached
+// without sending anything if the connection's subscription cap would be exceeded.
+func (c *StreamingClient) Subscribe(ctx context.Context, symbols []string, kind StreamKind) error {
+	return c.updateSubscriptions(ctx, symbols, kind, trTypeSubscribe)
+}
+
+// Unsubscribe deregisters symbols from the given feed.
+func (c *StreamingClient) Unsubscribe(ctx context.Context, symbols []string, kind StreamKind) error {
+	return c.updateSubscriptions(ctx, symbols, kind, trTypeUnsubscribe)
+}
+
+func (c *StreamingClient) updateSubscriptions(ctx context.Context, symbols []string, kind StreamKind, trType string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if trType == trTypeSubscribe {
+		added := 0
+		for _, symbol := range symbols {
+			if _, exists := c.subscribed[subscriptionKey(kind, symbol)]; !exists {
+				added++
+			}
+		}
+		if len(c.subscribed)+added > maxStreamSubscriptions {
+			return fmt.Errorf("%w: %d existing + %d new exceeds limit of %d",
+				ErrSubscriptionLimitReached, len(c.subscribed), added, maxStreamSubscriptions)
+		}
+	}
+
+	var approvalKey string
+	if c.conn != nil {
+		var err error
+		approvalKey, err = c.token.ApprovalKey(ctx)
+		if err != nil {
+			return fmt.Errorf("obtain approval key: %w", err)
+		}
+	}
+
+	for _, symbol := range symbols {
+		key := subscriptionKey(kind, symbol)
+		if trType == trTypeSubscribe {
+			c.subscribed[key] = kind
+		} else {
+			delete(c.subscribed, key)
+		}
+
+		if c.conn == nil {
+			continue
+		}
+		if err := c.sendSubscribeFrameLocked(symbol, kind, trType, approvalKey); err != nil {
+			return fmt.Errorf("%s %s %s: %w", trType, kind, symbol, err)
+		}
+	}
+
+	return nil
+}
+
+func subscriptionKey(kind StreamKind, symbol string) string {
+	return string(kind) + ":" + symbol
+}
+
+func (c *StreamingClient) sendSubscribeFrameLocked(symbol string, kind StreamKind, trType, approvalKey string) error {
+	frame := subscribeFrame{
+		Body: subscribeBody{Input: subscribeInput{TrID: kind.trID(), TrKey: symbol}},
+		Header: subscribeHeader{
+			ApprovalKey: approvalKey,
+			ContentType: "utf-8",
+			CustType:    "P",
+			TrType:      trType,
+		},
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.conn.WriteJSON(frame)
+}
+
+// Run connects to the WebSocket endpoint and serves incoming messages until ctx
+// is cancelled, reconnecting with exponential backoff (via ratelimit.WithRetry)
+// on any connection error. Every previously Subscribe'd symbol is resent on each
+// new connection.
+func (c *StreamingClient) Run(ctx context.Context, cfg ratelimit.RetryConfig) error {
+	_, err := ratelimit.WithRetry(ctx, cfg, nil, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, c.connectAndServe(ctx)
+	})
+	return err
+}
+
+func (c *StreamingClient) connectAndServe(ctx context.Context) error {
+	approvalKey, err := c.token.ApprovalKey(ctx)
+	if err != nil {
+		return fmt.Errorf("obtain approval key: %w", err)
+	}
+
+	conn, _, err := c.dialer.DialContext(ctx, c.wsURL, nil)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", c.wsURL, err)
+	}
+	defer conn.Close()
+
+	if err := c.attachAndResubscribe(ctx, conn, approvalKey); err != nil {
+		return err
+	}
+	defer c.detach()
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("read message: %w", err)
+		}
+		c.handleMessage(conn, data)
+	}
+}
+
+func (c *StreamingClient) attachAndResubscribe(ctx context.Context, conn *websocket.Conn, approvalKey string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.conn = conn
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	for key, kind := range c.subscribed {
+		symbol := strings.TrimPrefix(key, string(kind)+":")
+		frame := subscribeFrame{
+			Body: subscribeBody{Input: subscribeInput{TrID: kind.trID(), TrKey: symbol}},
+			Header: subscribeHeader{
+				ApprovalKey: approvalKey,
+				ContentType: "utf-8",
+				CustType:    "P",
+				TrType:      trTypeSubscribe,
+			},
+		}
+		if err := conn.WriteJSON(frame); err != nil {
+			return fmt.Errorf("resubscribe %s %s: %w", kind, symbol, err)
+		}
+	}
+	return nil
+}
+
+func (c *StreamingClient) detach() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn = nil
+}
+
+// handleMessage dispatches one WebSocket frame. Control messages (PINGPONG,
+// subscribe acks) arrive as JSON; real-time data arrives as pipe-delimited text
+// prefixed with an encryption flag ("0" plaintext, "1" encrypted) and the tr_id.
+func (c *StreamingClient) handleMessage(conn *websocket.Conn, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+
+	if data[0] == '{' {
+		c.handleControlFrame(conn, data)
+		return
+	}
+
+	c.handleDataFrame(data)
+}
+
+func (c *StreamingClient) handleControlFrame(conn *websocket.Conn, data []byte) {
+	var frame controlFrame
+	if err := json.Unmarshal(data, &frame); err != nil {
+		slog.Warn("kis stream: malformed control frame", "error", err)
+		return
+	}
+
+	if frame.Header.TrID == pingPongTRID {
+		// Why echo verbatim: KIS expects the exact PINGPONG payload reflected
+		// back as the heartbeat response, not a re-encoded copy.
+		c.writeMu.Lock()
+		err := conn.WriteMessage(websocket.TextMessage, data)
+		c.writeMu.Unlock()
+		if err != nil {
+			slog.Warn("kis stream: pong failed", "error", err)
+		}
+	}
+}
+
+// handleDataFrame parses "<encrypted>|<tr_id>|<count>|<payload>" and, for the
+// tick/order-book tr_ids, splits payload's '^'-delimited records (one per count)
+// into typed events on the client's channels.
+func (c *StreamingClient) handleDataFrame(data []byte) {
+	parts := strings.SplitN(string(data), "|", 4)
+	if len(parts) != 4 {
+		return
+	}
+	trID, payload := parts[1], parts[3]
+
+	switch trID {
+	case tickTRID:
+		for _, record := range strings.Split(payload, "\n") {
+			if record == "" {
+				continue
+			}
+			tick, err := parseTick(record)
+			if err != nil {
+				slog.Warn("kis stream: discarding malformed tick", "error", err)
+				continue
+			}
+			c.deliverTick(tick)
+		}
+	case orderbookTRID:
+		for _, record := range strings.Split(payload, "\n") {
+			if record == "" {
+				continue
+			}
+			snapshot, err := parseOrderBook(record)
+			if err != nil {
+				slog.Warn("kis stream: discarding malformed order book snapshot", "error", err)
+				continue
+			}
+			c.deliverOrderbook(snapshot)
+		}
+	}
+}
+
+// deliverTick sends non-blocking: while the channel is full, each incoming tick
+// is dropped rather than stalling the read loop (and with it, heartbeat responses).
+func (c *StreamingClient) deliverTick(t domain.Tick) {
+	select {
+	case c.ticks <- t:
+	default:
+		slog.Warn("kis stream: tick channel full, dropping tick", "symbol", t.Symbol)
+	}
+}
+
+func (c *StreamingClient) deliverOrderbook(s domain.OrderBookSnapshot) {
+	select {
+	case c.orderbook <- s:
+	default:
+		slog.Warn("kis stream: orderbook channel full, dropping snapshot", "symbol", s.Symbol)
+	}
+}
+
+// parseTick decodes a simplified H0STCNT0 record: symbol^HHMMSS^price^volume.
+// Why simplified: the real feed carries ~40 caret-delimited fields; only these
+// four are consumed downstream today.
+func parseTick(record string) (domain.Tick, error) {
+	fields := strings.Split(record, "^")
+	if len(fields) < 4 {
+		return domain.Tick{}, fmt.Errorf("expected at least 4 fields, got %d", len(fields))
+	}
+
+	price, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return domain.Tick{}, fmt.Errorf("parse price %q: %w", fields[2], err)
+	}
+	volume, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return domain.Tick{}, fmt.Errorf("parse volume %q: %w", fields[3], err)
+	}
+	timestamp, err := parseStreamTime(fields[1])
+	if err != nil {
+		return domain.Tick{}, fmt.Errorf("parse time %q: %w", fields[1], err)
+	}
+
+	return domain.Tick{
+		Price:     price,
+		Symbol:    fields[0],
+		Timestamp: timestamp,
+		Volume:    volume,
+	}, nil
+}
+
+// orderbookLevels is the number of bid/ask levels decoded from an H0STASP0
+// record. KIS's real feed carries up to 10; 5 covers the common use cases.
+const orderbookLevels = 5
+
+// parseOrderBook decodes a simplified H0STASP0 record:
+// symbol^HHMMSS^ask1price^ask1qty^...^ask5price^ask5qty^bid1price^bid1qty^...^bid5price^bid5qty.
+func parseOrderBook(record string) (domain.OrderBookSnapshot, error) {
+	fields := strings.Split(record, "^")
+	wantFields := 2 + 2*orderbookLevels*2
+	if len(fields) < wantFields {
+		return domain.OrderBookSnapshot{}, fmt.Errorf("expected at least %d fields, got %d", wantFields, len(fields))
+	}
+
+	timestamp, err := parseStreamTime(fields[1])
+	if err != nil {
+		return domain.OrderBookSnapshot{}, fmt.Errorf("parse time %q: %w", fields[1], err)
+	}
+
+	asks, err := parseOrderBookLevels(fields[2 : 2+2*orderbookLevels])
+	if err != nil {
+		return domain.OrderBookSnapshot{}, fmt.Errorf("parse asks: %w", err)
+	}
+	bids, err := parseOrderBookLevels(fields[2+2*orderbookLevels : 2+4*orderbookLevels])
+	if err != nil {
+		return domain.OrderBookSnapshot{}, fmt.Errorf("parse bids: %w", err)
+	}
+
+	return domain.OrderBookSnapshot{
+		Asks:      asks,
+		Bids:      bids,
+		Symbol:    fields[0],
+		Timestamp: timestamp,
+	}, nil
+}
+
+func parseOrderBookLevels(fields []string) ([]domain.OrderBookLevel, error) {
+	levels := make([]domain.OrderBookLevel, 0, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		price, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse price %q: %w", fields[i], err)
+		}
+		quantity, err := strconv.ParseInt(fields[i+1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse quantity %q: %w", fields[i+1], err)
+		}
+		levels = append(levels, domain.OrderBookLevel{Price: price, Quantity: quantity})
+	}
+	return levels, nil
+}
+
+// parseStreamTime interprets an HHMMSS field against today's date (UTC), since
+// KIS real-time frames carry only time-of-day, not a full date.
+func parseStreamTime(hhmmss string) (time.Time, error) {
+	if len(hhmmss) != 6 {
+		return time.Time{}, fmt.Errorf("expected 6 digits, got %q", hhmmss)
+	}
+	now := time.Now().UTC()
+	parsed, err := time.Parse("150405", hhmmss)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), parsed.Hour(), parsed.Minute(), parsed.Second(), 0, time.UTC), nil
+}
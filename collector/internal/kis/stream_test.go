@@ -0,0 +1,313 @@
+package kis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/jusikbot/collector/internal/ratelimit"
+)
+
+func newApprovalServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != approvalPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(approvalKeyResponse{ApprovalKey: "test-approval-key"})
+	}))
+}
+
+// newStreamServer starts a fake KIS WebSocket server. handler runs once per
+// accepted connection and owns the connection's lifetime.
+func newStreamServer(t *testing.T, handler func(conn *websocket.Conn)) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Logf("upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		handler(conn)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func wsURL(httpURL string) string {
+	return "ws" + strings.TrimPrefix(httpURL, "http")
+}
+
+func newTestStreamingClient(t *testing.T, wsURL, approvalURL string) *StreamingClient {
+	t.Helper()
+	token := NewTokenProvider(approvalURL, "app-key", "app-secret", nil, nil)
+	return NewStreamingClient(wsURL, token, nil)
+}
+
+func TestStreamingClient_SubscribeAndReceiveTicks(t *testing.T) {
+	approvalSrv := newApprovalServer(t)
+	defer approvalSrv.Close()
+
+	received := make(chan subscribeFrame, 1)
+	streamSrv := newStreamServer(t, func(conn *websocket.Conn) {
+		var frame subscribeFrame
+		if err := conn.ReadJSON(&frame); err != nil {
+			return
+		}
+		received <- frame
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte("0|H0STCNT0|1|005930^090000^70000^100\n"))
+
+		// Keep the connection open until the test closes it.
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	client := newTestStreamingClient(t, wsURL(streamSrv.URL), approvalSrv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := client.Subscribe(ctx, []string{"005930"}, StreamKindTick); err != nil {
+		t.Fatalf("subscribe before connect: %v", err)
+	}
+
+	go client.Run(ctx, ratelimitTestConfig())
+
+	select {
+	case frame := <-received:
+		if frame.Body.Input.TrID != tickTRID {
+			t.Errorf("tr_id = %q, want %q", frame.Body.Input.TrID, tickTRID)
+		}
+		if frame.Body.Input.TrKey != "005930" {
+			t.Errorf("tr_key = %q, want %q", frame.Body.Input.TrKey, "005930")
+		}
+		if frame.Header.ApprovalKey != "test-approval-key" {
+			t.Errorf("approval_key = %q, want %q", frame.Header.ApprovalKey, "test-approval-key")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for subscribe frame")
+	}
+
+	select {
+	case tick := <-client.Ticks():
+		if tick.Symbol != "005930" {
+			t.Errorf("symbol = %q, want %q", tick.Symbol, "005930")
+		}
+		if tick.Price != 70000 {
+			t.Errorf("price = %v, want 70000", tick.Price)
+		}
+		if tick.Volume != 100 {
+			t.Errorf("volume = %d, want 100", tick.Volume)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for tick")
+	}
+}
+
+func TestStreamingClient_PingPong(t *testing.T) {
+	approvalSrv := newApprovalServer(t)
+	defer approvalSrv.Close()
+
+	pong := make(chan []byte, 1)
+	ping := []byte(`{"header":{"tr_id":"PINGPONG"}}`)
+	streamSrv := newStreamServer(t, func(conn *websocket.Conn) {
+		if err := conn.WriteMessage(websocket.TextMessage, ping); err != nil {
+			return
+		}
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		pong <- data
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	client := newTestStreamingClient(t, wsURL(streamSrv.URL), approvalSrv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go client.Run(ctx, ratelimitTestConfig())
+
+	select {
+	case data := <-pong:
+		if string(data) != string(ping) {
+			t.Errorf("pong payload = %q, want echo of %q", data, ping)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for pong")
+	}
+}
+
+// TestStreamingClient_ConcurrentControlFrameAndSubscribeWrites drives
+// handleControlFrame's PINGPONG echo directly against
+// sendSubscribeFrameLocked's subscribe writes, both targeting the same live
+// conn, to catch an unserialized write to *websocket.Conn under -race. Going
+// through the full Subscribe/Run stack makes this race too rare to reproduce
+// reliably: every Subscribe call round-trips to the approval server for a
+// fresh approval key, which starves the write of CPU time relative to how
+// often PINGPONG frames actually arrive.
+func TestStreamingClient_ConcurrentControlFrameAndSubscribeWrites(t *testing.T) {
+	streamSrv := newStreamServer(t, func(conn *websocket.Conn) {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	client := newTestStreamingClient(t, wsURL(streamSrv.URL), "http://unused.invalid")
+
+	conn, _, err := client.dialer.DialContext(context.Background(), wsURL(streamSrv.URL), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	client.conn = conn
+
+	ping := []byte(`{"header":{"tr_id":"PINGPONG"}}`)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				client.handleControlFrame(conn, ping)
+			}
+		}()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				_ = client.sendSubscribeFrameLocked("005930", StreamKindTick, trTypeSubscribe, "test-approval-key")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStreamingClient_SubscriptionLimitReached(t *testing.T) {
+	approvalSrv := newApprovalServer(t)
+	defer approvalSrv.Close()
+
+	client := newTestStreamingClient(t, "ws://unused.invalid", approvalSrv.URL)
+
+	symbols := make([]string, maxStreamSubscriptions+1)
+	for i := range symbols {
+		symbols[i] = string(rune('A' + i))
+	}
+
+	err := client.Subscribe(context.Background(), symbols, StreamKindTick)
+	if err == nil {
+		t.Fatal("expected subscription limit error, got nil")
+	}
+}
+
+func TestStreamingClient_ReconnectsAfterDisconnect(t *testing.T) {
+	approvalSrv := newApprovalServer(t)
+	defer approvalSrv.Close()
+
+	var attempts atomic.Int32
+	streamSrv := newStreamServer(t, func(conn *websocket.Conn) {
+		var frame subscribeFrame
+		if err := conn.ReadJSON(&frame); err != nil {
+			return
+		}
+
+		if attempts.Add(1) == 1 {
+			// Drop the first connection right after the handshake to force Run
+			// through its reconnect path.
+			return
+		}
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte("0|H0STCNT0|1|005930^090000^70000^100\n"))
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	client := newTestStreamingClient(t, wsURL(streamSrv.URL), approvalSrv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := client.Subscribe(ctx, []string{"005930"}, StreamKindTick); err != nil {
+		t.Fatalf("subscribe before connect: %v", err)
+	}
+
+	go client.Run(ctx, ratelimitTestConfig())
+
+	select {
+	case tick := <-client.Ticks():
+		if tick.Symbol != "005930" {
+			t.Errorf("symbol = %q, want %q", tick.Symbol, "005930")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for tick after reconnect")
+	}
+
+	if got := attempts.Load(); got < 2 {
+		t.Errorf("connection attempts = %d, want at least 2 (reconnect did not happen)", got)
+	}
+}
+
+func TestParseTick(t *testing.T) {
+	tick, err := parseTick("005930^090000^70000^100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tick.Symbol != "005930" {
+		t.Errorf("symbol = %q, want %q", tick.Symbol, "005930")
+	}
+	if tick.Price != 70000 {
+		t.Errorf("price = %v, want 70000", tick.Price)
+	}
+	if tick.Volume != 100 {
+		t.Errorf("volume = %d, want 100", tick.Volume)
+	}
+}
+
+func TestParseOrderBook(t *testing.T) {
+	record := "005930^090000^" +
+		"70100^10^70200^20^70300^30^70400^40^70500^50^" +
+		"70000^15^69900^25^69800^35^69700^45^69600^55"
+	snapshot, err := parseOrderBook(record)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snapshot.Asks) != orderbookLevels || len(snapshot.Bids) != orderbookLevels {
+		t.Fatalf("asks/bids len = %d/%d, want %d/%d", len(snapshot.Asks), len(snapshot.Bids), orderbookLevels, orderbookLevels)
+	}
+	if snapshot.Asks[0].Price != 70100 || snapshot.Asks[0].Quantity != 10 {
+		t.Errorf("asks[0] = %+v, want {70100 10}", snapshot.Asks[0])
+	}
+	if snapshot.Bids[0].Price != 70000 || snapshot.Bids[0].Quantity != 15 {
+		t.Errorf("bids[0] = %+v, want {70000 15}", snapshot.Bids[0])
+	}
+}
+
+func ratelimitTestConfig() ratelimit.RetryConfig {
+	return ratelimit.RetryConfig{InitialBackoff: time.Millisecond, MaxAttempts: 3, MaxBackoff: 10 * time.Millisecond}
+}
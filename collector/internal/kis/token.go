@@ -1,17 +1,17 @@
 package kis
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"log/slog"
 	"sync"
 	"time"
+
+	"github.com/jusikbot/collector/internal/httpclient"
 )
 
 const (
+	approvalPath      = "/oauth2/Approval"
 	renewBeforeExpiry = 30 * time.Minute
 	tokenPath         = "/oauth2/tokenP"
 )
@@ -24,30 +24,30 @@ type tokenResponse struct {
 }
 
 // TokenProvider manages KIS OAuth2 tokens with lazy init and pre-expiry renewal.
-// Why net/http.Client instead of httpclient.Client: token endpoint requires POST,
-// but httpclient.Client only supports GET. Acceptable since token responses are small
-// and token issuance is infrequent (~once per 24h).
 // Why sync.Mutex over sync.RWMutex: token reads always check expiry, which may trigger
 // a renewal write. RWMutex adds complexity without benefit for this access pattern.
 type TokenProvider struct {
-	appKey     string
-	appSecret  string
-	baseURL    string
-	expiresAt  time.Time
-	httpClient *http.Client
-	mu         sync.Mutex
-	token      string
+	appKey    string
+	appSecret string
+	expiresAt time.Time
+	http      *httpclient.Client
+	mu        sync.Mutex
+	store     TokenStore
+	token     string
 }
 
-func NewTokenProvider(baseURL, appKey, appSecret string, httpClient *http.Client) *TokenProvider {
+// NewTokenProvider creates a TokenProvider for baseURL. Pass nil httpClient
+// for a default httpclient.Client (KIS token issuance is infrequent, so the
+// default timeout/body-size limits are generous enough).
+func NewTokenProvider(baseURL, appKey, appSecret string, httpClient *httpclient.Client, store TokenStore) *TokenProvider {
 	if httpClient == nil {
-		httpClient = &http.Client{Timeout: 10 * time.Second}
+		httpClient = httpclient.NewClient(baseURL, nil, nil, 0)
 	}
 	return &TokenProvider{
-		appKey:     appKey,
-		appSecret:  appSecret,
-		baseURL:    baseURL,
-		httpClient: httpClient,
+		appKey:    appKey,
+		appSecret: appSecret,
+		http:      httpClient,
+		store:     store,
 	}
 }
 
@@ -57,6 +57,10 @@ func (p *TokenProvider) Token(ctx context.Context) (string, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	if p.token == "" && p.store != nil {
+		p.loadFromStore(ctx)
+	}
+
 	if p.isValid() {
 		return p.token, nil
 	}
@@ -64,52 +68,110 @@ func (p *TokenProvider) Token(ctx context.Context) (string, error) {
 	return p.fetchToken(ctx)
 }
 
+// Start launches a background goroutine that proactively renews the token
+// renewBeforeExpiry before it expires, rather than waiting for the next
+// Token call to notice. This keeps long-lived consumers (e.g. StreamingClient)
+// from ever observing a stale token mid-stream. Start returns immediately; the
+// goroutine exits when ctx is done.
+func (p *TokenProvider) Start(ctx context.Context) {
+	go func() {
+		if _, err := p.Token(ctx); err != nil {
+			slog.Warn("kis token proactive refresh failed", "error", err)
+		}
+
+		for {
+			p.mu.Lock()
+			wait := time.Until(p.expiresAt.Add(-renewBeforeExpiry))
+			p.mu.Unlock()
+			if wait < 0 {
+				wait = 0
+			}
+
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return
+			}
+
+			if ctx.Err() != nil {
+				return
+			}
+			if _, err := p.Token(ctx); err != nil {
+				slog.Warn("kis token proactive refresh failed", "error", err)
+			}
+		}
+	}()
+}
+
+// loadFromStore populates the in-memory token from p.store so a short-lived
+// process can reuse a token issued by a previous invocation. Load errors are
+// swallowed since falling through to fetchToken is always a safe recovery.
+func (p *TokenProvider) loadFromStore(ctx context.Context) {
+	tok, err := p.store.Load(ctx)
+	if err != nil || tok.AccessToken == "" {
+		return
+	}
+	p.token = tok.AccessToken
+	p.expiresAt = tok.ExpiresAt
+}
+
 func (p *TokenProvider) isValid() bool {
 	return p.token != "" && time.Now().Before(p.expiresAt.Add(-renewBeforeExpiry))
 }
 
 func (p *TokenProvider) fetchToken(ctx context.Context) (string, error) {
-	reqBody, err := json.Marshal(map[string]string{
+	tok, _, err := httpclient.DoJSON[tokenResponse](p.http.Post(ctx, tokenPath, map[string]string{
 		"appkey":     p.appKey,
 		"appsecret":  p.appSecret,
 		"grant_type": "client_credentials",
-	})
+	}))
 	if err != nil {
-		return "", fmt.Errorf("marshal token request: %w", err)
+		return "", fmt.Errorf("token request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+tokenPath, bytes.NewReader(reqBody))
-	if err != nil {
-		return "", fmt.Errorf("create token request: %w", err)
+	if tok.AccessToken == "" {
+		return "", fmt.Errorf("empty access token in response")
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := p.httpClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("token request: %w", err)
+	issuedAt := time.Now()
+	p.token = tok.AccessToken
+	p.expiresAt = issuedAt.Add(time.Duration(tok.ExpiresIn) * time.Second)
+
+	if p.store != nil {
+		saveErr := p.store.Save(ctx, Token{
+			AccessToken: p.token,
+			ExpiresAt:   p.expiresAt,
+			IssuedAt:    issuedAt,
+		})
+		if saveErr != nil {
+			slog.Warn("persist kis token failed", "error", saveErr)
+		}
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("read token response: %w", err)
-	}
+	return p.token, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("token request failed (HTTP %d): %s", resp.StatusCode, string(body))
-	}
+// approvalKeyResponse represents the KIS WebSocket approval-key endpoint response.
+type approvalKeyResponse struct {
+	ApprovalKey string `json:"approval_key"`
+}
 
-	var tok tokenResponse
-	if err := json.Unmarshal(body, &tok); err != nil {
-		return "", fmt.Errorf("parse token response: %w", err)
+// ApprovalKey fetches a fresh approval key for the real-time WebSocket handshake.
+// Unlike Token, it is not cached on the provider: StreamingClient fetches one per
+// connection attempt (KIS approval keys are cheap to issue and only need to live
+// for that connection's lifetime).
+func (p *TokenProvider) ApprovalKey(ctx context.Context) (string, error) {
+	parsed, _, err := httpclient.DoJSON[approvalKeyResponse](p.http.Post(ctx, approvalPath, map[string]string{
+		"appkey":     p.appKey,
+		"grant_type": "client_credentials",
+		"secretkey":  p.appSecret,
+	}))
+	if err != nil {
+		return "", fmt.Errorf("approval key request: %w", err)
 	}
-
-	if tok.AccessToken == "" {
-		return "", fmt.Errorf("empty access token in response")
+	if parsed.ApprovalKey == "" {
+		return "", fmt.Errorf("empty approval key in response")
 	}
 
-	p.token = tok.AccessToken
-	p.expiresAt = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
-
-	return p.token, nil
+	return parsed.ApprovalKey, nil
 }
@@ -0,0 +1,233 @@
+package kis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// cacheDirName is the subdirectory of the user's cache directory where token
+// files are written; keyed by appKey hash so multiple credentials can coexist.
+const cacheDirName = "jusikbot"
+
+// defaultClockSkewSlack bounds how far in the past a persisted token's
+// ExpiresAt may be before Load treats it as absent rather than handing back a
+// token that looks valid only because the reader's clock is behind the
+// writer's. Tokens expired by more than this are indistinguishable from "no
+// token cached" to callers.
+const defaultClockSkewSlack = 5 * time.Second
+
+// Token is the KIS OAuth2 token state a TokenStore persists across process
+// invocations.
+type Token struct {
+	AccessToken string
+	ExpiresAt   time.Time
+	IssuedAt    time.Time
+}
+
+// TokenStore persists a KIS OAuth2 token across process invocations.
+type TokenStore interface {
+	Load(ctx context.Context) (Token, error)
+	Save(ctx context.Context, tok Token) error
+}
+
+// persistedToken is the on-disk JSON shape written by FileTokenStore.
+type persistedToken struct {
+	AccessToken string    `json:"access_token"`
+	AppKeyHash  string    `json:"app_key_hash"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	IssuedAt    time.Time `json:"issued_at"`
+}
+
+// FileTokenStore persists a token as JSON under ~/.cache/jusikbot/, named by a
+// hash of the app key so multiple credentials can coexist without colliding.
+// Why a file at all: KIS caps /oauth2/tokenP issuance to roughly once per minute
+// per app-key and locks the key out for the day if abused, so short-lived
+// processes (cron invocations) must reuse a token across runs.
+type FileTokenStore struct {
+	appKeyHash     string
+	path           string
+	clockSkewSlack time.Duration
+}
+
+// NewFileTokenStore returns a FileTokenStore for appKey rooted at ~/.cache/jusikbot/.
+func NewFileTokenStore(appKey string) (*FileTokenStore, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("resolve home directory: %w", err)
+	}
+
+	hash := sha256.Sum256([]byte(appKey))
+	appKeyHash := hex.EncodeToString(hash[:])
+
+	return &FileTokenStore{
+		appKeyHash:     appKeyHash,
+		path:           filepath.Join(home, ".cache", cacheDirName, appKeyHash+".json"),
+		clockSkewSlack: defaultClockSkewSlack,
+	}, nil
+}
+
+// Load reads the persisted token, returning a zero Token if no file exists
+// yet, or if the persisted token's ExpiresAt is further in the past than
+// clockSkewSlack allows (see defaultClockSkewSlack). ctx is accepted to
+// satisfy TokenStore; file reads don't need it.
+func (s *FileTokenStore) Load(_ context.Context) (Token, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return Token{}, err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Token{}, nil
+		}
+		return Token{}, fmt.Errorf("read token file %s: %w", s.path, err)
+	}
+
+	var tok persistedToken
+	if err := json.Unmarshal(data, &tok); err != nil {
+		return Token{}, fmt.Errorf("parse token file %s: %w", s.path, err)
+	}
+
+	if time.Now().After(tok.ExpiresAt.Add(s.clockSkewSlack)) {
+		return Token{}, nil
+	}
+
+	return Token{AccessToken: tok.AccessToken, ExpiresAt: tok.ExpiresAt, IssuedAt: tok.IssuedAt}, nil
+}
+
+// Save writes tok to disk via a temp file + rename, with 0600 perms since the
+// token is a secret. ctx is accepted to satisfy TokenStore; file writes don't
+// need it.
+func (s *FileTokenStore) Save(_ context.Context, tok Token) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := json.Marshal(persistedToken{
+		AccessToken: tok.AccessToken,
+		AppKeyHash:  s.appKeyHash,
+		ExpiresAt:   tok.ExpiresAt,
+		IssuedAt:    tok.IssuedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal token: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("create cache directory %s: %w", dir, err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write temp file %s: %w", tmp, err)
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rename %s to %s: %w", tmp, s.path, err)
+	}
+
+	return nil
+}
+
+// lock takes an exclusive advisory lock on a sibling .lock file, so two
+// processes racing to refresh the same app-key's token (e.g. a cron overlap)
+// serialize instead of one clobbering the other's write mid-rename. The
+// returned func releases the lock; callers must always invoke it.
+func (s *FileTokenStore) lock() (func(), error) {
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("create cache directory %s: %w", dir, err)
+	}
+
+	lockPath := s.path + ".lock"
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file %s: %w", lockPath, err)
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("lock %s: %w", lockPath, err)
+	}
+
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+// PostgresTokenStore persists a token in the kis_tokens table, for deployments
+// that run multiple short-lived collector processes against a shared
+// database instead of a shared filesystem (see FileTokenStore).
+type PostgresTokenStore struct {
+	pool           *pgxpool.Pool
+	appKeyHash     string
+	clockSkewSlack time.Duration
+}
+
+// NewPostgresTokenStore returns a PostgresTokenStore for appKey, keyed by a
+// hash of it so multiple credentials can share the kis_tokens table.
+func NewPostgresTokenStore(pool *pgxpool.Pool, appKey string) *PostgresTokenStore {
+	hash := sha256.Sum256([]byte(appKey))
+	return &PostgresTokenStore{
+		pool:           pool,
+		appKeyHash:     hex.EncodeToString(hash[:]),
+		clockSkewSlack: defaultClockSkewSlack,
+	}
+}
+
+// Load returns the persisted token, or a zero Token if no row exists yet, or
+// if the persisted token's ExpiresAt is further in the past than
+// clockSkewSlack allows (see defaultClockSkewSlack).
+func (s *PostgresTokenStore) Load(ctx context.Context) (Token, error) {
+	var tok Token
+	err := s.pool.QueryRow(ctx, `
+		SELECT access_token, expires_at, issued_at FROM kis_tokens WHERE app_key_hash = $1
+	`, s.appKeyHash).Scan(&tok.AccessToken, &tok.ExpiresAt, &tok.IssuedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Token{}, nil
+		}
+		return Token{}, fmt.Errorf("load kis token: %w", err)
+	}
+
+	if time.Now().After(tok.ExpiresAt.Add(s.clockSkewSlack)) {
+		return Token{}, nil
+	}
+
+	return tok, nil
+}
+
+// Save upserts tok into kis_tokens, keyed by the app key's hash.
+func (s *PostgresTokenStore) Save(ctx context.Context, tok Token) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO kis_tokens (app_key_hash, access_token, issued_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (app_key_hash) DO UPDATE SET
+			access_token = EXCLUDED.access_token,
+			issued_at    = EXCLUDED.issued_at,
+			expires_at   = EXCLUDED.expires_at
+	`, s.appKeyHash, tok.AccessToken, tok.IssuedAt, tok.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("save kis token: %w", err)
+	}
+	return nil
+}
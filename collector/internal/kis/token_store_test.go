@@ -0,0 +1,344 @@
+package kis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jusikbot/collector/internal/store"
+)
+
+// withHome temporarily overrides HOME so FileTokenStore writes under a
+// throwaway directory instead of the real ~/.cache.
+func withHome(t *testing.T, dir string) {
+	t.Helper()
+	t.Setenv("HOME", dir)
+}
+
+func TestFileTokenStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("load on empty cache returns zero value", func(t *testing.T) {
+		withHome(t, t.TempDir())
+
+		store, err := NewFileTokenStore("app-key")
+		if err != nil {
+			t.Fatalf("NewFileTokenStore: %v", err)
+		}
+
+		tok, err := store.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if tok != (Token{}) {
+			t.Errorf("Load() = %+v, want zero value", tok)
+		}
+	})
+
+	t.Run("save then load round-trips", func(t *testing.T) {
+		withHome(t, t.TempDir())
+
+		store, err := NewFileTokenStore("app-key")
+		if err != nil {
+			t.Fatalf("NewFileTokenStore: %v", err)
+		}
+
+		// JSON round-trips time.Time as RFC3339Nano, and the checks below use
+		// Equal, which ignores the monotonic reading; Truncate only rounds the
+		// expected values to whole seconds.
+		want := Token{
+			AccessToken: "saved-token",
+			ExpiresAt:   time.Now().Add(24 * time.Hour).Truncate(time.Second),
+			IssuedAt:    time.Now().Truncate(time.Second),
+		}
+		if err := store.Save(ctx, want); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		got, err := store.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if got.AccessToken != want.AccessToken {
+			t.Errorf("AccessToken = %q, want %q", got.AccessToken, want.AccessToken)
+		}
+		if !got.ExpiresAt.Equal(want.ExpiresAt) {
+			t.Errorf("ExpiresAt = %v, want %v", got.ExpiresAt, want.ExpiresAt)
+		}
+		if !got.IssuedAt.Equal(want.IssuedAt) {
+			t.Errorf("IssuedAt = %v, want %v", got.IssuedAt, want.IssuedAt)
+		}
+	})
+
+	t.Run("load rejects a token expired beyond the clock skew slack", func(t *testing.T) {
+		withHome(t, t.TempDir())
+
+		store, err := NewFileTokenStore("app-key")
+		if err != nil {
+			t.Fatalf("NewFileTokenStore: %v", err)
+		}
+		store.clockSkewSlack = time.Second
+
+		expired := Token{
+			AccessToken: "stale-token",
+			ExpiresAt:   time.Now().Add(-10 * time.Second),
+			IssuedAt:    time.Now().Add(-time.Hour),
+		}
+		if err := store.Save(ctx, expired); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		got, err := store.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if got != (Token{}) {
+			t.Errorf("Load() = %+v, want zero value for a token expired beyond the slack", got)
+		}
+	})
+
+	t.Run("load accepts a token only just past expiry within the clock skew slack", func(t *testing.T) {
+		withHome(t, t.TempDir())
+
+		store, err := NewFileTokenStore("app-key")
+		if err != nil {
+			t.Fatalf("NewFileTokenStore: %v", err)
+		}
+		store.clockSkewSlack = time.Minute
+
+		tok := Token{
+			AccessToken: "recent-token",
+			ExpiresAt:   time.Now().Add(-10 * time.Second),
+			IssuedAt:    time.Now().Add(-time.Hour),
+		}
+		if err := store.Save(ctx, tok); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		got, err := store.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if got.AccessToken != tok.AccessToken {
+			t.Errorf("AccessToken = %q, want %q (within clock skew slack)", got.AccessToken, tok.AccessToken)
+		}
+	})
+
+	t.Run("file is written with 0600 permissions", func(t *testing.T) {
+		home := t.TempDir()
+		withHome(t, home)
+
+		store, err := NewFileTokenStore("app-key")
+		if err != nil {
+			t.Fatalf("NewFileTokenStore: %v", err)
+		}
+		if err := store.Save(ctx, Token{AccessToken: "secret-token", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		info, err := os.Stat(filepath.Join(home, ".cache", cacheDirName, store.appKeyHash+".json"))
+		if err != nil {
+			t.Fatalf("stat token file: %v", err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("file perms = %o, want 0600", perm)
+		}
+	})
+
+	t.Run("different app keys use different files", func(t *testing.T) {
+		withHome(t, t.TempDir())
+
+		storeA, err := NewFileTokenStore("app-key-a")
+		if err != nil {
+			t.Fatalf("NewFileTokenStore: %v", err)
+		}
+		storeB, err := NewFileTokenStore("app-key-b")
+		if err != nil {
+			t.Fatalf("NewFileTokenStore: %v", err)
+		}
+
+		if err := storeA.Save(ctx, Token{AccessToken: "token-a", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("Save A: %v", err)
+		}
+
+		got, err := storeB.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load B: %v", err)
+		}
+		if got.AccessToken != "" {
+			t.Errorf("store B token = %q, want empty (isolated from store A)", got.AccessToken)
+		}
+	})
+
+	t.Run("concurrent saves do not corrupt the file", func(t *testing.T) {
+		withHome(t, t.TempDir())
+
+		store, err := NewFileTokenStore("app-key")
+		if err != nil {
+			t.Fatalf("NewFileTokenStore: %v", err)
+		}
+
+		done := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func(i int) {
+				done <- store.Save(ctx, Token{
+					AccessToken: "token",
+					ExpiresAt:   time.Now().Add(time.Duration(i+1) * time.Hour),
+				})
+			}(i)
+		}
+		for i := 0; i < 2; i++ {
+			if err := <-done; err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+		}
+
+		got, err := store.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if got.AccessToken != "token" {
+			t.Errorf("AccessToken = %q, want token", got.AccessToken)
+		}
+	})
+}
+
+// connectPostgres returns a pool with migrations applied and the kis_tokens
+// table truncated, or skips the test if DATABASE_URL is unset. Mirrors the
+// store package's own integration-test gating (see
+// internal/store/migrate_test.go): these tests only run where a real
+// Postgres is reachable.
+func connectPostgres(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		t.Skip("DATABASE_URL not set; skipping integration test")
+	}
+
+	ctx := context.Background()
+	pool, err := store.ConnectDB(ctx, url)
+	if err != nil {
+		t.Fatalf("connect to database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	if err := store.RunMigrations(ctx, pool); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+	if _, err := pool.Exec(ctx, "DELETE FROM kis_tokens"); err != nil {
+		t.Fatalf("truncate kis_tokens: %v", err)
+	}
+
+	return pool
+}
+
+func TestPostgresTokenStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("load on empty table returns zero value", func(t *testing.T) {
+		pool := connectPostgres(t)
+		store := NewPostgresTokenStore(pool, "app-key")
+
+		tok, err := store.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if tok != (Token{}) {
+			t.Errorf("Load() = %+v, want zero value", tok)
+		}
+	})
+
+	t.Run("save then load round-trips", func(t *testing.T) {
+		pool := connectPostgres(t)
+		store := NewPostgresTokenStore(pool, "app-key")
+
+		// Why Truncate: Postgres timestamptz round-trips to microsecond
+		// precision, not the monotonic-reading-carrying time.Time Go produces.
+		want := Token{
+			AccessToken: "saved-token",
+			ExpiresAt:   time.Now().Add(24 * time.Hour).Truncate(time.Microsecond),
+			IssuedAt:    time.Now().Truncate(time.Microsecond),
+		}
+		if err := store.Save(ctx, want); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		got, err := store.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if got.AccessToken != want.AccessToken {
+			t.Errorf("AccessToken = %q, want %q", got.AccessToken, want.AccessToken)
+		}
+		if !got.ExpiresAt.Equal(want.ExpiresAt) {
+			t.Errorf("ExpiresAt = %v, want %v", got.ExpiresAt, want.ExpiresAt)
+		}
+		if !got.IssuedAt.Equal(want.IssuedAt) {
+			t.Errorf("IssuedAt = %v, want %v", got.IssuedAt, want.IssuedAt)
+		}
+	})
+
+	t.Run("save upserts on conflict", func(t *testing.T) {
+		pool := connectPostgres(t)
+		store := NewPostgresTokenStore(pool, "app-key")
+
+		if err := store.Save(ctx, Token{AccessToken: "first-token", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("Save first: %v", err)
+		}
+		if err := store.Save(ctx, Token{AccessToken: "second-token", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("Save second: %v", err)
+		}
+
+		got, err := store.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if got.AccessToken != "second-token" {
+			t.Errorf("AccessToken = %q, want second-token (upserted)", got.AccessToken)
+		}
+	})
+
+	t.Run("load rejects a token expired beyond the clock skew slack", func(t *testing.T) {
+		pool := connectPostgres(t)
+		store := NewPostgresTokenStore(pool, "app-key")
+		store.clockSkewSlack = time.Second
+
+		expired := Token{
+			AccessToken: "stale-token",
+			ExpiresAt:   time.Now().Add(-10 * time.Second),
+			IssuedAt:    time.Now().Add(-time.Hour),
+		}
+		if err := store.Save(ctx, expired); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		got, err := store.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if got != (Token{}) {
+			t.Errorf("Load() = %+v, want zero value for a token expired beyond the slack", got)
+		}
+	})
+
+	t.Run("different app keys use different rows", func(t *testing.T) {
+		pool := connectPostgres(t)
+		storeA := NewPostgresTokenStore(pool, "app-key-a")
+		storeB := NewPostgresTokenStore(pool, "app-key-b")
+
+		if err := storeA.Save(ctx, Token{AccessToken: "token-a", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("Save A: %v", err)
+		}
+
+		got, err := storeB.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load B: %v", err)
+		}
+		if got.AccessToken != "" {
+			t.Errorf("store B token = %q, want empty (isolated from store A)", got.AccessToken)
+		}
+	})
+}
@@ -8,6 +8,8 @@ import (
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/jusikbot/collector/internal/httpclient"
 )
 
 func newTokenServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
@@ -51,7 +53,7 @@ func TestTokenProvider(t *testing.T) {
 		srv := newTokenServer(t, validTokenHandler("test-token-123", 86400))
 		defer srv.Close()
 
-		provider := NewTokenProvider(srv.URL, "app-key", "app-secret", srv.Client())
+		provider := NewTokenProvider(srv.URL, "app-key", "app-secret", httpclient.NewClient(srv.URL, nil, srv.Client(), 0), nil)
 
 		token, err := provider.Token(context.Background())
 		if err != nil {
@@ -70,7 +72,7 @@ func TestTokenProvider(t *testing.T) {
 		})
 		defer srv.Close()
 
-		provider := NewTokenProvider(srv.URL, "app-key", "app-secret", srv.Client())
+		provider := NewTokenProvider(srv.URL, "app-key", "app-secret", httpclient.NewClient(srv.URL, nil, srv.Client(), 0), nil)
 
 		token1, err := provider.Token(context.Background())
 		if err != nil {
@@ -103,7 +105,7 @@ func TestTokenProvider(t *testing.T) {
 		})
 		defer srv.Close()
 
-		provider := NewTokenProvider(srv.URL, "app-key", "app-secret", srv.Client())
+		provider := NewTokenProvider(srv.URL, "app-key", "app-secret", httpclient.NewClient(srv.URL, nil, srv.Client(), 0), nil)
 
 		token1, err := provider.Token(context.Background())
 		if err != nil {
@@ -155,7 +157,7 @@ func TestTokenProvider(t *testing.T) {
 		})
 		defer srv.Close()
 
-		provider := NewTokenProvider(srv.URL, "my-app-key", "my-app-secret", srv.Client())
+		provider := NewTokenProvider(srv.URL, "my-app-key", "my-app-secret", httpclient.NewClient(srv.URL, nil, srv.Client(), 0), nil)
 		if _, err := provider.Token(context.Background()); err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -168,7 +170,7 @@ func TestTokenProvider(t *testing.T) {
 		})
 		defer srv.Close()
 
-		provider := NewTokenProvider(srv.URL, "key", "secret", srv.Client())
+		provider := NewTokenProvider(srv.URL, "key", "secret", httpclient.NewClient(srv.URL, nil, srv.Client(), 0), nil)
 
 		_, err := provider.Token(context.Background())
 		if err == nil {
@@ -187,7 +189,7 @@ func TestTokenProvider(t *testing.T) {
 		})
 		defer srv.Close()
 
-		provider := NewTokenProvider(srv.URL, "key", "secret", srv.Client())
+		provider := NewTokenProvider(srv.URL, "key", "secret", httpclient.NewClient(srv.URL, nil, srv.Client(), 0), nil)
 
 		_, err := provider.Token(context.Background())
 		if err == nil {
@@ -206,7 +208,7 @@ func TestTokenProvider(t *testing.T) {
 		})
 		defer srv.Close()
 
-		provider := NewTokenProvider(srv.URL, "key", "secret", &http.Client{Timeout: 100 * time.Millisecond})
+		provider := NewTokenProvider(srv.URL, "key", "secret", httpclient.NewClient(srv.URL, nil, &http.Client{Timeout: 100 * time.Millisecond}, 0), nil)
 
 		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
 		defer cancel()
@@ -216,4 +218,89 @@ func TestTokenProvider(t *testing.T) {
 			t.Fatal("expected error for cancelled context")
 		}
 	})
+
+	t.Run("loads token from store before checking validity", func(t *testing.T) {
+		srv := newTokenServer(t, validTokenHandler("should-not-be-fetched", 86400))
+		defer srv.Close()
+
+		store := &fakeTokenStore{
+			tok: Token{AccessToken: "stored-token", ExpiresAt: time.Now().Add(24 * time.Hour)},
+		}
+		provider := NewTokenProvider(srv.URL, "app-key", "app-secret", httpclient.NewClient(srv.URL, nil, srv.Client(), 0), store)
+
+		token, err := provider.Token(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if token != "stored-token" {
+			t.Errorf("token = %q, want stored-token", token)
+		}
+		if store.loads != 1 {
+			t.Errorf("store.Load called %d times, want 1", store.loads)
+		}
+	})
+
+	t.Run("persists token to store after fetching", func(t *testing.T) {
+		srv := newTokenServer(t, validTokenHandler("fresh-token", 86400))
+		defer srv.Close()
+
+		store := &fakeTokenStore{}
+		provider := NewTokenProvider(srv.URL, "app-key", "app-secret", httpclient.NewClient(srv.URL, nil, srv.Client(), 0), store)
+
+		if _, err := provider.Token(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if store.tok.AccessToken != "fresh-token" {
+			t.Errorf("store.tok.AccessToken = %q, want fresh-token", store.tok.AccessToken)
+		}
+		if store.tok.IssuedAt.IsZero() {
+			t.Error("store.tok.IssuedAt = zero value, want populated")
+		}
+	})
+
+	t.Run("Start proactively refreshes before expiry", func(t *testing.T) {
+		var callCount atomic.Int32
+		srv := newTokenServer(t, func(w http.ResponseWriter, r *http.Request) {
+			count := callCount.Add(1)
+			token := "token-v1"
+			if count > 1 {
+				token = "token-v2"
+			}
+			// Why 1 second: forces immediate expiry so Start's refresh loop fires
+			// again right away instead of the test waiting out renewBeforeExpiry.
+			validTokenHandler(token, 1)(w, r)
+		})
+		defer srv.Close()
+
+		provider := NewTokenProvider(srv.URL, "app-key", "app-secret", httpclient.NewClient(srv.URL, nil, srv.Client(), 0), nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		provider.Start(ctx)
+
+		deadline := time.Now().Add(2 * time.Second)
+		for callCount.Load() < 2 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if callCount.Load() < 2 {
+			t.Errorf("server called %d times, want at least 2 (initial + proactive refresh)", callCount.Load())
+		}
+	})
+}
+
+// fakeTokenStore is an in-memory TokenStore for exercising TokenProvider's
+// load-before-fetch and persist-after-fetch behavior without touching disk.
+type fakeTokenStore struct {
+	tok   Token
+	loads int
+}
+
+func (s *fakeTokenStore) Load(_ context.Context) (Token, error) {
+	s.loads++
+	return s.tok, nil
+}
+
+func (s *fakeTokenStore) Save(_ context.Context, tok Token) error {
+	s.tok = tok
+	return nil
 }
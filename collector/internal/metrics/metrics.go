@@ -0,0 +1,136 @@
+// Package metrics defines the Prometheus collectors shared by store.Repository
+// and httpclient.Client. Both accept a prometheus.Registerer via a functional
+// option (store.WithMetrics, httpclient.WithMetrics) rather than reaching for
+// a package-level default registry, so a process embedding multiple
+// Repository/Client instances (or tests) can register each against its own
+// registry without collisions.
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// StoreMetrics instruments store.Repository's upsert paths.
+type StoreMetrics struct {
+	// UpsertDuration observes how long an upsert transaction takes, labeled by
+	// the target table (e.g. "price_history", "fx_rate").
+	UpsertDuration *prometheus.HistogramVec
+
+	// RowsInserted and RowsUpdated count rows by outcome, derived from the
+	// same xmax = 0 RETURNING check upsertPricesTx already uses to split its
+	// return value.
+	RowsInserted *prometheus.CounterVec
+	RowsUpdated  *prometheus.CounterVec
+
+	// TempTableRows gauges the row count copied into the temp table for the
+	// most recent upsert, labeled by table.
+	TempTableRows *prometheus.GaugeVec
+}
+
+// NewStoreMetrics builds and registers a StoreMetrics against reg.
+func NewStoreMetrics(reg prometheus.Registerer) *StoreMetrics {
+	m := &StoreMetrics{
+		UpsertDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "collector",
+			Subsystem: "store",
+			Name:      "upsert_duration_seconds",
+			Help:      "Time spent in an upsert transaction, by table.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"table"}),
+		RowsInserted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "collector",
+			Subsystem: "store",
+			Name:      "rows_inserted_total",
+			Help:      "Rows inserted by an upsert, by table.",
+		}, []string{"table"}),
+		RowsUpdated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "collector",
+			Subsystem: "store",
+			Name:      "rows_updated_total",
+			Help:      "Rows updated by an upsert, by table.",
+		}, []string{"table"}),
+		TempTableRows: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "collector",
+			Subsystem: "store",
+			Name:      "temp_table_rows",
+			Help:      "Rows copied into the temp table for the most recent upsert, by table.",
+		}, []string{"table"}),
+	}
+	reg.MustRegister(m.UpsertDuration, m.RowsInserted, m.RowsUpdated, m.TempTableRows)
+	return m
+}
+
+// HTTPMetrics instruments httpclient.Client's do method.
+type HTTPMetrics struct {
+	// RequestDuration observes a full request (including any followed
+	// redirects), labeled by host and status-class (e.g. "2xx", "5xx").
+	RequestDuration *prometheus.HistogramVec
+
+	// RateLimited, Timeouts, and BodyTooLarge count requests failing with
+	// ErrRateLimited, ErrTimeout, and ErrBodyTooLarge respectively, labeled by
+	// host.
+	RateLimited  *prometheus.CounterVec
+	Timeouts     *prometheus.CounterVec
+	BodyTooLarge *prometheus.CounterVec
+
+	// RetryAttempts counts redirect hops beyond the first, labeled by host.
+	RetryAttempts *prometheus.CounterVec
+}
+
+// NewHTTPMetrics builds and registers an HTTPMetrics against reg.
+func NewHTTPMetrics(reg prometheus.Registerer) *HTTPMetrics {
+	m := &HTTPMetrics{
+		RequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "collector",
+			Subsystem: "httpclient",
+			Name:      "request_duration_seconds",
+			Help:      "Time spent in a round trip, including followed redirects, by host and status class.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"host", "status_class"}),
+		RateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "collector",
+			Subsystem: "httpclient",
+			Name:      "rate_limited_total",
+			Help:      "Requests that failed with ErrRateLimited, by host.",
+		}, []string{"host"}),
+		Timeouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "collector",
+			Subsystem: "httpclient",
+			Name:      "timeouts_total",
+			Help:      "Requests that failed with ErrTimeout, by host.",
+		}, []string{"host"}),
+		BodyTooLarge: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "collector",
+			Subsystem: "httpclient",
+			Name:      "body_too_large_total",
+			Help:      "Requests that failed with ErrBodyTooLarge, by host.",
+		}, []string{"host"}),
+		RetryAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "collector",
+			Subsystem: "httpclient",
+			Name:      "retry_attempts_total",
+			Help:      "Redirect hops beyond the first followed for a request, by host.",
+		}, []string{"host"}),
+	}
+	reg.MustRegister(m.RequestDuration, m.RateLimited, m.Timeouts, m.BodyTooLarge, m.RetryAttempts)
+	return m
+}
+
+// StreamMetrics instruments internal/stream's Streamer implementations.
+type StreamMetrics struct {
+	// DroppedPrices counts daily bars dropped because a slow consumer left the
+	// price channel full, labeled by source (e.g. "tiingo", "kis").
+	DroppedPrices *prometheus.CounterVec
+}
+
+// NewStreamMetrics builds and registers a StreamMetrics against reg.
+func NewStreamMetrics(reg prometheus.Registerer) *StreamMetrics {
+	m := &StreamMetrics{
+		DroppedPrices: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "collector",
+			Subsystem: "stream",
+			Name:      "dropped_prices_total",
+			Help:      "Daily bars dropped because a slow consumer left the price channel full, by source.",
+		}, []string{"source"}),
+	}
+	reg.MustRegister(m.DroppedPrices)
+	return m
+}
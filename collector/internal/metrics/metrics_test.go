@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestNewStoreMetrics_RegistersAgainstGivenRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewStoreMetrics(reg)
+
+	m.UpsertDuration.WithLabelValues("price_history").Observe(0.1)
+	m.RowsInserted.WithLabelValues("price_history").Add(2)
+	m.RowsUpdated.WithLabelValues("price_history").Add(1)
+	m.TempTableRows.WithLabelValues("price_history").Set(3)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	if len(families) != 4 {
+		t.Errorf("len(families) = %d, want 4 (duration, inserted, updated, temp rows)", len(families))
+	}
+}
+
+func TestNewHTTPMetrics_RegistersAgainstGivenRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewHTTPMetrics(reg)
+
+	m.RequestDuration.WithLabelValues("example.com", "2xx").Observe(0.05)
+	m.RateLimited.WithLabelValues("example.com").Inc()
+	m.Timeouts.WithLabelValues("example.com").Inc()
+	m.BodyTooLarge.WithLabelValues("example.com").Inc()
+	m.RetryAttempts.WithLabelValues("example.com").Inc()
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	if len(families) != 5 {
+		t.Errorf("len(families) = %d, want 5 (duration, rate limited, timeouts, body too large, retry attempts)", len(families))
+	}
+}
+
+func TestNewStreamMetrics_RegistersAgainstGivenRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewStreamMetrics(reg)
+
+	m.DroppedPrices.WithLabelValues("tiingo").Inc()
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	if len(families) != 1 {
+		t.Errorf("len(families) = %d, want 1 (dropped prices)", len(families))
+	}
+}
@@ -0,0 +1,303 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrCircuitOpen is returned by FetchWithRateLimitCB when the breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitState is one of Closed, Open, or HalfOpen.
+type CircuitState int
+
+const (
+	StateClosed CircuitState = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig controls trip and recovery thresholds.
+type CircuitBreakerConfig struct {
+	ConsecutiveFailures int
+	FailureThreshold    float64
+	MaxOpenTimeout      time.Duration
+	MinSamples          int
+	OpenTimeout         time.Duration
+	WindowSize          int
+}
+
+// DefaultCircuitBreakerConfig returns the defaults described in the package docs:
+// 20-sample rolling window, trip at >50% failures (once MinSamples is reached) or
+// 5 consecutive failures, 30s initial open timeout doubling up to 5m.
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		ConsecutiveFailures: 5,
+		FailureThreshold:    0.5,
+		MaxOpenTimeout:      5 * time.Minute,
+		MinSamples:          20,
+		OpenTimeout:         30 * time.Second,
+		WindowSize:          20,
+	}
+}
+
+// CircuitBreaker trips Open when a rolling window of call outcomes shows a high
+// failure ratio, or on a run of consecutive failures. While Open, calls are
+// short-circuited until OpenTimeout elapses, at which point a single HalfOpen
+// trial is admitted: success closes the breaker, failure re-opens it with the
+// open timeout doubled (capped at MaxOpenTimeout).
+type CircuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu                  sync.Mutex
+	state               CircuitState
+	outcomes            []bool
+	outcomeHead         int
+	consecutiveFailures int
+	currentOpenTimeout  time.Duration
+	openedAt            time.Time
+	halfOpenInFlight    bool
+}
+
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	if cfg.WindowSize <= 0 {
+		cfg.WindowSize = 20
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = 20
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 0.5
+	}
+	if cfg.ConsecutiveFailures <= 0 {
+		cfg.ConsecutiveFailures = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.MaxOpenTimeout <= 0 {
+		cfg.MaxOpenTimeout = 5 * time.Minute
+	}
+
+	return &CircuitBreaker{
+		cfg:                cfg,
+		currentOpenTimeout: cfg.OpenTimeout,
+		outcomes:           make([]bool, 0, cfg.WindowSize),
+		state:              StateClosed,
+	}
+}
+
+// State returns the breaker's current state. Primarily for tests/observability.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// Allow reports whether a call may proceed. Transitions Open->HalfOpen once
+// OpenTimeout has elapsed, admitting exactly one trial call at a time.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case StateClosed:
+		return true
+	case StateOpen:
+		if time.Since(cb.openedAt) < cb.currentOpenTimeout {
+			return false
+		}
+		cb.state = StateHalfOpen
+		cb.halfOpenInFlight = true
+		return true
+	case StateHalfOpen:
+		if cb.halfOpenInFlight {
+			return false
+		}
+		cb.halfOpenInFlight = true
+		return true
+	default:
+		return false
+	}
+}
+
+// RecordSuccess reports a successful call. A success during HalfOpen closes the
+// breaker and resets all counters.
+func (cb *CircuitBreaker) RecordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateHalfOpen {
+		cb.reset()
+		return
+	}
+
+	cb.consecutiveFailures = 0
+	cb.recordOutcome(true)
+}
+
+// RecordFailure reports a failed call. A failure during HalfOpen re-opens the
+// breaker with the open timeout doubled. Otherwise it trips Open when either
+// threshold is exceeded.
+func (cb *CircuitBreaker) RecordFailure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateHalfOpen {
+		cb.trip(true)
+		return
+	}
+
+	cb.consecutiveFailures++
+	cb.recordOutcome(false)
+
+	if cb.consecutiveFailures >= cb.cfg.ConsecutiveFailures {
+		cb.trip(false)
+		return
+	}
+
+	if len(cb.outcomes) >= cb.cfg.MinSamples && cb.failureRatio() > cb.cfg.FailureThreshold {
+		cb.trip(false)
+	}
+}
+
+func (cb *CircuitBreaker) recordOutcome(success bool) {
+	if len(cb.outcomes) < cb.cfg.WindowSize {
+		cb.outcomes = append(cb.outcomes, success)
+		return
+	}
+	cb.outcomes[cb.outcomeHead] = success
+	cb.outcomeHead = (cb.outcomeHead + 1) % cb.cfg.WindowSize
+}
+
+func (cb *CircuitBreaker) failureRatio() float64 {
+	var failures int
+	for _, ok := range cb.outcomes {
+		if !ok {
+			failures++
+		}
+	}
+	return float64(failures) / float64(len(cb.outcomes))
+}
+
+// trip opens the breaker. escalate doubles the open timeout (capped at
+// MaxOpenTimeout) for a repeated trip from HalfOpen.
+func (cb *CircuitBreaker) trip(escalate bool) {
+	if escalate {
+		cb.currentOpenTimeout *= 2
+		if cb.currentOpenTimeout > cb.cfg.MaxOpenTimeout {
+			cb.currentOpenTimeout = cb.cfg.MaxOpenTimeout
+		}
+	}
+	cb.state = StateOpen
+	cb.openedAt = time.Now()
+	cb.halfOpenInFlight = false
+}
+
+func (cb *CircuitBreaker) reset() {
+	cb.state = StateClosed
+	cb.outcomes = cb.outcomes[:0]
+	cb.outcomeHead = 0
+	cb.consecutiveFailures = 0
+	cb.currentOpenTimeout = cb.cfg.OpenTimeout
+	cb.halfOpenInFlight = false
+}
+
+// Group holds one CircuitBreaker per key (typically a host), so a failing
+// endpoint doesn't trip circuits for unrelated ones.
+type Group struct {
+	cfg      CircuitBreakerConfig
+	mu       sync.Mutex
+	breakers map[string]*CircuitBreaker
+}
+
+func NewGroup(cfg CircuitBreakerConfig) *Group {
+	return &Group{
+		breakers: make(map[string]*CircuitBreaker),
+		cfg:      cfg,
+	}
+}
+
+// Get returns the CircuitBreaker for key, creating one on first use.
+func (g *Group) Get(key string) *CircuitBreaker {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	cb, ok := g.breakers[key]
+	if !ok {
+		cb = NewCircuitBreaker(g.cfg)
+		g.breakers[key] = cb
+	}
+	return cb
+}
+
+// FetchWithRateLimitCB wraps FetchWithRateLimit with a CircuitBreaker. When the
+// breaker is open, fn is never invoked and ErrCircuitOpen is returned immediately,
+// preserving rate-limiter budget for healthy sources. isRetryable still governs
+// retries within a single admitted attempt; circuit state takes precedence over it.
+func FetchWithRateLimitCB[T any](
+	ctx context.Context,
+	limiter *rate.Limiter,
+	cb *CircuitBreaker,
+	cfg RetryConfig,
+	isRetryable func(error) bool,
+	fn func(ctx context.Context) (T, error),
+) (T, error) {
+	var zero T
+
+	if !cb.Allow() {
+		return zero, ErrCircuitOpen
+	}
+
+	result, err := FetchWithRateLimit(ctx, limiter, cfg, isRetryable, fn)
+	if err != nil {
+		cb.RecordFailure()
+		return zero, err
+	}
+
+	cb.RecordSuccess()
+	return result, nil
+}
+
+// WithRetryCB wraps WithRetry with a CircuitBreaker, for callers with no shared
+// rate.Limiter to thread through FetchWithRateLimitCB (e.g. fx.Collector's
+// per-provider fan-out). Semantics otherwise match FetchWithRateLimitCB.
+func WithRetryCB[T any](
+	ctx context.Context,
+	cb *CircuitBreaker,
+	cfg RetryConfig,
+	isRetryable func(error) bool,
+	fn func(ctx context.Context) (T, error),
+) (T, error) {
+	var zero T
+
+	if !cb.Allow() {
+		return zero, ErrCircuitOpen
+	}
+
+	result, err := WithRetry(ctx, cfg, isRetryable, fn)
+	if err != nil {
+		cb.RecordFailure()
+		return zero, err
+	}
+
+	cb.RecordSuccess()
+	return result, nil
+}
@@ -0,0 +1,214 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestCircuitBreaker_TripsOnConsecutiveFailures(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		ConsecutiveFailures: 3,
+		MinSamples:          100,
+		OpenTimeout:         time.Hour,
+	})
+
+	for range 3 {
+		if !cb.Allow() {
+			t.Fatal("expected Allow() = true before trip")
+		}
+		cb.RecordFailure()
+	}
+
+	if cb.State() != StateOpen {
+		t.Fatalf("state = %v, want Open", cb.State())
+	}
+	if cb.Allow() {
+		t.Error("Allow() = true, want false while Open")
+	}
+}
+
+func TestCircuitBreaker_TripsOnFailureRatio(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		ConsecutiveFailures: 100,
+		FailureThreshold:    0.5,
+		MinSamples:          4,
+		OpenTimeout:         time.Hour,
+		WindowSize:          4,
+	})
+
+	cb.RecordSuccess()
+	cb.RecordFailure()
+	cb.RecordSuccess()
+	if cb.State() != StateClosed {
+		t.Fatalf("state = %v, want Closed before threshold reached", cb.State())
+	}
+
+	cb.RecordFailure() // window now [S,F,S,F]: 2/4 = 0.5, not > threshold
+	if cb.State() != StateClosed {
+		t.Fatalf("state = %v, want Closed at exactly 50%% failure ratio", cb.State())
+	}
+
+	cb.RecordFailure() // ring buffer overwrites oldest success: [F,F,S,F] = 3/4 = 0.75 > 0.5
+	if cb.State() != StateOpen {
+		t.Fatalf("state = %v, want Open once failure ratio exceeds threshold", cb.State())
+	}
+}
+
+func TestCircuitBreaker_HalfOpenTrialSuccess(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		OpenTimeout:         time.Millisecond,
+	})
+
+	cb.RecordFailure()
+	if cb.State() != StateOpen {
+		t.Fatalf("state = %v, want Open", cb.State())
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if !cb.Allow() {
+		t.Fatal("expected HalfOpen trial to be admitted")
+	}
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("state = %v, want HalfOpen", cb.State())
+	}
+	if cb.Allow() {
+		t.Error("expected a second concurrent trial to be rejected during HalfOpen")
+	}
+
+	cb.RecordSuccess()
+	if cb.State() != StateClosed {
+		t.Fatalf("state = %v, want Closed after successful trial", cb.State())
+	}
+	if !cb.Allow() {
+		t.Error("expected Allow() = true after reset")
+	}
+}
+
+func TestCircuitBreaker_HalfOpenTrialFailureEscalatesTimeout(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		MaxOpenTimeout:      time.Second,
+		OpenTimeout:         time.Millisecond,
+	})
+
+	cb.RecordFailure()
+	time.Sleep(5 * time.Millisecond)
+
+	if !cb.Allow() {
+		t.Fatal("expected HalfOpen trial to be admitted")
+	}
+	cb.RecordFailure()
+
+	if cb.State() != StateOpen {
+		t.Fatalf("state = %v, want Open after failed trial", cb.State())
+	}
+	if cb.Allow() {
+		t.Error("expected Allow() = false immediately after re-opening with escalated timeout")
+	}
+}
+
+func TestGroup_PerHostIsolation(t *testing.T) {
+	g := NewGroup(CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		OpenTimeout:         time.Hour,
+	})
+
+	hostA := g.Get("api-a.example.com")
+	hostB := g.Get("api-b.example.com")
+
+	hostA.RecordFailure()
+
+	if hostA.State() != StateOpen {
+		t.Fatalf("hostA state = %v, want Open", hostA.State())
+	}
+	if hostB.State() != StateClosed {
+		t.Fatalf("hostB state = %v, want Closed (unaffected by hostA)", hostB.State())
+	}
+	if !hostB.Allow() {
+		t.Error("hostB.Allow() = false, want true (isolated from hostA's trip)")
+	}
+}
+
+func TestFetchWithRateLimitCB_ShortCircuitsWhenOpen(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		OpenTimeout:         time.Hour,
+	})
+	cb.RecordFailure()
+
+	limiter := rate.NewLimiter(rate.Inf, 1)
+	cfg := RetryConfig{MaxAttempts: 1, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	var calls int
+	_, err := FetchWithRateLimitCB(context.Background(), limiter, cb, cfg, nil, func(ctx context.Context) (string, error) {
+		calls++
+		return "ok", nil
+	})
+
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("error = %v, want ErrCircuitOpen", err)
+	}
+	if calls != 0 {
+		t.Errorf("calls = %d, want 0 (fn must not run while open)", calls)
+	}
+}
+
+func TestFetchWithRateLimitCB_RecordsOutcomes(t *testing.T) {
+	cb := NewCircuitBreaker(DefaultCircuitBreakerConfig())
+	limiter := rate.NewLimiter(rate.Inf, 1)
+	cfg := RetryConfig{MaxAttempts: 1, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	_, err := FetchWithRateLimitCB(context.Background(), limiter, cb, cfg, nil, func(ctx context.Context) (string, error) {
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("state = %v, want Closed after success", cb.State())
+	}
+}
+
+func TestWithRetryCB_ShortCircuitsWhenOpen(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		OpenTimeout:         time.Hour,
+	})
+	cb.RecordFailure()
+
+	cfg := RetryConfig{MaxAttempts: 1, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	var calls int
+	_, err := WithRetryCB(context.Background(), cb, cfg, nil, func(ctx context.Context) (string, error) {
+		calls++
+		return "ok", nil
+	})
+
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("error = %v, want ErrCircuitOpen", err)
+	}
+	if calls != 0 {
+		t.Errorf("calls = %d, want 0 (fn must not run while open)", calls)
+	}
+}
+
+func TestWithRetryCB_RecordsOutcomes(t *testing.T) {
+	cb := NewCircuitBreaker(DefaultCircuitBreakerConfig())
+	cfg := RetryConfig{MaxAttempts: 1, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	_, err := WithRetryCB(context.Background(), cb, cfg, nil, func(ctx context.Context) (string, error) {
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("state = %v, want Closed after success", cb.State())
+	}
+}
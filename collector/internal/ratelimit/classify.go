@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jusikbot/collector/internal/httpclient"
+)
+
+// RetryDecisionKind is the outcome of classifying an error for retry purposes.
+type RetryDecisionKind int
+
+const (
+	DecisionRetry RetryDecisionKind = iota
+	DecisionRetryAfter
+	DecisionStop
+)
+
+// RetryDecision pairs a RetryDecisionKind with the delay to honor when Kind is
+// DecisionRetryAfter (e.g. parsed from a server's Retry-After header).
+type RetryDecision struct {
+	After time.Duration
+	Kind  RetryDecisionKind
+}
+
+func RetryNow() RetryDecision { return RetryDecision{Kind: DecisionRetry} }
+
+func RetryAfterDuration(d time.Duration) RetryDecision {
+	return RetryDecision{After: d, Kind: DecisionRetryAfter}
+}
+
+func StopRetrying() RetryDecision { return RetryDecision{Kind: DecisionStop} }
+
+// DefaultIsRetryable is the isRetryable argument to WithRetry/FetchWithRateLimit
+// shared by collectors that don't need a Classify-level Retry-After hint: rate
+// limiting (HTTP 429 or a body-level httpclient.ErrRateLimited) and server errors
+// (5xx) are retryable, everything else is not.
+func DefaultIsRetryable(err error) bool {
+	if errors.Is(err, httpclient.ErrRateLimited) {
+		return true
+	}
+	var apiErr *httpclient.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.IsRetryable
+	}
+	return false
+}
+
+// HTTPRetryClassifier classifies an httpclient.APIError for retry decisions,
+// honoring a server-supplied Retry-After header on 429/503 responses.
+// 408/425/429/5xx → Retry (or RetryAfter when the header parses). Other 4xx → Stop.
+// Non-APIError errors (network failures, timeouts) → Retry.
+func HTTPRetryClassifier(err error) RetryDecision {
+	var apiErr *httpclient.APIError
+	if !errors.As(err, &apiErr) {
+		return RetryNow()
+	}
+
+	switch apiErr.StatusCode {
+	case 429, 503:
+		if d, ok := parseRetryAfter(apiErr.Header); ok {
+			return RetryAfterDuration(d)
+		}
+		return RetryNow()
+	case 408, 425:
+		return RetryNow()
+	}
+
+	if apiErr.StatusCode >= 500 {
+		return RetryNow()
+	}
+	if apiErr.StatusCode >= 400 {
+		return StopRetrying()
+	}
+
+	return RetryNow()
+}
+
+// parseRetryAfter parses a Retry-After header as either delta-seconds or an
+// HTTP-date, per RFC 7231 §7.1.3. Returns ok=false when absent or malformed.
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	if header == nil {
+		return 0, false
+	}
+
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+
+	d := time.Until(t)
+	if d < 0 {
+		return 0, true
+	}
+	return d, true
+}
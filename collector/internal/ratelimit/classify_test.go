@@ -0,0 +1,184 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/httpclient"
+	"golang.org/x/time/rate"
+)
+
+func TestDefaultIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"body-level rate limit sentinel", httpclient.ErrRateLimited, true},
+		{"wrapped rate limit sentinel", fmt.Errorf("fetch: %w", httpclient.ErrRateLimited), true},
+		{"retryable APIError", &httpclient.APIError{IsRetryable: true, StatusCode: 503}, true},
+		{"non-retryable APIError", &httpclient.APIError{IsRetryable: false, StatusCode: 404}, false},
+		{"non-APIError error", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultIsRetryable(tt.err); got != tt.want {
+				t.Errorf("DefaultIsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPRetryClassifier_Permanent4xx(t *testing.T) {
+	err := &httpclient.APIError{StatusCode: 404}
+	if got := HTTPRetryClassifier(err); got.Kind != DecisionStop {
+		t.Errorf("Kind = %v, want DecisionStop", got.Kind)
+	}
+}
+
+func TestHTTPRetryClassifier_RetryableStatuses(t *testing.T) {
+	for _, status := range []int{408, 425, 500, 502, 503} {
+		err := &httpclient.APIError{StatusCode: status}
+		got := HTTPRetryClassifier(err)
+		if got.Kind != DecisionRetry {
+			t.Errorf("status %d: Kind = %v, want DecisionRetry", status, got.Kind)
+		}
+	}
+}
+
+func TestHTTPRetryClassifier_RetryAfterSeconds(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "5")
+	err := &httpclient.APIError{Header: header, StatusCode: 429}
+
+	got := HTTPRetryClassifier(err)
+	if got.Kind != DecisionRetryAfter {
+		t.Fatalf("Kind = %v, want DecisionRetryAfter", got.Kind)
+	}
+	if got.After != 5*time.Second {
+		t.Errorf("After = %v, want 5s", got.After)
+	}
+}
+
+func TestHTTPRetryClassifier_RetryAfterHTTPDate(t *testing.T) {
+	target := time.Now().Add(10 * time.Second).UTC()
+	header := http.Header{}
+	header.Set("Retry-After", target.Format(http.TimeFormat))
+	err := &httpclient.APIError{Header: header, StatusCode: 503}
+
+	got := HTTPRetryClassifier(err)
+	if got.Kind != DecisionRetryAfter {
+		t.Fatalf("Kind = %v, want DecisionRetryAfter", got.Kind)
+	}
+	if got.After < 9*time.Second || got.After > 11*time.Second {
+		t.Errorf("After = %v, want ~10s", got.After)
+	}
+}
+
+func TestHTTPRetryClassifier_MalformedRetryAfterFallsBackToRetry(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "not-a-valid-value")
+	err := &httpclient.APIError{Header: header, StatusCode: 429}
+
+	got := HTTPRetryClassifier(err)
+	if got.Kind != DecisionRetry {
+		t.Errorf("Kind = %v, want DecisionRetry (fallback)", got.Kind)
+	}
+}
+
+func TestHTTPRetryClassifier_NonAPIErrorRetries(t *testing.T) {
+	got := HTTPRetryClassifier(errors.New("network blip"))
+	if got.Kind != DecisionRetry {
+		t.Errorf("Kind = %v, want DecisionRetry", got.Kind)
+	}
+}
+
+func TestWithRetry_ClassifyHonorsRetryAfterDelay(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "0")
+	apiErr := &httpclient.APIError{Header: header, StatusCode: 429}
+
+	cfg := RetryConfig{
+		Classify:       HTTPRetryClassifier,
+		InitialBackoff: 10 * time.Second, // would dominate if Classify were ignored
+		MaxAttempts:    2,
+		MaxBackoff:     20 * time.Second,
+	}
+
+	start := time.Now()
+	var calls int
+	_, err := WithRetry(context.Background(), cfg, nil, func(ctx context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", apiErr
+		}
+		return "ok", nil
+	})
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("elapsed = %v, want ~0s honoring Retry-After instead of the 10s backoff", elapsed)
+	}
+}
+
+func TestWithRetry_ClassifyStopsOnPermanentError(t *testing.T) {
+	apiErr := &httpclient.APIError{StatusCode: 400}
+
+	cfg := RetryConfig{
+		Classify:       HTTPRetryClassifier,
+		InitialBackoff: time.Millisecond,
+		MaxAttempts:    5,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+
+	var calls int
+	_, err := WithRetry(context.Background(), cfg, nil, func(ctx context.Context) (string, error) {
+		calls++
+		return "", apiErr
+	})
+
+	if !errors.Is(err, apiErr) && err != apiErr {
+		t.Errorf("error = %v, want apiErr", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1 (Classify should Stop immediately)", calls)
+	}
+}
+
+func TestFetchWithRateLimit_ClassifyPausesLimiter(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "1")
+	apiErr := &httpclient.APIError{Header: header, StatusCode: 429}
+
+	limiter := rate.NewLimiter(rate.Inf, 1)
+	cfg := RetryConfig{
+		Classify:       HTTPRetryClassifier,
+		InitialBackoff: time.Millisecond,
+		MaxAttempts:    2,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+
+	var calls int
+	_, err := FetchWithRateLimit(context.Background(), limiter, cfg, nil, func(ctx context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", apiErr
+		}
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if limiter.Limit() != 0 {
+		t.Errorf("limiter.Limit() = %v, want 0 immediately after pausing", limiter.Limit())
+	}
+}
@@ -11,7 +11,11 @@ import (
 )
 
 // RetryConfig controls exponential backoff behavior.
+// Classify, when set, takes precedence over the isRetryable argument passed to
+// WithRetry: it can additionally request an exact RetryAfter delay instead of the
+// computed exponential backoff (see RetryDecision).
 type RetryConfig struct {
+	Classify       func(error) RetryDecision
 	InitialBackoff time.Duration
 	MaxAttempts    int
 	MaxBackoff     time.Duration
@@ -19,7 +23,9 @@ type RetryConfig struct {
 
 // WithRetry executes fn with exponential backoff + full jitter.
 // isRetryable decides whether an error warrants retry; nil treats all errors as retryable.
-// MaxAttempts is total invocations (1 = no retry).
+// If cfg.Classify is set, it governs both the retry/stop decision and the delay
+// (honoring a server-supplied Retry-After via RetryDecision.After), and isRetryable
+// is ignored. MaxAttempts is total invocations (1 = no retry).
 func WithRetry[T any](
 	ctx context.Context,
 	cfg RetryConfig,
@@ -34,6 +40,7 @@ func WithRetry[T any](
 
 	var lastErr error
 
+attempts:
 	for attempt := range cfg.MaxAttempts {
 		result, err := fn(ctx)
 		if err == nil {
@@ -51,11 +58,19 @@ func WithRetry[T any](
 			return zero, lastErr
 		}
 
-		if isRetryable != nil && !isRetryable(err) {
+		backoff := backoffWithJitter(cfg, attempt)
+
+		if cfg.Classify != nil {
+			switch decision := cfg.Classify(err); decision.Kind {
+			case DecisionStop:
+				break attempts
+			case DecisionRetryAfter:
+				backoff = decision.After
+			}
+		} else if isRetryable != nil && !isRetryable(err) {
 			break
 		}
 
-		backoff := backoffWithJitter(cfg, attempt)
 		timer := time.NewTimer(backoff)
 		select {
 		case <-ctx.Done():
@@ -70,6 +85,9 @@ func WithRetry[T any](
 
 // FetchWithRateLimit combines rate.Limiter.Wait + WithRetry.
 // The limiter is waited on BEFORE each attempt, ensuring retries also respect rate limits.
+// When cfg.Classify yields a RetryAfter decision, the limiter's token bucket is also
+// paused for that duration (via SetLimitAt), so other callers sharing the limiter
+// hold off for the same Retry-After window that WithRetry sleeps through.
 func FetchWithRateLimit[T any](
 	ctx context.Context,
 	limiter *rate.Limiter,
@@ -85,9 +103,30 @@ func FetchWithRateLimit[T any](
 		return fn(ctx)
 	}
 
+	if cfg.Classify != nil {
+		userClassify := cfg.Classify
+		cfg.Classify = func(err error) RetryDecision {
+			decision := userClassify(err)
+			if decision.Kind == DecisionRetryAfter {
+				pauseLimiter(limiter, decision.After)
+			}
+			return decision
+		}
+	}
+
 	return WithRetry(ctx, cfg, isRetryable, rateLimitedFn)
 }
 
+// pauseLimiter drops the limiter's rate to zero for d, then restores its previous
+// limit. Used so a server-supplied Retry-After is honored by the token bucket too.
+func pauseLimiter(limiter *rate.Limiter, d time.Duration) {
+	prevLimit := limiter.Limit()
+	limiter.SetLimitAt(time.Now(), 0)
+	time.AfterFunc(d, func() {
+		limiter.SetLimitAt(time.Now(), prevLimit)
+	})
+}
+
 // backoffWithJitter computes full jitter: rand * min(maxBackoff, initialBackoff * 2^attempt).
 // Why cap at 62: float64 precision degrades beyond 2^63, causing overflow.
 func backoffWithJitter(cfg RetryConfig, attempt int) time.Duration {
@@ -0,0 +1,55 @@
+// Package report turns a collection run's []collector.SourceResult into a
+// domain.CollectionRun and fans it out to one or more Sinks (stdout, a file,
+// Postgres). It sits downstream of collector.ReportResults, which only logs
+// OK/FAIL + elapsed; report.BuildRun keeps the row-level counts
+// collector.SourceStats carries so a sink can render or persist them.
+package report
+
+import (
+	"time"
+
+	"github.com/jusikbot/collector/internal/collector"
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// Format identifies how StdoutSink renders a run, mirroring summary.Format.
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// BuildRun converts one collection run's results into a domain.CollectionRun,
+// using runID to correlate it with whatever sink(s) it's written to (cmd/collect
+// passes started.Format(time.RFC3339)). errSummary is AggregateErrors(results)'s
+// message, or empty when every source succeeded.
+func BuildRun(runID string, started, finished time.Time, results []collector.SourceResult, errSummary string) domain.CollectionRun {
+	sources := make([]domain.CollectionRunSource, len(results))
+	for i, r := range results {
+		errText := ""
+		if r.Error != nil {
+			errText = r.Error.Error()
+		}
+		sources[i] = domain.CollectionRunSource{
+			AnomaliesFlagged: r.Stats.AnomaliesFlagged,
+			Elapsed:          r.Elapsed,
+			Error:            errText,
+			HTTPCallCount:    r.Stats.HTTPCallCount,
+			OK:               r.IsOK(),
+			RetriesAttempted: r.Stats.RetriesAttempted,
+			RowsFetched:      r.Stats.RowsFetched,
+			RowsInserted:     r.Stats.RowsInserted,
+			RowsSkipped:      r.Stats.RowsSkipped,
+			Source:           r.Source,
+		}
+	}
+
+	return domain.CollectionRun{
+		ErrorSummary: errSummary,
+		FinishedAt:   finished,
+		RunID:        runID,
+		Sources:      sources,
+		StartedAt:    started,
+	}
+}
@@ -0,0 +1,55 @@
+package report_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/collector"
+	"github.com/jusikbot/collector/internal/report"
+)
+
+func TestBuildRun(t *testing.T) {
+	started := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	finished := started.Add(time.Minute)
+	results := []collector.SourceResult{
+		{
+			Elapsed: 250 * time.Millisecond,
+			Source:  "tiingo",
+			Stats:   collector.SourceStats{RowsFetched: 100, RowsInserted: 90, RowsSkipped: 10, AnomaliesFlagged: 1},
+		},
+		{
+			Elapsed: 50 * time.Millisecond,
+			Error:   errors.New("token expired"),
+			Source:  "kis",
+		},
+	}
+
+	run := report.BuildRun("2024-03-01T00:00:00Z", started, finished, results, "kis: token expired")
+
+	if run.RunID != "2024-03-01T00:00:00Z" {
+		t.Errorf("RunID = %q, want 2024-03-01T00:00:00Z", run.RunID)
+	}
+	if !run.StartedAt.Equal(started) || !run.FinishedAt.Equal(finished) {
+		t.Errorf("StartedAt/FinishedAt = %v/%v, want %v/%v", run.StartedAt, run.FinishedAt, started, finished)
+	}
+	if run.ErrorSummary != "kis: token expired" {
+		t.Errorf("ErrorSummary = %q, want %q", run.ErrorSummary, "kis: token expired")
+	}
+	if len(run.Sources) != 2 {
+		t.Fatalf("len(Sources) = %d, want 2", len(run.Sources))
+	}
+
+	tiingo := run.Sources[0]
+	if !tiingo.OK || tiingo.RowsFetched != 100 || tiingo.RowsInserted != 90 || tiingo.RowsSkipped != 10 || tiingo.AnomaliesFlagged != 1 {
+		t.Errorf("tiingo source = %+v, want rows fetched=100 inserted=90 skipped=10 anomalies=1 ok=true", tiingo)
+	}
+
+	kis := run.Sources[1]
+	if kis.OK {
+		t.Error("kis.OK = true, want false")
+	}
+	if kis.Error != "token expired" {
+		t.Errorf("kis.Error = %q, want %q", kis.Error, "token expired")
+	}
+}
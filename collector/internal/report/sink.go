@@ -0,0 +1,170 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/store"
+)
+
+// Sink persists or displays a finished CollectionRun. Sinks are meant to be
+// combined (e.g. StdoutSink for the operator watching the job plus
+// PostgresSink for later querying), so Write should have no side effect on
+// run beyond its own output.
+type Sink interface {
+	Write(ctx context.Context, run domain.CollectionRun) error
+}
+
+// sourceJSON and totalsJSON are the wire shapes StdoutSink (FormatJSON) and
+// FileSink emit; field names are snake_case to match this repo's other JSON
+// outputs (e.g. kis's persisted token file).
+type sourceJSON struct {
+	AnomaliesFlagged int64  `json:"anomalies_flagged"`
+	ElapsedMS        int64  `json:"elapsed_ms"`
+	Error            string `json:"error,omitempty"`
+	HTTPCallCount    int64  `json:"http_call_count"`
+	OK               bool   `json:"ok"`
+	RetriesAttempted int64  `json:"retries_attempted"`
+	RowsFetched      int64  `json:"rows_fetched"`
+	RowsInserted     int64  `json:"rows_inserted"`
+	RowsSkipped      int64  `json:"rows_skipped"`
+	Source           string `json:"source"`
+}
+
+type totalsJSON struct {
+	FinishedAt   string `json:"finished_at"`
+	RowsFetched  int64  `json:"rows_fetched"`
+	RowsInserted int64  `json:"rows_inserted"`
+	RunID        string `json:"run_id"`
+	SourceCount  int    `json:"source_count"`
+	StartedAt    string `json:"started_at"`
+	SuccessCount int    `json:"success_count"`
+}
+
+func toSourceJSON(s domain.CollectionRunSource) sourceJSON {
+	return sourceJSON{
+		AnomaliesFlagged: s.AnomaliesFlagged,
+		ElapsedMS:        s.Elapsed.Milliseconds(),
+		Error:            s.Error,
+		HTTPCallCount:    s.HTTPCallCount,
+		OK:               s.OK,
+		RetriesAttempted: s.RetriesAttempted,
+		RowsFetched:      s.RowsFetched,
+		RowsInserted:     s.RowsInserted,
+		RowsSkipped:      s.RowsSkipped,
+		Source:           s.Source,
+	}
+}
+
+func toTotalsJSON(run domain.CollectionRun) totalsJSON {
+	totals := totalsJSON{
+		FinishedAt:  run.FinishedAt.Format("2006-01-02T15:04:05Z07:00"),
+		RunID:       run.RunID,
+		SourceCount: len(run.Sources),
+		StartedAt:   run.StartedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	for _, s := range run.Sources {
+		if s.OK {
+			totals.SuccessCount++
+		}
+		totals.RowsFetched += s.RowsFetched
+		totals.RowsInserted += s.RowsInserted
+	}
+	return totals
+}
+
+// StdoutSink writes run to Out (normally os.Stdout) in the format an operator
+// (Format) asked for. FormatText reuses the OK/FAIL/elapsed line
+// collector.ReportResults already logs and appends each source's row counts,
+// addressing the complaint that formatSourceSummary otherwise loses everything
+// but OK/FAIL and elapsed. FormatJSON writes one JSON object per source
+// followed by a totals object, newline-delimited so a monitoring pipeline can
+// consume it one object at a time instead of parsing a full document up front.
+type StdoutSink struct {
+	Format Format
+	Out    io.Writer
+}
+
+func (s StdoutSink) Write(_ context.Context, run domain.CollectionRun) error {
+	switch s.Format {
+	case FormatJSON:
+		return s.writeJSON(run)
+	default:
+		return s.writeText(run)
+	}
+}
+
+func (s StdoutSink) writeText(run domain.CollectionRun) error {
+	for _, src := range run.Sources {
+		status := "OK"
+		if !src.OK {
+			status = "FAIL"
+		}
+		fmt.Fprintf(s.Out, "%s: %s | %s | fetched=%d inserted=%d skipped=%d anomalies=%d\n",
+			src.Source, status, src.Elapsed.Round(time.Millisecond), src.RowsFetched, src.RowsInserted, src.RowsSkipped, src.AnomaliesFlagged)
+	}
+	totals := toTotalsJSON(run)
+	fmt.Fprintf(s.Out, "collection complete: %d/%d OK | rows fetched=%d inserted=%d\n",
+		totals.SuccessCount, totals.SourceCount, totals.RowsFetched, totals.RowsInserted)
+	return nil
+}
+
+func (s StdoutSink) writeJSON(run domain.CollectionRun) error {
+	enc := json.NewEncoder(s.Out)
+	for _, src := range run.Sources {
+		if err := enc.Encode(toSourceJSON(src)); err != nil {
+			return fmt.Errorf("encode source %s json: %w", src.Source, err)
+		}
+	}
+	if err := enc.Encode(toTotalsJSON(run)); err != nil {
+		return fmt.Errorf("encode totals json: %w", err)
+	}
+	return nil
+}
+
+// FileSink writes run to Path as a single indented JSON document (sources
+// plus a totals object), unlike StdoutSink's newline-delimited objects: a
+// file is read whole by whatever consumes it later, not streamed.
+type FileSink struct {
+	Path string
+}
+
+func (s FileSink) Write(_ context.Context, run domain.CollectionRun) error {
+	f, err := os.Create(s.Path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", s.Path, err)
+	}
+	defer f.Close()
+
+	sources := make([]sourceJSON, len(run.Sources))
+	for i, src := range run.Sources {
+		sources[i] = toSourceJSON(src)
+	}
+	doc := struct {
+		Sources []sourceJSON `json:"sources"`
+		Totals  totalsJSON   `json:"totals"`
+	}{Sources: sources, Totals: toTotalsJSON(run)}
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode collection run json: %w", err)
+	}
+	return nil
+}
+
+// PostgresSink persists run via Repo.InsertCollectionRun, so an operator can
+// query historical collection health (e.g. "how many rows has tiingo
+// inserted per day this month") instead of grepping logs.
+type PostgresSink struct {
+	Repo *store.Repository
+}
+
+func (s PostgresSink) Write(ctx context.Context, run domain.CollectionRun) error {
+	return s.Repo.InsertCollectionRun(ctx, run)
+}
@@ -0,0 +1,123 @@
+package report_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/report"
+)
+
+func testRun() domain.CollectionRun {
+	return domain.CollectionRun{
+		FinishedAt: time.Date(2024, 3, 1, 0, 1, 0, 0, time.UTC),
+		RunID:      "2024-03-01T00:00:00Z",
+		StartedAt:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Sources: []domain.CollectionRunSource{
+			{
+				AnomaliesFlagged: 1,
+				Elapsed:          250 * time.Millisecond,
+				OK:               true,
+				RowsFetched:      100,
+				RowsInserted:     90,
+				RowsSkipped:      10,
+				Source:           "tiingo",
+			},
+			{
+				Elapsed: 50 * time.Millisecond,
+				Error:   "token expired",
+				OK:      false,
+				Source:  "kis",
+			},
+		},
+	}
+}
+
+func TestStdoutSink_Text(t *testing.T) {
+	var buf bytes.Buffer
+	sink := report.StdoutSink{Format: report.FormatText, Out: &buf}
+
+	if err := sink.Write(context.Background(), testRun()); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "tiingo: OK") || !strings.Contains(out, "fetched=100 inserted=90 skipped=10 anomalies=1") {
+		t.Errorf("missing tiingo line, got:\n%s", out)
+	}
+	if !strings.Contains(out, "kis: FAIL") {
+		t.Errorf("missing kis FAIL line, got:\n%s", out)
+	}
+	if !strings.Contains(out, "collection complete: 1/2 OK | rows fetched=100 inserted=90") {
+		t.Errorf("missing totals line, got:\n%s", out)
+	}
+}
+
+func TestStdoutSink_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	sink := report.StdoutSink{Format: report.FormatJSON, Out: &buf}
+
+	if err := sink.Write(context.Background(), testRun()); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 3 {
+		t.Fatalf("len(lines) = %d, want 3 (2 sources + 1 totals)", len(lines))
+	}
+
+	var tiingo map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &tiingo); err != nil {
+		t.Fatalf("unmarshal tiingo line: %v", err)
+	}
+	if tiingo["source"] != "tiingo" || tiingo["rows_fetched"] != float64(100) {
+		t.Errorf("tiingo line = %v, want source=tiingo rows_fetched=100", tiingo)
+	}
+
+	var totals map[string]any
+	if err := json.Unmarshal([]byte(lines[2]), &totals); err != nil {
+		t.Fatalf("unmarshal totals line: %v", err)
+	}
+	if totals["success_count"] != float64(1) || totals["source_count"] != float64(2) {
+		t.Errorf("totals line = %v, want success_count=1 source_count=2", totals)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.json")
+	sink := report.FileSink{Path: path}
+
+	if err := sink.Write(context.Background(), testRun()); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+
+	var doc struct {
+		Sources []map[string]any `json:"sources"`
+		Totals  map[string]any   `json:"totals"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(doc.Sources) != 2 {
+		t.Fatalf("len(Sources) = %d, want 2", len(doc.Sources))
+	}
+	if doc.Totals["success_count"] != float64(1) {
+		t.Errorf("Totals.success_count = %v, want 1", doc.Totals["success_count"])
+	}
+}
@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// computeDrawdown walks clean's AdjClose series tracking the running peak and
+// returns both the single largest peak-to-trough decline (as a DrawdownPeriod,
+// ValuePct negative e.g. -23.5) and the full day-by-day drawdown-pct series
+// (also negative-or-zero), which ulcerIndex squares and averages.
+func computeDrawdown(clean []domain.DailyPrice) (DrawdownPeriod, []float64) {
+	series := make([]float64, len(clean))
+	if len(clean) == 0 {
+		return DrawdownPeriod{}, series
+	}
+
+	var worst DrawdownPeriod
+	peak := clean[0].AdjClose
+	peakDate := clean[0].Date
+
+	for i, p := range clean {
+		if p.AdjClose > peak {
+			peak = p.AdjClose
+			peakDate = p.Date
+		}
+		if peak == 0 {
+			continue
+		}
+
+		ddPct := (p.AdjClose - peak) / peak * 100
+		series[i] = ddPct
+
+		if ddPct < worst.ValuePct {
+			worst = DrawdownPeriod{
+				PeakDate:   peakDate,
+				TroughDate: p.Date,
+				ValueAbs:   p.AdjClose - peak,
+				ValuePct:   ddPct,
+			}
+		}
+	}
+
+	return worst, series
+}
+
+// ulcerIndex returns sqrt(mean(drawdown_pct^2)) over series, Peter Martin's
+// measure of drawdown depth and duration combined (unlike MaxDrawdown, which
+// only captures depth).
+func ulcerIndex(series []float64) float64 {
+	if len(series) == 0 {
+		return 0
+	}
+	var sumSq float64
+	for _, dd := range series {
+		sumSq += dd * dd
+	}
+	return math.Sqrt(sumSq / float64(len(series)))
+}
@@ -0,0 +1,43 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestComputeDrawdown_Empty(t *testing.T) {
+	worst, series := computeDrawdown(nil)
+	if worst.ValuePct != 0 || series != nil && len(series) != 0 {
+		t.Errorf("computeDrawdown(nil) = %+v, %v, want zero value", worst, series)
+	}
+}
+
+func TestComputeDrawdown_NoDecline(t *testing.T) {
+	start := dailyPrices([]float64{100, 110, 120}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	worst, series := computeDrawdown(start)
+
+	if worst.ValuePct != 0 {
+		t.Errorf("ValuePct = %v, want 0", worst.ValuePct)
+	}
+	for i, dd := range series {
+		if dd != 0 {
+			t.Errorf("series[%d] = %v, want 0", i, dd)
+		}
+	}
+}
+
+func TestUlcerIndex(t *testing.T) {
+	series := []float64{0, -10, -20, 0}
+	want := math.Sqrt((0 + 100 + 400 + 0) / 4.0)
+	got := ulcerIndex(series)
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("ulcerIndex(%v) = %v, want %v", series, got, want)
+	}
+}
+
+func TestUlcerIndex_Empty(t *testing.T) {
+	if got := ulcerIndex(nil); got != 0 {
+		t.Errorf("ulcerIndex(nil) = %v, want 0", got)
+	}
+}
@@ -0,0 +1,44 @@
+package stats
+
+import (
+	"fmt"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// ConvertToUSD rescales prices (assumed quoted in the currency that's the
+// quote side of pair, e.g. KRW for "USD/KRW") into USD using fxRates,
+// matched by date. domain.FXRate.Rate is quote-per-base (see internal/fx),
+// so converting to USD divides by Rate. A date missing from fxRates is
+// dropped rather than left unconverted, since an unconverted KRW value mixed
+// into a USD series would silently corrupt any report computed over it.
+func ConvertToUSD(prices []domain.DailyPrice, fxRates []domain.FXRate, pair string) ([]domain.DailyPrice, error) {
+	rateByDate := make(map[string]float64, len(fxRates))
+	for _, r := range fxRates {
+		if r.Pair != pair {
+			continue
+		}
+		rateByDate[r.Date.Format("2006-01-02")] = r.Rate
+	}
+	if len(rateByDate) == 0 {
+		return nil, fmt.Errorf("no fx rates found for pair %q", pair)
+	}
+
+	converted := make([]domain.DailyPrice, 0, len(prices))
+	for _, p := range prices {
+		rate, ok := rateByDate[p.Date.Format("2006-01-02")]
+		if !ok || rate == 0 {
+			continue
+		}
+
+		usd := p
+		usd.AdjClose = p.AdjClose / rate
+		usd.Close = p.Close / rate
+		usd.High = p.High / rate
+		usd.Low = p.Low / rate
+		usd.Open = p.Open / rate
+		converted = append(converted, usd)
+	}
+
+	return converted, nil
+}
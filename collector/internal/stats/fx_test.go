@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func TestConvertToUSD_NoMatchingRates(t *testing.T) {
+	prices := dailyPrices([]float64{100}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	_, err := ConvertToUSD(prices, nil, "USD/KRW")
+	if err == nil {
+		t.Fatal("expected an error when no fx rates match pair")
+	}
+}
+
+func TestConvertToUSD_DividesByRate(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	prices := []domain.DailyPrice{{
+		AdjClose: 130000,
+		Close:    130000,
+		High:     131000,
+		Low:      129000,
+		Open:     130500,
+		Date:     date,
+		Symbol:   "005930",
+	}}
+	rates := []domain.FXRate{{Date: date, Pair: "USD/KRW", Rate: 1300}}
+
+	converted, err := ConvertToUSD(prices, rates, "USD/KRW")
+	if err != nil {
+		t.Fatalf("ConvertToUSD() error = %v", err)
+	}
+	if len(converted) != 1 {
+		t.Fatalf("len(converted) = %d, want 1", len(converted))
+	}
+	if math.Abs(converted[0].AdjClose-100) > 1e-9 {
+		t.Errorf("AdjClose = %v, want 100", converted[0].AdjClose)
+	}
+}
+
+func TestConvertToUSD_DropsUnmatchedDates(t *testing.T) {
+	matched := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	unmatched := matched.AddDate(0, 0, 1)
+	prices := []domain.DailyPrice{
+		{AdjClose: 1300, Date: matched},
+		{AdjClose: 1300, Date: unmatched},
+	}
+	rates := []domain.FXRate{{Date: matched, Pair: "USD/KRW", Rate: 1300}}
+
+	converted, err := ConvertToUSD(prices, rates, "USD/KRW")
+	if err != nil {
+		t.Fatalf("ConvertToUSD() error = %v", err)
+	}
+	if len(converted) != 1 {
+		t.Fatalf("len(converted) = %d, want 1 (unmatched date dropped)", len(converted))
+	}
+}
+
+func TestConvertToUSD_IgnoresOtherPairs(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	prices := dailyPrices([]float64{100}, date)
+	rates := []domain.FXRate{{Date: date, Pair: "EUR/KRW", Rate: 1450}}
+
+	_, err := ConvertToUSD(prices, rates, "USD/KRW")
+	if err == nil {
+		t.Fatal("expected an error since no rate matches the requested pair")
+	}
+}
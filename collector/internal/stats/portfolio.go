@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// MergePortfolio combines each symbol's price series in bySymbol into a
+// single synthetic series suitable for ComputeReport, weighting each
+// symbol's daily simple return (AdjClose/prev - 1) by weights[symbol] (weights need not sum to 1;
+// they're used as-is). Only dates present in every symbol's series are
+// included, since a weighted return is undefined for a symbol missing that
+// day. The synthetic series starts at 100 and compounds the weighted daily
+// return forward; its AdjClose and Close are identical (there's no
+// underlying OHLC for a portfolio).
+func MergePortfolio(bySymbol map[string][]domain.DailyPrice, weights map[string]float64) []domain.DailyPrice {
+	if len(bySymbol) == 0 {
+		return nil
+	}
+
+	returnsBySymbol := make(map[string]map[string]float64, len(bySymbol))
+	var dates []string
+	seen := make(map[string]int)
+
+	for symbol, prices := range bySymbol {
+		clean := cleanSeries(prices)
+		sort.Slice(clean, func(i, j int) bool { return clean[i].Date.Before(clean[j].Date) })
+
+		byDate := make(map[string]float64, len(clean))
+		for i := 1; i < len(clean); i++ {
+			prev := clean[i-1].AdjClose
+			if prev <= 0 || clean[i].AdjClose <= 0 {
+				continue
+			}
+			dateKey := clean[i].Date.Format("2006-01-02")
+			byDate[dateKey] = clean[i].AdjClose/prev - 1
+			if seen[dateKey] == 0 {
+				dates = append(dates, dateKey)
+			}
+			seen[dateKey]++
+		}
+		returnsBySymbol[symbol] = byDate
+	}
+
+	sort.Strings(dates)
+
+	merged := make([]domain.DailyPrice, 0, len(dates))
+	value := 100.0
+
+	for _, dateKey := range dates {
+		if seen[dateKey] != len(bySymbol) {
+			continue
+		}
+
+		var weightedReturn float64
+		for symbol, byDate := range returnsBySymbol {
+			weightedReturn += weights[symbol] * byDate[dateKey]
+		}
+
+		value *= 1 + weightedReturn
+		date, _ := time.Parse("2006-01-02", dateKey)
+		merged = append(merged, domain.DailyPrice{
+			AdjClose: value,
+			Close:    value,
+			Date:     date,
+			Source:   "portfolio",
+		})
+	}
+
+	return merged
+}
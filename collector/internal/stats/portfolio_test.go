@@ -0,0 +1,52 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func TestMergePortfolio_Empty(t *testing.T) {
+	if got := MergePortfolio(nil, nil); got != nil {
+		t.Errorf("MergePortfolio(nil, nil) = %v, want nil", got)
+	}
+}
+
+func TestMergePortfolio_WeightedReturn(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bySymbol := map[string][]domain.DailyPrice{
+		"A": dailyPrices([]float64{100, 110}, start),
+		"B": dailyPrices([]float64{100, 90}, start),
+	}
+	weights := map[string]float64{"A": 0.5, "B": 0.5}
+
+	merged := MergePortfolio(bySymbol, weights)
+	if len(merged) != 1 {
+		t.Fatalf("len(merged) = %d, want 1", len(merged))
+	}
+
+	wantReturn := 0.5*0.1 + 0.5*(-0.1)
+	want := 100 * (1 + wantReturn)
+	if math.Abs(merged[0].AdjClose-want) > 1e-9 {
+		t.Errorf("merged[0].AdjClose = %v, want %v", merged[0].AdjClose, want)
+	}
+	if merged[0].Source != "portfolio" {
+		t.Errorf("merged[0].Source = %q, want %q", merged[0].Source, "portfolio")
+	}
+}
+
+func TestMergePortfolio_OnlySharedDatesIncluded(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bySymbol := map[string][]domain.DailyPrice{
+		"A": dailyPrices([]float64{100, 110, 121}, start),
+		"B": dailyPrices([]float64{100, 90}, start),
+	}
+	weights := map[string]float64{"A": 1, "B": 0}
+
+	merged := MergePortfolio(bySymbol, weights)
+	if len(merged) != 1 {
+		t.Fatalf("len(merged) = %d, want 1 (only the date both symbols share)", len(merged))
+	}
+}
@@ -0,0 +1,26 @@
+package stats
+
+import "math"
+
+// rollingSharpe computes one annualized Sharpe ratio per trailing window of
+// size window ending on each day of returns, once enough history exists.
+// Returns nil if returns is shorter than window.
+func rollingSharpe(returns []float64, window int, rfDaily float64) []float64 {
+	if len(returns) < window {
+		return nil
+	}
+
+	result := make([]float64, 0, len(returns)-window+1)
+	for end := window; end <= len(returns); end++ {
+		trailing := returns[end-window : end]
+		mean := meanOf(trailing)
+		stdev := stdevOf(trailing, mean)
+
+		var sharpe float64
+		if stdev >= zeroVarianceEpsilon {
+			sharpe = (mean - rfDaily) / stdev * math.Sqrt(tradingDaysPerYear)
+		}
+		result = append(result, sharpe)
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package stats
+
+import "testing"
+
+func TestRollingSharpe_ShorterThanWindow(t *testing.T) {
+	if got := rollingSharpe([]float64{0.01, 0.02}, 5, 0); got != nil {
+		t.Errorf("rollingSharpe = %v, want nil", got)
+	}
+}
+
+func TestRollingSharpe_WindowLength(t *testing.T) {
+	returns := make([]float64, 40)
+	for i := range returns {
+		returns[i] = 0.001 + 0.0001*float64(i%2)
+	}
+
+	got := rollingSharpe(returns, 30, 0)
+	want := len(returns) - 30 + 1
+	if len(got) != want {
+		t.Fatalf("len(rollingSharpe) = %d, want %d", len(got), want)
+	}
+	for i, v := range got {
+		if v <= 0 {
+			t.Errorf("rollingSharpe[%d] = %v, want > 0 for varying positive returns", i, v)
+		}
+	}
+}
+
+func TestRollingSharpe_ZeroStdevWindowIsZero(t *testing.T) {
+	returns := make([]float64, 30)
+	for i := range returns {
+		returns[i] = 0.001
+	}
+
+	got := rollingSharpe(returns, 30, 0)
+	if len(got) != 1 {
+		t.Fatalf("len(rollingSharpe) = %d, want 1", len(got))
+	}
+	if got[0] != 0 {
+		t.Errorf("rollingSharpe[0] = %v, want 0 for zero-variance window", got[0])
+	}
+}
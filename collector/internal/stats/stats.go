@@ -0,0 +1,201 @@
+// Package stats computes single-pass performance statistics over a
+// domain.DailyPrice series, for backtest and portfolio reporting. It overlaps
+// in subject matter with internal/summary's per-symbol dashboard indicators
+// (CAGR, Sharpe, Sortino, MaxDrawdown), but answers a different question — a
+// standalone report over an arbitrary price series (one symbol, a merged
+// portfolio, or an FX-converted series) rather than a dashboard row computed
+// against fixed lookback windows — so it doesn't share summary's unexported
+// helpers; see parseRetryAfter in internal/httpclient's error.go for the same
+// tradeoff made for the same reason (avoiding a helper-only import cycle).
+package stats
+
+import (
+	"math"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+const tradingDaysPerYear = 252
+
+// zeroVarianceEpsilon is the threshold below which stdev/downsideDev is
+// treated as zero. A genuinely constant return series still accumulates
+// floating-point rounding error through stdevOf's sum-of-squares, so an
+// exact != 0 check lets that noise through and blows up the division.
+const zeroVarianceEpsilon = 1e-9
+
+// DrawdownPeriod identifies the largest peak-to-trough decline in AdjClose
+// over a series, with both the absolute and percentage decline.
+type DrawdownPeriod struct {
+	PeakDate   time.Time
+	TroughDate time.Time
+	ValueAbs   float64
+	ValuePct   float64
+}
+
+// PerformanceReport is the full statistic set ComputeReport produces.
+// Percentage-valued fields (CAGR, AnnualizedVolatility, MaxDrawdown.ValuePct,
+// UlcerIndex, AverageWin, AverageLoss) are expressed as e.g. 23.5, not 0.235;
+// ratio fields (Sharpe, Sortino, Calmar, ProfitFactor) are dimensionless.
+type PerformanceReport struct {
+	CAGR                 float64
+	AnnualizedVolatility float64
+	Sharpe               float64
+	Sortino              float64
+	Calmar               float64
+	MaxDrawdown          DrawdownPeriod
+	UlcerIndex           float64
+	WinDays              int
+	LossDays             int
+	ProfitFactor         float64
+	AverageWin           float64
+	AverageLoss          float64
+
+	// RollingSharpe30D/90D/252D hold one annualized Sharpe ratio per window
+	// ending on each day once enough trailing history exists; shorter than
+	// the window, the slice is nil.
+	RollingSharpe30D  []float64
+	RollingSharpe90D  []float64
+	RollingSharpe252D []float64
+}
+
+// ComputeReport computes a PerformanceReport from prices. Anomaly rows
+// (IsAnomaly) are excluded before any statistic is computed. riskFreeAnnual
+// is the annual risk-free rate (e.g. 0.04 for 4%), converted to a daily rate
+// internally for Sharpe/Sortino.
+func ComputeReport(prices []domain.DailyPrice, riskFreeAnnual float64) PerformanceReport {
+	clean := cleanSeries(prices)
+	if len(clean) < 2 {
+		return PerformanceReport{}
+	}
+
+	returns := logReturns(clean)
+	rfDaily := riskFreeAnnual / tradingDaysPerYear
+
+	mean := meanOf(returns)
+	stdev := stdevOf(returns, mean)
+	downsideDev := downsideDeviation(returns)
+
+	drawdown, ddSeries := computeDrawdown(clean)
+	cagr := cagrOf(clean)
+
+	report := PerformanceReport{
+		CAGR:                 cagr,
+		AnnualizedVolatility: stdev * math.Sqrt(tradingDaysPerYear) * 100,
+		MaxDrawdown:          drawdown,
+		UlcerIndex:           ulcerIndex(ddSeries),
+		RollingSharpe30D:     rollingSharpe(returns, 30, rfDaily),
+		RollingSharpe90D:     rollingSharpe(returns, 90, rfDaily),
+		RollingSharpe252D:    rollingSharpe(returns, 252, rfDaily),
+	}
+
+	if stdev >= zeroVarianceEpsilon {
+		report.Sharpe = (mean - rfDaily) / stdev * math.Sqrt(tradingDaysPerYear)
+	}
+	if downsideDev >= zeroVarianceEpsilon {
+		report.Sortino = (mean - rfDaily) / downsideDev * math.Sqrt(tradingDaysPerYear)
+	}
+	if math.Abs(drawdown.ValuePct) >= zeroVarianceEpsilon {
+		report.Calmar = cagr / math.Abs(drawdown.ValuePct)
+	}
+
+	var gains, losses float64
+	for _, r := range returns {
+		switch {
+		case r > 0:
+			report.WinDays++
+			gains += r
+		case r < 0:
+			report.LossDays++
+			losses += -r
+		}
+	}
+	if losses != 0 {
+		report.ProfitFactor = gains / losses
+	}
+	if report.WinDays > 0 {
+		report.AverageWin = gains / float64(report.WinDays) * 100
+	}
+	if report.LossDays > 0 {
+		report.AverageLoss = -losses / float64(report.LossDays) * 100
+	}
+
+	return report
+}
+
+// cagrOf returns the compound annual growth rate (percent) of clean's
+// AdjClose series, assuming tradingDaysPerYear trading days per year.
+// Returns 0 if the first value isn't strictly positive.
+func cagrOf(clean []domain.DailyPrice) float64 {
+	first, last := clean[0].AdjClose, clean[len(clean)-1].AdjClose
+	if first <= 0 {
+		return 0
+	}
+	years := float64(len(clean)-1) / tradingDaysPerYear
+	if years <= 0 {
+		return 0
+	}
+	return (math.Pow(last/first, 1/years) - 1) * 100
+}
+
+// logReturns computes daily log returns of clean's AdjClose series.
+func logReturns(clean []domain.DailyPrice) []float64 {
+	returns := make([]float64, 0, len(clean)-1)
+	for i := 1; i < len(clean); i++ {
+		prev := clean[i-1].AdjClose
+		if prev <= 0 || clean[i].AdjClose <= 0 {
+			continue
+		}
+		returns = append(returns, math.Log(clean[i].AdjClose/prev))
+	}
+	return returns
+}
+
+// downsideDeviation returns the RMS of returns' negative values against a
+// target of 0, as Sortino uses in place of Sharpe's full stdev.
+func downsideDeviation(returns []float64) float64 {
+	if len(returns) == 0 {
+		return 0
+	}
+	var sumSq float64
+	for _, r := range returns {
+		if r < 0 {
+			sumSq += r * r
+		}
+	}
+	return math.Sqrt(sumSq / float64(len(returns)))
+}
+
+func cleanSeries(prices []domain.DailyPrice) []domain.DailyPrice {
+	clean := make([]domain.DailyPrice, 0, len(prices))
+	for _, p := range prices {
+		if !p.IsAnomaly {
+			clean = append(clean, p)
+		}
+	}
+	return clean
+}
+
+func meanOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// stdevOf returns the sample standard deviation (N-1 denominator).
+func stdevOf(values []float64, mean float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	var sumSqDev float64
+	for _, v := range values {
+		dev := v - mean
+		sumSqDev += dev * dev
+	}
+	return math.Sqrt(sumSqDev / float64(len(values)-1))
+}
@@ -0,0 +1,95 @@
+package stats
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func dailyPrices(adjCloses []float64, start time.Time) []domain.DailyPrice {
+	prices := make([]domain.DailyPrice, len(adjCloses))
+	for i, c := range adjCloses {
+		prices[i] = domain.DailyPrice{
+			AdjClose: c,
+			Close:    c,
+			Date:     start.AddDate(0, 0, i),
+		}
+	}
+	return prices
+}
+
+func TestComputeReport_InsufficientData(t *testing.T) {
+	report := ComputeReport(dailyPrices([]float64{100}, time.Now()), 0)
+	if !reflect.DeepEqual(report, PerformanceReport{}) {
+		t.Errorf("report = %+v, want zero value", report)
+	}
+}
+
+func TestComputeReport_SteadyGrowth(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	closes := make([]float64, 253)
+	closes[0] = 100
+	for i := 1; i < len(closes); i++ {
+		rate := 1.001
+		if i%2 == 0 {
+			rate = 1.002
+		}
+		closes[i] = closes[i-1] * rate
+	}
+
+	report := ComputeReport(dailyPrices(closes, start), 0)
+
+	if report.CAGR <= 0 {
+		t.Errorf("CAGR = %v, want > 0 for steady growth", report.CAGR)
+	}
+	if report.Sharpe <= 0 {
+		t.Errorf("Sharpe = %v, want > 0 for steady growth with no down days", report.Sharpe)
+	}
+	if report.MaxDrawdown.ValuePct != 0 {
+		t.Errorf("MaxDrawdown.ValuePct = %v, want 0 for monotonic growth", report.MaxDrawdown.ValuePct)
+	}
+	if report.LossDays != 0 {
+		t.Errorf("LossDays = %d, want 0 for monotonic growth", report.LossDays)
+	}
+	if report.WinDays != len(closes)-1 {
+		t.Errorf("WinDays = %d, want %d", report.WinDays, len(closes)-1)
+	}
+	if len(report.RollingSharpe30D) != len(closes)-1-30+1 {
+		t.Errorf("len(RollingSharpe30D) = %d, want %d", len(report.RollingSharpe30D), len(closes)-1-30+1)
+	}
+}
+
+func TestComputeReport_Drawdown(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	closes := []float64{100, 110, 120, 90, 95, 130}
+	report := ComputeReport(dailyPrices(closes, start), 0)
+
+	wantPct := (90.0 - 120.0) / 120.0 * 100
+	if math.Abs(report.MaxDrawdown.ValuePct-wantPct) > 1e-9 {
+		t.Errorf("MaxDrawdown.ValuePct = %v, want %v", report.MaxDrawdown.ValuePct, wantPct)
+	}
+	if !report.MaxDrawdown.PeakDate.Equal(start.AddDate(0, 0, 2)) {
+		t.Errorf("PeakDate = %v, want day index 2", report.MaxDrawdown.PeakDate)
+	}
+	if !report.MaxDrawdown.TroughDate.Equal(start.AddDate(0, 0, 3)) {
+		t.Errorf("TroughDate = %v, want day index 3", report.MaxDrawdown.TroughDate)
+	}
+	if report.UlcerIndex <= 0 {
+		t.Errorf("UlcerIndex = %v, want > 0 when a drawdown occurred", report.UlcerIndex)
+	}
+}
+
+func TestComputeReport_IgnoresAnomalies(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	prices := dailyPrices([]float64{100, 101, 102, 103}, start)
+	prices[2].IsAnomaly = true
+	prices[2].AdjClose = 9999
+
+	report := ComputeReport(prices, 0)
+	if report.MaxDrawdown.ValuePct != 0 {
+		t.Errorf("MaxDrawdown.ValuePct = %v, want 0 (anomaly row should be excluded)", report.MaxDrawdown.ValuePct)
+	}
+}
@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteJSON marshals report as indented JSON to w.
+func WriteJSON(w io.Writer, report PerformanceReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("encode performance report json: %w", err)
+	}
+	return nil
+}
+
+// csvHeader and csvRow list the report's scalar fields in the same order;
+// RollingSharpe30D/90D/252D are omitted since they're series, not scalars,
+// and don't fit a one-row-per-report layout.
+var csvHeader = []string{
+	"cagr", "annualized_volatility", "sharpe", "sortino", "calmar",
+	"max_drawdown_pct", "max_drawdown_abs", "max_drawdown_peak_date", "max_drawdown_trough_date",
+	"ulcer_index", "win_days", "loss_days", "profit_factor", "average_win", "average_loss",
+}
+
+// WriteCSV writes report as a single CSV header row plus one data row to w.
+func WriteCSV(w io.Writer, report PerformanceReport) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("write performance report csv header: %w", err)
+	}
+
+	row := []string{
+		formatFloat(report.CAGR),
+		formatFloat(report.AnnualizedVolatility),
+		formatFloat(report.Sharpe),
+		formatFloat(report.Sortino),
+		formatFloat(report.Calmar),
+		formatFloat(report.MaxDrawdown.ValuePct),
+		formatFloat(report.MaxDrawdown.ValueAbs),
+		report.MaxDrawdown.PeakDate.Format("2006-01-02"),
+		report.MaxDrawdown.TroughDate.Format("2006-01-02"),
+		formatFloat(report.UlcerIndex),
+		strconv.Itoa(report.WinDays),
+		strconv.Itoa(report.LossDays),
+		formatFloat(report.ProfitFactor),
+		formatFloat(report.AverageWin),
+		formatFloat(report.AverageLoss),
+	}
+	if err := cw.Write(row); err != nil {
+		return fmt.Errorf("write performance report csv row: %w", err)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
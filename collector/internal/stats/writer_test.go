@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteJSON(t *testing.T) {
+	report := PerformanceReport{CAGR: 12.5, Sharpe: 1.1}
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, report); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var decoded PerformanceReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if decoded.CAGR != report.CAGR || decoded.Sharpe != report.Sharpe {
+		t.Errorf("decoded = %+v, want %+v", decoded, report)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	report := PerformanceReport{
+		CAGR:   12.5,
+		Sharpe: 1.1,
+		MaxDrawdown: DrawdownPeriod{
+			PeakDate:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			TroughDate: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+			ValuePct:   -10,
+		},
+		WinDays:  5,
+		LossDays: 2,
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, report); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("csv.ReadAll() error = %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("len(rows) = %d, want 2 (header + data)", len(rows))
+	}
+	if len(rows[0]) != len(csvHeader) || len(rows[1]) != len(csvHeader) {
+		t.Errorf("row lengths = %d, %d, want %d", len(rows[0]), len(rows[1]), len(csvHeader))
+	}
+	if rows[1][5] != "-10" {
+		t.Errorf("max_drawdown_pct = %q, want %q", rows[1][5], "-10")
+	}
+}
@@ -0,0 +1,65 @@
+package store
+
+import "time"
+
+// TradingCalendar decides whether date is a trading day, so
+// DetectMissingRanges / DetectMissingFXRanges can exclude weekends and
+// holidays before coalescing the remaining missing dates into ranges.
+type TradingCalendar interface {
+	IsTradingDay(date time.Time) bool
+}
+
+// DefaultTradingCalendar excludes weekends plus a static list of fixed-date
+// NYSE and KRX holidays. Repository uses it for DetectMissingRanges.
+// Why a union of both exchanges rather than one per market: Repository has
+// no per-symbol market to key off of (callers already filter symbols by
+// market before calling, the same way they do for DetectGaps), so this
+// conservatively treats a date as non-trading if either exchange observes
+// it.
+// Why incomplete: this only covers fixed-date observances (New Year's Day,
+// Christmas); it does not compute moving holidays (US Thanksgiving, KRX's
+// lunar-calendar Lunar New Year and Chuseok). Good enough to avoid the
+// common case of a range request landing on an obviously-closed day; not a
+// certified market calendar.
+type DefaultTradingCalendar struct {
+	holidays map[string]struct{}
+}
+
+// NewDefaultTradingCalendar builds a DefaultTradingCalendar from
+// nyseKrxHolidays.
+func NewDefaultTradingCalendar() *DefaultTradingCalendar {
+	c := &DefaultTradingCalendar{holidays: make(map[string]struct{}, len(nyseKrxHolidays))}
+	for _, d := range nyseKrxHolidays {
+		c.holidays[d] = struct{}{}
+	}
+	return c
+}
+
+func (c *DefaultTradingCalendar) IsTradingDay(date time.Time) bool {
+	if isWeekend(date) {
+		return false
+	}
+	_, isHoliday := c.holidays[date.Format("2006-01-02")]
+	return !isHoliday
+}
+
+// nyseKrxHolidays lists fixed-date NYSE/KRX observances, in "2006-01-02" form.
+var nyseKrxHolidays = []string{
+	"2024-01-01", "2024-12-25",
+	"2025-01-01", "2025-12-25",
+	"2026-01-01", "2026-12-25",
+}
+
+// WeekdaysOnlyCalendar excludes only Saturdays and Sundays. Repository uses
+// it for DetectMissingFXRanges: FX trades through most exchange holidays, so
+// a full NYSE/KRX holiday list would falsely flag tradeable days as missing.
+type WeekdaysOnlyCalendar struct{}
+
+func (WeekdaysOnlyCalendar) IsTradingDay(date time.Time) bool {
+	return !isWeekend(date)
+}
+
+func isWeekend(date time.Time) bool {
+	wd := date.Weekday()
+	return wd == time.Saturday || wd == time.Sunday
+}
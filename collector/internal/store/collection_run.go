@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// InsertCollectionRun persists one collection run and its per-source rows in
+// a single transaction, so a partially-written run (e.g. the process is
+// killed mid-insert) never leaves an orphaned collection_runs row with no
+// matching collection_run_sources. Unlike UpsertPrices/UpsertSummaryRisk,
+// this is a one-row-per-run insert rather than a bulk upsert, so it follows
+// SetSourcePriority's simple per-row tx.Exec loop rather than the temp-table
+// copy pattern those higher-volume paths use.
+func (r *Repository) InsertCollectionRun(ctx context.Context, run domain.CollectionRun) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin insert collection run: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO collection_runs (run_id, started_at, finished_at, source_count, success_count, error_summary)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, run.RunID, run.StartedAt, run.FinishedAt, len(run.Sources), countOKSources(run.Sources), run.ErrorSummary); err != nil {
+		return fmt.Errorf("insert collection run: %w", err)
+	}
+
+	for _, s := range run.Sources {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO collection_run_sources (
+				run_id, source, ok, elapsed_ms, rows_fetched, rows_inserted, rows_skipped,
+				anomalies_flagged, retries_attempted, http_call_count, error
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`, run.RunID, s.Source, s.OK, s.Elapsed.Milliseconds(), s.RowsFetched, s.RowsInserted, s.RowsSkipped,
+			s.AnomaliesFlagged, s.RetriesAttempted, s.HTTPCallCount, s.Error); err != nil {
+			return fmt.Errorf("insert collection run source %s: %w", s.Source, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit insert collection run: %w", err)
+	}
+
+	return nil
+}
+
+func countOKSources(sources []domain.CollectionRunSource) int {
+	n := 0
+	for _, s := range sources {
+		if s.OK {
+			n++
+		}
+	}
+	return n
+}
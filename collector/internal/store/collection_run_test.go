@@ -0,0 +1,95 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/store"
+)
+
+func TestInsertCollectionRun(t *testing.T) {
+	pool := connectAndClean(t)
+	t.Cleanup(pool.Close)
+	ctx := context.Background()
+
+	if err := store.RunMigrations(ctx, pool); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+	repo := store.NewRepository(pool)
+
+	run := domain.CollectionRun{
+		ErrorSummary: "",
+		FinishedAt:   time.Date(2024, 3, 1, 0, 1, 0, 0, time.UTC),
+		RunID:        "2024-03-01T00:00:00Z",
+		StartedAt:    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Sources: []domain.CollectionRunSource{
+			{
+				AnomaliesFlagged: 1,
+				Elapsed:          250 * time.Millisecond,
+				OK:               true,
+				RowsFetched:      100,
+				RowsInserted:     90,
+				RowsSkipped:      10,
+				Source:           "tiingo",
+			},
+			{
+				Elapsed: 50 * time.Millisecond,
+				Error:   "token expired",
+				OK:      false,
+				Source:  "kis",
+			},
+		},
+	}
+
+	if err := repo.InsertCollectionRun(ctx, run); err != nil {
+		t.Fatalf("insert collection run: %v", err)
+	}
+
+	var sourceCount, successCount int
+	var errorSummary *string
+	err := pool.QueryRow(ctx, `
+		SELECT source_count, success_count, error_summary FROM collection_runs WHERE run_id = $1
+	`, run.RunID).Scan(&sourceCount, &successCount, &errorSummary)
+	if err != nil {
+		t.Fatalf("fetch collection run: %v", err)
+	}
+	if sourceCount != 2 {
+		t.Errorf("source_count = %d, want 2", sourceCount)
+	}
+	if successCount != 1 {
+		t.Errorf("success_count = %d, want 1", successCount)
+	}
+
+	var ok bool
+	var rowsFetched, rowsInserted int64
+	var errText *string
+	err = pool.QueryRow(ctx, `
+		SELECT ok, rows_fetched, rows_inserted, error FROM collection_run_sources WHERE run_id = $1 AND source = $2
+	`, run.RunID, "kis").Scan(&ok, &rowsFetched, &rowsInserted, &errText)
+	if err != nil {
+		t.Fatalf("fetch collection run source: %v", err)
+	}
+	if ok {
+		t.Error("kis ok = true, want false")
+	}
+	if errText == nil || *errText != "token expired" {
+		t.Errorf("kis error = %v, want token expired", errText)
+	}
+}
+
+func TestInsertCollectionRun_EmptySources(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	run := domain.CollectionRun{
+		FinishedAt: time.Date(2024, 3, 2, 0, 1, 0, 0, time.UTC),
+		RunID:      "2024-03-02T00:00:00Z",
+		StartedAt:  time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	if err := repo.InsertCollectionRun(ctx, run); err != nil {
+		t.Fatalf("insert collection run with no sources: %v", err)
+	}
+}
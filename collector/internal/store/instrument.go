@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// FetchInstrument returns symbol's persisted domain.Instrument. The bool
+// return is false (with a zero Instrument and nil error) when no row exists
+// yet, so callers (e.g. summary.ComputeSymbolIndicators tick-size rounding)
+// can treat "never collected" the same as "not applicable" rather than
+// special-casing pgx.ErrNoRows.
+func (r *Repository) FetchInstrument(ctx context.Context, symbol string) (domain.Instrument, bool, error) {
+	var inst domain.Instrument
+	var settlementType string
+	var delivery *time.Time
+
+	err := r.pool.QueryRow(ctx, `
+		SELECT symbol, exchange, quote_currency, price_tick_size, amount_tick_size,
+			contract_multiplier, settlement_type, contract_type, delivery
+		FROM instrument
+		WHERE symbol = $1
+	`, symbol).Scan(
+		&inst.Symbol, &inst.Exchange, &inst.QuoteCurrency, &inst.PriceTickSize, &inst.AmountTickSize,
+		&inst.ContractMultiplier, &settlementType, &inst.ContractType, &delivery,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Instrument{}, false, nil
+	}
+	if err != nil {
+		return domain.Instrument{}, false, fmt.Errorf("fetch instrument %s: %w", symbol, err)
+	}
+
+	inst.SettlementType = domain.SettlementType(settlementType)
+	if delivery != nil {
+		inst.Delivery = *delivery
+	}
+	return inst, true, nil
+}
+
+// UpsertInstruments inserts or updates instruments, keyed by Symbol. This
+// follows SetSourcePriority's simple per-row tx.Exec loop rather than the
+// temp-table copy pattern UpsertPrices uses: instruments are one row per
+// watchlist symbol (tens, not thousands) and change rarely, so the bulk-copy
+// machinery would add complexity without a measurable throughput benefit.
+func (r *Repository) UpsertInstruments(ctx context.Context, instruments []domain.Instrument) (int64, error) {
+	if len(instruments) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin upsert instruments: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var affected int64
+	for _, inst := range instruments {
+		var delivery *time.Time
+		if !inst.Delivery.IsZero() {
+			d := inst.Delivery
+			delivery = &d
+		}
+
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO instrument (
+				symbol, exchange, quote_currency, price_tick_size, amount_tick_size,
+				contract_multiplier, settlement_type, contract_type, delivery
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (symbol) DO UPDATE SET
+				exchange            = EXCLUDED.exchange,
+				quote_currency      = EXCLUDED.quote_currency,
+				price_tick_size     = EXCLUDED.price_tick_size,
+				amount_tick_size    = EXCLUDED.amount_tick_size,
+				contract_multiplier = EXCLUDED.contract_multiplier,
+				settlement_type     = EXCLUDED.settlement_type,
+				contract_type       = EXCLUDED.contract_type,
+				delivery            = EXCLUDED.delivery
+		`, inst.Symbol, inst.Exchange, inst.QuoteCurrency, inst.PriceTickSize, inst.AmountTickSize,
+			inst.ContractMultiplier, string(inst.SettlementType), inst.ContractType, delivery)
+		if err != nil {
+			return 0, fmt.Errorf("upsert instrument %s: %w", inst.Symbol, err)
+		}
+		affected += tag.RowsAffected()
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit upsert instruments: %w", err)
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,117 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func TestUpsertInstruments_EmptyInput(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	n, err := repo.UpsertInstruments(ctx, nil)
+	if err != nil {
+		t.Fatalf("upsert instruments: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("rows affected = %d, want 0", n)
+	}
+}
+
+func TestFetchInstrument_NotFound(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	_, ok, err := repo.FetchInstrument(ctx, "NOPE")
+	if err != nil {
+		t.Fatalf("fetch instrument: %v", err)
+	}
+	if ok {
+		t.Error("ok = true, want false for an unseeded symbol")
+	}
+}
+
+func TestUpsertInstruments_InsertThenUpdate(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	inst := domain.Instrument{
+		AmountTickSize:     1,
+		ContractMultiplier: 1,
+		Exchange:           "KRX",
+		PriceTickSize:      10,
+		QuoteCurrency:      "KRW",
+		SettlementType:     domain.SettlementTPlus2,
+		Symbol:             "005930",
+	}
+
+	if _, err := repo.UpsertInstruments(ctx, []domain.Instrument{inst}); err != nil {
+		t.Fatalf("insert instrument: %v", err)
+	}
+
+	got, ok, err := repo.FetchInstrument(ctx, "005930")
+	if err != nil {
+		t.Fatalf("fetch instrument: %v", err)
+	}
+	if !ok {
+		t.Fatal("ok = false, want true after insert")
+	}
+	if got.PriceTickSize != 10 || got.Exchange != "KRX" || got.SettlementType != domain.SettlementTPlus2 {
+		t.Errorf("got = %+v, want tick=10 exchange=KRX settlement=T+2", got)
+	}
+
+	inst.PriceTickSize = 50
+	if _, err := repo.UpsertInstruments(ctx, []domain.Instrument{inst}); err != nil {
+		t.Fatalf("update instrument: %v", err)
+	}
+
+	got, ok, err = repo.FetchInstrument(ctx, "005930")
+	if err != nil {
+		t.Fatalf("fetch instrument after update: %v", err)
+	}
+	if !ok {
+		t.Fatal("ok = false, want true after update")
+	}
+	if got.PriceTickSize != 50 {
+		t.Errorf("PriceTickSize = %v, want 50 after update", got.PriceTickSize)
+	}
+}
+
+func TestUpsertInstruments_FuturesDelivery(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	delivery := time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC)
+	inst := domain.Instrument{
+		AmountTickSize:     1,
+		ContractMultiplier: 250000,
+		ContractType:       "cash",
+		Delivery:           delivery,
+		Exchange:           "KRX",
+		PriceTickSize:      0.05,
+		QuoteCurrency:      "KRW",
+		SettlementType:     domain.SettlementTPlus1,
+		Symbol:             "101W06",
+	}
+
+	if _, err := repo.UpsertInstruments(ctx, []domain.Instrument{inst}); err != nil {
+		t.Fatalf("insert futures instrument: %v", err)
+	}
+
+	got, ok, err := repo.FetchInstrument(ctx, "101W06")
+	if err != nil {
+		t.Fatalf("fetch futures instrument: %v", err)
+	}
+	if !ok {
+		t.Fatal("ok = false, want true")
+	}
+	if !got.Delivery.Equal(delivery) {
+		t.Errorf("Delivery = %v, want %v", got.Delivery, delivery)
+	}
+	if got.ContractType != "cash" {
+		t.Errorf("ContractType = %q, want cash", got.ContractType)
+	}
+}
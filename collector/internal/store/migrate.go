@@ -1,13 +1,18 @@
 package store
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"fmt"
-	"path"
+	"io/fs"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -15,32 +20,110 @@ import (
 //go:embed migrations/*.sql
 var migrationFS embed.FS
 
+// migration is a single versioned migration parsed from a rockhopper-style
+// "-- +up / -- +begin ... -- +end" / "-- +down / -- +begin ... -- +end" SQL
+// file. checksum covers the raw file content so any edit to either section of
+// an already-applied migration is detected as drift.
 type migration struct {
-	name    string
-	sql     string
-	version int
+	checksum string
+	down     string
+	name     string
+	up       string
+	version  int
 }
 
+// MigrationStatus reports whether a discovered migration has been applied,
+// for the `migrate status` CLI subcommand. AppliedAt is the zero time when
+// Applied is false.
+type MigrationStatus struct {
+	Applied   bool
+	AppliedAt time.Time
+	Name      string
+	Version   int
+}
+
+// RunMigrations applies all pending migrations embedded in this package,
+// recording progress in schema_migrations. Kept as a convenience wrapper
+// around Migrate for callers that don't need to inject an alternate fs.FS.
 func RunMigrations(ctx context.Context, pool *pgxpool.Pool) error {
-	if err := ensureSchemaVersionTable(ctx, pool); err != nil {
+	dir, err := MigrationsDir()
+	if err != nil {
+		return err
+	}
+	return Migrate(ctx, pool, dir)
+}
+
+// RollbackMigrations rolls back applied migrations embedded in this package
+// to targetVersion, recording progress in schema_migrations. Kept as a
+// convenience wrapper around MigrateDown for callers that don't need to
+// inject an alternate fs.FS.
+func RollbackMigrations(ctx context.Context, pool *pgxpool.Pool, targetVersion int) error {
+	dir, err := MigrationsDir()
+	if err != nil {
+		return err
+	}
+	return MigrateDown(ctx, pool, dir, targetVersion)
+}
+
+// MigrationStatuses reports every migration embedded in this package
+// alongside its applied/pending state, for operators auditing drift without a
+// manual SQL query. Kept as a convenience wrapper around Status for callers
+// that don't need to inject an alternate fs.FS.
+func MigrationStatuses(ctx context.Context, pool *pgxpool.Pool) ([]MigrationStatus, error) {
+	dir, err := MigrationsDir()
+	if err != nil {
+		return nil, err
+	}
+	return Status(ctx, pool, dir)
+}
+
+// MigrationsDir exposes this package's embedded migrations as an fs.FS rooted
+// at the migration files, for callers (e.g. the `migrate` CLI) that drive
+// Migrate, MigrateDown, or Status directly instead of through RunMigrations.
+func MigrationsDir() (fs.FS, error) {
+	dir, err := fs.Sub(migrationFS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("open embedded migrations directory: %w", err)
+	}
+	return dir, nil
+}
+
+// Migrate applies every migration in dir whose version is greater than the
+// highest version recorded in schema_migrations, in ascending order, each
+// wrapped in its own transaction. It refuses to proceed if the checksum of a
+// previously-applied migration file no longer matches the recorded checksum,
+// since that means the applied schema and the migration history have
+// silently diverged.
+func Migrate(ctx context.Context, pool *pgxpool.Pool, dir fs.FS) error {
+	if err := ensureSchemaMigrationsTable(ctx, pool); err != nil {
 		return err
 	}
 
-	currentVersion, err := readCurrentVersion(ctx, pool)
+	migrations, err := loadMigrations(dir)
 	if err != nil {
 		return err
 	}
 
-	migrations, err := loadMigrations()
+	applied, err := readAppliedMigrations(ctx, pool)
 	if err != nil {
 		return err
 	}
 
 	for _, m := range migrations {
-		if m.version <= currentVersion {
+		record, ok := applied[m.version]
+		if !ok {
+			continue
+		}
+		if record.checksum != m.checksum {
+			return fmt.Errorf("migration %d (%s) has changed since it was applied: checksum drift detected", m.version, m.name)
+		}
+	}
+
+	for _, m := range migrations {
+		if _, ok := applied[m.version]; ok {
 			continue
 		}
-		if err := applyMigration(ctx, pool, m); err != nil {
+		if err := applyMigrationUp(ctx, pool, m); err != nil {
 			return err
 		}
 	}
@@ -48,40 +131,140 @@ func RunMigrations(ctx context.Context, pool *pgxpool.Pool) error {
 	return nil
 }
 
-func ensureSchemaVersionTable(ctx context.Context, pool *pgxpool.Pool) error {
+// MigrateDown rolls back applied migrations in descending version order
+// until the current version is at most target, running each migration's
+// down section in its own transaction.
+func MigrateDown(ctx context.Context, pool *pgxpool.Pool, dir fs.FS, target int) error {
+	if err := ensureSchemaMigrationsTable(ctx, pool); err != nil {
+		return err
+	}
+
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		return err
+	}
+
+	applied, err := readAppliedMigrations(ctx, pool)
+	if err != nil {
+		return err
+	}
+
+	byVersion := make(map[int]migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.version] = m
+	}
+
+	var toRollback []migration
+	for version := range applied {
+		if version <= target {
+			continue
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			return fmt.Errorf("applied migration %d has no corresponding file; cannot roll back", version)
+		}
+		toRollback = append(toRollback, m)
+	}
+	sort.Slice(toRollback, func(i, j int) bool { return toRollback[i].version > toRollback[j].version })
+
+	for _, m := range toRollback {
+		if err := applyMigrationDown(ctx, pool, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Status reports every discovered migration alongside whether it has been applied.
+func Status(ctx context.Context, pool *pgxpool.Pool, dir fs.FS) ([]MigrationStatus, error) {
+	if err := ensureSchemaMigrationsTable(ctx, pool); err != nil {
+		return nil, err
+	}
+
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := readAppliedMigrations(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		record, ok := applied[m.version]
+		statuses = append(statuses, MigrationStatus{
+			Applied:   ok,
+			AppliedAt: record.appliedAt,
+			Name:      m.name,
+			Version:   m.version,
+		})
+	}
+
+	return statuses, nil
+}
+
+type appliedMigration struct {
+	appliedAt time.Time
+	checksum  string
+}
+
+func ensureSchemaMigrationsTable(ctx context.Context, pool *pgxpool.Pool) error {
 	_, err := pool.Exec(ctx, `
-		CREATE TABLE IF NOT EXISTS schema_version (
+		CREATE TABLE IF NOT EXISTS schema_migrations (
 			version    INTEGER     NOT NULL PRIMARY KEY,
-			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			name       TEXT        NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			checksum   TEXT        NOT NULL
 		)
 	`)
 	if err != nil {
-		return fmt.Errorf("create schema_version table: %w", err)
+		return fmt.Errorf("create schema_migrations table: %w", err)
 	}
 	return nil
 }
 
-func readCurrentVersion(ctx context.Context, pool *pgxpool.Pool) (int, error) {
-	var version int
-	err := pool.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version)
+func readAppliedMigrations(ctx context.Context, pool *pgxpool.Pool) (map[int]appliedMigration, error) {
+	rows, err := pool.Query(ctx, `SELECT version, checksum, applied_at FROM schema_migrations`)
 	if err != nil {
-		return 0, fmt.Errorf("read current schema version: %w", err)
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var version int
+		var checksum string
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &checksum, &appliedAt); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations row: %w", err)
+		}
+		applied[version] = appliedMigration{appliedAt: appliedAt, checksum: checksum}
 	}
-	return version, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate schema_migrations rows: %w", err)
+	}
+
+	return applied, nil
 }
 
-func applyMigration(ctx context.Context, pool *pgxpool.Pool, m migration) error {
+func applyMigrationUp(ctx context.Context, pool *pgxpool.Pool, m migration) error {
 	tx, err := pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin transaction for migration %d: %w", m.version, err)
 	}
 	defer tx.Rollback(ctx)
 
-	if _, err := tx.Exec(ctx, m.sql); err != nil {
+	if _, err := tx.Exec(ctx, m.up); err != nil {
 		return fmt.Errorf("apply migration %d (%s): %w", m.version, m.name, err)
 	}
 
-	if _, err := tx.Exec(ctx, `INSERT INTO schema_version (version) VALUES ($1)`, m.version); err != nil {
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+		m.version, m.name, m.checksum,
+	); err != nil {
 		return fmt.Errorf("record migration %d: %w", m.version, err)
 	}
 
@@ -92,32 +275,130 @@ func applyMigration(ctx context.Context, pool *pgxpool.Pool, m migration) error
 	return nil
 }
 
-func loadMigrations() ([]migration, error) {
-	entries, err := migrationFS.ReadDir("migrations")
+func applyMigrationDown(ctx context.Context, pool *pgxpool.Pool, m migration) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction for rollback of migration %d: %w", m.version, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, m.down); err != nil {
+		return fmt.Errorf("roll back migration %d (%s): %w", m.version, m.name, err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.version); err != nil {
+		return fmt.Errorf("unrecord migration %d: %w", m.version, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit rollback of migration %d: %w", m.version, err)
+	}
+
+	return nil
+}
+
+// pairedMigrationFile accumulates the up and down halves of a
+// "NNN_name.up.sql" / "NNN_name.down.sql" pair as each half is discovered;
+// loadMigrations requires both before it can build a migration from them.
+type pairedMigrationFile struct {
+	down    []byte
+	hasDown bool
+	hasUp   bool
+	up      []byte
+}
+
+// loadMigrations discovers every migration in dir, in two supported file
+// layouts: the original single "NNN_name.sql" file containing both
+// "-- +up"/"-- +down" sections, and a paired "NNN_name.up.sql" /
+// "NNN_name.down.sql" layout, for migrations simple enough not to need the
+// section markers. Mixing both layouts in one directory is fine; each
+// migration uses whichever layout its files are in.
+func loadMigrations(dir fs.FS) ([]migration, error) {
+	entries, err := fs.ReadDir(dir, ".")
 	if err != nil {
 		return nil, fmt.Errorf("read migrations directory: %w", err)
 	}
 
 	var migrations []migration
+	paired := make(map[string]*pairedMigrationFile)
+	var pairedOrder []string
+
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+		name := entry.Name()
+		if entry.IsDir() {
 			continue
 		}
 
-		version, err := parseVersion(entry.Name())
-		if err != nil {
-			return nil, fmt.Errorf("parse migration filename %s: %w", entry.Name(), err)
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			key := strings.TrimSuffix(name, ".up.sql")
+			content, err := fs.ReadFile(dir, name)
+			if err != nil {
+				return nil, fmt.Errorf("read migration %s: %w", name, err)
+			}
+			p := pairedFile(paired, &pairedOrder, key)
+			p.up, p.hasUp = content, true
+
+		case strings.HasSuffix(name, ".down.sql"):
+			key := strings.TrimSuffix(name, ".down.sql")
+			content, err := fs.ReadFile(dir, name)
+			if err != nil {
+				return nil, fmt.Errorf("read migration %s: %w", name, err)
+			}
+			p := pairedFile(paired, &pairedOrder, key)
+			p.down, p.hasDown = content, true
+
+		case strings.HasSuffix(name, ".sql"):
+			version, err := parseVersion(name)
+			if err != nil {
+				return nil, fmt.Errorf("parse migration filename %s: %w", name, err)
+			}
+
+			content, err := fs.ReadFile(dir, name)
+			if err != nil {
+				return nil, fmt.Errorf("read migration %s: %w", name, err)
+			}
+
+			up, down, err := parseMigrationSections(content)
+			if err != nil {
+				return nil, fmt.Errorf("parse migration %s: %w", name, err)
+			}
+
+			sum := sha256.Sum256(content)
+			migrations = append(migrations, migration{
+				checksum: hex.EncodeToString(sum[:]),
+				down:     down,
+				name:     name,
+				up:       up,
+				version:  version,
+			})
+		}
+	}
+
+	for _, key := range pairedOrder {
+		p := paired[key]
+		if !p.hasUp {
+			return nil, fmt.Errorf("migration %s.down.sql has no matching %s.up.sql", key, key)
+		}
+		if !p.hasDown {
+			return nil, fmt.Errorf("migration %s.up.sql has no matching %s.down.sql", key, key)
 		}
 
-		content, err := migrationFS.ReadFile(path.Join("migrations", entry.Name()))
+		version, err := parseVersion(key)
 		if err != nil {
-			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+			return nil, fmt.Errorf("parse migration filename %s: %w", key, err)
 		}
 
+		sum := sha256.New()
+		sum.Write(p.up)
+		sum.Write(p.down)
+
 		migrations = append(migrations, migration{
-			name:    entry.Name(),
-			sql:     string(content),
-			version: version,
+			checksum: hex.EncodeToString(sum.Sum(nil)),
+			down:     string(p.down),
+			name:     key + ".sql",
+			up:       string(p.up),
+			version:  version,
 		})
 	}
 
@@ -128,6 +409,19 @@ func loadMigrations() ([]migration, error) {
 	return migrations, nil
 }
 
+// pairedFile returns the in-progress pairedMigrationFile for key, creating it
+// (and recording key's first-seen order in order) if this is the first half
+// of the pair encountered.
+func pairedFile(paired map[string]*pairedMigrationFile, order *[]string, key string) *pairedMigrationFile {
+	p, ok := paired[key]
+	if !ok {
+		p = &pairedMigrationFile{}
+		paired[key] = p
+		*order = append(*order, key)
+	}
+	return p
+}
+
 func parseVersion(filename string) (int, error) {
 	parts := strings.SplitN(filename, "_", 2)
 	if len(parts) < 2 {
@@ -135,3 +429,66 @@ func parseVersion(filename string) (int, error) {
 	}
 	return strconv.Atoi(parts[0])
 }
+
+// parseMigrationSections extracts the SQL between "-- +begin" and "-- +end"
+// markers nested under "-- +up" and "-- +down" section headers.
+func parseMigrationSections(content []byte) (up, down string, err error) {
+	const (
+		sectionNone = iota
+		sectionUp
+		sectionDown
+	)
+
+	var (
+		section    = sectionNone
+		collecting bool
+		upBuf      bytes.Buffer
+		downBuf    bytes.Buffer
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch trimmed {
+		case "-- +up":
+			section = sectionUp
+			continue
+		case "-- +down":
+			section = sectionDown
+			continue
+		case "-- +begin":
+			collecting = true
+			continue
+		case "-- +end":
+			collecting = false
+			continue
+		}
+
+		if !collecting {
+			continue
+		}
+
+		switch section {
+		case sectionUp:
+			upBuf.WriteString(line)
+			upBuf.WriteByte('\n')
+		case sectionDown:
+			downBuf.WriteString(line)
+			downBuf.WriteByte('\n')
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", fmt.Errorf("scan migration content: %w", err)
+	}
+
+	if upBuf.Len() == 0 {
+		return "", "", fmt.Errorf("missing -- +up -- +begin ... -- +end section")
+	}
+	if downBuf.Len() == 0 {
+		return "", "", fmt.Errorf("missing -- +down -- +begin ... -- +end section")
+	}
+
+	return upBuf.String(), downBuf.String(), nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"testing/fstest"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jusikbot/collector/internal/store"
@@ -28,7 +29,7 @@ func connectAndClean(t *testing.T) *pgxpool.Pool {
 		t.Fatalf("connect to database: %v", err)
 	}
 
-	for _, table := range []string{"price_history", "fx_rate", "schema_version"} {
+	for _, table := range []string{"instrument", "kis_tokens", "collection_run_sources", "collection_runs", "summary_risk", "margin_loan", "margin_interest", "margin_repayment", "corporate_action", "price_history_weekly", "fx_rate_weekly", "order_book_snapshot", "tick", "price_history", "fx_rate", "schema_migrations", "schema_version"} {
 		if _, err := pool.Exec(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s", table)); err != nil {
 			t.Fatalf("drop table %s: %v", table, err)
 		}
@@ -66,14 +67,18 @@ func TestRunMigrations(t *testing.T) {
 
 		assertTableExists(t, pool, "price_history")
 		assertTableExists(t, pool, "fx_rate")
-		assertTableExists(t, pool, "schema_version")
+		assertTableExists(t, pool, "corporate_action")
+		assertTableExists(t, pool, "margin_loan")
+		assertTableExists(t, pool, "margin_interest")
+		assertTableExists(t, pool, "margin_repayment")
+		assertTableExists(t, pool, "schema_migrations")
 
 		var version int
-		if err := pool.QueryRow(ctx, `SELECT MAX(version) FROM schema_version`).Scan(&version); err != nil {
+		if err := pool.QueryRow(ctx, `SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
 			t.Fatalf("read schema version: %v", err)
 		}
-		if version != 1 {
-			t.Errorf("schema version = %d, want 1", version)
+		if version != 12 {
+			t.Errorf("schema version = %d, want 12", version)
 		}
 	})
 
@@ -83,15 +88,201 @@ func TestRunMigrations(t *testing.T) {
 		}
 
 		var count int
-		if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM schema_version`).Scan(&count); err != nil {
-			t.Fatalf("count schema_version rows: %v", err)
+		if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&count); err != nil {
+			t.Fatalf("count schema_migrations rows: %v", err)
 		}
-		if count != 1 {
-			t.Errorf("schema_version rows = %d, want 1 (duplicate detected)", count)
+		if count != 12 {
+			t.Errorf("schema_migrations rows = %d, want 12 (duplicate detected)", count)
 		}
 	})
 }
 
+func TestMigrate_RollbackAndReapply(t *testing.T) {
+	pool := connectAndClean(t)
+	defer pool.Close()
+	ctx := context.Background()
+
+	dir, err := store.MigrationsDir()
+	if err != nil {
+		t.Fatalf("load embedded migrations: %v", err)
+	}
+
+	if err := store.Migrate(ctx, pool, dir); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	assertTableExists(t, pool, "margin_loan")
+
+	t.Run("rolls back to target version", func(t *testing.T) {
+		if err := store.MigrateDown(ctx, pool, dir, 2); err != nil {
+			t.Fatalf("migrate down to 2: %v", err)
+		}
+		assertTableDoesNotExist(t, pool, "margin_loan")
+		assertTableDoesNotExist(t, pool, "margin_interest")
+		assertTableDoesNotExist(t, pool, "margin_repayment")
+		assertTableExists(t, pool, "corporate_action")
+
+		var version int
+		if err := pool.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+			t.Fatalf("read schema version: %v", err)
+		}
+		if version != 2 {
+			t.Errorf("schema version = %d, want 2", version)
+		}
+	})
+
+	t.Run("rolls back to zero", func(t *testing.T) {
+		if err := store.MigrateDown(ctx, pool, dir, 0); err != nil {
+			t.Fatalf("migrate down to 0: %v", err)
+		}
+		assertTableDoesNotExist(t, pool, "price_history")
+		assertTableDoesNotExist(t, pool, "fx_rate")
+	})
+
+	t.Run("reapplies cleanly after a full rollback", func(t *testing.T) {
+		if err := store.Migrate(ctx, pool, dir); err != nil {
+			t.Fatalf("migrate up after rollback: %v", err)
+		}
+		assertTableExists(t, pool, "price_history")
+		assertTableExists(t, pool, "margin_loan")
+	})
+}
+
+func TestMigrate_ChecksumDrift(t *testing.T) {
+	pool := connectAndClean(t)
+	defer pool.Close()
+	ctx := context.Background()
+
+	dir := fstest.MapFS{
+		"000001_initial.sql": &fstest.MapFile{Data: []byte(
+			"-- +up\n-- +begin\nCREATE TABLE widget (id INT PRIMARY KEY);\n-- +end\n\n" +
+				"-- +down\n-- +begin\nDROP TABLE widget;\n-- +end\n",
+		)},
+	}
+
+	if err := store.Migrate(ctx, pool, dir); err != nil {
+		t.Fatalf("initial migrate: %v", err)
+	}
+	assertTableExists(t, pool, "widget")
+
+	drifted := fstest.MapFS{
+		"000001_initial.sql": &fstest.MapFile{Data: []byte(
+			"-- +up\n-- +begin\nCREATE TABLE widget (id INT PRIMARY KEY, name TEXT);\n-- +end\n\n" +
+				"-- +down\n-- +begin\nDROP TABLE widget;\n-- +end\n",
+		)},
+	}
+
+	if err := store.Migrate(ctx, pool, drifted); err == nil {
+		t.Error("expected checksum drift error, got nil")
+	}
+
+	if _, err := pool.Exec(ctx, "DROP TABLE IF EXISTS widget"); err != nil {
+		t.Fatalf("cleanup widget table: %v", err)
+	}
+}
+
+func TestMigrate_PairedUpDownFiles(t *testing.T) {
+	pool := connectAndClean(t)
+	defer pool.Close()
+	ctx := context.Background()
+
+	dir := fstest.MapFS{
+		"000001_widget.up.sql":   &fstest.MapFile{Data: []byte("CREATE TABLE widget (id INT PRIMARY KEY);\n")},
+		"000001_widget.down.sql": &fstest.MapFile{Data: []byte("DROP TABLE widget;\n")},
+	}
+
+	if err := store.Migrate(ctx, pool, dir); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	assertTableExists(t, pool, "widget")
+
+	if err := store.MigrateDown(ctx, pool, dir, 0); err != nil {
+		t.Fatalf("migrate down: %v", err)
+	}
+	assertTableDoesNotExist(t, pool, "widget")
+}
+
+func TestMigrate_PairedFileMissingDownHalf(t *testing.T) {
+	pool := connectAndClean(t)
+	defer pool.Close()
+	ctx := context.Background()
+
+	dir := fstest.MapFS{
+		"000001_widget.up.sql": &fstest.MapFile{Data: []byte("CREATE TABLE widget (id INT PRIMARY KEY);\n")},
+	}
+
+	if err := store.Migrate(ctx, pool, dir); err == nil {
+		t.Fatal("expected error for a .up.sql file with no matching .down.sql, got nil")
+	}
+}
+
+func TestMigrationStatuses(t *testing.T) {
+	pool := connectAndClean(t)
+	defer pool.Close()
+	ctx := context.Background()
+
+	if err := store.RunMigrations(ctx, pool); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	statuses, err := store.MigrationStatuses(ctx, pool)
+	if err != nil {
+		t.Fatalf("migration statuses: %v", err)
+	}
+	if len(statuses) == 0 {
+		t.Fatal("expected at least one migration status")
+	}
+	for _, s := range statuses {
+		if !s.Applied {
+			t.Errorf("migration %d (%s) reported pending after RunMigrations", s.Version, s.Name)
+		}
+		if s.AppliedAt.IsZero() {
+			t.Errorf("migration %d (%s) has a zero AppliedAt", s.Version, s.Name)
+		}
+	}
+}
+
+func TestRollbackMigrations(t *testing.T) {
+	pool := connectAndClean(t)
+	defer pool.Close()
+	ctx := context.Background()
+
+	if err := store.RunMigrations(ctx, pool); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	if err := store.RollbackMigrations(ctx, pool, 0); err != nil {
+		t.Fatalf("rollback migrations: %v", err)
+	}
+	assertTableDoesNotExist(t, pool, "price_history")
+
+	statuses, err := store.MigrationStatuses(ctx, pool)
+	if err != nil {
+		t.Fatalf("migration statuses: %v", err)
+	}
+	for _, s := range statuses {
+		if s.Applied {
+			t.Errorf("migration %d (%s) still reported applied after rollback to 0", s.Version, s.Name)
+		}
+	}
+}
+
+func assertTableDoesNotExist(t *testing.T, pool *pgxpool.Pool, tableName string) {
+	t.Helper()
+	var exists bool
+	err := pool.QueryRow(context.Background(), `
+		SELECT EXISTS (
+			SELECT 1 FROM information_schema.tables
+			WHERE table_schema = 'public' AND table_name = $1
+		)
+	`, tableName).Scan(&exists)
+	if err != nil {
+		t.Fatalf("check table %s: %v", tableName, err)
+	}
+	if exists {
+		t.Errorf("table %s still exists", tableName)
+	}
+}
+
 func TestRunMigrations_CheckConstraints(t *testing.T) {
 	pool := connectAndClean(t)
 	defer pool.Close()
@@ -125,6 +316,34 @@ func TestRunMigrations_CheckConstraints(t *testing.T) {
 			name: "zero adj_close rejects",
 			sql:  `INSERT INTO price_history (symbol, date, open, high, low, close, adj_close, volume, source) VALUES ('T', '2024-01-01', 100, 110, 90, 95, 0, 1000, 'test')`,
 		},
+		{
+			name: "invalid corporate action kind rejects",
+			sql:  `INSERT INTO corporate_action (symbol, ex_date, kind) VALUES ('T', '2024-01-01', 'merger')`,
+		},
+		{
+			name: "negative split_ratio rejects",
+			sql:  `INSERT INTO corporate_action (symbol, ex_date, kind, split_ratio) VALUES ('T', '2024-01-01', 'split', -1)`,
+		},
+		{
+			name: "invalid margin loan status rejects",
+			sql:  `INSERT INTO margin_loan (account_no, loan_id, symbol, issued_at, principal, status) VALUES ('1234567801', 'L1', 'T', '2024-01-01', 1000, 'pending')`,
+		},
+		{
+			name: "negative margin principal rejects",
+			sql:  `INSERT INTO margin_loan (account_no, loan_id, symbol, issued_at, principal, status) VALUES ('1234567801', 'L1', 'T', '2024-01-01', -1, 'open')`,
+		},
+		{
+			name: "negative margin interest amount rejects",
+			sql:  `INSERT INTO margin_interest (account_no, date, amount, rate) VALUES ('1234567801', '2024-01-01', -1, 0.05)`,
+		},
+		{
+			name: "zero tick price rejects",
+			sql:  `INSERT INTO tick (symbol, ts, price, volume) VALUES ('T', '2024-01-01T09:00:00Z', 0, 100)`,
+		},
+		{
+			name: "negative tick volume rejects",
+			sql:  `INSERT INTO tick (symbol, ts, price, volume) VALUES ('T', '2024-01-01T09:00:00Z', 100, -1)`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -166,6 +385,76 @@ func TestRunMigrations_ValidInsert(t *testing.T) {
 		}
 	})
 
+	t.Run("corporate_action accepts valid split and dividend rows", func(t *testing.T) {
+		_, err := pool.Exec(ctx, `
+			INSERT INTO corporate_action (symbol, ex_date, kind, split_ratio)
+			VALUES ('NVDA', '2024-06-10', 'split', 10)
+		`)
+		if err != nil {
+			t.Fatalf("valid split insert: %v", err)
+		}
+
+		_, err = pool.Exec(ctx, `
+			INSERT INTO corporate_action (symbol, ex_date, kind, cash_amount, currency)
+			VALUES ('NVDA', '2024-09-01', 'dividend', 0.10, 'USD')
+		`)
+		if err != nil {
+			t.Fatalf("valid dividend insert: %v", err)
+		}
+
+		_, err = pool.Exec(ctx, `
+			INSERT INTO corporate_action (symbol, ex_date, kind)
+			VALUES ('NVDA', '2024-11-15', 'spinoff')
+		`)
+		if err != nil {
+			t.Fatalf("valid spinoff insert: %v", err)
+		}
+	})
+
+	t.Run("margin tables accept valid rows", func(t *testing.T) {
+		_, err := pool.Exec(ctx, `
+			INSERT INTO margin_loan (account_no, loan_id, symbol, issued_at, principal, status)
+			VALUES ('1234567801', 'L1', 'NVDA', '2024-01-02', 5000000, 'open')
+		`)
+		if err != nil {
+			t.Fatalf("valid margin_loan insert: %v", err)
+		}
+
+		_, err = pool.Exec(ctx, `
+			INSERT INTO margin_interest (account_no, date, amount, rate)
+			VALUES ('1234567801', '2024-01-02', 1234.5, 0.07)
+		`)
+		if err != nil {
+			t.Fatalf("valid margin_interest insert: %v", err)
+		}
+
+		_, err = pool.Exec(ctx, `
+			INSERT INTO margin_repayment (account_no, loan_id, date, amount)
+			VALUES ('1234567801', 'L1', '2024-01-10', 1000000)
+		`)
+		if err != nil {
+			t.Fatalf("valid margin_repayment insert: %v", err)
+		}
+	})
+
+	t.Run("streaming tables accept valid rows", func(t *testing.T) {
+		_, err := pool.Exec(ctx, `
+			INSERT INTO tick (symbol, ts, price, volume)
+			VALUES ('NVDA', '2024-01-02T09:00:00Z', 496.30, 1500)
+		`)
+		if err != nil {
+			t.Fatalf("valid tick insert: %v", err)
+		}
+
+		_, err = pool.Exec(ctx, `
+			INSERT INTO order_book_snapshot (symbol, ts, ask_prices, ask_quantities, bid_prices, bid_quantities)
+			VALUES ('NVDA', '2024-01-02T09:00:00Z', ARRAY[497.0], ARRAY[100]::BIGINT[], ARRAY[496.0], ARRAY[200]::BIGINT[])
+		`)
+		if err != nil {
+			t.Fatalf("valid order_book_snapshot insert: %v", err)
+		}
+	})
+
 	t.Run("price_history upsert on duplicate PK", func(t *testing.T) {
 		_, err := pool.Exec(ctx, `
 			INSERT INTO price_history (symbol, date, open, high, low, close, adj_close, volume, source)
@@ -8,14 +8,39 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 type Repository struct {
 	pool *pgxpool.Pool
+
+	// Calendar decides which dates DetectMissingRanges treats as tradeable.
+	// Exported so callers can swap in a market-specific calendar; defaults to
+	// DefaultTradingCalendar.
+	Calendar TradingCalendar
+
+	metrics *metrics.StoreMetrics
+}
+
+// Option customizes a Repository at construction time.
+type Option func(*Repository)
+
+// WithMetrics registers the upsert-path instrumentation (see metrics.StoreMetrics)
+// against reg and has the Repository record to it. Without this option the
+// Repository records nothing, matching historical behavior.
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(r *Repository) {
+		r.metrics = metrics.NewStoreMetrics(reg)
+	}
 }
 
-func NewRepository(pool *pgxpool.Pool) *Repository {
-	return &Repository{pool: pool}
+func NewRepository(pool *pgxpool.Pool, opts ...Option) *Repository {
+	r := &Repository{pool: pool, Calendar: NewDefaultTradingCalendar()}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // UpsertPrices bulk-inserts or updates price_history via temp table + CopyFrom + INSERT ON CONFLICT.
@@ -31,9 +56,159 @@ func (r *Repository) UpsertPrices(ctx context.Context, prices []domain.DailyPric
 	}
 	defer tx.Rollback(ctx)
 
+	inserted, updated, err := upsertPricesTx(ctx, tx, prices, "", r.metrics)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit upsert prices: %w", err)
+	}
+
+	return inserted + updated, nil
+}
+
+// UpsertPricesWithBatch is UpsertPrices plus provenance: every row is stamped
+// with batchID (a caller-generated UUID string) and a price_history_batch
+// ledger row is written in the same transaction, so operators can audit which
+// ingestion produced which row and replay a failed batch idempotently by
+// calling this again with the same batchID and prices.
+// Why idempotent: price_history's ON CONFLICT keeps last-write-wins per
+// (symbol, date) gated by source_priority, so replaying the same batch just
+// re-applies the same values; it does not double-count rows because the
+// ledger row itself is keyed on batch_id.
+func (r *Repository) UpsertPricesWithBatch(ctx context.Context, batchID string, prices []domain.DailyPrice) (int64, error) {
+	if len(prices) == 0 {
+		return 0, nil
+	}
+	if batchID == "" {
+		return 0, fmt.Errorf("upsert prices with batch: batchID is required")
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin upsert prices with batch: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	inserted, updated, err := upsertPricesTx(ctx, tx, prices, batchID, r.metrics)
+	if err != nil {
+		return 0, err
+	}
+
+	symbols := make(map[string]struct{}, len(prices))
+	source := prices[0].Source
+	for _, p := range prices {
+		symbols[p.Symbol] = struct{}{}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO price_history_batch (batch_id, rows_inserted, rows_updated, source, symbol_count)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (batch_id) DO UPDATE SET
+			rows_inserted = EXCLUDED.rows_inserted,
+			rows_updated  = EXCLUDED.rows_updated,
+			source        = EXCLUDED.source,
+			symbol_count  = EXCLUDED.symbol_count
+	`, batchID, inserted, updated, source, len(symbols)); err != nil {
+		return 0, fmt.Errorf("record price history batch %s: %w", batchID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit upsert prices with batch: %w", err)
+	}
+
+	return inserted + updated, nil
+}
+
+// SetSourcePriority upserts the rank each source carries when two providers
+// report the same (symbol, date): UpsertPrices only overwrites an existing
+// row when the incoming source's rank is >= the existing row's. Sources
+// absent from source_priority default to rank 0.
+func (r *Repository) SetSourcePriority(ctx context.Context, priority map[string]int) error {
+	if len(priority) == 0 {
+		return nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin set source priority: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for source, rank := range priority {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO source_priority (source, rank) VALUES ($1, $2)
+			ON CONFLICT (source) DO UPDATE SET rank = EXCLUDED.rank
+		`, source, rank); err != nil {
+			return fmt.Errorf("set priority for source %s: %w", source, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit set source priority: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertPricesAndCorporateActions persists daily prices and the corporate actions
+// observed alongside them in a single transaction, so a split/dividend row never
+// commits without the price row it was derived from (or vice versa).
+func (r *Repository) UpsertPricesAndCorporateActions(
+	ctx context.Context,
+	prices []domain.DailyPrice,
+	actions []domain.CorporateAction,
+) (int64, int64, error) {
+	if len(prices) == 0 && len(actions) == 0 {
+		return 0, 0, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("begin upsert prices and corporate actions: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	inserted, updated, err := upsertPricesTx(ctx, tx, prices, "", r.metrics)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	actionRows, err := upsertCorporateActionsTx(ctx, tx, actions)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, fmt.Errorf("commit upsert prices and corporate actions: %w", err)
+	}
+
+	return inserted + updated, actionRows, nil
+}
+
+// upsertPricesTx upserts prices gated by source_priority (see SetSourcePriority):
+// a conflicting row only gets overwritten when the incoming source's rank is
+// >= the existing row's, so a lower-quality provider can't clobber a
+// higher-quality one that already landed. Sources absent from
+// source_priority default to rank 0. batchID stamps every row's batch_id
+// column; an empty batchID leaves it NULL. m may be nil, in which case no
+// metrics are recorded.
+func upsertPricesTx(ctx context.Context, tx pgx.Tx, prices []domain.DailyPrice, batchID string, m *metrics.StoreMetrics) (inserted, updated int64, err error) {
+	if len(prices) == 0 {
+		return 0, 0, nil
+	}
+
+	const table = "price_history"
+	if m != nil {
+		start := time.Now()
+		defer func() { m.UpsertDuration.WithLabelValues(table).Observe(time.Since(start).Seconds()) }()
+	}
+
 	if _, err := tx.Exec(ctx, `
 		CREATE TEMP TABLE tmp_prices (
 			adj_close  DOUBLE PRECISION NOT NULL,
+			batch_id   UUID,
 			close      DOUBLE PRECISION NOT NULL,
 			date       DATE             NOT NULL,
 			high       DOUBLE PRECISION NOT NULL,
@@ -45,48 +220,68 @@ func (r *Repository) UpsertPrices(ctx context.Context, prices []domain.DailyPric
 			volume     BIGINT           NOT NULL
 		) ON COMMIT DROP
 	`); err != nil {
-		return 0, fmt.Errorf("create temp prices table: %w", err)
+		return 0, 0, fmt.Errorf("create temp prices table: %w", err)
+	}
+
+	var batchIDArg any
+	if batchID != "" {
+		batchIDArg = batchID
 	}
 
 	// Why fetched_at excluded: server-side NOW() used for both insert (DEFAULT) and update (SET).
 	// DailyPrice.FetchedAt is read-only, populated by FetchPriceHistory.
-	columns := []string{"adj_close", "close", "date", "high", "is_anomaly", "low", "open", "source", "symbol", "volume"}
+	columns := []string{"adj_close", "batch_id", "close", "date", "high", "is_anomaly", "low", "open", "source", "symbol", "volume"}
 	if _, err := tx.CopyFrom(
 		ctx,
 		pgx.Identifier{"tmp_prices"},
 		columns,
 		pgx.CopyFromSlice(len(prices), func(i int) ([]any, error) {
 			p := prices[i]
-			return []any{p.AdjClose, p.Close, p.Date, p.High, p.IsAnomaly, p.Low, p.Open, p.Source, p.Symbol, p.Volume}, nil
+			return []any{p.AdjClose, batchIDArg, p.Close, p.Date, p.High, p.IsAnomaly, p.Low, p.Open, p.Source, p.Symbol, p.Volume}, nil
 		}),
 	); err != nil {
-		return 0, fmt.Errorf("copy prices to temp table: %w", err)
+		return 0, 0, fmt.Errorf("copy prices to temp table: %w", err)
+	}
+	if m != nil {
+		m.TempTableRows.WithLabelValues(table).Set(float64(len(prices)))
 	}
 
-	tag, err := tx.Exec(ctx, `
-		INSERT INTO price_history (adj_close, close, date, high, is_anomaly, low, open, source, symbol, volume)
-		SELECT adj_close, close, date, high, is_anomaly, low, open, source, symbol, volume
-		FROM tmp_prices
-		ON CONFLICT (symbol, date) DO UPDATE SET
-			adj_close  = EXCLUDED.adj_close,
-			close      = EXCLUDED.close,
-			high       = EXCLUDED.high,
-			is_anomaly = EXCLUDED.is_anomaly,
-			low        = EXCLUDED.low,
-			open       = EXCLUDED.open,
-			source     = EXCLUDED.source,
-			volume     = EXCLUDED.volume,
-			fetched_at = NOW()
+	// xmax = 0 distinguishes an inserted row from an updated one in the
+	// RETURNING clause of an INSERT ... ON CONFLICT DO UPDATE.
+	row := tx.QueryRow(ctx, `
+		WITH upserted AS (
+			INSERT INTO price_history (adj_close, batch_id, close, date, high, is_anomaly, low, open, source, symbol, volume)
+			SELECT adj_close, batch_id, close, date, high, is_anomaly, low, open, source, symbol, volume
+			FROM tmp_prices
+			ON CONFLICT (symbol, date) DO UPDATE SET
+				adj_close  = EXCLUDED.adj_close,
+				batch_id   = EXCLUDED.batch_id,
+				close      = EXCLUDED.close,
+				high       = EXCLUDED.high,
+				is_anomaly = EXCLUDED.is_anomaly,
+				low        = EXCLUDED.low,
+				open       = EXCLUDED.open,
+				source     = EXCLUDED.source,
+				volume     = EXCLUDED.volume,
+				fetched_at = NOW()
+			WHERE COALESCE((SELECT rank FROM source_priority WHERE source = EXCLUDED.source), 0)
+			   >= COALESCE((SELECT rank FROM source_priority WHERE source = price_history.source), 0)
+			RETURNING (xmax = 0) AS inserted
+		)
+		SELECT
+			COALESCE(SUM(CASE WHEN inserted THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN NOT inserted THEN 1 ELSE 0 END), 0)
+		FROM upserted
 	`)
-	if err != nil {
-		return 0, fmt.Errorf("upsert prices from temp table: %w", err)
+	if err := row.Scan(&inserted, &updated); err != nil {
+		return 0, 0, fmt.Errorf("upsert prices from temp table: %w", err)
 	}
-
-	if err := tx.Commit(ctx); err != nil {
-		return 0, fmt.Errorf("commit upsert prices: %w", err)
+	if m != nil {
+		m.RowsInserted.WithLabelValues(table).Add(float64(inserted))
+		m.RowsUpdated.WithLabelValues(table).Add(float64(updated))
 	}
 
-	return tag.RowsAffected(), nil
+	return inserted, updated, nil
 }
 
 // UpsertFXRates bulk-inserts or updates fx_rate via the same temp table pattern.
@@ -103,36 +298,42 @@ func (r *Repository) UpsertFXRates(ctx context.Context, rates []domain.FXRate) (
 
 	if _, err := tx.Exec(ctx, `
 		CREATE TEMP TABLE tmp_fx_rates (
-			date   DATE             NOT NULL,
-			pair   TEXT             NOT NULL,
-			rate   DOUBLE PRECISION NOT NULL,
-			source TEXT             NOT NULL
+			date    DATE             NOT NULL,
+			pair    TEXT             NOT NULL,
+			rate    DOUBLE PRECISION NOT NULL,
+			source  TEXT             NOT NULL,
+			sources TEXT[]           NOT NULL
 		) ON COMMIT DROP
 	`); err != nil {
 		return 0, fmt.Errorf("create temp fx_rates table: %w", err)
 	}
 
 	// Why fetched_at excluded: same rationale as UpsertPrices.
-	columns := []string{"date", "pair", "rate", "source"}
+	columns := []string{"date", "pair", "rate", "source", "sources"}
 	if _, err := tx.CopyFrom(
 		ctx,
 		pgx.Identifier{"tmp_fx_rates"},
 		columns,
 		pgx.CopyFromSlice(len(rates), func(i int) ([]any, error) {
 			rate := rates[i]
-			return []any{rate.Date, rate.Pair, rate.Rate, rate.Source}, nil
+			sources := rate.Sources
+			if len(sources) == 0 {
+				sources = []string{rate.Source}
+			}
+			return []any{rate.Date, rate.Pair, rate.Rate, rate.Source, sources}, nil
 		}),
 	); err != nil {
 		return 0, fmt.Errorf("copy fx rates to temp table: %w", err)
 	}
 
 	tag, err := tx.Exec(ctx, `
-		INSERT INTO fx_rate (date, pair, rate, source)
-		SELECT date, pair, rate, source
+		INSERT INTO fx_rate (date, pair, rate, source, sources)
+		SELECT date, pair, rate, source, sources
 		FROM tmp_fx_rates
 		ON CONFLICT (pair, date) DO UPDATE SET
 			rate       = EXCLUDED.rate,
 			source     = EXCLUDED.source,
+			sources    = EXCLUDED.sources,
 			fetched_at = NOW()
 	`)
 	if err != nil {
@@ -146,6 +347,195 @@ func (r *Repository) UpsertFXRates(ctx context.Context, rates []domain.FXRate) (
 	return tag.RowsAffected(), nil
 }
 
+// UpsertCorporateActions bulk-inserts or updates corporate_action via the same temp table pattern.
+func (r *Repository) UpsertCorporateActions(ctx context.Context, actions []domain.CorporateAction) (int64, error) {
+	if len(actions) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin upsert corporate actions: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	n, err := upsertCorporateActionsTx(ctx, tx, actions)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit upsert corporate actions: %w", err)
+	}
+
+	return n, nil
+}
+
+func upsertCorporateActionsTx(ctx context.Context, tx pgx.Tx, actions []domain.CorporateAction) (int64, error) {
+	if len(actions) == 0 {
+		return 0, nil
+	}
+
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE tmp_corporate_actions (
+			cash_amount DOUBLE PRECISION NOT NULL,
+			currency    TEXT             NOT NULL,
+			ex_date     DATE             NOT NULL,
+			kind        TEXT             NOT NULL,
+			split_ratio DOUBLE PRECISION NOT NULL,
+			symbol      TEXT             NOT NULL
+		) ON COMMIT DROP
+	`); err != nil {
+		return 0, fmt.Errorf("create temp corporate_action table: %w", err)
+	}
+
+	columns := []string{"cash_amount", "currency", "ex_date", "kind", "split_ratio", "symbol"}
+	if _, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"tmp_corporate_actions"},
+		columns,
+		pgx.CopyFromSlice(len(actions), func(i int) ([]any, error) {
+			a := actions[i]
+			return []any{a.CashAmount, a.Currency, a.ExDate, string(a.Kind), a.SplitRatio, a.Symbol}, nil
+		}),
+	); err != nil {
+		return 0, fmt.Errorf("copy corporate actions to temp table: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO corporate_action (cash_amount, currency, ex_date, kind, split_ratio, symbol)
+		SELECT cash_amount, currency, ex_date, kind, split_ratio, symbol
+		FROM tmp_corporate_actions
+		ON CONFLICT (symbol, ex_date, kind) DO UPDATE SET
+			cash_amount = EXCLUDED.cash_amount,
+			currency    = EXCLUDED.currency,
+			split_ratio = EXCLUDED.split_ratio
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("upsert corporate actions from temp table: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// FetchCorporateActions retrieves split/dividend events for a symbol within a date range, sorted ascending.
+func (r *Repository) FetchCorporateActions(ctx context.Context, symbol string, from, to time.Time) ([]domain.CorporateAction, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT cash_amount, currency, ex_date, kind, split_ratio, symbol
+		FROM corporate_action
+		WHERE symbol = $1 AND ex_date >= $2 AND ex_date <= $3
+		ORDER BY ex_date ASC
+	`, symbol, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("fetch corporate actions for %s: %w", symbol, err)
+	}
+	defer rows.Close()
+
+	actions := make([]domain.CorporateAction, 0)
+	for rows.Next() {
+		var a domain.CorporateAction
+		var kind string
+		if err := rows.Scan(&a.CashAmount, &a.Currency, &a.ExDate, &kind, &a.SplitRatio, &a.Symbol); err != nil {
+			return nil, fmt.Errorf("scan corporate action row: %w", err)
+		}
+		a.Kind = domain.CorporateActionKind(kind)
+		actions = append(actions, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate corporate action rows: %w", err)
+	}
+
+	return actions, nil
+}
+
+// RecomputeAdjustedClose rebuilds adj_close for every price_history row of symbol
+// from the full corporate_action history, replacing whatever adj_close the source
+// (e.g. tiingo) supplied at ingestion time. It walks rows newest-to-oldest, carrying
+// a running adjustment factor that is updated by 1/ratio on a split's ex_date and by
+// (1 - cash_amount/close_on_day_before_ex) on a dividend's ex_date, so every row older
+// than an action reflects it while rows on or after do not. Spinoffs are recorded but
+// left unadjusted: unlike splits and dividends they carry no well-defined ratio here.
+func (r *Repository) RecomputeAdjustedClose(ctx context.Context, symbol string) error {
+	rows, err := r.pool.Query(ctx, `
+		SELECT close, date
+		FROM price_history
+		WHERE symbol = $1
+		ORDER BY date DESC
+	`, symbol)
+	if err != nil {
+		return fmt.Errorf("fetch price history for %s: %w", symbol, err)
+	}
+
+	type priceRow struct {
+		close float64
+		date  time.Time
+	}
+	var prices []priceRow
+	for rows.Next() {
+		var p priceRow
+		if err := rows.Scan(&p.close, &p.date); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan price row: %w", err)
+		}
+		prices = append(prices, p)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("iterate price rows: %w", err)
+	}
+	rows.Close()
+
+	if len(prices) == 0 {
+		return nil
+	}
+
+	actions, err := r.FetchCorporateActions(ctx, symbol, time.Time{}, prices[0].date)
+	if err != nil {
+		return fmt.Errorf("fetch corporate actions for %s: %w", symbol, err)
+	}
+	actionsByDate := make(map[time.Time][]domain.CorporateAction, len(actions))
+	for _, a := range actions {
+		actionsByDate[a.ExDate] = append(actionsByDate[a.ExDate], a)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin recompute adjusted close: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	factor := 1.0
+	for i, p := range prices {
+		if _, err := tx.Exec(ctx,
+			`UPDATE price_history SET adj_close = $1 WHERE symbol = $2 AND date = $3`,
+			p.close*factor, symbol, p.date,
+		); err != nil {
+			return fmt.Errorf("update adj_close for %s on %s: %w", symbol, p.date, err)
+		}
+
+		for _, a := range actionsByDate[p.date] {
+			switch a.Kind {
+			case domain.CorporateActionSplit:
+				factor /= a.SplitRatio
+			case domain.CorporateActionDividend:
+				closeBeforeEx := p.close
+				if i+1 < len(prices) {
+					closeBeforeEx = prices[i+1].close
+				}
+				if closeBeforeEx > 0 {
+					factor *= 1 - a.CashAmount/closeBeforeEx
+				}
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit recompute adjusted close for %s: %w", symbol, err)
+	}
+
+	return nil
+}
+
 // DetectGaps returns the last recorded date per symbol for incremental collection.
 // Symbols with no data are absent from the returned map.
 func (r *Repository) DetectGaps(ctx context.Context, symbols []string) (map[string]time.Time, error) {
@@ -213,6 +603,122 @@ func (r *Repository) DetectFXGaps(ctx context.Context, pairs []string) (map[stri
 	return gaps, nil
 }
 
+// DetectMissingRanges returns, per symbol, the contiguous spans of trading
+// days between from and to (inclusive) that have no price_history row.
+// Weekends and r.Calendar holidays are excluded before coalescing, so the
+// caller can issue one backfill request per range instead of one per date.
+// Symbols with no gaps are absent from the returned map.
+func (r *Repository) DetectMissingRanges(ctx context.Context, symbols []string, from, to time.Time) (map[string][]domain.DateRange, error) {
+	if len(symbols) == 0 {
+		return make(map[string][]domain.DateRange), nil
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT sym, d
+		FROM unnest($1::text[]) AS sym
+		CROSS JOIN generate_series($2::date, $3::date, interval '1 day') AS d
+		WHERE NOT EXISTS (
+			SELECT 1 FROM price_history
+			WHERE symbol = sym AND date = d
+		)
+		ORDER BY sym, d
+	`, symbols, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("detect missing price ranges: %w", err)
+	}
+	defer rows.Close()
+
+	missing := make(map[string][]time.Time, len(symbols))
+	for rows.Next() {
+		var symbol string
+		var date time.Time
+		if err := rows.Scan(&symbol, &date); err != nil {
+			return nil, fmt.Errorf("scan missing price range row: %w", err)
+		}
+		if !r.Calendar.IsTradingDay(date) {
+			continue
+		}
+		missing[symbol] = append(missing[symbol], date)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate missing price range rows: %w", err)
+	}
+
+	ranges := make(map[string][]domain.DateRange, len(missing))
+	for symbol, dates := range missing {
+		ranges[symbol] = coalesceDateRanges(dates)
+	}
+	return ranges, nil
+}
+
+// DetectMissingFXRanges mirrors DetectMissingRanges for fx_rate, using
+// WeekdaysOnlyCalendar since FX trades through most exchange holidays.
+func (r *Repository) DetectMissingFXRanges(ctx context.Context, pairs []string, from, to time.Time) (map[string][]domain.DateRange, error) {
+	if len(pairs) == 0 {
+		return make(map[string][]domain.DateRange), nil
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT p, d
+		FROM unnest($1::text[]) AS p
+		CROSS JOIN generate_series($2::date, $3::date, interval '1 day') AS d
+		WHERE NOT EXISTS (
+			SELECT 1 FROM fx_rate
+			WHERE pair = p AND date = d
+		)
+		ORDER BY p, d
+	`, pairs, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("detect missing fx ranges: %w", err)
+	}
+	defer rows.Close()
+
+	calendar := WeekdaysOnlyCalendar{}
+	missing := make(map[string][]time.Time, len(pairs))
+	for rows.Next() {
+		var pair string
+		var date time.Time
+		if err := rows.Scan(&pair, &date); err != nil {
+			return nil, fmt.Errorf("scan missing fx range row: %w", err)
+		}
+		if !calendar.IsTradingDay(date) {
+			continue
+		}
+		missing[pair] = append(missing[pair], date)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate missing fx range rows: %w", err)
+	}
+
+	ranges := make(map[string][]domain.DateRange, len(missing))
+	for pair, dates := range missing {
+		ranges[pair] = coalesceDateRanges(dates)
+	}
+	return ranges, nil
+}
+
+// coalesceDateRanges merges consecutive (day-apart) sorted dates into
+// inclusive DateRange spans.
+func coalesceDateRanges(dates []time.Time) []domain.DateRange {
+	if len(dates) == 0 {
+		return nil
+	}
+
+	var ranges []domain.DateRange
+	start := dates[0]
+	prev := dates[0]
+	for _, d := range dates[1:] {
+		if d.Sub(prev) > 24*time.Hour {
+			ranges = append(ranges, domain.DateRange{Start: start, End: prev})
+			start = d
+		}
+		prev = d
+	}
+	ranges = append(ranges, domain.DateRange{Start: start, End: prev})
+
+	return ranges
+}
+
 // FetchPriceHistory retrieves price data for a symbol within a date range, sorted ascending.
 func (r *Repository) FetchPriceHistory(ctx context.Context, symbol string, from, to time.Time) ([]domain.DailyPrice, error) {
 	rows, err := r.pool.Query(ctx, `
@@ -244,7 +750,7 @@ func (r *Repository) FetchPriceHistory(ctx context.Context, symbol string, from,
 // FetchFXRates retrieves FX rate data for a currency pair within a date range, sorted ascending.
 func (r *Repository) FetchFXRates(ctx context.Context, pair string, from, to time.Time) ([]domain.FXRate, error) {
 	rows, err := r.pool.Query(ctx, `
-		SELECT date, fetched_at, pair, rate, source
+		SELECT date, fetched_at, pair, rate, source, sources
 		FROM fx_rate
 		WHERE pair = $1 AND date >= $2 AND date <= $3
 		ORDER BY date ASC
@@ -257,7 +763,7 @@ func (r *Repository) FetchFXRates(ctx context.Context, pair string, from, to tim
 	rates := make([]domain.FXRate, 0)
 	for rows.Next() {
 		var fr domain.FXRate
-		if err := rows.Scan(&fr.Date, &fr.FetchedAt, &fr.Pair, &fr.Rate, &fr.Source); err != nil {
+		if err := rows.Scan(&fr.Date, &fr.FetchedAt, &fr.Pair, &fr.Rate, &fr.Source, &fr.Sources); err != nil {
 			return nil, fmt.Errorf("scan fx rate row: %w", err)
 		}
 		rates = append(rates, fr)
@@ -268,3 +774,604 @@ func (r *Repository) FetchFXRates(ctx context.Context, pair string, from, to tim
 
 	return rates, nil
 }
+
+// defaultStreamBatchSize is the page size StreamPriceHistory, StreamFXRates, and
+// StreamPricesMulti use when the caller passes batchSize <= 0.
+const defaultStreamBatchSize = 5000
+
+// PriceEvent is one row (or a terminal error) pushed by StreamPriceHistory /
+// StreamPricesMulti. A non-nil Err is always the last event on the channel.
+type PriceEvent struct {
+	Err   error
+	Price domain.DailyPrice
+}
+
+// FXEvent is one row (or a terminal error) pushed by StreamFXRates. A non-nil
+// Err is always the last event on the channel.
+type FXEvent struct {
+	Err  error
+	Rate domain.FXRate
+}
+
+// StreamPriceHistory streams price_history rows for symbol within [from, to]
+// via a server-side cursor inside a read-only transaction, fetching batchSize
+// rows per round trip (batchSize <= 0 uses defaultStreamBatchSize). The
+// returned channel is closed after the stream is exhausted, ctx is cancelled,
+// or a fetch/scan error occurs; an error is always reported as the final
+// PriceEvent before the channel closes. The underlying transaction is rolled
+// back once the channel closes, so callers must drain it (or cancel ctx) to
+// avoid leaking the connection.
+func (r *Repository) StreamPriceHistory(ctx context.Context, symbol string, from, to time.Time, batchSize int) (<-chan PriceEvent, error) {
+	if batchSize <= 0 {
+		batchSize = defaultStreamBatchSize
+	}
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("begin stream price history for %s: %w", symbol, err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		DECLARE price_history_cursor CURSOR FOR
+		SELECT adj_close, close, date, fetched_at, high, is_anomaly, low, open, source, symbol, volume
+		FROM price_history
+		WHERE symbol = $1 AND date >= $2 AND date <= $3
+		ORDER BY date ASC
+	`, symbol, from, to); err != nil {
+		tx.Rollback(ctx)
+		return nil, fmt.Errorf("declare price history cursor for %s: %w", symbol, err)
+	}
+
+	events := make(chan PriceEvent, batchSize)
+	go func() {
+		defer close(events)
+		defer tx.Rollback(ctx)
+
+		for {
+			rows, err := tx.Query(ctx, fmt.Sprintf("FETCH %d FROM price_history_cursor", batchSize))
+			if err != nil {
+				sendPriceEvent(ctx, events, PriceEvent{Err: fmt.Errorf("fetch price history cursor for %s: %w", symbol, err)})
+				return
+			}
+
+			fetched := 0
+			for rows.Next() {
+				fetched++
+				var p domain.DailyPrice
+				if err := rows.Scan(&p.AdjClose, &p.Close, &p.Date, &p.FetchedAt, &p.High, &p.IsAnomaly, &p.Low, &p.Open, &p.Source, &p.Symbol, &p.Volume); err != nil {
+					rows.Close()
+					sendPriceEvent(ctx, events, PriceEvent{Err: fmt.Errorf("scan price cursor row: %w", err)})
+					return
+				}
+				if !sendPriceEvent(ctx, events, PriceEvent{Price: p}) {
+					rows.Close()
+					return
+				}
+			}
+			err = rows.Err()
+			rows.Close()
+			if err != nil {
+				sendPriceEvent(ctx, events, PriceEvent{Err: fmt.Errorf("iterate price cursor rows: %w", err)})
+				return
+			}
+			if fetched < batchSize {
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// StreamPricesMulti streams price_history rows for all symbols within
+// [from, to], interleaved in (date, symbol) order via a single
+// symbol = ANY($1) query, so downstream consumers can run time-aligned joins
+// without loading a per-symbol slice. Semantics otherwise match
+// StreamPriceHistory.
+func (r *Repository) StreamPricesMulti(ctx context.Context, symbols []string, from, to time.Time, batchSize int) (<-chan PriceEvent, error) {
+	if batchSize <= 0 {
+		batchSize = defaultStreamBatchSize
+	}
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("begin stream prices multi: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		DECLARE prices_multi_cursor CURSOR FOR
+		SELECT adj_close, close, date, fetched_at, high, is_anomaly, low, open, source, symbol, volume
+		FROM price_history
+		WHERE symbol = ANY($1) AND date >= $2 AND date <= $3
+		ORDER BY date ASC, symbol ASC
+	`, symbols, from, to); err != nil {
+		tx.Rollback(ctx)
+		return nil, fmt.Errorf("declare prices multi cursor: %w", err)
+	}
+
+	events := make(chan PriceEvent, batchSize)
+	go func() {
+		defer close(events)
+		defer tx.Rollback(ctx)
+
+		for {
+			rows, err := tx.Query(ctx, fmt.Sprintf("FETCH %d FROM prices_multi_cursor", batchSize))
+			if err != nil {
+				sendPriceEvent(ctx, events, PriceEvent{Err: fmt.Errorf("fetch prices multi cursor: %w", err)})
+				return
+			}
+
+			fetched := 0
+			for rows.Next() {
+				fetched++
+				var p domain.DailyPrice
+				if err := rows.Scan(&p.AdjClose, &p.Close, &p.Date, &p.FetchedAt, &p.High, &p.IsAnomaly, &p.Low, &p.Open, &p.Source, &p.Symbol, &p.Volume); err != nil {
+					rows.Close()
+					sendPriceEvent(ctx, events, PriceEvent{Err: fmt.Errorf("scan prices multi cursor row: %w", err)})
+					return
+				}
+				if !sendPriceEvent(ctx, events, PriceEvent{Price: p}) {
+					rows.Close()
+					return
+				}
+			}
+			err = rows.Err()
+			rows.Close()
+			if err != nil {
+				sendPriceEvent(ctx, events, PriceEvent{Err: fmt.Errorf("iterate prices multi cursor rows: %w", err)})
+				return
+			}
+			if fetched < batchSize {
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// StreamFXRates streams fx_rate rows for pair within [from, to] via a
+// server-side cursor, mirroring StreamPriceHistory.
+func (r *Repository) StreamFXRates(ctx context.Context, pair string, from, to time.Time, batchSize int) (<-chan FXEvent, error) {
+	if batchSize <= 0 {
+		batchSize = defaultStreamBatchSize
+	}
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("begin stream fx rates for %s: %w", pair, err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		DECLARE fx_rate_cursor CURSOR FOR
+		SELECT date, fetched_at, pair, rate, source, sources
+		FROM fx_rate
+		WHERE pair = $1 AND date >= $2 AND date <= $3
+		ORDER BY date ASC
+	`, pair, from, to); err != nil {
+		tx.Rollback(ctx)
+		return nil, fmt.Errorf("declare fx rate cursor for %s: %w", pair, err)
+	}
+
+	events := make(chan FXEvent, batchSize)
+	go func() {
+		defer close(events)
+		defer tx.Rollback(ctx)
+
+		for {
+			rows, err := tx.Query(ctx, fmt.Sprintf("FETCH %d FROM fx_rate_cursor", batchSize))
+			if err != nil {
+				sendFXEvent(ctx, events, FXEvent{Err: fmt.Errorf("fetch fx rate cursor for %s: %w", pair, err)})
+				return
+			}
+
+			fetched := 0
+			for rows.Next() {
+				fetched++
+				var fr domain.FXRate
+				if err := rows.Scan(&fr.Date, &fr.FetchedAt, &fr.Pair, &fr.Rate, &fr.Source, &fr.Sources); err != nil {
+					rows.Close()
+					sendFXEvent(ctx, events, FXEvent{Err: fmt.Errorf("scan fx rate cursor row: %w", err)})
+					return
+				}
+				if !sendFXEvent(ctx, events, FXEvent{Rate: fr}) {
+					rows.Close()
+					return
+				}
+			}
+			err = rows.Err()
+			rows.Close()
+			if err != nil {
+				sendFXEvent(ctx, events, FXEvent{Err: fmt.Errorf("iterate fx rate cursor rows: %w", err)})
+				return
+			}
+			if fetched < batchSize {
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// sendPriceEvent delivers ev on events, returning false without blocking
+// forever if ctx is cancelled first.
+func sendPriceEvent(ctx context.Context, events chan<- PriceEvent, ev PriceEvent) bool {
+	select {
+	case events <- ev:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// sendFXEvent delivers ev on events, returning false without blocking forever
+// if ctx is cancelled first.
+func sendFXEvent(ctx context.Context, events chan<- FXEvent, ev FXEvent) bool {
+	select {
+	case events <- ev:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// UpsertMarginLoans bulk-inserts or updates margin_loan via the same temp table pattern.
+func (r *Repository) UpsertMarginLoans(ctx context.Context, loans []domain.MarginLoan) (int64, error) {
+	if len(loans) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin upsert margin loans: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE tmp_margin_loan (
+			account_no TEXT             NOT NULL,
+			issued_at  DATE             NOT NULL,
+			loan_id    TEXT             NOT NULL,
+			principal  DOUBLE PRECISION NOT NULL,
+			status     TEXT             NOT NULL,
+			symbol     TEXT             NOT NULL
+		) ON COMMIT DROP
+	`); err != nil {
+		return 0, fmt.Errorf("create temp margin_loan table: %w", err)
+	}
+
+	columns := []string{"account_no", "issued_at", "loan_id", "principal", "status", "symbol"}
+	if _, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"tmp_margin_loan"},
+		columns,
+		pgx.CopyFromSlice(len(loans), func(i int) ([]any, error) {
+			l := loans[i]
+			return []any{l.AccountNo, l.IssuedAt, l.LoanID, l.Principal, string(l.Status), l.Symbol}, nil
+		}),
+	); err != nil {
+		return 0, fmt.Errorf("copy margin loans to temp table: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO margin_loan (account_no, issued_at, loan_id, principal, status, symbol)
+		SELECT account_no, issued_at, loan_id, principal, status, symbol
+		FROM tmp_margin_loan
+		ON CONFLICT (account_no, loan_id) DO UPDATE SET
+			principal = EXCLUDED.principal,
+			status    = EXCLUDED.status
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("upsert margin loans from temp table: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit upsert margin loans: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// UpsertMarginInterest bulk-inserts or updates margin_interest via the same temp table pattern.
+func (r *Repository) UpsertMarginInterest(ctx context.Context, entries []domain.MarginInterest) (int64, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin upsert margin interest: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE tmp_margin_interest (
+			account_no TEXT             NOT NULL,
+			amount     DOUBLE PRECISION NOT NULL,
+			date       DATE             NOT NULL,
+			rate       DOUBLE PRECISION NOT NULL
+		) ON COMMIT DROP
+	`); err != nil {
+		return 0, fmt.Errorf("create temp margin_interest table: %w", err)
+	}
+
+	columns := []string{"account_no", "amount", "date", "rate"}
+	if _, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"tmp_margin_interest"},
+		columns,
+		pgx.CopyFromSlice(len(entries), func(i int) ([]any, error) {
+			e := entries[i]
+			return []any{e.AccountNo, e.Amount, e.Date, e.Rate}, nil
+		}),
+	); err != nil {
+		return 0, fmt.Errorf("copy margin interest to temp table: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO margin_interest (account_no, amount, date, rate)
+		SELECT account_no, amount, date, rate
+		FROM tmp_margin_interest
+		ON CONFLICT (account_no, date) DO UPDATE SET
+			amount = EXCLUDED.amount,
+			rate   = EXCLUDED.rate
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("upsert margin interest from temp table: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit upsert margin interest: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// UpsertMarginRepayments bulk-inserts or updates margin_repayment via the same temp table pattern.
+func (r *Repository) UpsertMarginRepayments(ctx context.Context, repayments []domain.MarginRepayment) (int64, error) {
+	if len(repayments) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin upsert margin repayments: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE tmp_margin_repayment (
+			account_no TEXT             NOT NULL,
+			amount     DOUBLE PRECISION NOT NULL,
+			date       DATE             NOT NULL,
+			loan_id    TEXT             NOT NULL
+		) ON COMMIT DROP
+	`); err != nil {
+		return 0, fmt.Errorf("create temp margin_repayment table: %w", err)
+	}
+
+	columns := []string{"account_no", "amount", "date", "loan_id"}
+	if _, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"tmp_margin_repayment"},
+		columns,
+		pgx.CopyFromSlice(len(repayments), func(i int) ([]any, error) {
+			rp := repayments[i]
+			return []any{rp.AccountNo, rp.Amount, rp.Date, rp.LoanID}, nil
+		}),
+	); err != nil {
+		return 0, fmt.Errorf("copy margin repayments to temp table: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO margin_repayment (account_no, amount, date, loan_id)
+		SELECT account_no, amount, date, loan_id
+		FROM tmp_margin_repayment
+		ON CONFLICT (account_no, loan_id, date) DO UPDATE SET
+			amount = EXCLUDED.amount
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("upsert margin repayments from temp table: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit upsert margin repayments: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// FetchOutstandingMarginLoans retrieves all open (unrepaid) margin loans for an account.
+func (r *Repository) FetchOutstandingMarginLoans(ctx context.Context, accountNo string) ([]domain.MarginLoan, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT account_no, issued_at, loan_id, principal, status, symbol
+		FROM margin_loan
+		WHERE account_no = $1 AND status = $2
+		ORDER BY issued_at ASC
+	`, accountNo, string(domain.MarginLoanOpen))
+	if err != nil {
+		return nil, fmt.Errorf("fetch outstanding margin loans for %s: %w", accountNo, err)
+	}
+	defer rows.Close()
+
+	loans := make([]domain.MarginLoan, 0)
+	for rows.Next() {
+		var l domain.MarginLoan
+		var status string
+		if err := rows.Scan(&l.AccountNo, &l.IssuedAt, &l.LoanID, &l.Principal, &status, &l.Symbol); err != nil {
+			return nil, fmt.Errorf("scan margin loan row: %w", err)
+		}
+		l.Status = domain.MarginLoanStatus(status)
+		loans = append(loans, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate margin loan rows: %w", err)
+	}
+
+	return loans, nil
+}
+
+// FetchMarginInterest retrieves daily margin interest charges for an account within a date range, sorted ascending.
+func (r *Repository) FetchMarginInterest(ctx context.Context, accountNo string, from, to time.Time) ([]domain.MarginInterest, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT account_no, amount, date, rate
+		FROM margin_interest
+		WHERE account_no = $1 AND date >= $2 AND date <= $3
+		ORDER BY date ASC
+	`, accountNo, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("fetch margin interest for %s: %w", accountNo, err)
+	}
+	defer rows.Close()
+
+	entries := make([]domain.MarginInterest, 0)
+	for rows.Next() {
+		var e domain.MarginInterest
+		if err := rows.Scan(&e.AccountNo, &e.Amount, &e.Date, &e.Rate); err != nil {
+			return nil, fmt.Errorf("scan margin interest row: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate margin interest rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// FetchMarginRepayments retrieves margin loan repayments for an account within a date range, sorted ascending.
+func (r *Repository) FetchMarginRepayments(ctx context.Context, accountNo string, from, to time.Time) ([]domain.MarginRepayment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT account_no, amount, date, loan_id
+		FROM margin_repayment
+		WHERE account_no = $1 AND date >= $2 AND date <= $3
+		ORDER BY date ASC
+	`, accountNo, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("fetch margin repayments for %s: %w", accountNo, err)
+	}
+	defer rows.Close()
+
+	repayments := make([]domain.MarginRepayment, 0)
+	for rows.Next() {
+		var rp domain.MarginRepayment
+		if err := rows.Scan(&rp.AccountNo, &rp.Amount, &rp.Date, &rp.LoanID); err != nil {
+			return nil, fmt.Errorf("scan margin repayment row: %w", err)
+		}
+		repayments = append(repayments, rp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate margin repayment rows: %w", err)
+	}
+
+	return repayments, nil
+}
+
+// DetectMarginGaps returns the last recorded date per account across loans, interest,
+// and repayments, for incremental margin-history collection. Accounts with no data
+// in any of the three tables are absent from the returned map.
+func (r *Repository) DetectMarginGaps(ctx context.Context, accounts []string) (map[string]time.Time, error) {
+	if len(accounts) == 0 {
+		return make(map[string]time.Time), nil
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT account_no, MAX(last_date) FROM (
+			SELECT account_no, MAX(issued_at) AS last_date FROM margin_loan WHERE account_no = ANY($1) GROUP BY account_no
+			UNION ALL
+			SELECT account_no, MAX(date) AS last_date FROM margin_interest WHERE account_no = ANY($1) GROUP BY account_no
+			UNION ALL
+			SELECT account_no, MAX(date) AS last_date FROM margin_repayment WHERE account_no = ANY($1) GROUP BY account_no
+		) combined
+		GROUP BY account_no
+	`, accounts)
+	if err != nil {
+		return nil, fmt.Errorf("detect margin gaps: %w", err)
+	}
+	defer rows.Close()
+
+	gaps := make(map[string]time.Time, len(accounts))
+	for rows.Next() {
+		var accountNo string
+		var lastDate time.Time
+		if err := rows.Scan(&accountNo, &lastDate); err != nil {
+			return nil, fmt.Errorf("scan margin gap row: %w", err)
+		}
+		gaps[accountNo] = lastDate
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate margin gap rows: %w", err)
+	}
+
+	return gaps, nil
+}
+
+// UpsertSummaryRisk bulk-inserts or updates summary_risk via the same temp table
+// pattern as UpsertMarginLoans. Re-running a summary for the same symbol and
+// as_of replaces that row rather than duplicating it.
+func (r *Repository) UpsertSummaryRisk(ctx context.Context, metrics []domain.SummaryRiskMetric) (int64, error) {
+	if len(metrics) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin upsert summary risk: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE tmp_summary_risk (
+			symbol                   TEXT        NOT NULL,
+			as_of                    TIMESTAMPTZ NOT NULL,
+			annualized_volatility    DOUBLE PRECISION,
+			cagr                     DOUBLE PRECISION,
+			calmar                   DOUBLE PRECISION,
+			max_drawdown             DOUBLE PRECISION,
+			max_drawdown_peak_date   DATE,
+			max_drawdown_trough_date DATE,
+			sharpe                   DOUBLE PRECISION,
+			sortino                  DOUBLE PRECISION
+		) ON COMMIT DROP
+	`); err != nil {
+		return 0, fmt.Errorf("create temp summary_risk table: %w", err)
+	}
+
+	columns := []string{"symbol", "as_of", "annualized_volatility", "cagr", "calmar", "max_drawdown", "max_drawdown_peak_date", "max_drawdown_trough_date", "sharpe", "sortino"}
+	if _, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"tmp_summary_risk"},
+		columns,
+		pgx.CopyFromSlice(len(metrics), func(i int) ([]any, error) {
+			m := metrics[i]
+			return []any{m.Symbol, m.AsOf, m.AnnualizedVolatility, m.CAGR, m.Calmar, m.MaxDrawdown, m.MaxDrawdownPeakDate, m.MaxDrawdownTroughDate, m.Sharpe, m.Sortino}, nil
+		}),
+	); err != nil {
+		return 0, fmt.Errorf("copy summary risk to temp table: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO summary_risk (symbol, as_of, annualized_volatility, cagr, calmar, max_drawdown, max_drawdown_peak_date, max_drawdown_trough_date, sharpe, sortino)
+		SELECT symbol, as_of, annualized_volatility, cagr, calmar, max_drawdown, max_drawdown_peak_date, max_drawdown_trough_date, sharpe, sortino
+		FROM tmp_summary_risk
+		ON CONFLICT (symbol, as_of) DO UPDATE SET
+			annualized_volatility    = EXCLUDED.annualized_volatility,
+			cagr                     = EXCLUDED.cagr,
+			calmar                   = EXCLUDED.calmar,
+			max_drawdown             = EXCLUDED.max_drawdown,
+			max_drawdown_peak_date   = EXCLUDED.max_drawdown_peak_date,
+			max_drawdown_trough_date = EXCLUDED.max_drawdown_trough_date,
+			sharpe                   = EXCLUDED.sharpe,
+			sortino                  = EXCLUDED.sortino
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("upsert summary risk from temp table: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit upsert summary risk: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/jusikbot/collector/internal/domain"
 	"github.com/jusikbot/collector/internal/store"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func setupRepository(t *testing.T) *store.Repository {
@@ -102,6 +103,211 @@ func TestUpsertPrices(t *testing.T) {
 			t.Errorf("rows affected = %d, want 0", affected)
 		}
 	})
+
+	t.Run("lower priority source cannot clobber a higher priority row", func(t *testing.T) {
+		if err := repo.SetSourcePriority(ctx, map[string]int{"good-source": 10, "bad-source": 1}); err != nil {
+			t.Fatalf("set source priority: %v", err)
+		}
+
+		good := []domain.DailyPrice{
+			{
+				AdjClose: 100, Close: 100,
+				Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				High: 101, Low: 99, Open: 100,
+				Source: "good-source", Symbol: "PRIO", Volume: 1000,
+			},
+		}
+		if _, err := repo.UpsertPrices(ctx, good); err != nil {
+			t.Fatalf("seed good source: %v", err)
+		}
+
+		bad := []domain.DailyPrice{
+			{
+				AdjClose: 999, Close: 999,
+				Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				High: 1000, Low: 998, Open: 999,
+				Source: "bad-source", Symbol: "PRIO", Volume: 1,
+			},
+		}
+		affected, err := repo.UpsertPrices(ctx, bad)
+		if err != nil {
+			t.Fatalf("upsert bad source: %v", err)
+		}
+		if affected != 0 {
+			t.Errorf("rows affected = %d, want 0 (lower priority should not overwrite)", affected)
+		}
+
+		history, err := repo.FetchPriceHistory(ctx, "PRIO",
+			time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("fetch: %v", err)
+		}
+		if len(history) != 1 || history[0].AdjClose != 100 {
+			t.Errorf("history = %+v, want unchanged good-source row", history)
+		}
+	})
+
+	t.Run("equal or higher priority source can overwrite", func(t *testing.T) {
+		if err := repo.SetSourcePriority(ctx, map[string]int{"equal-source": 10}); err != nil {
+			t.Fatalf("set source priority: %v", err)
+		}
+
+		seed := []domain.DailyPrice{
+			{
+				AdjClose: 100, Close: 100,
+				Date: time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+				High: 101, Low: 99, Open: 100,
+				Source: "equal-source", Symbol: "PRIO2", Volume: 1000,
+			},
+		}
+		if _, err := repo.UpsertPrices(ctx, seed); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+
+		update := []domain.DailyPrice{
+			{
+				AdjClose: 200, Close: 200,
+				Date: time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+				High: 201, Low: 199, Open: 200,
+				Source: "equal-source", Symbol: "PRIO2", Volume: 2000,
+			},
+		}
+		affected, err := repo.UpsertPrices(ctx, update)
+		if err != nil {
+			t.Fatalf("upsert update: %v", err)
+		}
+		if affected != 1 {
+			t.Errorf("rows affected = %d, want 1", affected)
+		}
+	})
+}
+
+// TestUpsertPrices_RecordsMetrics doesn't use setupRepository: it needs a
+// Repository built with store.WithMetrics, which setupRepository doesn't
+// thread through.
+func TestUpsertPrices_RecordsMetrics(t *testing.T) {
+	pool := connectAndClean(t)
+	t.Cleanup(pool.Close)
+	ctx := context.Background()
+
+	if err := store.RunMigrations(ctx, pool); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	reg := prometheus.NewRegistry()
+	repo := store.NewRepository(pool, store.WithMetrics(reg))
+
+	prices := []domain.DailyPrice{
+		{
+			AdjClose: 496.30, Close: 496.30,
+			Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			High: 498.50, Low: 492.10, Open: 495.22,
+			Source: "tiingo", Symbol: "NVDA", Volume: 40000000,
+		},
+	}
+	if _, err := repo.UpsertPrices(ctx, prices); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	var sawRowsInserted bool
+	for _, f := range families {
+		if f.GetName() == "collector_store_rows_inserted_total" {
+			sawRowsInserted = true
+			for _, metric := range f.GetMetric() {
+				if got := metric.GetCounter().GetValue(); got != 1 {
+					t.Errorf("rows_inserted_total = %v, want 1", got)
+				}
+			}
+		}
+	}
+	if !sawRowsInserted {
+		t.Error("rows_inserted_total was not registered/recorded")
+	}
+}
+
+func TestUpsertPricesWithBatch(t *testing.T) {
+	pool := connectAndClean(t)
+	t.Cleanup(pool.Close)
+	ctx := context.Background()
+
+	if err := store.RunMigrations(ctx, pool); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+	repo := store.NewRepository(pool)
+
+	batchID := "11111111-1111-1111-1111-111111111111"
+	prices := []domain.DailyPrice{
+		{
+			AdjClose: 100, Close: 100,
+			Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			High: 101, Low: 99, Open: 100,
+			Source: "tiingo", Symbol: "BATCH1", Volume: 1000,
+		},
+		{
+			AdjClose: 200, Close: 200,
+			Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			High: 201, Low: 199, Open: 200,
+			Source: "tiingo", Symbol: "BATCH2", Volume: 2000,
+		},
+	}
+
+	t.Run("requires a batchID", func(t *testing.T) {
+		if _, err := repo.UpsertPricesWithBatch(ctx, "", prices); err == nil {
+			t.Fatal("expected error for empty batchID")
+		}
+	})
+
+	t.Run("writes rows and a ledger entry", func(t *testing.T) {
+		affected, err := repo.UpsertPricesWithBatch(ctx, batchID, prices)
+		if err != nil {
+			t.Fatalf("upsert with batch: %v", err)
+		}
+		if affected != 2 {
+			t.Errorf("rows affected = %d, want 2", affected)
+		}
+
+		var rowsInserted, rowsUpdated, symbolCount int64
+		var source string
+		err = pool.QueryRow(ctx, `
+			SELECT rows_inserted, rows_updated, source, symbol_count
+			FROM price_history_batch WHERE batch_id = $1
+		`, batchID).Scan(&rowsInserted, &rowsUpdated, &source, &symbolCount)
+		if err != nil {
+			t.Fatalf("read ledger row: %v", err)
+		}
+		if rowsInserted != 2 {
+			t.Errorf("rows_inserted = %d, want 2", rowsInserted)
+		}
+		if rowsUpdated != 0 {
+			t.Errorf("rows_updated = %d, want 0", rowsUpdated)
+		}
+		if source != "tiingo" {
+			t.Errorf("source = %q, want tiingo", source)
+		}
+		if symbolCount != 2 {
+			t.Errorf("symbol_count = %d, want 2", symbolCount)
+		}
+	})
+
+	t.Run("replaying the same batch is idempotent", func(t *testing.T) {
+		if _, err := repo.UpsertPricesWithBatch(ctx, batchID, prices); err != nil {
+			t.Fatalf("replay batch: %v", err)
+		}
+
+		var count int64
+		err := pool.QueryRow(ctx, `SELECT count(*) FROM price_history_batch WHERE batch_id = $1`, batchID).Scan(&count)
+		if err != nil {
+			t.Fatalf("count ledger rows: %v", err)
+		}
+		if count != 1 {
+			t.Errorf("ledger rows for batch = %d, want 1 (replay should not duplicate)", count)
+		}
+	})
 }
 
 func TestUpsertPrices_CheckViolation(t *testing.T) {
@@ -218,6 +424,157 @@ func TestUpsertFXRates(t *testing.T) {
 	})
 }
 
+func TestUpsertCorporateActions(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	t.Run("inserts new rows", func(t *testing.T) {
+		actions := []domain.CorporateAction{
+			{ExDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Kind: domain.CorporateActionSplit, SplitRatio: 4, Symbol: "NVDA"},
+			{CashAmount: 0.10, Currency: "USD", ExDate: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), Kind: domain.CorporateActionDividend, Symbol: "NVDA"},
+		}
+		affected, err := repo.UpsertCorporateActions(ctx, actions)
+		if err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+		if affected != 2 {
+			t.Errorf("rows affected = %d, want 2", affected)
+		}
+	})
+
+	t.Run("updates on duplicate key", func(t *testing.T) {
+		seed := []domain.CorporateAction{
+			{ExDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Kind: domain.CorporateActionSplit, SplitRatio: 2, Symbol: "META"},
+		}
+		if _, err := repo.UpsertCorporateActions(ctx, seed); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+
+		updated := []domain.CorporateAction{
+			{ExDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Kind: domain.CorporateActionSplit, SplitRatio: 3, Symbol: "META"},
+		}
+		if _, err := repo.UpsertCorporateActions(ctx, updated); err != nil {
+			t.Fatalf("upsert update: %v", err)
+		}
+
+		fetched, err := repo.FetchCorporateActions(ctx, "META",
+			time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("fetch: %v", err)
+		}
+		if len(fetched) != 1 {
+			t.Fatalf("actions len = %d, want 1", len(fetched))
+		}
+		if fetched[0].SplitRatio != 3 {
+			t.Errorf("split_ratio = %f, want 3", fetched[0].SplitRatio)
+		}
+	})
+
+	t.Run("empty slice returns zero", func(t *testing.T) {
+		affected, err := repo.UpsertCorporateActions(ctx, nil)
+		if err != nil {
+			t.Fatalf("upsert empty: %v", err)
+		}
+		if affected != 0 {
+			t.Errorf("rows affected = %d, want 0", affected)
+		}
+	})
+}
+
+func TestUpsertPricesAndCorporateActions(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	prices := []domain.DailyPrice{
+		{AdjClose: 30, Close: 30, Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), High: 31, Low: 29, Open: 30, Source: "tiingo", Symbol: "NVDA", Volume: 1000},
+	}
+	actions := []domain.CorporateAction{
+		{ExDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Kind: domain.CorporateActionSplit, SplitRatio: 10, Symbol: "NVDA"},
+	}
+
+	priceRows, actionRows, err := repo.UpsertPricesAndCorporateActions(ctx, prices, actions)
+	if err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if priceRows != 1 {
+		t.Errorf("price rows = %d, want 1", priceRows)
+	}
+	if actionRows != 1 {
+		t.Errorf("action rows = %d, want 1", actionRows)
+	}
+
+	history, err := repo.FetchPriceHistory(ctx, "NVDA",
+		time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("fetch prices: %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("history len = %d, want 1", len(history))
+	}
+
+	fetchedActions, err := repo.FetchCorporateActions(ctx, "NVDA",
+		time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("fetch actions: %v", err)
+	}
+	if len(fetchedActions) != 1 {
+		t.Fatalf("actions len = %d, want 1", len(fetchedActions))
+	}
+}
+
+func TestRecomputeAdjustedClose(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	prices := []domain.DailyPrice{
+		{AdjClose: 900, Close: 900, Date: time.Date(2024, 6, 7, 0, 0, 0, 0, time.UTC), High: 910, Low: 890, Open: 900, Source: "tiingo", Symbol: "NVDA", Volume: 1000},
+		{AdjClose: 95, Close: 95, Date: time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC), High: 100, Low: 90, Open: 95, Source: "tiingo", Symbol: "NVDA", Volume: 2000},
+	}
+	if _, err := repo.UpsertPrices(ctx, prices); err != nil {
+		t.Fatalf("seed prices: %v", err)
+	}
+
+	actions := []domain.CorporateAction{
+		{ExDate: time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC), Kind: domain.CorporateActionSplit, SplitRatio: 2, Symbol: "NVDA"},
+	}
+	if _, err := repo.UpsertCorporateActions(ctx, actions); err != nil {
+		t.Fatalf("seed corporate action: %v", err)
+	}
+
+	if err := repo.RecomputeAdjustedClose(ctx, "NVDA"); err != nil {
+		t.Fatalf("recompute adjusted close: %v", err)
+	}
+
+	history, err := repo.FetchPriceHistory(ctx, "NVDA",
+		time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("fetch prices: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("history len = %d, want 2", len(history))
+	}
+
+	var preSplit, postSplit domain.DailyPrice
+	for _, p := range history {
+		if p.Date.Equal(time.Date(2024, 6, 7, 0, 0, 0, 0, time.UTC)) {
+			preSplit = p
+		} else {
+			postSplit = p
+		}
+	}
+
+	if preSplit.AdjClose != preSplit.Close/2 {
+		t.Errorf("pre-split adj_close = %f, want %f (close %f halved)", preSplit.AdjClose, preSplit.Close/2, preSplit.Close)
+	}
+	if postSplit.AdjClose != postSplit.Close {
+		t.Errorf("post-split adj_close = %f, want %f (unadjusted)", postSplit.AdjClose, postSplit.Close)
+	}
+}
+
 func TestDetectGaps(t *testing.T) {
 	repo := setupRepository(t)
 	ctx := context.Background()
@@ -301,6 +658,103 @@ func TestDetectFXGaps(t *testing.T) {
 	}
 }
 
+func TestDetectMissingRanges(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	prices := []domain.DailyPrice{
+		{AdjClose: 100, Close: 100, Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), High: 110, Low: 90, Open: 100, Source: "tiingo", Symbol: "NVDA", Volume: 1000},
+		{AdjClose: 105, Close: 105, Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), High: 110, Low: 100, Open: 103, Source: "tiingo", Symbol: "NVDA", Volume: 2000},
+	}
+	if _, err := repo.UpsertPrices(ctx, prices); err != nil {
+		t.Fatalf("setup prices: %v", err)
+	}
+
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	t.Run("coalesces consecutive missing trading days", func(t *testing.T) {
+		ranges, err := repo.DetectMissingRanges(ctx, []string{"NVDA"}, from, to)
+		if err != nil {
+			t.Fatalf("detect missing ranges: %v", err)
+		}
+
+		nvdaRanges, ok := ranges["NVDA"]
+		if !ok {
+			t.Fatal("NVDA not found in ranges")
+		}
+		if len(nvdaRanges) != 1 {
+			t.Fatalf("len(nvdaRanges) = %d, want 1", len(nvdaRanges))
+		}
+
+		want := domain.DateRange{
+			Start: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC),
+		}
+		if !nvdaRanges[0].Start.Equal(want.Start) || !nvdaRanges[0].End.Equal(want.End) {
+			t.Errorf("nvdaRanges[0] = %+v, want %+v", nvdaRanges[0], want)
+		}
+	})
+
+	t.Run("symbol with no gaps is absent", func(t *testing.T) {
+		narrow := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		ranges, err := repo.DetectMissingRanges(ctx, []string{"NVDA"}, narrow, narrow)
+		if err != nil {
+			t.Fatalf("detect missing ranges: %v", err)
+		}
+		if _, ok := ranges["NVDA"]; ok {
+			t.Error("NVDA should not be in ranges (no gap in requested window)")
+		}
+	})
+
+	t.Run("empty symbols returns empty map", func(t *testing.T) {
+		ranges, err := repo.DetectMissingRanges(ctx, nil, from, to)
+		if err != nil {
+			t.Fatalf("detect missing ranges empty: %v", err)
+		}
+		if len(ranges) != 0 {
+			t.Errorf("ranges len = %d, want 0", len(ranges))
+		}
+	})
+}
+
+func TestDetectMissingFXRanges(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	rates := []domain.FXRate{
+		{Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Pair: "USD/KRW", Rate: 1305.50, Source: "frankfurter"},
+		{Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), Pair: "USD/KRW", Rate: 1310.25, Source: "frankfurter"},
+	}
+	if _, err := repo.UpsertFXRates(ctx, rates); err != nil {
+		t.Fatalf("setup fx rates: %v", err)
+	}
+
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	ranges, err := repo.DetectMissingFXRanges(ctx, []string{"USD/KRW"}, from, to)
+	if err != nil {
+		t.Fatalf("detect missing fx ranges: %v", err)
+	}
+
+	usdRanges, ok := ranges["USD/KRW"]
+	if !ok {
+		t.Fatal("USD/KRW not found in ranges")
+	}
+	if len(usdRanges) != 1 {
+		t.Fatalf("len(usdRanges) = %d, want 1", len(usdRanges))
+	}
+
+	want := domain.DateRange{
+		Start: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC),
+	}
+	if !usdRanges[0].Start.Equal(want.Start) || !usdRanges[0].End.Equal(want.End) {
+		t.Errorf("usdRanges[0] = %+v, want %+v", usdRanges[0], want)
+	}
+}
+
 func TestFetchPriceHistory(t *testing.T) {
 	repo := setupRepository(t)
 	ctx := context.Background()
@@ -435,3 +889,411 @@ func TestFetchFXRates(t *testing.T) {
 		}
 	})
 }
+
+func drainPriceEvents(t *testing.T, events <-chan store.PriceEvent) []domain.DailyPrice {
+	t.Helper()
+
+	var prices []domain.DailyPrice
+	for ev := range events {
+		if ev.Err != nil {
+			t.Fatalf("stream error: %v", ev.Err)
+		}
+		prices = append(prices, ev.Price)
+	}
+	return prices
+}
+
+func TestStreamPriceHistory(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	prices := []domain.DailyPrice{
+		{AdjClose: 100, Close: 100, Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), High: 110, Low: 90, Open: 100, Source: "tiingo", Symbol: "NVDA", Volume: 1000},
+		{AdjClose: 105, Close: 105, Date: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), High: 110, Low: 100, Open: 103, Source: "tiingo", Symbol: "NVDA", Volume: 2000},
+		{AdjClose: 110, Close: 110, Date: time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC), High: 115, Low: 105, Open: 107, Source: "tiingo", Symbol: "NVDA", Volume: 3000},
+		{AdjClose: 200, Close: 200, Date: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), High: 210, Low: 190, Open: 200, Source: "tiingo", Symbol: "META", Volume: 5000},
+	}
+	if _, err := repo.UpsertPrices(ctx, prices); err != nil {
+		t.Fatalf("setup prices: %v", err)
+	}
+
+	t.Run("pages across a small batch size", func(t *testing.T) {
+		events, err := repo.StreamPriceHistory(ctx, "NVDA",
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+			1)
+		if err != nil {
+			t.Fatalf("stream: %v", err)
+		}
+
+		got := drainPriceEvents(t, events)
+		if len(got) != 3 {
+			t.Fatalf("len(got) = %d, want 3", len(got))
+		}
+		for i := 1; i < len(got); i++ {
+			if got[i].Date.Before(got[i-1].Date) {
+				t.Error("expected ascending date order")
+			}
+		}
+	})
+
+	t.Run("batchSize <= 0 defaults and still excludes other symbols", func(t *testing.T) {
+		events, err := repo.StreamPriceHistory(ctx, "NVDA",
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+			0)
+		if err != nil {
+			t.Fatalf("stream: %v", err)
+		}
+
+		got := drainPriceEvents(t, events)
+		for _, p := range got {
+			if p.Symbol != "NVDA" {
+				t.Errorf("unexpected symbol %s in NVDA stream", p.Symbol)
+			}
+		}
+	})
+
+	t.Run("cancelled context stops the stream", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		events, err := repo.StreamPriceHistory(cctx, "NVDA",
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+			1)
+		if err != nil {
+			t.Fatalf("stream: %v", err)
+		}
+		cancel()
+
+		for range events {
+			// Draining to confirm the channel is closed rather than hanging.
+		}
+	})
+}
+
+func TestStreamPricesMulti(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	prices := []domain.DailyPrice{
+		{AdjClose: 100, Close: 100, Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), High: 110, Low: 90, Open: 100, Source: "tiingo", Symbol: "NVDA", Volume: 1000},
+		{AdjClose: 200, Close: 200, Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), High: 210, Low: 190, Open: 200, Source: "tiingo", Symbol: "META", Volume: 5000},
+		{AdjClose: 105, Close: 105, Date: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), High: 110, Low: 100, Open: 103, Source: "tiingo", Symbol: "NVDA", Volume: 2000},
+	}
+	if _, err := repo.UpsertPrices(ctx, prices); err != nil {
+		t.Fatalf("setup prices: %v", err)
+	}
+
+	events, err := repo.StreamPricesMulti(ctx, []string{"NVDA", "META"},
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		2)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+
+	got := drainPriceEvents(t, events)
+	if len(got) != 3 {
+		t.Fatalf("len(got) = %d, want 3", len(got))
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].Date.Before(got[i-1].Date) {
+			t.Error("expected ascending date order across symbols")
+		}
+	}
+	if !got[0].Date.Equal(got[1].Date) || got[0].Symbol >= got[1].Symbol {
+		t.Errorf("same-date rows should interleave symbol-ascending, got %s then %s", got[0].Symbol, got[1].Symbol)
+	}
+}
+
+func TestStreamFXRates(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	rates := []domain.FXRate{
+		{Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Pair: "USD/KRW", Rate: 1305.50, Source: "frankfurter"},
+		{Date: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), Pair: "USD/KRW", Rate: 1310.25, Source: "frankfurter"},
+	}
+	if _, err := repo.UpsertFXRates(ctx, rates); err != nil {
+		t.Fatalf("setup fx rates: %v", err)
+	}
+
+	events, err := repo.StreamFXRates(ctx, "USD/KRW",
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		1)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+
+	var got []domain.FXRate
+	for ev := range events {
+		if ev.Err != nil {
+			t.Fatalf("stream error: %v", ev.Err)
+		}
+		got = append(got, ev.Rate)
+	}
+	if len(got) != 2 {
+		t.Fatalf("len(got) = %d, want 2", len(got))
+	}
+	if got[0].Date.After(got[1].Date) {
+		t.Error("expected ascending date order")
+	}
+}
+
+func TestFetchCorporateActions(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	actions := []domain.CorporateAction{
+		{ExDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Kind: domain.CorporateActionSplit, SplitRatio: 4, Symbol: "NVDA"},
+		{CashAmount: 0.10, Currency: "USD", ExDate: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), Kind: domain.CorporateActionDividend, Symbol: "NVDA"},
+		{ExDate: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), Kind: domain.CorporateActionSplit, SplitRatio: 2, Symbol: "META"},
+	}
+	if _, err := repo.UpsertCorporateActions(ctx, actions); err != nil {
+		t.Fatalf("setup corporate actions: %v", err)
+	}
+
+	t.Run("filters by symbol and date range, sorted ascending", func(t *testing.T) {
+		fetched, err := repo.FetchCorporateActions(ctx, "NVDA",
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("fetch: %v", err)
+		}
+		if len(fetched) != 2 {
+			t.Fatalf("actions len = %d, want 2", len(fetched))
+		}
+		if fetched[0].ExDate.After(fetched[1].ExDate) {
+			t.Error("expected ascending ex_date order")
+		}
+		if fetched[0].Kind != domain.CorporateActionSplit {
+			t.Errorf("kind = %s, want split", fetched[0].Kind)
+		}
+	})
+
+	t.Run("returns empty for no matches", func(t *testing.T) {
+		fetched, err := repo.FetchCorporateActions(ctx, "ASML",
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("fetch: %v", err)
+		}
+		if len(fetched) != 0 {
+			t.Errorf("actions len = %d, want 0", len(fetched))
+		}
+	})
+}
+
+func TestUpsertMarginLoans(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	t.Run("inserts new rows", func(t *testing.T) {
+		loans := []domain.MarginLoan{
+			{AccountNo: "1234567801", IssuedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), LoanID: "L1", Principal: 5000000, Status: domain.MarginLoanOpen, Symbol: "NVDA"},
+		}
+		affected, err := repo.UpsertMarginLoans(ctx, loans)
+		if err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+		if affected != 1 {
+			t.Errorf("rows affected = %d, want 1", affected)
+		}
+	})
+
+	t.Run("updates status on duplicate key", func(t *testing.T) {
+		seed := []domain.MarginLoan{
+			{AccountNo: "1234567801", IssuedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), LoanID: "L2", Principal: 1000000, Status: domain.MarginLoanOpen, Symbol: "META"},
+		}
+		if _, err := repo.UpsertMarginLoans(ctx, seed); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+
+		updated := []domain.MarginLoan{
+			{AccountNo: "1234567801", IssuedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), LoanID: "L2", Principal: 1000000, Status: domain.MarginLoanClosed, Symbol: "META"},
+		}
+		if _, err := repo.UpsertMarginLoans(ctx, updated); err != nil {
+			t.Fatalf("upsert update: %v", err)
+		}
+
+		loans, err := repo.FetchOutstandingMarginLoans(ctx, "1234567801")
+		if err != nil {
+			t.Fatalf("fetch outstanding: %v", err)
+		}
+		for _, l := range loans {
+			if l.LoanID == "L2" {
+				t.Error("L2 should no longer be outstanding after status update")
+			}
+		}
+	})
+
+	t.Run("empty slice returns zero", func(t *testing.T) {
+		affected, err := repo.UpsertMarginLoans(ctx, nil)
+		if err != nil {
+			t.Fatalf("upsert empty: %v", err)
+		}
+		if affected != 0 {
+			t.Errorf("rows affected = %d, want 0", affected)
+		}
+	})
+}
+
+func TestUpsertMarginInterest(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	entries := []domain.MarginInterest{
+		{AccountNo: "1234567801", Amount: 1234.5, Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Rate: 0.07},
+		{AccountNo: "1234567801", Amount: 1300, Date: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), Rate: 0.07},
+	}
+	if _, err := repo.UpsertMarginInterest(ctx, entries); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+
+	fetched, err := repo.FetchMarginInterest(ctx, "1234567801",
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if len(fetched) != 2 {
+		t.Fatalf("entries len = %d, want 2", len(fetched))
+	}
+	if fetched[0].Date.After(fetched[1].Date) {
+		t.Error("expected ascending date order")
+	}
+}
+
+func TestUpsertMarginRepayments(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	repayments := []domain.MarginRepayment{
+		{AccountNo: "1234567801", Amount: 1000000, Date: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), LoanID: "L1"},
+	}
+	if _, err := repo.UpsertMarginRepayments(ctx, repayments); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+
+	fetched, err := repo.FetchMarginRepayments(ctx, "1234567801",
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if len(fetched) != 1 {
+		t.Fatalf("repayments len = %d, want 1", len(fetched))
+	}
+	if fetched[0].Amount != 1000000 {
+		t.Errorf("amount = %f, want 1000000", fetched[0].Amount)
+	}
+}
+
+func TestDetectMarginGaps(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	loans := []domain.MarginLoan{
+		{AccountNo: "1234567801", IssuedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), LoanID: "L1", Principal: 5000000, Status: domain.MarginLoanOpen, Symbol: "NVDA"},
+	}
+	interest := []domain.MarginInterest{
+		{AccountNo: "1234567801", Amount: 1234.5, Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), Rate: 0.07},
+	}
+	if _, err := repo.UpsertMarginLoans(ctx, loans); err != nil {
+		t.Fatalf("setup loans: %v", err)
+	}
+	if _, err := repo.UpsertMarginInterest(ctx, interest); err != nil {
+		t.Fatalf("setup interest: %v", err)
+	}
+
+	t.Run("returns max date across all margin tables per account", func(t *testing.T) {
+		gaps, err := repo.DetectMarginGaps(ctx, []string{"1234567801", "9999999901"})
+		if err != nil {
+			t.Fatalf("detect margin gaps: %v", err)
+		}
+
+		lastDate, ok := gaps["1234567801"]
+		if !ok {
+			t.Fatal("1234567801 not found in gaps")
+		}
+		want := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+		if !lastDate.Equal(want) {
+			t.Errorf("last date = %v, want %v", lastDate, want)
+		}
+
+		if _, ok := gaps["9999999901"]; ok {
+			t.Error("9999999901 should not be in gaps (no data)")
+		}
+	})
+
+	t.Run("empty accounts returns empty map", func(t *testing.T) {
+		gaps, err := repo.DetectMarginGaps(ctx, nil)
+		if err != nil {
+			t.Fatalf("detect margin gaps empty: %v", err)
+		}
+		if len(gaps) != 0 {
+			t.Errorf("gaps len = %d, want 0", len(gaps))
+		}
+	})
+}
+
+func TestUpsertSummaryRisk(t *testing.T) {
+	pool := connectAndClean(t)
+	t.Cleanup(pool.Close)
+	ctx := context.Background()
+
+	if err := store.RunMigrations(ctx, pool); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+	repo := store.NewRepository(pool)
+
+	cagr, sharpe := 23.5, 1.2
+	asOf := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	t.Run("inserts new rows", func(t *testing.T) {
+		metrics := []domain.SummaryRiskMetric{
+			{AsOf: asOf, CAGR: &cagr, Sharpe: &sharpe, Symbol: "NVDA"},
+		}
+		affected, err := repo.UpsertSummaryRisk(ctx, metrics)
+		if err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+		if affected != 1 {
+			t.Errorf("rows affected = %d, want 1", affected)
+		}
+	})
+
+	t.Run("updates metrics on duplicate symbol/as_of", func(t *testing.T) {
+		updatedCAGR := 30.0
+		metrics := []domain.SummaryRiskMetric{
+			{AsOf: asOf, CAGR: &updatedCAGR, Symbol: "NVDA"},
+		}
+		if _, err := repo.UpsertSummaryRisk(ctx, metrics); err != nil {
+			t.Fatalf("upsert update: %v", err)
+		}
+
+		var gotCAGR float64
+		var gotSharpe *float64
+		err := pool.QueryRow(ctx, `SELECT cagr, sharpe FROM summary_risk WHERE symbol = $1 AND as_of = $2`, "NVDA", asOf).Scan(&gotCAGR, &gotSharpe)
+		if err != nil {
+			t.Fatalf("query: %v", err)
+		}
+		if gotCAGR != 30.0 {
+			t.Errorf("cagr = %v, want 30.0", gotCAGR)
+		}
+		if gotSharpe != nil {
+			t.Errorf("sharpe = %v, want nil (overwritten by the second upsert)", *gotSharpe)
+		}
+	})
+
+	t.Run("empty metrics is a no-op", func(t *testing.T) {
+		affected, err := repo.UpsertSummaryRisk(ctx, nil)
+		if err != nil {
+			t.Fatalf("upsert empty: %v", err)
+		}
+		if affected != 0 {
+			t.Errorf("rows affected = %d, want 0", affected)
+		}
+	})
+}
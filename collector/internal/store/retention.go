@@ -0,0 +1,193 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// RunRetention downsamples and prunes raw rows for each table named in rules.
+// Each symbol (or currency pair) is processed in its own transaction: a
+// weekly rollup is upserted from rows older than KeepRawDays, those raw rows
+// are then deleted, and weekly rollups older than KeepDownsampledDays are
+// pruned in turn. Mirrors InfluxDB's retention-policy + continuous-query
+// model (EXTERNAL DOC 4, 7, 11) for a plain Postgres backend.
+func RunRetention(ctx context.Context, pool *pgxpool.Pool, rules []domain.RetentionRule) error {
+	for _, rule := range rules {
+		switch rule.Table {
+		case "price_history":
+			if err := runPriceRetention(ctx, pool, rule); err != nil {
+				return fmt.Errorf("run retention for price_history: %w", err)
+			}
+		case "fx_rate":
+			if err := runFXRetention(ctx, pool, rule); err != nil {
+				return fmt.Errorf("run retention for fx_rate: %w", err)
+			}
+		default:
+			return fmt.Errorf("unsupported retention table %q", rule.Table)
+		}
+	}
+	return nil
+}
+
+func runPriceRetention(ctx context.Context, pool *pgxpool.Pool, rule domain.RetentionRule) error {
+	symbols, err := distinctColumn(ctx, pool, "SELECT DISTINCT symbol FROM price_history")
+	if err != nil {
+		return fmt.Errorf("list symbols: %w", err)
+	}
+
+	for _, symbol := range symbols {
+		if err := runPriceRetentionForSymbol(ctx, pool, symbol, rule); err != nil {
+			return fmt.Errorf("symbol %s: %w", symbol, err)
+		}
+	}
+	return nil
+}
+
+func runPriceRetentionForSymbol(ctx context.Context, pool *pgxpool.Pool, symbol string, rule domain.RetentionRule) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO price_history_weekly (symbol, week_start, open, high, low, close, adj_close, volume, source)
+		SELECT DISTINCT
+			symbol,
+			date_trunc('week', date)::date AS week_start,
+			FIRST_VALUE(open) OVER w AS open,
+			MAX(high) OVER w AS high,
+			MIN(low) OVER w AS low,
+			LAST_VALUE(close) OVER w AS close,
+			LAST_VALUE(adj_close) OVER w AS adj_close,
+			SUM(volume) OVER w AS volume,
+			FIRST_VALUE(source) OVER w AS source
+		FROM price_history
+		WHERE symbol = $1 AND date < CURRENT_DATE - make_interval(days => $2)
+		WINDOW w AS (
+			PARTITION BY symbol, date_trunc('week', date)
+			ORDER BY date
+			ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING
+		)
+		ON CONFLICT (symbol, week_start) DO UPDATE SET
+			open = EXCLUDED.open,
+			high = EXCLUDED.high,
+			low = EXCLUDED.low,
+			close = EXCLUDED.close,
+			adj_close = EXCLUDED.adj_close,
+			volume = EXCLUDED.volume,
+			source = EXCLUDED.source
+	`, symbol, rule.KeepRawDays)
+	if err != nil {
+		return fmt.Errorf("upsert weekly rollup: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM price_history WHERE symbol = $1 AND date < CURRENT_DATE - make_interval(days => $2)`,
+		symbol, rule.KeepRawDays,
+	); err != nil {
+		return fmt.Errorf("delete raw rows: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM price_history_weekly WHERE symbol = $1 AND week_start < CURRENT_DATE - make_interval(days => $2)`,
+		symbol, rule.KeepDownsampledDays,
+	); err != nil {
+		return fmt.Errorf("delete expired weekly rollups: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit retention for symbol %s: %w", symbol, err)
+	}
+	return nil
+}
+
+func runFXRetention(ctx context.Context, pool *pgxpool.Pool, rule domain.RetentionRule) error {
+	pairs, err := distinctColumn(ctx, pool, "SELECT DISTINCT pair FROM fx_rate")
+	if err != nil {
+		return fmt.Errorf("list pairs: %w", err)
+	}
+
+	for _, pair := range pairs {
+		if err := runFXRetentionForPair(ctx, pool, pair, rule); err != nil {
+			return fmt.Errorf("pair %s: %w", pair, err)
+		}
+	}
+	return nil
+}
+
+func runFXRetentionForPair(ctx context.Context, pool *pgxpool.Pool, pair string, rule domain.RetentionRule) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO fx_rate_weekly (pair, week_start, rate_avg, rate_high, rate_low, source)
+		SELECT
+			pair,
+			date_trunc('week', date)::date AS week_start,
+			AVG(rate) AS rate_avg,
+			MAX(rate) AS rate_high,
+			MIN(rate) AS rate_low,
+			MIN(source) AS source
+		FROM fx_rate
+		WHERE pair = $1 AND date < CURRENT_DATE - make_interval(days => $2)
+		GROUP BY pair, date_trunc('week', date)
+		ON CONFLICT (pair, week_start) DO UPDATE SET
+			rate_avg = EXCLUDED.rate_avg,
+			rate_high = EXCLUDED.rate_high,
+			rate_low = EXCLUDED.rate_low,
+			source = EXCLUDED.source
+	`, pair, rule.KeepRawDays)
+	if err != nil {
+		return fmt.Errorf("upsert weekly rollup: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM fx_rate WHERE pair = $1 AND date < CURRENT_DATE - make_interval(days => $2)`,
+		pair, rule.KeepRawDays,
+	); err != nil {
+		return fmt.Errorf("delete raw rows: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM fx_rate_weekly WHERE pair = $1 AND week_start < CURRENT_DATE - make_interval(days => $2)`,
+		pair, rule.KeepDownsampledDays,
+	); err != nil {
+		return fmt.Errorf("delete expired weekly rollups: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit retention for pair %s: %w", pair, err)
+	}
+	return nil
+}
+
+// distinctColumn runs a fixed, internally-defined single-column SELECT
+// DISTINCT query and returns the results as strings.
+func distinctColumn(ctx context.Context, pool *pgxpool.Pool, query string) ([]string, error) {
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		values = append(values, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,115 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/store"
+)
+
+func setupRepositoryWithPool(t *testing.T) (*store.Repository, *pgxpool.Pool) {
+	t.Helper()
+	pool := connectAndClean(t)
+	t.Cleanup(pool.Close)
+	ctx := context.Background()
+
+	if err := store.RunMigrations(ctx, pool); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	return store.NewRepository(pool), pool
+}
+
+func TestRunRetention_PriceHistory(t *testing.T) {
+	repo, dbPool := setupRepositoryWithPool(t)
+	ctx := context.Background()
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	var prices []domain.DailyPrice
+	for i := range 800 {
+		date := now.AddDate(0, 0, -i)
+		prices = append(prices, domain.DailyPrice{
+			AdjClose: 100 + float64(i), Close: 100 + float64(i),
+			Date: date, High: 105 + float64(i), Low: 95 + float64(i), Open: 100 + float64(i),
+			Source: "tiingo", Symbol: "NVDA", Volume: 1000,
+		})
+	}
+	if _, err := repo.UpsertPrices(ctx, prices); err != nil {
+		t.Fatalf("seed prices: %v", err)
+	}
+
+	rules := []domain.RetentionRule{
+		{DownsampleTo: "weekly", KeepDownsampledDays: 7300, KeepRawDays: 365, Table: "price_history"},
+	}
+
+	if err := store.RunRetention(ctx, dbPool, rules); err != nil {
+		t.Fatalf("run retention: %v", err)
+	}
+
+	cutoff := now.AddDate(0, 0, -365)
+
+	raw, err := repo.FetchPriceHistory(ctx, "NVDA", now.AddDate(-5, 0, 0), now)
+	if err != nil {
+		t.Fatalf("fetch price history: %v", err)
+	}
+	for _, p := range raw {
+		if p.Date.Before(cutoff) {
+			t.Errorf("raw row for %s should have been deleted (cutoff %s)", p.Date, cutoff)
+		}
+	}
+
+	var rollupRows int
+	if err := dbPool.QueryRow(ctx, `SELECT COUNT(*) FROM price_history_weekly WHERE symbol = $1`, "NVDA").Scan(&rollupRows); err != nil {
+		t.Fatalf("count weekly rollups: %v", err)
+	}
+	if rollupRows == 0 {
+		t.Error("expected weekly rollup rows for NVDA, got none")
+	}
+}
+
+func TestRunRetention_FXRate(t *testing.T) {
+	repo, dbPool := setupRepositoryWithPool(t)
+	ctx := context.Background()
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	var rates []domain.FXRate
+	for i := range 800 {
+		rates = append(rates, domain.FXRate{
+			Date: now.AddDate(0, 0, -i), Pair: "USD/KRW", Rate: 1300 + float64(i), Source: "frankfurter",
+		})
+	}
+	if _, err := repo.UpsertFXRates(ctx, rates); err != nil {
+		t.Fatalf("seed fx rates: %v", err)
+	}
+
+	rules := []domain.RetentionRule{
+		{DownsampleTo: "weekly", KeepDownsampledDays: 7300, KeepRawDays: 365, Table: "fx_rate"},
+	}
+
+	if err := store.RunRetention(ctx, dbPool, rules); err != nil {
+		t.Fatalf("run retention: %v", err)
+	}
+
+	cutoff := now.AddDate(0, 0, -365)
+
+	raw, err := repo.FetchFXRates(ctx, "USD/KRW", now.AddDate(-5, 0, 0), now)
+	if err != nil {
+		t.Fatalf("fetch fx rates: %v", err)
+	}
+	for _, r := range raw {
+		if r.Date.Before(cutoff) {
+			t.Errorf("raw row for %s should have been deleted (cutoff %s)", r.Date, cutoff)
+		}
+	}
+
+	var rollupRows int
+	if err := dbPool.QueryRow(ctx, `SELECT COUNT(*) FROM fx_rate_weekly WHERE pair = $1`, "USD/KRW").Scan(&rollupRows); err != nil {
+		t.Fatalf("count weekly rollups: %v", err)
+	}
+	if rollupRows == 0 {
+		t.Error("expected weekly rollup rows for USD/KRW, got none")
+	}
+}
@@ -0,0 +1,179 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// UpsertTicks bulk-inserts or updates tick via the same temp table pattern as
+// UpsertPrices, so real-time streaming and batch collection share one write path.
+func (r *Repository) UpsertTicks(ctx context.Context, ticks []domain.Tick) (int64, error) {
+	if len(ticks) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin upsert ticks: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE tmp_ticks (
+			price  DOUBLE PRECISION NOT NULL,
+			symbol TEXT             NOT NULL,
+			ts     TIMESTAMPTZ      NOT NULL,
+			volume BIGINT           NOT NULL
+		) ON COMMIT DROP
+	`); err != nil {
+		return 0, fmt.Errorf("create temp tick table: %w", err)
+	}
+
+	columns := []string{"price", "symbol", "ts", "volume"}
+	if _, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"tmp_ticks"},
+		columns,
+		pgx.CopyFromSlice(len(ticks), func(i int) ([]any, error) {
+			t := ticks[i]
+			return []any{t.Price, t.Symbol, t.Timestamp, t.Volume}, nil
+		}),
+	); err != nil {
+		return 0, fmt.Errorf("copy ticks to temp table: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO tick (price, symbol, ts, volume)
+		SELECT price, symbol, ts, volume
+		FROM tmp_ticks
+		ON CONFLICT (symbol, ts) DO UPDATE SET
+			price  = EXCLUDED.price,
+			volume = EXCLUDED.volume
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("upsert ticks from temp table: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit upsert ticks: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// UpsertOrderBookSnapshots bulk-inserts or updates order_book_snapshot via the
+// same temp table pattern as UpsertPrices.
+func (r *Repository) UpsertOrderBookSnapshots(ctx context.Context, snapshots []domain.OrderBookSnapshot) (int64, error) {
+	if len(snapshots) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin upsert order book snapshots: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE tmp_order_book_snapshots (
+			ask_prices     DOUBLE PRECISION[] NOT NULL,
+			ask_quantities BIGINT[]            NOT NULL,
+			bid_prices     DOUBLE PRECISION[] NOT NULL,
+			bid_quantities BIGINT[]            NOT NULL,
+			symbol         TEXT                NOT NULL,
+			ts             TIMESTAMPTZ         NOT NULL
+		) ON COMMIT DROP
+	`); err != nil {
+		return 0, fmt.Errorf("create temp order_book_snapshot table: %w", err)
+	}
+
+	columns := []string{"ask_prices", "ask_quantities", "bid_prices", "bid_quantities", "symbol", "ts"}
+	if _, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"tmp_order_book_snapshots"},
+		columns,
+		pgx.CopyFromSlice(len(snapshots), func(i int) ([]any, error) {
+			s := snapshots[i]
+			askPrices, askQuantities := splitOrderBookLevels(s.Asks)
+			bidPrices, bidQuantities := splitOrderBookLevels(s.Bids)
+			return []any{askPrices, askQuantities, bidPrices, bidQuantities, s.Symbol, s.Timestamp}, nil
+		}),
+	); err != nil {
+		return 0, fmt.Errorf("copy order book snapshots to temp table: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO order_book_snapshot (ask_prices, ask_quantities, bid_prices, bid_quantities, symbol, ts)
+		SELECT ask_prices, ask_quantities, bid_prices, bid_quantities, symbol, ts
+		FROM tmp_order_book_snapshots
+		ON CONFLICT (symbol, ts) DO UPDATE SET
+			ask_prices     = EXCLUDED.ask_prices,
+			ask_quantities = EXCLUDED.ask_quantities,
+			bid_prices     = EXCLUDED.bid_prices,
+			bid_quantities = EXCLUDED.bid_quantities
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("upsert order book snapshots from temp table: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit upsert order book snapshots: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// FetchOrderBookSnapshots retrieves order book snapshots for a symbol within a
+// date range, sorted ascending by ts.
+func (r *Repository) FetchOrderBookSnapshots(ctx context.Context, symbol string, from, to time.Time) ([]domain.OrderBookSnapshot, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT ask_prices, ask_quantities, bid_prices, bid_quantities, symbol, ts
+		FROM order_book_snapshot
+		WHERE symbol = $1 AND ts >= $2 AND ts <= $3
+		ORDER BY ts ASC
+	`, symbol, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("fetch order book snapshots for %s: %w", symbol, err)
+	}
+	defer rows.Close()
+
+	snapshots := make([]domain.OrderBookSnapshot, 0)
+	for rows.Next() {
+		var askPrices, bidPrices []float64
+		var askQuantities, bidQuantities []int64
+		var s domain.OrderBookSnapshot
+		if err := rows.Scan(&askPrices, &askQuantities, &bidPrices, &bidQuantities, &s.Symbol, &s.Timestamp); err != nil {
+			return nil, fmt.Errorf("scan order book snapshot row: %w", err)
+		}
+		s.Asks = joinOrderBookLevels(askPrices, askQuantities)
+		s.Bids = joinOrderBookLevels(bidPrices, bidQuantities)
+		snapshots = append(snapshots, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate order book snapshot rows: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+func joinOrderBookLevels(prices []float64, quantities []int64) []domain.OrderBookLevel {
+	levels := make([]domain.OrderBookLevel, len(prices))
+	for i, price := range prices {
+		levels[i] = domain.OrderBookLevel{Price: price, Quantity: quantities[i]}
+	}
+	return levels
+}
+
+func splitOrderBookLevels(levels []domain.OrderBookLevel) ([]float64, []int64) {
+	prices := make([]float64, len(levels))
+	quantities := make([]int64, len(levels))
+	for i, l := range levels {
+		prices[i] = l.Price
+		quantities[i] = l.Quantity
+	}
+	return prices, quantities
+}
@@ -0,0 +1,150 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func TestUpsertTicks(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	t.Run("inserts new rows", func(t *testing.T) {
+		ticks := []domain.Tick{
+			{Price: 496.30, Symbol: "NVDA", Timestamp: time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC), Volume: 1500},
+			{Price: 496.40, Symbol: "NVDA", Timestamp: time.Date(2024, 1, 2, 9, 0, 1, 0, time.UTC), Volume: 800},
+		}
+		affected, err := repo.UpsertTicks(ctx, ticks)
+		if err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+		if affected != 2 {
+			t.Errorf("rows affected = %d, want 2", affected)
+		}
+	})
+
+	t.Run("updates on duplicate key", func(t *testing.T) {
+		ts := time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC)
+		seed := []domain.Tick{{Price: 100, Symbol: "META", Timestamp: ts, Volume: 10}}
+		if _, err := repo.UpsertTicks(ctx, seed); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+
+		updated := []domain.Tick{{Price: 101, Symbol: "META", Timestamp: ts, Volume: 20}}
+		if _, err := repo.UpsertTicks(ctx, updated); err != nil {
+			t.Fatalf("upsert update: %v", err)
+		}
+	})
+
+	t.Run("empty slice returns zero", func(t *testing.T) {
+		affected, err := repo.UpsertTicks(ctx, nil)
+		if err != nil {
+			t.Fatalf("upsert empty: %v", err)
+		}
+		if affected != 0 {
+			t.Errorf("rows affected = %d, want 0", affected)
+		}
+	})
+
+	t.Run("zero price rejects", func(t *testing.T) {
+		bad := []domain.Tick{{Price: 0, Symbol: "NVDA", Timestamp: time.Now(), Volume: 1}}
+		_, err := repo.UpsertTicks(ctx, bad)
+		if err == nil {
+			t.Error("expected CHECK constraint violation, got nil")
+		}
+	})
+}
+
+func TestUpsertOrderBookSnapshots(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	t.Run("inserts new rows", func(t *testing.T) {
+		snapshots := []domain.OrderBookSnapshot{
+			{
+				Asks:      []domain.OrderBookLevel{{Price: 497.0, Quantity: 100}, {Price: 497.5, Quantity: 50}},
+				Bids:      []domain.OrderBookLevel{{Price: 496.5, Quantity: 200}},
+				Symbol:    "NVDA",
+				Timestamp: time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC),
+			},
+		}
+		affected, err := repo.UpsertOrderBookSnapshots(ctx, snapshots)
+		if err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+		if affected != 1 {
+			t.Errorf("rows affected = %d, want 1", affected)
+		}
+	})
+
+	t.Run("empty slice returns zero", func(t *testing.T) {
+		affected, err := repo.UpsertOrderBookSnapshots(ctx, nil)
+		if err != nil {
+			t.Fatalf("upsert empty: %v", err)
+		}
+		if affected != 0 {
+			t.Errorf("rows affected = %d, want 0", affected)
+		}
+	})
+}
+
+func TestFetchOrderBookSnapshots(t *testing.T) {
+	repo := setupRepository(t)
+	ctx := context.Background()
+
+	seed := []domain.OrderBookSnapshot{
+		{
+			Asks:      []domain.OrderBookLevel{{Price: 497.0, Quantity: 100}},
+			Bids:      []domain.OrderBookLevel{{Price: 496.5, Quantity: 200}},
+			Symbol:    "005930",
+			Timestamp: time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			Asks:      []domain.OrderBookLevel{{Price: 498.0, Quantity: 80}},
+			Bids:      []domain.OrderBookLevel{{Price: 497.5, Quantity: 150}},
+			Symbol:    "005930",
+			Timestamp: time.Date(2024, 1, 2, 9, 0, 5, 0, time.UTC),
+		},
+	}
+	if _, err := repo.UpsertOrderBookSnapshots(ctx, seed); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	t.Run("returns snapshots sorted ascending within range", func(t *testing.T) {
+		from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+
+		snapshots, err := repo.FetchOrderBookSnapshots(ctx, "005930", from, to)
+		if err != nil {
+			t.Fatalf("fetch: %v", err)
+		}
+		if len(snapshots) != 2 {
+			t.Fatalf("len(snapshots) = %d, want 2", len(snapshots))
+		}
+		if !snapshots[0].Timestamp.Before(snapshots[1].Timestamp) {
+			t.Errorf("snapshots not sorted ascending")
+		}
+		if snapshots[0].Bids[0].Price != 496.5 {
+			t.Errorf("Bids[0].Price = %v, want 496.5", snapshots[0].Bids[0].Price)
+		}
+		if snapshots[0].Asks[0].Quantity != 100 {
+			t.Errorf("Asks[0].Quantity = %v, want 100", snapshots[0].Asks[0].Quantity)
+		}
+	})
+
+	t.Run("no rows in range returns empty slice", func(t *testing.T) {
+		from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+
+		snapshots, err := repo.FetchOrderBookSnapshots(ctx, "005930", from, to)
+		if err != nil {
+			t.Fatalf("fetch: %v", err)
+		}
+		if len(snapshots) != 0 {
+			t.Errorf("len(snapshots) = %d, want 0", len(snapshots))
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package store
+
+import (
+	"context"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// TSDBWriter fans out collected prices and FX rates to a time-series database
+// in parallel with Repository's Postgres writes, for dashboards that want
+// native TSDB query tooling instead of ad-hoc SQL over price_history/fx_rate.
+type TSDBWriter interface {
+	WriteFXRates(ctx context.Context, rates []domain.FXRate) error
+	WritePrices(ctx context.Context, prices []domain.DailyPrice) error
+}
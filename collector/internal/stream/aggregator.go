@@ -0,0 +1,96 @@
+package stream
+
+import (
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// bar is an in-progress daily bar for one symbol, built up tick by tick.
+type bar struct {
+	close time.Time
+	price domain.DailyPrice
+}
+
+// Aggregator rolls a real-time tick stream into domain.DailyPrice bars, one per
+// symbol per trading session. It holds no goroutines or channels of its own —
+// a Streamer calls Add for every tick it receives and forwards the bar Add
+// returns, if any, to its output channel.
+type Aggregator struct {
+	calendar MarketCalendar
+	source   string
+
+	bars map[string]*bar
+}
+
+// NewAggregator creates an Aggregator that closes sessions per calendar and
+// stamps completed bars with source (e.g. "tiingo", "kis").
+func NewAggregator(calendar MarketCalendar, source string) *Aggregator {
+	return &Aggregator{
+		calendar: calendar,
+		source:   source,
+		bars:     make(map[string]*bar),
+	}
+}
+
+// Add folds t into its symbol's in-progress bar. If t's timestamp falls in a
+// later session than the bar currently held for that symbol, the prior
+// session's bar is completed and returned (ok is true) before t starts a new
+// one; otherwise Add returns a zero value and false.
+func (a *Aggregator) Add(t domain.Tick) (domain.DailyPrice, bool) {
+	existing, ok := a.bars[t.Symbol]
+	if ok && t.Timestamp.Before(existing.close) {
+		updateBar(existing, t)
+		return domain.DailyPrice{}, false
+	}
+
+	a.bars[t.Symbol] = newBar(a.calendar, a.source, t)
+
+	if !ok {
+		return domain.DailyPrice{}, false
+	}
+	return existing.price, true
+}
+
+// Flush returns every in-progress bar as a completed DailyPrice, for use at
+// shutdown when a session's close was never reached. The Aggregator is left
+// empty afterward.
+func (a *Aggregator) Flush() []domain.DailyPrice {
+	prices := make([]domain.DailyPrice, 0, len(a.bars))
+	for _, b := range a.bars {
+		prices = append(prices, b.price)
+	}
+	a.bars = make(map[string]*bar)
+	return prices
+}
+
+func newBar(calendar MarketCalendar, source string, t domain.Tick) *bar {
+	return &bar{
+		close: calendar.Close(t.Timestamp),
+		price: domain.DailyPrice{
+			AdjClose:  t.Price,
+			Close:     t.Price,
+			Date:      t.Timestamp,
+			FetchedAt: t.Timestamp,
+			High:      t.Price,
+			Low:       t.Price,
+			Open:      t.Price,
+			Source:    source,
+			Symbol:    t.Symbol,
+			Volume:    t.Volume,
+		},
+	}
+}
+
+func updateBar(b *bar, t domain.Tick) {
+	b.price.AdjClose = t.Price
+	b.price.Close = t.Price
+	b.price.FetchedAt = t.Timestamp
+	if t.Price > b.price.High {
+		b.price.High = t.Price
+	}
+	if t.Price < b.price.Low {
+		b.price.Low = t.Price
+	}
+	b.price.Volume += t.Volume
+}
@@ -0,0 +1,60 @@
+package stream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func TestAggregator_AddRollsIntoDailyBar(t *testing.T) {
+	agg := NewAggregator(NewUSMarketCalendar(), "tiingo")
+
+	loc, _ := time.LoadLocation("America/New_York")
+	day1 := time.Date(2024, 1, 2, 10, 0, 0, 0, loc)
+
+	if _, ok := agg.Add(domain.Tick{Symbol: "AAPL", Price: 190, Volume: 100, Timestamp: day1}); ok {
+		t.Fatal("first tick should not complete a bar")
+	}
+	if _, ok := agg.Add(domain.Tick{Symbol: "AAPL", Price: 195, Volume: 50, Timestamp: day1.Add(time.Hour)}); ok {
+		t.Fatal("second tick in the same session should not complete a bar")
+	}
+	if _, ok := agg.Add(domain.Tick{Symbol: "AAPL", Price: 200, Volume: 10, Timestamp: day1.Add(-time.Hour)}); ok {
+		t.Fatal("out-of-order tick within the same session should not complete a bar")
+	}
+
+	day2 := day1.Add(24 * time.Hour)
+	price, ok := agg.Add(domain.Tick{Symbol: "AAPL", Price: 205, Volume: 20, Timestamp: day2})
+	if !ok {
+		t.Fatal("tick in a new session should complete the prior bar")
+	}
+
+	if price.Open != 190 || price.Close != 200 {
+		t.Errorf("open/close = %v/%v, want 190/200", price.Open, price.Close)
+	}
+	if price.High != 200 || price.Low != 190 {
+		t.Errorf("high/low = %v/%v, want 200/190", price.High, price.Low)
+	}
+	if price.Volume != 160 {
+		t.Errorf("volume = %d, want 160", price.Volume)
+	}
+	if price.Source != "tiingo" {
+		t.Errorf("source = %q, want %q", price.Source, "tiingo")
+	}
+}
+
+func TestAggregator_Flush(t *testing.T) {
+	agg := NewAggregator(NewUSMarketCalendar(), "kis")
+
+	agg.Add(domain.Tick{Symbol: "005930", Price: 70000, Volume: 10, Timestamp: time.Now()})
+	agg.Add(domain.Tick{Symbol: "000660", Price: 150000, Volume: 5, Timestamp: time.Now()})
+
+	prices := agg.Flush()
+	if len(prices) != 2 {
+		t.Fatalf("len(prices) = %d, want 2", len(prices))
+	}
+
+	if more := agg.Flush(); len(more) != 0 {
+		t.Errorf("Flush after Flush returned %d bars, want 0", len(more))
+	}
+}
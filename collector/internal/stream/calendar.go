@@ -0,0 +1,58 @@
+package stream
+
+import (
+	"time"
+)
+
+// MarketCalendar determines the session close for a tick's timestamp, so
+// Aggregator knows when to flush a symbol's in-progress bar. Implementations
+// use a fixed daily close time rather than a full trading calendar, so
+// holidays and half-days are not recognized.
+type MarketCalendar interface {
+	// Close returns the end of the trading session containing t.
+	Close(t time.Time) time.Time
+}
+
+// USMarketCalendar treats every day as ending at 16:00 America/New_York, the
+// regular NYSE/NASDAQ close. It does not account for holidays or early closes.
+type USMarketCalendar struct {
+	loc *time.Location
+}
+
+// NewUSMarketCalendar loads America/New_York, falling back to UTC if the
+// timezone database is unavailable.
+func NewUSMarketCalendar() USMarketCalendar {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		loc = time.UTC
+	}
+	return USMarketCalendar{loc: loc}
+}
+
+// Close implements MarketCalendar.
+func (c USMarketCalendar) Close(t time.Time) time.Time {
+	local := t.In(c.loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 16, 0, 0, 0, c.loc)
+}
+
+// KRMarketCalendar treats every day as ending at 15:30 Asia/Seoul, the regular
+// KRX close. It does not account for holidays or early closes.
+type KRMarketCalendar struct {
+	loc *time.Location
+}
+
+// NewKRMarketCalendar loads Asia/Seoul, falling back to UTC if the timezone
+// database is unavailable.
+func NewKRMarketCalendar() KRMarketCalendar {
+	loc, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		loc = time.UTC
+	}
+	return KRMarketCalendar{loc: loc}
+}
+
+// Close implements MarketCalendar.
+func (c KRMarketCalendar) Close(t time.Time) time.Time {
+	local := t.In(c.loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 15, 30, 0, 0, c.loc)
+}
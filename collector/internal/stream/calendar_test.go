@@ -0,0 +1,28 @@
+package stream
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUSMarketCalendar_Close(t *testing.T) {
+	cal := NewUSMarketCalendar()
+	loc, _ := time.LoadLocation("America/New_York")
+
+	got := cal.Close(time.Date(2024, 1, 2, 10, 0, 0, 0, loc))
+	want := time.Date(2024, 1, 2, 16, 0, 0, 0, loc)
+	if !got.Equal(want) {
+		t.Errorf("Close() = %v, want %v", got, want)
+	}
+}
+
+func TestKRMarketCalendar_Close(t *testing.T) {
+	cal := NewKRMarketCalendar()
+	loc, _ := time.LoadLocation("Asia/Seoul")
+
+	got := cal.Close(time.Date(2024, 1, 2, 10, 0, 0, 0, loc))
+	want := time.Date(2024, 1, 2, 15, 30, 0, 0, loc)
+	if !got.Equal(want) {
+		t.Errorf("Close() = %v, want %v", got, want)
+	}
+}
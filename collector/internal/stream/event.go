@@ -0,0 +1,91 @@
+package stream
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// EventKind identifies which field of a StreamEvent is populated.
+type EventKind string
+
+const (
+	EventTrade  EventKind = "trade"
+	EventQuote  EventKind = "quote"
+	EventBar    EventKind = "bar"
+	EventStatus EventKind = "status"
+)
+
+// StreamEvent is the typed union StreamAll delivers. Exactly one of Trade,
+// Quote, or Bar is populated for EventTrade/EventQuote/EventBar respectively;
+// Status carries a human-readable connection-lifecycle message (e.g.
+// "connected", "reconnecting: dial tcp: ...") for EventStatus. Source
+// identifies which EventSource (e.g. "tiingo", "kis") produced the event, so a
+// consumer fanned in from multiple sources can tell them apart.
+type StreamEvent struct {
+	Kind   EventKind
+	Source string
+
+	Trade  *domain.Tick
+	Quote  *domain.OrderBookSnapshot
+	Bar    *domain.DailyPrice
+	Status string
+}
+
+// EventSource is a long-lived real-time feed whose tracked symbol set can
+// change at runtime, unlike Streamer's fixed symbol list passed once to
+// Subscribe. An implementation owns its own reconnection (with exponential
+// backoff and jitter), authentication handshake, heartbeat/ping handling, and
+// resubscription of the last known symbol set after a dropped connection; Run
+// blocks serving the connection until ctx is done or an unrecoverable error
+// occurs. Subscribe and Unsubscribe may be called concurrently with Run, both
+// before the first connection is established and while one is live.
+type EventSource interface {
+	// Run dials and serves the feed, reconnecting with backoff until ctx is
+	// done. It returns nil when ctx is done, or a non-nil error if the source
+	// gave up retrying.
+	Run(ctx context.Context) error
+
+	// Subscribe adds symbols to the tracked set, resending the subscription
+	// over the connection immediately if one is live.
+	Subscribe(ctx context.Context, symbols []string) error
+
+	// Unsubscribe removes symbols from the tracked set.
+	Unsubscribe(ctx context.Context, symbols []string) error
+
+	// Events returns the channel StreamEvents are delivered on. It closes once
+	// Run returns.
+	Events() <-chan StreamEvent
+}
+
+// StreamAll runs every source's Run loop and fans its Events into a single
+// channel, tagging nothing further (each EventSource already stamps its own
+// Source on every StreamEvent). The returned channel closes once every
+// source's Run has returned, which happens when ctx is done.
+func StreamAll(ctx context.Context, sources []EventSource) <-chan StreamEvent {
+	out := make(chan StreamEvent)
+
+	var wg sync.WaitGroup
+	for _, src := range sources {
+		wg.Add(1)
+		go func(src EventSource) {
+			defer wg.Done()
+			go func() { _ = src.Run(ctx) }()
+			for evt := range src.Events() {
+				select {
+				case out <- evt:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(src)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
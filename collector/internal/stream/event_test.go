@@ -0,0 +1,77 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeEventSource is a minimal EventSource for exercising StreamAll without a
+// real connection: Run just emits preloaded events then blocks until ctx is
+// done.
+type fakeEventSource struct {
+	name   string
+	events chan StreamEvent
+	preset []StreamEvent
+}
+
+func newFakeEventSource(name string, preset ...StreamEvent) *fakeEventSource {
+	return &fakeEventSource{
+		name:   name,
+		events: make(chan StreamEvent, len(preset)+1),
+		preset: preset,
+	}
+}
+
+func (f *fakeEventSource) Subscribe(_ context.Context, _ []string) error   { return nil }
+func (f *fakeEventSource) Unsubscribe(_ context.Context, _ []string) error { return nil }
+func (f *fakeEventSource) Events() <-chan StreamEvent                      { return f.events }
+
+func (f *fakeEventSource) Run(ctx context.Context) error {
+	defer close(f.events)
+	for _, evt := range f.preset {
+		f.events <- evt
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestStreamAll_FansInMultipleSources(t *testing.T) {
+	a := newFakeEventSource("a", StreamEvent{Kind: EventStatus, Source: "a", Status: "connected"})
+	b := newFakeEventSource("b", StreamEvent{Kind: EventStatus, Source: "b", Status: "connected"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	out := StreamAll(ctx, []EventSource{a, b})
+
+	seen := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case evt := <-out:
+			seen[evt.Source] = true
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for fanned-in event")
+		}
+	}
+	if !seen["a"] || !seen["b"] {
+		t.Errorf("seen = %v, want both a and b", seen)
+	}
+}
+
+func TestStreamAll_ClosesWhenContextDone(t *testing.T) {
+	a := newFakeEventSource("a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := StreamAll(ctx, []EventSource{a})
+	cancel()
+
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Error("expected channel to be closed, got a value")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for output channel to close")
+	}
+}
@@ -0,0 +1,115 @@
+package stream
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/kis"
+	"github.com/jusikbot/collector/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// kisPriceChannelBuffer bounds KISStreamer's output channel; while it is full,
+// each newly completed bar is dropped rather than stalling the tick loop.
+const kisPriceChannelBuffer = 256
+
+// KISStreamer adapts a *kis.StreamingClient into the Streamer interface,
+// rolling its raw tick feed into daily bars via Aggregator. Reconnect,
+// resubscribe, and PINGPONG handling are all already handled by the wrapped
+// StreamingClient (see kis.StreamingClient.Run); KISStreamer's job is the
+// tick-to-bar aggregation and drop-when-full flow control on top of it.
+type KISStreamer struct {
+	client   *kis.StreamingClient
+	calendar MarketCalendar
+	metrics  *metrics.StreamMetrics
+}
+
+// KISStreamerOption customizes a KISStreamer at construction time.
+type KISStreamerOption func(*KISStreamer)
+
+// WithKISMetrics registers stream instrumentation (see metrics.StreamMetrics)
+// against reg. Without this option, dropped bars are logged but not counted.
+func WithKISMetrics(reg prometheus.Registerer) KISStreamerOption {
+	return func(s *KISStreamer) {
+		s.metrics = metrics.NewStreamMetrics(reg)
+	}
+}
+
+// NewKISStreamer creates a KISStreamer over an already-constructed
+// *kis.StreamingClient. calendar determines when an in-progress bar for a
+// symbol closes.
+func NewKISStreamer(client *kis.StreamingClient, calendar MarketCalendar, opts ...KISStreamerOption) *KISStreamer {
+	s := &KISStreamer{client: client, calendar: calendar}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Subscribe implements Streamer.
+func (s *KISStreamer) Subscribe(ctx context.Context, symbols []string) (<-chan domain.DailyPrice, <-chan error) {
+	prices := make(chan domain.DailyPrice, kisPriceChannelBuffer)
+	errs := make(chan error, 1)
+
+	if err := s.client.Subscribe(ctx, symbols, kis.StreamKindTick); err != nil {
+		errs <- err
+		close(prices)
+		close(errs)
+		return prices, errs
+	}
+
+	go s.run(ctx, prices, errs)
+
+	return prices, errs
+}
+
+func (s *KISStreamer) run(ctx context.Context, prices chan<- domain.DailyPrice, errs chan<- error) {
+	defer close(prices)
+	defer close(errs)
+
+	agg := NewAggregator(s.calendar, "kis")
+
+	runDone := make(chan error, 1)
+	go func() { runDone <- s.client.Run(ctx, kis.DefaultStreamRetryConfig()) }()
+
+	for {
+		select {
+		case t := <-s.client.Ticks():
+			if price, ok := agg.Add(t); ok {
+				s.deliver(prices, price)
+			}
+		case err := <-runDone:
+			s.flush(agg, prices)
+			if err != nil && ctx.Err() == nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+			return
+		case <-ctx.Done():
+			s.flush(agg, prices)
+			return
+		}
+	}
+}
+
+func (s *KISStreamer) flush(agg *Aggregator, prices chan<- domain.DailyPrice) {
+	for _, price := range agg.Flush() {
+		s.deliver(prices, price)
+	}
+}
+
+// deliver sends non-blocking: while the channel is full, p is dropped rather
+// than stalling KISStreamer's tick loop.
+func (s *KISStreamer) deliver(prices chan<- domain.DailyPrice, p domain.DailyPrice) {
+	select {
+	case prices <- p:
+	default:
+		slog.Warn("kis stream: price channel full, dropping bar", "symbol", p.Symbol)
+		if s.metrics != nil {
+			s.metrics.DroppedPrices.WithLabelValues("kis").Inc()
+		}
+	}
+}
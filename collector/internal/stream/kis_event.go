@@ -0,0 +1,89 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jusikbot/collector/internal/kis"
+)
+
+// kisEventChannelBuffer bounds KISEventSource's output channel; while it is
+// full, each new event is dropped rather than stalling the underlying
+// StreamingClient's read loop.
+const kisEventChannelBuffer = 256
+
+// KISEventSource adapts a *kis.StreamingClient into EventSource, surfacing its
+// raw ticks and order-book snapshots as StreamEvents instead of rolling them
+// into daily bars (contrast KISStreamer). Reconnect, resubscribe, and
+// PINGPONG handling are all already handled by the wrapped StreamingClient.
+type KISEventSource struct {
+	client *kis.StreamingClient
+	events chan StreamEvent
+}
+
+// NewKISEventSource creates a KISEventSource over an already-constructed
+// *kis.StreamingClient.
+func NewKISEventSource(client *kis.StreamingClient) *KISEventSource {
+	return &KISEventSource{
+		client: client,
+		events: make(chan StreamEvent, kisEventChannelBuffer),
+	}
+}
+
+// Subscribe implements EventSource, registering symbols on both the tick and
+// order-book feeds so consumers get both Trade and Quote events for them.
+func (s *KISEventSource) Subscribe(ctx context.Context, symbols []string) error {
+	return errors.Join(
+		s.client.Subscribe(ctx, symbols, kis.StreamKindTick),
+		s.client.Subscribe(ctx, symbols, kis.StreamKindOrderBook),
+	)
+}
+
+// Unsubscribe implements EventSource.
+func (s *KISEventSource) Unsubscribe(ctx context.Context, symbols []string) error {
+	return errors.Join(
+		s.client.Unsubscribe(ctx, symbols, kis.StreamKindTick),
+		s.client.Unsubscribe(ctx, symbols, kis.StreamKindOrderBook),
+	)
+}
+
+// Events implements EventSource.
+func (s *KISEventSource) Events() <-chan StreamEvent {
+	return s.events
+}
+
+// Run implements EventSource, forwarding the wrapped client's tick and
+// order-book channels as StreamEvents until ctx is done or the client's Run
+// gives up retrying.
+func (s *KISEventSource) Run(ctx context.Context) error {
+	defer close(s.events)
+
+	runDone := make(chan error, 1)
+	go func() { runDone <- s.client.Run(ctx, kis.DefaultStreamRetryConfig()) }()
+
+	for {
+		select {
+		case t := <-s.client.Ticks():
+			tick := t
+			s.deliver(StreamEvent{Kind: EventTrade, Source: "kis", Trade: &tick})
+		case ob := <-s.client.Orderbook():
+			snapshot := ob
+			s.deliver(StreamEvent{Kind: EventQuote, Source: "kis", Quote: &snapshot})
+		case err := <-runDone:
+			return err
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// deliver sends non-blocking: while the channel is full, evt is dropped rather
+// than stalling KISEventSource's forwarding loop.
+func (s *KISEventSource) deliver(evt StreamEvent) {
+	select {
+	case s.events <- evt:
+	default:
+		slog.Warn("kis event source: event channel full, dropping event", "kind", evt.Kind)
+	}
+}
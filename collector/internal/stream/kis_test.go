@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/jusikbot/collector/internal/kis"
+)
+
+func newKISApprovalServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"approval_key": "test-approval-key"})
+	}))
+}
+
+func TestKISStreamer_SubscribeAndReceiveBar(t *testing.T) {
+	approvalSrv := newKISApprovalServer(t)
+	defer approvalSrv.Close()
+
+	streamSrv := newTiingoStreamServer(t, func(conn *websocket.Conn) {
+		// Drain the subscribe frame, then push a single tick.
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, []byte("0|H0STCNT0|1|005930^090000^70000^100\n"))
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	token := kis.NewTokenProvider(approvalSrv.URL, "app-key", "app-secret", nil, nil)
+	client := kis.NewStreamingClient(wsURL(streamSrv.URL), token, nil)
+
+	calendar := NewKRMarketCalendar()
+	streamer := NewKISStreamer(client, calendar)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	prices, _ := streamer.Subscribe(ctx, []string{"005930"})
+
+	// The lone tick only opens a bar; cancel to force a flush of the
+	// in-progress bar rather than waiting for the session to close.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case price := <-prices:
+		if price.Symbol != "005930" {
+			t.Errorf("symbol = %q, want %q", price.Symbol, "005930")
+		}
+		if price.Close != 70000 {
+			t.Errorf("close = %v, want 70000", price.Close)
+		}
+		if price.Source != "kis" {
+			t.Errorf("source = %q, want %q", price.Source, "kis")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for flushed bar")
+	}
+}
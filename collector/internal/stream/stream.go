@@ -0,0 +1,20 @@
+// Package stream provides a provider-agnostic Streamer interface over real-time
+// market-data WebSocket feeds (Tiingo IEX, KIS), rolling intraday ticks into
+// domain.DailyPrice records via Aggregator once each tracked session closes.
+package stream
+
+import (
+	"context"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// Streamer subscribes to real-time data for symbols and delivers a completed
+// daily bar once each symbol's trading session closes. The error channel reports
+// connection and decode failures; an implementation keeps retrying internally
+// (see httpclient.Backoff) rather than treating them as fatal, so a value on the
+// error channel is informational, not a signal to stop reading the price channel.
+// Both channels close once ctx is done.
+type Streamer interface {
+	Subscribe(ctx context.Context, symbols []string) (<-chan domain.DailyPrice, <-chan error)
+}
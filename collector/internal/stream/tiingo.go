@@ -0,0 +1,262 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/httpclient"
+	"github.com/jusikbot/collector/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// defaultTiingoWSURL is Tiingo's IEX real-time trade feed endpoint.
+	defaultTiingoWSURL = "wss://iex.tiingo.com/iex"
+
+	// tiingoThresholdLevel selects Tiingo's "top of book + trades" event tier;
+	// see the IEX streaming docs for the full 0-7 scale.
+	tiingoThresholdLevel = 5
+
+	// tiingoPriceChannelBuffer bounds TiingoStreamer's output channel; while it
+	// is full, each newly completed bar is dropped rather than stalling the read loop.
+	tiingoPriceChannelBuffer = 256
+
+	tiingoMsgTypeError = "E"
+	tiingoMsgTypeTrade = "T"
+)
+
+// tiingoSubscribeFrame is the handshake message Tiingo's IEX feed expects to
+// register a connection for a set of tickers.
+type tiingoSubscribeFrame struct {
+	Authorization string          `json:"authorization"`
+	EventData     tiingoEventData `json:"eventData"`
+	EventName     string          `json:"eventName"`
+}
+
+type tiingoEventData struct {
+	ThresholdLevel int      `json:"thresholdLevel"`
+	Tickers        []string `json:"tickers"`
+}
+
+// tiingoEnvelope is the outer shape of every message Tiingo's IEX feed sends.
+// Data's contents depend on MessageType; see parseTiingoTrade.
+type tiingoEnvelope struct {
+	MessageType string            `json:"messageType"`
+	Data        []json.RawMessage `json:"data"`
+}
+
+// TiingoStreamer implements Streamer over Tiingo's IEX real-time trade feed,
+// rolling ticks into daily bars via Aggregator. Unlike KISStreamer, which wraps
+// an already-reconnecting client, TiingoStreamer owns its WebSocket connection
+// directly and reconnects itself with httpclient.Backoff, since tiingo has no
+// existing streaming client to delegate that to.
+type TiingoStreamer struct {
+	apiKey  string
+	wsURL   string
+	dialer  *websocket.Dialer
+	backoff httpclient.Backoff
+
+	calendar MarketCalendar
+	metrics  *metrics.StreamMetrics
+
+	// onTrade, if set, is called synchronously after each trade tick is
+	// aggregated. It exists solely so tests can wait for a trade to be
+	// processed instead of racing it against a subsequent cancel().
+	onTrade func()
+}
+
+// TiingoStreamerOption customizes a TiingoStreamer at construction time.
+type TiingoStreamerOption func(*TiingoStreamer)
+
+// WithTiingoMetrics registers stream instrumentation (see
+// metrics.StreamMetrics) against reg. Without this option, dropped bars are
+// logged but not counted.
+func WithTiingoMetrics(reg prometheus.Registerer) TiingoStreamerOption {
+	return func(s *TiingoStreamer) {
+		s.metrics = metrics.NewStreamMetrics(reg)
+	}
+}
+
+// NewTiingoStreamer creates a TiingoStreamer. wsURL overrides
+// defaultTiingoWSURL, for pointing at a fake server in tests; pass "" for
+// production use. Pass nil dialer for websocket.DefaultDialer.
+func NewTiingoStreamer(apiKey, wsURL string, dialer *websocket.Dialer, calendar MarketCalendar, opts ...TiingoStreamerOption) *TiingoStreamer {
+	if wsURL == "" {
+		wsURL = defaultTiingoWSURL
+	}
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	s := &TiingoStreamer{
+		apiKey:   apiKey,
+		wsURL:    wsURL,
+		dialer:   dialer,
+		backoff:  httpclient.Backoff{Initial: time.Second, Max: 30 * time.Second},
+		calendar: calendar,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Subscribe implements Streamer.
+func (s *TiingoStreamer) Subscribe(ctx context.Context, symbols []string) (<-chan domain.DailyPrice, <-chan error) {
+	prices := make(chan domain.DailyPrice, tiingoPriceChannelBuffer)
+	errs := make(chan error, 1)
+
+	go s.run(ctx, symbols, prices, errs)
+
+	return prices, errs
+}
+
+// run dials, serves, and reconnects with exponential backoff until ctx is
+// done, resubscribing symbols on every new connection.
+func (s *TiingoStreamer) run(ctx context.Context, symbols []string, prices chan<- domain.DailyPrice, errs chan<- error) {
+	defer close(prices)
+	defer close(errs)
+
+	agg := NewAggregator(s.calendar, "tiingo")
+
+	for attempt := 0; ctx.Err() == nil; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(s.backoff.Next(attempt - 1)):
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		err := s.connectAndServe(ctx, symbols, agg, prices)
+		if err != nil && ctx.Err() == nil {
+			slog.Warn("tiingo stream: connection lost, reconnecting", "error", err)
+			select {
+			case errs <- err:
+			default:
+			}
+		}
+	}
+
+	for _, price := range agg.Flush() {
+		s.deliver(prices, price)
+	}
+}
+
+func (s *TiingoStreamer) connectAndServe(ctx context.Context, symbols []string, agg *Aggregator, prices chan<- domain.DailyPrice) error {
+	conn, _, err := s.dialer.DialContext(ctx, s.wsURL, nil)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", s.wsURL, err)
+	}
+	defer conn.Close()
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	frame := tiingoSubscribeFrame{
+		Authorization: s.apiKey,
+		EventData: tiingoEventData{
+			ThresholdLevel: tiingoThresholdLevel,
+			Tickers:        symbols,
+		},
+		EventName: "subscribe",
+	}
+	if err := conn.WriteJSON(frame); err != nil {
+		return fmt.Errorf("subscribe: %w", err)
+	}
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("read message: %w", err)
+		}
+		s.handleMessage(data, agg, prices)
+	}
+}
+
+func (s *TiingoStreamer) handleMessage(data []byte, agg *Aggregator, prices chan<- domain.DailyPrice) {
+	var env tiingoEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		slog.Warn("tiingo stream: malformed message", "error", err)
+		return
+	}
+
+	switch env.MessageType {
+	case tiingoMsgTypeTrade:
+		tick, err := parseTiingoTrade(env.Data)
+		if err != nil {
+			slog.Warn("tiingo stream: discarding malformed trade", "error", err)
+			return
+		}
+		if price, ok := agg.Add(tick); ok {
+			s.deliver(prices, price)
+		}
+		if s.onTrade != nil {
+			s.onTrade()
+		}
+	case tiingoMsgTypeError:
+		var reason string
+		if len(env.Data) > 0 {
+			_ = json.Unmarshal(env.Data[0], &reason)
+		}
+		slog.Warn("tiingo stream: server reported an error", "reason", reason)
+	}
+}
+
+// deliver sends non-blocking: while the channel is full, p is dropped rather
+// than stalling TiingoStreamer's read loop.
+func (s *TiingoStreamer) deliver(prices chan<- domain.DailyPrice, p domain.DailyPrice) {
+	select {
+	case prices <- p:
+	default:
+		slog.Warn("tiingo stream: price channel full, dropping bar", "symbol", p.Symbol)
+		if s.metrics != nil {
+			s.metrics.DroppedPrices.WithLabelValues("tiingo").Inc()
+		}
+	}
+}
+
+// parseTiingoTrade decodes a simplified IEX trade message:
+// ["T", ticker, timestamp, size, price]. Why simplified: the real feed's
+// trade payload also carries exchange and sale-condition fields not consumed
+// downstream today.
+func parseTiingoTrade(data []json.RawMessage) (domain.Tick, error) {
+	if len(data) < 5 {
+		return domain.Tick{}, fmt.Errorf("expected at least 5 fields, got %d", len(data))
+	}
+
+	var symbol string
+	if err := json.Unmarshal(data[1], &symbol); err != nil {
+		return domain.Tick{}, fmt.Errorf("parse symbol: %w", err)
+	}
+	var timestamp time.Time
+	if err := json.Unmarshal(data[2], &timestamp); err != nil {
+		return domain.Tick{}, fmt.Errorf("parse timestamp: %w", err)
+	}
+	var size int64
+	if err := json.Unmarshal(data[3], &size); err != nil {
+		return domain.Tick{}, fmt.Errorf("parse size: %w", err)
+	}
+	var price float64
+	if err := json.Unmarshal(data[4], &price); err != nil {
+		return domain.Tick{}, fmt.Errorf("parse price: %w", err)
+	}
+
+	return domain.Tick{
+		Price:     price,
+		Symbol:    symbol,
+		Timestamp: timestamp,
+		Volume:    size,
+	}, nil
+}
@@ -0,0 +1,262 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/jusikbot/collector/internal/httpclient"
+	"github.com/jusikbot/collector/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// tiingoEventChannelBuffer bounds TiingoEventSource's output channel; while
+	// it is full, each new event is dropped rather than stalling the read loop.
+	tiingoEventChannelBuffer = 256
+
+	// tiingoHeartbeatTimeout bounds how long TiingoEventSource waits for any
+	// message (including Tiingo's periodic heartbeat) before treating the
+	// connection as dead and reconnecting.
+	tiingoHeartbeatTimeout = 30 * time.Second
+
+	tiingoMsgTypeHeartbeat = "H"
+)
+
+// TiingoEventSource implements EventSource over Tiingo's IEX real-time trade
+// feed, surfacing raw trades as StreamEvents instead of rolling them into
+// daily bars (contrast TiingoStreamer). Unlike TiingoStreamer, the tracked
+// symbol set can change at runtime via Subscribe/Unsubscribe: both update an
+// in-memory set and, if a connection is live, resend the subscribe frame for
+// the updated set immediately; a dropped connection resubscribes the full set
+// on reconnect.
+type TiingoEventSource struct {
+	apiKey  string
+	wsURL   string
+	dialer  *websocket.Dialer
+	backoff httpclient.Backoff
+
+	events  chan StreamEvent
+	metrics *metrics.StreamMetrics
+
+	mu      sync.Mutex
+	symbols map[string]struct{}
+	conn    *websocket.Conn
+
+	// writeMu serializes every WriteJSON call against conn: gorilla/websocket
+	// permits only one concurrent writer per connection, but Subscribe and
+	// Unsubscribe send their subscribe frame after releasing mu, independently
+	// of connectAndServe's initial resubscribe on connect.
+	writeMu sync.Mutex
+}
+
+// TiingoEventSourceOption customizes a TiingoEventSource at construction time.
+type TiingoEventSourceOption func(*TiingoEventSource)
+
+// WithTiingoEventMetrics registers stream instrumentation (see
+// metrics.StreamMetrics) against reg. Without this option, dropped events are
+// logged but not counted.
+func WithTiingoEventMetrics(reg prometheus.Registerer) TiingoEventSourceOption {
+	return func(s *TiingoEventSource) {
+		s.metrics = metrics.NewStreamMetrics(reg)
+	}
+}
+
+// NewTiingoEventSource creates a TiingoEventSource. wsURL overrides
+// defaultTiingoWSURL, for pointing at a fake server in tests; pass "" for
+// production use. Pass nil dialer for websocket.DefaultDialer.
+func NewTiingoEventSource(apiKey, wsURL string, dialer *websocket.Dialer, opts ...TiingoEventSourceOption) *TiingoEventSource {
+	if wsURL == "" {
+		wsURL = defaultTiingoWSURL
+	}
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	s := &TiingoEventSource{
+		apiKey:  apiKey,
+		wsURL:   wsURL,
+		dialer:  dialer,
+		backoff: httpclient.Backoff{Initial: time.Second, Max: 30 * time.Second},
+		events:  make(chan StreamEvent, tiingoEventChannelBuffer),
+		symbols: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Subscribe implements EventSource.
+func (s *TiingoEventSource) Subscribe(_ context.Context, symbols []string) error {
+	s.mu.Lock()
+	for _, sym := range symbols {
+		s.symbols[sym] = struct{}{}
+	}
+	conn := s.conn
+	all := s.symbolsLocked()
+	s.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	return s.sendSubscribeFrame(conn, all)
+}
+
+// Unsubscribe implements EventSource.
+func (s *TiingoEventSource) Unsubscribe(_ context.Context, symbols []string) error {
+	s.mu.Lock()
+	for _, sym := range symbols {
+		delete(s.symbols, sym)
+	}
+	conn := s.conn
+	all := s.symbolsLocked()
+	s.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	return s.sendSubscribeFrame(conn, all)
+}
+
+func (s *TiingoEventSource) symbolsLocked() []string {
+	all := make([]string, 0, len(s.symbols))
+	for sym := range s.symbols {
+		all = append(all, sym)
+	}
+	return all
+}
+
+// Events implements EventSource.
+func (s *TiingoEventSource) Events() <-chan StreamEvent {
+	return s.events
+}
+
+// Run implements EventSource, dialing and serving the feed, reconnecting with
+// exponential backoff until ctx is done and resubscribing the last known
+// symbol set on every new connection.
+func (s *TiingoEventSource) Run(ctx context.Context) error {
+	defer close(s.events)
+
+	for attempt := 0; ctx.Err() == nil; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(s.backoff.Next(attempt - 1)):
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		err := s.connectAndServe(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Warn("tiingo event source: connection lost, reconnecting", "error", err)
+			s.deliver(StreamEvent{Kind: EventStatus, Source: "tiingo", Status: fmt.Sprintf("reconnecting: %v", err)})
+		}
+	}
+
+	return nil
+}
+
+func (s *TiingoEventSource) connectAndServe(ctx context.Context) error {
+	conn, _, err := s.dialer.DialContext(ctx, s.wsURL, nil)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", s.wsURL, err)
+	}
+	defer conn.Close()
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	s.mu.Lock()
+	s.conn = conn
+	symbols := s.symbolsLocked()
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.conn = nil
+		s.mu.Unlock()
+	}()
+
+	if len(symbols) > 0 {
+		if err := s.sendSubscribeFrame(conn, symbols); err != nil {
+			return err
+		}
+	}
+	s.deliver(StreamEvent{Kind: EventStatus, Source: "tiingo", Status: "connected"})
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(tiingoHeartbeatTimeout))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("read message: %w", err)
+		}
+		s.handleMessage(data)
+	}
+}
+
+func (s *TiingoEventSource) sendSubscribeFrame(conn *websocket.Conn, symbols []string) error {
+	frame := tiingoSubscribeFrame{
+		Authorization: s.apiKey,
+		EventData: tiingoEventData{
+			ThresholdLevel: tiingoThresholdLevel,
+			Tickers:        symbols,
+		},
+		EventName: "subscribe",
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := conn.WriteJSON(frame); err != nil {
+		return fmt.Errorf("subscribe: %w", err)
+	}
+	return nil
+}
+
+func (s *TiingoEventSource) handleMessage(data []byte) {
+	var env tiingoEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		slog.Warn("tiingo event source: malformed message", "error", err)
+		return
+	}
+
+	switch env.MessageType {
+	case tiingoMsgTypeTrade:
+		tick, err := parseTiingoTrade(env.Data)
+		if err != nil {
+			slog.Warn("tiingo event source: discarding malformed trade", "error", err)
+			return
+		}
+		s.deliver(StreamEvent{Kind: EventTrade, Source: "tiingo", Trade: &tick})
+	case tiingoMsgTypeHeartbeat:
+		// No payload to act on; receiving it already reset the read deadline.
+	case tiingoMsgTypeError:
+		var reason string
+		if len(env.Data) > 0 {
+			_ = json.Unmarshal(env.Data[0], &reason)
+		}
+		slog.Warn("tiingo event source: server reported an error", "reason", reason)
+		s.deliver(StreamEvent{Kind: EventStatus, Source: "tiingo", Status: fmt.Sprintf("server error: %s", reason)})
+	}
+}
+
+// deliver sends non-blocking: while the channel is full, evt is dropped rather
+// than stalling TiingoEventSource's read loop.
+func (s *TiingoEventSource) deliver(evt StreamEvent) {
+	select {
+	case s.events <- evt:
+	default:
+		slog.Warn("tiingo event source: event channel full, dropping event", "kind", evt.Kind)
+		if s.metrics != nil {
+			s.metrics.DroppedPrices.WithLabelValues("tiingo").Inc()
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestTiingoEventSource_SubscribeAndReceiveTrade(t *testing.T) {
+	received := make(chan tiingoSubscribeFrame, 1)
+	srv := newTiingoStreamServer(t, func(conn *websocket.Conn) {
+		var frame tiingoSubscribeFrame
+		if err := conn.ReadJSON(&frame); err != nil {
+			return
+		}
+		received <- frame
+
+		trade := `{"messageType":"T","data":["T","AAPL","2024-01-02T15:00:00Z",100,190.5]}`
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(trade))
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	source := NewTiingoEventSource("test-key", wsURL(srv.URL), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go source.Run(ctx)
+	if err := source.Subscribe(ctx, []string{"AAPL"}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	select {
+	case frame := <-received:
+		if len(frame.EventData.Tickers) != 1 || frame.EventData.Tickers[0] != "AAPL" {
+			t.Errorf("tickers = %v, want [AAPL]", frame.EventData.Tickers)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for subscribe frame")
+	}
+
+	evt := waitForKind(t, source.Events(), EventTrade)
+	if evt.Trade == nil || evt.Trade.Symbol != "AAPL" || evt.Trade.Price != 190.5 {
+		t.Errorf("trade = %+v, want symbol=AAPL price=190.5", evt.Trade)
+	}
+	if evt.Source != "tiingo" {
+		t.Errorf("source = %q, want %q", evt.Source, "tiingo")
+	}
+}
+
+// waitForKind reads events off ch, skipping any that don't match kind (e.g.
+// the EventStatus "connected" event every connection opens with), until one
+// matches or the test times out.
+func waitForKind(t *testing.T, ch <-chan StreamEvent, kind EventKind) StreamEvent {
+	t.Helper()
+	for {
+		select {
+		case evt := <-ch:
+			if evt.Kind == kind {
+				return evt
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for a %s event", kind)
+		}
+	}
+}
+
+func TestTiingoEventSource_ReconnectsAndResubscribes(t *testing.T) {
+	var attempts int
+	frames := make(chan tiingoSubscribeFrame, 2)
+	srv := newTiingoStreamServer(t, func(conn *websocket.Conn) {
+		attempts++
+		var frame tiingoSubscribeFrame
+		if err := conn.ReadJSON(&frame); err == nil {
+			frames <- frame
+		}
+		if attempts == 1 {
+			conn.Close()
+			return
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	source := NewTiingoEventSource("test-key", wsURL(srv.URL), nil)
+	source.backoff = backoffForTest()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := source.Subscribe(ctx, []string{"AAPL"}); err != nil {
+		t.Fatalf("subscribe before connect: %v", err)
+	}
+	go source.Run(ctx)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case frame := <-frames:
+			if len(frame.EventData.Tickers) != 1 || frame.EventData.Tickers[0] != "AAPL" {
+				t.Fatalf("attempt %d tickers = %v, want [AAPL]", i+1, frame.EventData.Tickers)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for subscribe frame on attempt %d", i+1)
+		}
+	}
+}
+
+func TestTiingoEventSource_UnsubscribeUpdatesLiveConnection(t *testing.T) {
+	frames := make(chan tiingoSubscribeFrame, 2)
+	srv := newTiingoStreamServer(t, func(conn *websocket.Conn) {
+		for {
+			var frame tiingoSubscribeFrame
+			if err := conn.ReadJSON(&frame); err != nil {
+				return
+			}
+			frames <- frame
+		}
+	})
+
+	source := NewTiingoEventSource("test-key", wsURL(srv.URL), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go source.Run(ctx)
+	if err := source.Subscribe(ctx, []string{"AAPL", "MSFT"}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	select {
+	case <-frames:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for initial subscribe frame")
+	}
+
+	if err := source.Unsubscribe(ctx, []string{"MSFT"}); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+
+	select {
+	case frame := <-frames:
+		if len(frame.EventData.Tickers) != 1 || frame.EventData.Tickers[0] != "AAPL" {
+			t.Errorf("tickers after unsubscribe = %v, want [AAPL]", frame.EventData.Tickers)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for updated subscribe frame")
+	}
+}
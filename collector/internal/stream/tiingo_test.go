@@ -0,0 +1,177 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/jusikbot/collector/internal/httpclient"
+)
+
+func backoffForTest() httpclient.Backoff {
+	return httpclient.Backoff{Initial: time.Millisecond, Max: 10 * time.Millisecond}
+}
+
+func newTiingoStreamServer(t *testing.T, handler func(conn *websocket.Conn)) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Logf("upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		handler(conn)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func wsURL(httpURL string) string {
+	return "ws" + strings.TrimPrefix(httpURL, "http")
+}
+
+func TestTiingoStreamer_SubscribeAndReceiveBar(t *testing.T) {
+	received := make(chan tiingoSubscribeFrame, 1)
+	srv := newTiingoStreamServer(t, func(conn *websocket.Conn) {
+		var frame tiingoSubscribeFrame
+		if err := conn.ReadJSON(&frame); err != nil {
+			return
+		}
+		received <- frame
+
+		trade := `{"messageType":"T","data":["T","AAPL","2024-01-02T15:00:00Z",100,190.5]}`
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(trade))
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	calendar := NewUSMarketCalendar()
+	streamer := NewTiingoStreamer("test-key", wsURL(srv.URL), nil, calendar)
+	traded := make(chan struct{}, 1)
+	streamer.onTrade = func() { traded <- struct{}{} }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	prices, _ := streamer.Subscribe(ctx, []string{"AAPL"})
+
+	select {
+	case frame := <-received:
+		if frame.Authorization != "test-key" {
+			t.Errorf("authorization = %q, want %q", frame.Authorization, "test-key")
+		}
+		if frame.EventName != "subscribe" {
+			t.Errorf("eventName = %q, want %q", frame.EventName, "subscribe")
+		}
+		if len(frame.EventData.Tickers) != 1 || frame.EventData.Tickers[0] != "AAPL" {
+			t.Errorf("tickers = %v, want [AAPL]", frame.EventData.Tickers)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for subscribe frame")
+	}
+
+	// Wait for the trade to actually be read and aggregated before
+	// cancelling: racing cancel() against the in-flight write/read would let
+	// the connection close before the client ever sees the trade.
+	select {
+	case <-traded:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the trade to be aggregated")
+	}
+
+	// The lone trade only opens a bar; it is not emitted until the session
+	// closes or the stream shuts down, at which point Flush delivers it.
+	cancel()
+
+	select {
+	case price := <-prices:
+		if price.Symbol != "AAPL" {
+			t.Errorf("symbol = %q, want %q", price.Symbol, "AAPL")
+		}
+		if price.Close != 190.5 {
+			t.Errorf("close = %v, want 190.5", price.Close)
+		}
+		if price.Volume != 100 {
+			t.Errorf("volume = %d, want 100", price.Volume)
+		}
+		if price.Source != "tiingo" {
+			t.Errorf("source = %q, want %q", price.Source, "tiingo")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for flushed bar")
+	}
+}
+
+func TestTiingoStreamer_ReconnectsAfterDrop(t *testing.T) {
+	var attempts int
+	connected := make(chan struct{}, 2)
+	srv := newTiingoStreamServer(t, func(conn *websocket.Conn) {
+		attempts++
+		connected <- struct{}{}
+		if attempts == 1 {
+			conn.Close()
+			return
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	calendar := NewUSMarketCalendar()
+	streamer := NewTiingoStreamer("test-key", wsURL(srv.URL), nil, calendar)
+	streamer.backoff = backoffForTest()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	streamer.Subscribe(ctx, []string{"AAPL"})
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-connected:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for connection attempt %d", i+1)
+		}
+	}
+}
+
+func TestParseTiingoTrade(t *testing.T) {
+	var env tiingoEnvelope
+	raw := `{"messageType":"T","data":["T","AAPL","2024-01-02T15:00:00Z",100,190.5]}`
+	if err := json.Unmarshal([]byte(raw), &env); err != nil {
+		t.Fatalf("unmarshal envelope: %v", err)
+	}
+
+	tick, err := parseTiingoTrade(env.Data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tick.Symbol != "AAPL" {
+		t.Errorf("symbol = %q, want %q", tick.Symbol, "AAPL")
+	}
+	if tick.Price != 190.5 {
+		t.Errorf("price = %v, want 190.5", tick.Price)
+	}
+	if tick.Volume != 100 {
+		t.Errorf("volume = %d, want 100", tick.Volume)
+	}
+}
+
+func TestParseTiingoTrade_TooFewFields(t *testing.T) {
+	if _, err := parseTiingoTrade(nil); err == nil {
+		t.Fatal("expected error for empty data, got nil")
+	}
+}
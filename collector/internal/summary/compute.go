@@ -9,31 +9,62 @@ var BenchmarkSymbols = map[domain.Market]string{
 	domain.MarketKR: "069500",
 }
 
-// SymbolIndicators holds the indicator set for one symbol (14 columns in summary:
-// AdjClose + 12 optional metrics + MACross categorical signal).
+// SymbolIndicators holds the indicator set for one symbol (23 columns in summary:
+// AdjClose + 21 optional metrics + MACross categorical signal). json tags fix a
+// stable schema for JSONRenderer; nil *float64 fields marshal as null, never omitted.
 type SymbolIndicators struct {
-	AdjClose         float64
-	Change5D         *float64
-	Change20D        *float64
-	FiftyTwoWeekHigh *float64
-	FiftyTwoWeekLow  *float64
-	FiftyTwoWeekPos  *float64
-	HV20D            *float64
-	HV60D            *float64
-	MACross          *string
-	MADivergence50D  *float64
-	MADivergence200D *float64
-	RelativeBench20D *float64
-	VolRatio         *float64
+	AdjClose              float64  `json:"adj_close"`
+	AnnualizedVolatility  *float64 `json:"annualized_volatility"`
+	Beta                  *float64 `json:"beta"`
+	CAGR                  *float64 `json:"cagr"`
+	Calmar                *float64 `json:"calmar"`
+	Change5D              *float64 `json:"change_5d"`
+	Change20D             *float64 `json:"change_20d"`
+	FiftyTwoWeekHigh      *float64 `json:"fifty_two_week_high"`
+	FiftyTwoWeekLow       *float64 `json:"fifty_two_week_low"`
+	FiftyTwoWeekPos       *float64 `json:"fifty_two_week_pos"`
+	HV20D                 *float64 `json:"hv_20d"`
+	HV60D                 *float64 `json:"hv_60d"`
+	MACross               *string  `json:"ma_cross"`
+	MADivergence50D       *float64 `json:"ma_divergence_50d"`
+	MADivergence200D      *float64 `json:"ma_divergence_200d"`
+	MaxDrawdown           *float64 `json:"max_drawdown"`
+	MaxDrawdownPeakDate   *string  `json:"max_drawdown_peak_date"`
+	MaxDrawdownTroughDate *string  `json:"max_drawdown_trough_date"`
+	RelativeBench20D      *float64 `json:"relative_bench_20d"`
+	Sharpe                *float64 `json:"sharpe"`
+	Sortino               *float64 `json:"sortino"`
+	VolRatio              *float64 `json:"vol_ratio"`
 }
 
-// ComputeSymbolIndicators computes all 14-column indicators for a single symbol.
+// MarketAggregate holds the cross-symbol mean of each risk/return stat for one
+// market's table, so a reader can gauge the market as a whole alongside individual
+// symbols. Each field averages only over symbols where that stat was available.
+type MarketAggregate struct {
+	Beta        *float64 `json:"beta"`
+	CAGR        *float64 `json:"cagr"`
+	Calmar      *float64 `json:"calmar"`
+	MaxDrawdown *float64 `json:"max_drawdown"`
+	Sharpe      *float64 `json:"sharpe"`
+	Sortino     *float64 `json:"sortino"`
+}
+
+// ComputeSymbolIndicators computes all 18-column indicators for a single symbol.
 // benchPrices provides the benchmark's price history for relative performance.
 // isBenchmark skips RelativeBench20D (benchmark vs itself is meaningless).
+// minSamples gates the full-history risk/return stats (CAGR, MaxDrawdown, Sharpe,
+// Sortino): series shorter than minSamples non-anomaly entries leave them nil rather
+// than reporting a statistic computed on too little data.
+// tickSize snaps the 52-week high/low to the symbol's exchange-mandated price
+// increment (see domain.Instrument.PriceTickSize); a tickSize <= 0 (the symbol's
+// Instrument hasn't been collected yet) leaves them unrounded.
 func ComputeSymbolIndicators(
 	prices []domain.DailyPrice,
 	benchPrices []domain.DailyPrice,
 	isBenchmark bool,
+	minSamples int,
+	riskFreeAnnual float64,
+	tickSize float64,
 ) SymbolIndicators {
 	adjCloses := extractAdjCloses(prices)
 	if len(adjCloses) == 0 {
@@ -44,6 +75,14 @@ func ComputeSymbolIndicators(
 
 	high := FiftyTwoWeekHigh(prices)
 	low := FiftyTwoWeekLow(prices)
+	if high != nil {
+		snapped := snapToTick(*high, tickSize)
+		high = &snapped
+	}
+	if low != nil {
+		snapped := snapToTick(*low, tickSize)
+		low = &snapped
+	}
 
 	var pos *float64
 	if high != nil && low != nil {
@@ -61,7 +100,7 @@ func ComputeSymbolIndicators(
 		relBench = RelativeBenchmark(change20D, benchChange)
 	}
 
-	return SymbolIndicators{
+	indicators := SymbolIndicators{
 		AdjClose:         currentAdj,
 		Change5D:         PriceChange(prices, 5),
 		Change20D:        change20D,
@@ -76,4 +115,26 @@ func ComputeSymbolIndicators(
 		RelativeBench20D: relBench,
 		VolRatio:         VolumeRatio(prices, 20),
 	}
+
+	if len(adjCloses) >= minSamples {
+		window := len(adjCloses) - 1
+		indicators.CAGR = CAGR(prices)
+		indicators.MaxDrawdown = MaxDrawdown(prices)
+		indicators.Sharpe = SharpeRatio(prices, window, riskFreeAnnual)
+		indicators.Sortino = SortinoRatio(prices, window, riskFreeAnnual)
+		indicators.Calmar = CalmarRatio(prices, window)
+		indicators.AnnualizedVolatility = AnnualizedVolatility(prices, window)
+		if !isBenchmark {
+			indicators.Beta = Beta(prices, benchPrices, window)
+		}
+
+		if detail := MaxDrawdownDetail(prices); detail != nil {
+			peakDate := detail.PeakDate.Format("2006-01-02")
+			troughDate := detail.TroughDate.Format("2006-01-02")
+			indicators.MaxDrawdownPeakDate = &peakDate
+			indicators.MaxDrawdownTroughDate = &troughDate
+		}
+	}
+
+	return indicators
 }
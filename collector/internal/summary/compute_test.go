@@ -16,7 +16,7 @@ func TestComputeSymbolIndicators(t *testing.T) {
 		prices := makePriceSeries(baseDate, adjCloses, 1000)
 		benchPrices := makePriceSeries(baseDate, adjCloses, 1000)
 
-		result := ComputeSymbolIndicators(prices, benchPrices, false)
+		result := ComputeSymbolIndicators(prices, benchPrices, false, 20, 0.0, 0)
 
 		if result.AdjClose == 0 {
 			t.Error("AdjClose should not be zero")
@@ -51,19 +51,103 @@ func TestComputeSymbolIndicators(t *testing.T) {
 		if result.HV60D == nil {
 			t.Error("HV60D should not be nil")
 		}
+		if result.CAGR == nil {
+			t.Error("CAGR should not be nil")
+		}
+		if result.MaxDrawdown == nil {
+			t.Error("MaxDrawdown should not be nil")
+		}
+		if result.Sharpe == nil {
+			t.Error("Sharpe should not be nil")
+		}
+		if result.Beta == nil {
+			t.Error("Beta should not be nil")
+		}
+		// Sortino is nil here: a strictly increasing series has no downside returns,
+		// so its downside deviation is zero (see SortinoRatio).
+	})
+
+	t.Run("series shorter than minSamples leaves risk stats nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 101, 102, 103, 104}, 1000)
+		benchPrices := makePriceSeries(baseDate, []float64{100, 101, 102, 103, 104}, 1000)
+
+		result := ComputeSymbolIndicators(prices, benchPrices, false, 20, 0.0, 0)
+
+		if result.CAGR != nil {
+			t.Error("CAGR should be nil below minSamples")
+		}
+		if result.MaxDrawdown != nil {
+			t.Error("MaxDrawdown should be nil below minSamples")
+		}
+		if result.Sharpe != nil {
+			t.Error("Sharpe should be nil below minSamples")
+		}
+		if result.Sortino != nil {
+			t.Error("Sortino should be nil below minSamples")
+		}
+		if result.Calmar != nil {
+			t.Error("Calmar should be nil below minSamples")
+		}
+		if result.AnnualizedVolatility != nil {
+			t.Error("AnnualizedVolatility should be nil below minSamples")
+		}
+		if result.Beta != nil {
+			t.Error("Beta should be nil below minSamples")
+		}
+		if result.MaxDrawdownPeakDate != nil {
+			t.Error("MaxDrawdownPeakDate should be nil below minSamples")
+		}
+	})
+
+	t.Run("mixed returns computes a non-nil Sortino", func(t *testing.T) {
+		closes := []float64{100, 105, 103, 108, 106, 110, 107, 112, 109, 115,
+			112, 118, 114, 120, 117, 123, 119, 126, 122, 130, 128}
+		prices := makePriceSeries(baseDate, closes, 1000)
+		benchPrices := makePriceSeries(baseDate, closes, 1000)
+
+		result := ComputeSymbolIndicators(prices, benchPrices, false, 20, 0.0, 0)
+
+		if result.Sortino == nil {
+			t.Error("Sortino should not be nil for a series with downside returns")
+		}
+		if result.Calmar == nil {
+			t.Error("Calmar should not be nil for a series with a drawdown")
+		}
+		if result.AnnualizedVolatility == nil {
+			t.Error("AnnualizedVolatility should not be nil for a series with non-zero returns")
+		}
+		if result.MaxDrawdownPeakDate == nil {
+			t.Error("MaxDrawdownPeakDate should not be nil for a series with a drawdown")
+		}
+		if result.MaxDrawdownTroughDate == nil {
+			t.Error("MaxDrawdownTroughDate should not be nil for a series with a drawdown")
+		}
 	})
 
 	t.Run("benchmark symbol has nil RelativeBench", func(t *testing.T) {
 		prices := makePriceSeries(baseDate, repeatFloat(100, 30), 1000)
 		benchPrices := makePriceSeries(baseDate, repeatFloat(100, 30), 1000)
 
-		result := ComputeSymbolIndicators(prices, benchPrices, true)
+		result := ComputeSymbolIndicators(prices, benchPrices, true, 20, 0.0, 0)
 
 		if result.RelativeBench20D != nil {
 			t.Errorf("RelativeBench20D = %v, want nil for benchmark", *result.RelativeBench20D)
 		}
 	})
 
+	t.Run("benchmark symbol has nil Beta", func(t *testing.T) {
+		closes := []float64{100, 105, 103, 108, 110, 112, 109, 115, 113, 118,
+			116, 120, 117, 122, 119, 124, 121, 126, 123, 128, 125}
+		prices := makePriceSeries(baseDate, closes, 1000)
+		benchPrices := makePriceSeries(baseDate, closes, 1000)
+
+		result := ComputeSymbolIndicators(prices, benchPrices, true, 20, 0.0, 0)
+
+		if result.Beta != nil {
+			t.Errorf("Beta = %v, want nil for benchmark (beta against itself is meaningless)", *result.Beta)
+		}
+	})
+
 	t.Run("non-benchmark computes RelativeBench", func(t *testing.T) {
 		// Stock: 100 → 110 (10% change over 20 days)
 		stockCloses := make([]float64, 25)
@@ -76,7 +160,7 @@ func TestComputeSymbolIndicators(t *testing.T) {
 		// Bench: constant 100 (0% change)
 		benchPrices := makePriceSeries(baseDate, repeatFloat(100, 25), 1000)
 
-		result := ComputeSymbolIndicators(stockPrices, benchPrices, false)
+		result := ComputeSymbolIndicators(stockPrices, benchPrices, false, 20, 0.0, 0)
 
 		if result.RelativeBench20D == nil {
 			t.Fatal("RelativeBench20D should not be nil")
@@ -86,8 +170,23 @@ func TestComputeSymbolIndicators(t *testing.T) {
 		}
 	})
 
+	t.Run("tickSize rounds 52-week high and low", func(t *testing.T) {
+		adjCloses := []float64{100.03, 101.07, 99.96, 102.12}
+		prices := makePriceSeries(baseDate, adjCloses, 1000)
+		benchPrices := makePriceSeries(baseDate, adjCloses, 1000)
+
+		result := ComputeSymbolIndicators(prices, benchPrices, false, 20, 0.0, 0.05)
+
+		if result.FiftyTwoWeekHigh == nil || !almostEqual(*result.FiftyTwoWeekHigh, 102.10, 1e-9) {
+			t.Errorf("FiftyTwoWeekHigh = %v, want 102.10", result.FiftyTwoWeekHigh)
+		}
+		if result.FiftyTwoWeekLow == nil || !almostEqual(*result.FiftyTwoWeekLow, 99.95, 1e-9) {
+			t.Errorf("FiftyTwoWeekLow = %v, want 99.95", result.FiftyTwoWeekLow)
+		}
+	})
+
 	t.Run("empty prices returns zero AdjClose", func(t *testing.T) {
-		result := ComputeSymbolIndicators(nil, nil, false)
+		result := ComputeSymbolIndicators(nil, nil, false, 20, 0.0, 0)
 
 		if result.AdjClose != 0 {
 			t.Errorf("AdjClose = %v, want 0", result.AdjClose)
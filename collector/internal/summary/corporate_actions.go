@@ -0,0 +1,96 @@
+package summary
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// Why 30: a 30-day window keeps the section focused on actions still relevant to a
+// reader deciding whether to trade around an ex-date, without listing the full history.
+const corporateActionWindowDays = 30
+
+// CorporateActionEntry is a single split or dividend formatted for the summary report.
+type CorporateActionEntry struct {
+	CashAmount float64
+	Currency   string
+	ExDate     time.Time
+	Kind       domain.CorporateActionKind
+	Name       string
+	SplitRatio float64
+	Symbol     string
+}
+
+// RecentAndUpcomingCorporateActions filters actions to those within
+// corporateActionWindowDays before or after now, sorted by ex-date ascending.
+func RecentAndUpcomingCorporateActions(
+	actions []domain.CorporateAction,
+	symbol, name string,
+	now time.Time,
+) []CorporateActionEntry {
+	window := corporateActionWindowDays * 24 * time.Hour
+	from := now.Add(-window)
+	to := now.Add(window)
+
+	var entries []CorporateActionEntry
+	for _, a := range actions {
+		if a.ExDate.Before(from) || a.ExDate.After(to) {
+			continue
+		}
+		entries = append(entries, CorporateActionEntry{
+			CashAmount: a.CashAmount,
+			Currency:   a.Currency,
+			ExDate:     a.ExDate,
+			Kind:       a.Kind,
+			Name:       name,
+			SplitRatio: a.SplitRatio,
+			Symbol:     symbol,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ExDate.Before(entries[j].ExDate) })
+	return entries
+}
+
+// AdjustForSplits rescales Open/High/Low/Close in prices to reflect cumulative stock
+// splits, producing an un-adjusted-to-adjusted series comparable to Tiingo's AdjClose.
+// Each split only affects prices recorded before its ex-date, so every row is
+// divided by the product of the ratios of all splits dated after it.
+func AdjustForSplits(prices []domain.DailyPrice, actions []domain.CorporateAction) []domain.DailyPrice {
+	splits := make([]domain.CorporateAction, 0, len(actions))
+	for _, a := range actions {
+		if a.Kind == domain.CorporateActionSplit && a.SplitRatio > 0 {
+			splits = append(splits, a)
+		}
+	}
+	sort.Slice(splits, func(i, j int) bool { return splits[i].ExDate.Before(splits[j].ExDate) })
+
+	adjusted := make([]domain.DailyPrice, len(prices))
+	copy(adjusted, prices)
+
+	for i := len(adjusted) - 1; i >= 0; i-- {
+		factor := cumulativeSplitFactor(splits, adjusted[i].Date)
+		if factor == 1.0 {
+			continue
+		}
+		adjusted[i].Close /= factor
+		adjusted[i].High /= factor
+		adjusted[i].Low /= factor
+		adjusted[i].Open /= factor
+	}
+
+	return adjusted
+}
+
+// cumulativeSplitFactor multiplies the ratios of every split whose ex-date is after
+// date, reflecting how many shares a pre-split holding would have become by today.
+func cumulativeSplitFactor(splits []domain.CorporateAction, date time.Time) float64 {
+	factor := 1.0
+	for _, s := range splits {
+		if s.ExDate.After(date) {
+			factor *= s.SplitRatio
+		}
+	}
+	return factor
+}
@@ -0,0 +1,103 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func TestRecentAndUpcomingCorporateActions(t *testing.T) {
+	now := baseDate
+
+	t.Run("keeps actions within the window, sorted by ex-date", func(t *testing.T) {
+		actions := []domain.CorporateAction{
+			{ExDate: now.AddDate(0, 0, 10), Kind: domain.CorporateActionDividend, CashAmount: 0.5, Symbol: "NVDA"},
+			{ExDate: now.AddDate(0, 0, -5), Kind: domain.CorporateActionSplit, SplitRatio: 10, Symbol: "NVDA"},
+		}
+
+		got := RecentAndUpcomingCorporateActions(actions, "NVDA", "NVIDIA", now)
+		if len(got) != 2 {
+			t.Fatalf("len(got) = %d, want 2", len(got))
+		}
+		if got[0].Kind != domain.CorporateActionSplit || got[1].Kind != domain.CorporateActionDividend {
+			t.Errorf("entries not sorted by ex-date ascending: %+v", got)
+		}
+		if got[0].Name != "NVIDIA" {
+			t.Errorf("Name = %q, want %q", got[0].Name, "NVIDIA")
+		}
+	})
+
+	t.Run("drops actions outside the window", func(t *testing.T) {
+		actions := []domain.CorporateAction{
+			{ExDate: now.AddDate(0, 0, -60), Kind: domain.CorporateActionSplit, SplitRatio: 2, Symbol: "NVDA"},
+			{ExDate: now.AddDate(0, 0, 60), Kind: domain.CorporateActionDividend, CashAmount: 1, Symbol: "NVDA"},
+		}
+
+		if got := RecentAndUpcomingCorporateActions(actions, "NVDA", "NVIDIA", now); len(got) != 0 {
+			t.Errorf("len(got) = %d, want 0", len(got))
+		}
+	})
+
+	t.Run("empty input returns empty slice", func(t *testing.T) {
+		if got := RecentAndUpcomingCorporateActions(nil, "NVDA", "NVIDIA", now); len(got) != 0 {
+			t.Errorf("len(got) = %d, want 0", len(got))
+		}
+	})
+}
+
+func TestAdjustForSplits(t *testing.T) {
+	t.Run("rescales prices before the split ex-date", func(t *testing.T) {
+		prices := []domain.DailyPrice{
+			makeBar(baseDate, 110, 90, 100),
+			makeBar(baseDate.AddDate(0, 0, 1), 33, 27, 30),
+		}
+		actions := []domain.CorporateAction{
+			{ExDate: baseDate.AddDate(0, 0, 1), Kind: domain.CorporateActionSplit, SplitRatio: 3},
+		}
+
+		got := AdjustForSplits(prices, actions)
+
+		assertAlmostEqual(t, &got[0].Close, 100.0/3, 0.001, "adjusted Close before split")
+		assertAlmostEqual(t, &got[0].High, 110.0/3, 0.001, "adjusted High before split")
+		assertAlmostEqual(t, &got[0].Low, 90.0/3, 0.001, "adjusted Low before split")
+		assertAlmostEqual(t, &got[1].Close, 30.0, 0.001, "Close on split date unchanged")
+	})
+
+	t.Run("compounds multiple splits", func(t *testing.T) {
+		prices := []domain.DailyPrice{
+			makeBar(baseDate, 0, 0, 100),
+			makeBar(baseDate.AddDate(0, 0, 1), 0, 0, 20),
+			makeBar(baseDate.AddDate(0, 0, 2), 0, 0, 10),
+		}
+		actions := []domain.CorporateAction{
+			{ExDate: baseDate.AddDate(0, 0, 1), Kind: domain.CorporateActionSplit, SplitRatio: 5},
+			{ExDate: baseDate.AddDate(0, 0, 2), Kind: domain.CorporateActionSplit, SplitRatio: 2},
+		}
+
+		got := AdjustForSplits(prices, actions)
+
+		assertAlmostEqual(t, &got[0].Close, 100.0/10, 0.001, "Close before both splits")
+		assertAlmostEqual(t, &got[1].Close, 20.0/2, 0.001, "Close between the two splits")
+		assertAlmostEqual(t, &got[2].Close, 10.0, 0.001, "Close on the latest split date unchanged")
+	})
+
+	t.Run("dividends do not affect price scaling", func(t *testing.T) {
+		prices := []domain.DailyPrice{makeBar(baseDate, 110, 90, 100)}
+		actions := []domain.CorporateAction{
+			{ExDate: baseDate.AddDate(0, 0, 1), Kind: domain.CorporateActionDividend, CashAmount: 1},
+		}
+
+		got := AdjustForSplits(prices, actions)
+		if got[0].Close != 100 {
+			t.Errorf("Close = %v, want unchanged 100", got[0].Close)
+		}
+	})
+
+	t.Run("no actions returns prices unchanged", func(t *testing.T) {
+		prices := []domain.DailyPrice{makeBar(baseDate, 110, 90, 100)}
+		got := AdjustForSplits(prices, nil)
+		if got[0].Close != 100 {
+			t.Errorf("Close = %v, want unchanged 100", got[0].Close)
+		}
+	})
+}
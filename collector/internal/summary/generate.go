@@ -4,24 +4,87 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/jusikbot/collector/internal/domain"
 )
 
+// RiskWriter persists the risk/return indicators computed for each symbol during
+// a summary run, so historical risk profiles can be queried later without
+// recomputing them from price history. Satisfied by store.Repository. A nil
+// RiskWriter passed to GenerateSummary skips persistence, the same way an empty
+// accountNo skips the Margin section.
+type RiskWriter interface {
+	UpsertSummaryRisk(ctx context.Context, metrics []domain.SummaryRiskMetric) (int64, error)
+}
+
 // PriceReader abstracts DB read-back for summary generation.
 // Satisfied by store.Repository.
 type PriceReader interface {
 	FetchPriceHistory(ctx context.Context, symbol string, from, to time.Time) ([]domain.DailyPrice, error)
 	FetchFXRates(ctx context.Context, pair string, from, to time.Time) ([]domain.FXRate, error)
+	FetchOutstandingMarginLoans(ctx context.Context, accountNo string) ([]domain.MarginLoan, error)
+	FetchMarginInterest(ctx context.Context, accountNo string, from, to time.Time) ([]domain.MarginInterest, error)
+	FetchMarginRepayments(ctx context.Context, accountNo string, from, to time.Time) ([]domain.MarginRepayment, error)
+	FetchOrderBookSnapshots(ctx context.Context, symbol string, from, to time.Time) ([]domain.OrderBookSnapshot, error)
+	FetchInstrument(ctx context.Context, symbol string) (domain.Instrument, bool, error)
 }
 
+// Why 90: matches kis.defaultMarginLookbackDays — margin loans are short-lived,
+// so a 90-day window comfortably covers any interest/repayment worth reporting.
+const marginHistoryLookbackDays = 90
+
 // Why 380: 252 trading days/year + ~128 calendar gap days ensures full 52-week coverage
 // plus buffer for 200D MA calculation.
 const priceHistoryLookbackDays = 380
 
-// GenerateSummary reads prices from DB, computes indicators, and writes data/summary.md.
-func GenerateSummary(ctx context.Context, reader PriceReader, watchlist []domain.WatchlistEntry, outputPath string) error {
+// Why 7: order book snapshots are a periodic intraday sample (see
+// kis.OrderBookSampler), not a long daily history, so a short trailing window
+// keeps the Liquidity section focused on recent conditions.
+const liquidityLookbackDays = 7
+
+// Why 7: rebalancing only needs each holding's latest close, not a full
+// history; a short trailing window tolerates a stale/missing latest bar
+// without pulling priceHistoryLookbackDays worth of unused rows.
+const rebalancePriceLookbackDays = 7
+
+// SummaryOptions configures the optional risk/return statistics (CAGR, MaxDrawdown,
+// Sharpe, Sortino, Calmar) added to each symbol row.
+type SummaryOptions struct {
+	// MinSamples is the minimum number of non-anomaly daily bars a symbol needs before
+	// its risk/return stats are computed; shorter series render "n/a" and are listed
+	// in the report's Notes section instead. See ComputeSymbolIndicators.
+	MinSamples int
+	// RiskFreeAnnualByMarket is the annualized risk-free rate used by Sharpe and
+	// Sortino, keyed by market (KR/US each carry their own base rate). A market
+	// missing from the map defaults to 0.
+	RiskFreeAnnualByMarket map[domain.Market]float64
+}
+
+// DefaultSummaryOptions returns the default MinSamples (20 trading days) and an
+// empty RiskFreeAnnualByMarket (every market defaults to a 0 risk-free rate).
+func DefaultSummaryOptions() SummaryOptions {
+	return SummaryOptions{MinSamples: 20, RiskFreeAnnualByMarket: map[domain.Market]float64{}}
+}
+
+// GenerateSummary reads prices from DB, computes indicators, and renders the
+// result in the requested format(s). outputPath is the Markdown-style path
+// (e.g. "../data/summary.md"); for FormatAll, the other formats reuse the same
+// base name with their own extension (summary.json, summary.html). accountNo
+// is optional; when empty, the Margin section is omitted from the report.
+func GenerateSummary(
+	ctx context.Context,
+	reader PriceReader,
+	watchlist []domain.WatchlistEntry,
+	outputPath string,
+	format Format,
+	accountNo string,
+	opts SummaryOptions,
+	rebalanceOpts RebalanceOptions,
+	riskWriter RiskWriter,
+) error {
 	now := time.Now()
 	from := now.AddDate(0, 0, -priceHistoryLookbackDays)
 	to := now
@@ -31,22 +94,63 @@ func GenerateSummary(ctx context.Context, reader PriceReader, watchlist []domain
 		return err
 	}
 
-	usRows, krRows, insufficientSymbols, err := computeAllIndicators(ctx, reader, watchlist, benchPrices, from, to)
+	usRows, krRows, insufficientSymbols, err := computeAllIndicators(ctx, reader, watchlist, benchPrices, from, to, opts)
 	if err != nil {
 		return err
 	}
 
+	if riskWriter != nil {
+		persistSummaryRisk(ctx, riskWriter, now, usRows, krRows)
+	}
+
 	fxEntry := loadLatestFXRate(ctx, reader, from, to)
+	marginSummary := loadMarginSummary(ctx, reader, accountNo, now)
+	liquidity := loadLiquidity(ctx, reader, watchlist, now)
+	rebalance := loadRebalance(ctx, reader, watchlist, fxEntry, now, rebalanceOpts)
 
 	data := SummaryData{
 		FXRate:              fxEntry,
 		GeneratedAt:         now.UTC().Format("2006-01-02 15:04 UTC"),
 		InsufficientSymbols: insufficientSymbols,
+		KRAggregate:         computeMarketAggregate(krRows),
 		KRRows:              krRows,
+		Liquidity:           liquidity,
+		Margin:              marginSummary,
+		Rebalance:           rebalance,
+		USAggregate:         computeMarketAggregate(usRows),
 		USRows:              usRows,
 	}
 
-	return RenderSummary(data, outputPath)
+	return renderFormats(data, outputPath, format)
+}
+
+// renderFormats writes data through the Renderer(s) matching format. FormatAll
+// expands to every registered format so a single collection run can emit all
+// artifacts atomically.
+func renderFormats(data SummaryData, outputPath string, format Format) error {
+	formats := []Format{format}
+	if format == FormatAll {
+		formats = []Format{FormatMarkdown, FormatJSON, FormatHTML}
+	}
+
+	for _, f := range formats {
+		renderer, ok := renderers[f]
+		if !ok {
+			return fmt.Errorf("unknown summary format %q", f)
+		}
+		if err := renderer.Render(data, outputPathForFormat(outputPath, f)); err != nil {
+			return fmt.Errorf("render %s summary: %w", f, err)
+		}
+	}
+
+	return nil
+}
+
+// outputPathForFormat swaps base's extension for format's, e.g.
+// "../data/summary.md" + FormatJSON -> "../data/summary.json".
+func outputPathForFormat(base string, format Format) string {
+	trimmed := strings.TrimSuffix(base, filepath.Ext(base))
+	return trimmed + "." + string(format)
 }
 
 func loadBenchmarkPrices(ctx context.Context, reader PriceReader, from, to time.Time) (map[domain.Market][]domain.DailyPrice, error) {
@@ -67,6 +171,7 @@ func computeAllIndicators(
 	watchlist []domain.WatchlistEntry,
 	benchPrices map[domain.Market][]domain.DailyPrice,
 	from, to time.Time,
+	opts SummaryOptions,
 ) (usRows, krRows []SymbolRow, insufficientSymbols []string, err error) {
 	for _, entry := range watchlist {
 		prices, fetchErr := reader.FetchPriceHistory(ctx, entry.Symbol, from, to)
@@ -80,7 +185,19 @@ func computeAllIndicators(
 		}
 
 		isBenchmark := BenchmarkSymbols[entry.Market] == entry.Symbol
-		indicators := ComputeSymbolIndicators(prices, benchPrices[entry.Market], isBenchmark)
+		riskFreeAnnual := opts.RiskFreeAnnualByMarket[entry.Market]
+
+		// A lookup failure or an uncollected Instrument just leaves tickSize at 0,
+		// which ComputeSymbolIndicators treats as "don't round" — the same
+		// graceful-degradation behavior resolveLotSizes uses for rebalancing.
+		var tickSize float64
+		if inst, ok, instErr := reader.FetchInstrument(ctx, entry.Symbol); instErr != nil {
+			slog.Warn("fetch instrument failed, skipping tick-size rounding", "error", instErr, "symbol", entry.Symbol)
+		} else if ok {
+			tickSize = inst.PriceTickSize
+		}
+
+		indicators := ComputeSymbolIndicators(prices, benchPrices[entry.Market], isBenchmark, opts.MinSamples, riskFreeAnnual, tickSize)
 
 		row := SymbolRow{
 			Indicators: indicators,
@@ -99,11 +216,122 @@ func computeAllIndicators(
 			insufficientSymbols = append(insufficientSymbols,
 				fmt.Sprintf("%s (%s): 200D MA 데이터 부족 (< 200 거래일)", entry.Symbol, entry.Name))
 		}
+		if indicators.CAGR == nil && len(extractAdjCloses(prices)) < opts.MinSamples {
+			insufficientSymbols = append(insufficientSymbols,
+				fmt.Sprintf("%s (%s): 리스크 지표 n/a (< %d 거래일)", entry.Symbol, entry.Name, opts.MinSamples))
+		}
 	}
 
 	return usRows, krRows, insufficientSymbols, nil
 }
 
+// computeMarketAggregate averages each risk/return stat across rows, skipping nil
+// values per field so a market with a mix of long- and short-history symbols still
+// reports an aggregate from whatever data is available. Returns a zero-value
+// MarketAggregate (all fields nil) for an empty market.
+func computeMarketAggregate(rows []SymbolRow) MarketAggregate {
+	var betaSum, cagrSum, calmarSum, ddSum, sharpeSum, sortinoSum float64
+	var betaN, cagrN, calmarN, ddN, sharpeN, sortinoN int
+
+	for _, row := range rows {
+		if v := row.Indicators.Beta; v != nil {
+			betaSum += *v
+			betaN++
+		}
+		if v := row.Indicators.CAGR; v != nil {
+			cagrSum += *v
+			cagrN++
+		}
+		if v := row.Indicators.Calmar; v != nil {
+			calmarSum += *v
+			calmarN++
+		}
+		if v := row.Indicators.MaxDrawdown; v != nil {
+			ddSum += *v
+			ddN++
+		}
+		if v := row.Indicators.Sharpe; v != nil {
+			sharpeSum += *v
+			sharpeN++
+		}
+		if v := row.Indicators.Sortino; v != nil {
+			sortinoSum += *v
+			sortinoN++
+		}
+	}
+
+	var agg MarketAggregate
+	if betaN > 0 {
+		mean := betaSum / float64(betaN)
+		agg.Beta = &mean
+	}
+	if cagrN > 0 {
+		mean := cagrSum / float64(cagrN)
+		agg.CAGR = &mean
+	}
+	if calmarN > 0 {
+		mean := calmarSum / float64(calmarN)
+		agg.Calmar = &mean
+	}
+	if ddN > 0 {
+		mean := ddSum / float64(ddN)
+		agg.MaxDrawdown = &mean
+	}
+	if sharpeN > 0 {
+		mean := sharpeSum / float64(sharpeN)
+		agg.Sharpe = &mean
+	}
+	if sortinoN > 0 {
+		mean := sortinoSum / float64(sortinoN)
+		agg.Sortino = &mean
+	}
+	return agg
+}
+
+// persistSummaryRisk upserts each row's risk/return indicators via riskWriter.
+// Failures are logged and otherwise ignored, the same as the report's other
+// best-effort DB reads (see loadLatestFXRate), since a persistence hiccup
+// shouldn't block the report itself from rendering.
+func persistSummaryRisk(ctx context.Context, riskWriter RiskWriter, asOf time.Time, usRows, krRows []SymbolRow) {
+	metrics := make([]domain.SummaryRiskMetric, 0, len(usRows)+len(krRows))
+	for _, row := range append(append([]SymbolRow{}, usRows...), krRows...) {
+		metrics = append(metrics, symbolRiskMetric(row, asOf))
+	}
+	if len(metrics) == 0 {
+		return
+	}
+
+	if _, err := riskWriter.UpsertSummaryRisk(ctx, metrics); err != nil {
+		slog.Warn("summary risk persistence skipped", "error", err)
+	}
+}
+
+func symbolRiskMetric(row SymbolRow, asOf time.Time) domain.SummaryRiskMetric {
+	metric := domain.SummaryRiskMetric{
+		AnnualizedVolatility: row.Indicators.AnnualizedVolatility,
+		AsOf:                 asOf,
+		CAGR:                 row.Indicators.CAGR,
+		Calmar:               row.Indicators.Calmar,
+		MaxDrawdown:          row.Indicators.MaxDrawdown,
+		Sharpe:               row.Indicators.Sharpe,
+		Sortino:              row.Indicators.Sortino,
+		Symbol:               row.Symbol,
+	}
+
+	if row.Indicators.MaxDrawdownPeakDate != nil {
+		if t, err := time.Parse("2006-01-02", *row.Indicators.MaxDrawdownPeakDate); err == nil {
+			metric.MaxDrawdownPeakDate = &t
+		}
+	}
+	if row.Indicators.MaxDrawdownTroughDate != nil {
+		if t, err := time.Parse("2006-01-02", *row.Indicators.MaxDrawdownTroughDate); err == nil {
+			metric.MaxDrawdownTroughDate = &t
+		}
+	}
+
+	return metric
+}
+
 func loadLatestFXRate(ctx context.Context, reader PriceReader, from, to time.Time) *FXRateEntry {
 	fxRates, err := reader.FetchFXRates(ctx, "USD/KRW", from, to)
 	if err != nil {
@@ -114,10 +342,119 @@ func loadLatestFXRate(ctx context.Context, reader PriceReader, from, to time.Tim
 		return nil
 	}
 
-	latest := fxRates[len(fxRates)-1]
+	latest := latestNonAnomalousFXRate(fxRates)
 	return &FXRateEntry{
 		Date: latest.Date.Format("2006-01-02"),
 		Pair: latest.Pair,
 		Rate: latest.Rate,
 	}
 }
+
+// latestNonAnomalousFXRate walks fxRates (ascending by Date) backwards from
+// the most recent entry and returns the first one not flagged IsAnomaly, so a
+// day-over-day spike never becomes the report's headline rate. Falls back to
+// the literal latest entry when every rate in range is anomalous, since
+// showing a stale-but-flagged rate still beats showing nothing.
+func latestNonAnomalousFXRate(fxRates []domain.FXRate) *domain.FXRate {
+	for i := len(fxRates) - 1; i >= 0; i-- {
+		if !fxRates[i].IsAnomaly {
+			return &fxRates[i]
+		}
+	}
+	return &fxRates[len(fxRates)-1]
+}
+
+// loadMarginSummary reads outstanding loans, interest, and repayments for
+// accountNo and computes the Margin section. Returns nil when accountNo is
+// empty (margin collection not configured) or any read fails, so a margin
+// outage never blocks the rest of the summary.
+func loadMarginSummary(ctx context.Context, reader PriceReader, accountNo string, now time.Time) *MarginSummary {
+	if accountNo == "" {
+		return nil
+	}
+
+	from := now.AddDate(0, 0, -marginHistoryLookbackDays)
+
+	loans, err := reader.FetchOutstandingMarginLoans(ctx, accountNo)
+	if err != nil {
+		slog.Warn("margin loans fetch skipped", "error", err)
+		return nil
+	}
+	interest, err := reader.FetchMarginInterest(ctx, accountNo, from, now)
+	if err != nil {
+		slog.Warn("margin interest fetch skipped", "error", err)
+		return nil
+	}
+	repayments, err := reader.FetchMarginRepayments(ctx, accountNo, from, now)
+	if err != nil {
+		slog.Warn("margin repayments fetch skipped", "error", err)
+		return nil
+	}
+
+	summary := ComputeMarginSummary(loans, interest, repayments, now)
+	return &summary
+}
+
+// loadLiquidity reads order book snapshots from the trailing liquidityLookbackDays
+// window for every watchlist symbol and computes its LiquidityEntry. Symbols with
+// no snapshots (order book sampling not configured, or never run for that market)
+// are silently omitted rather than failing the whole summary.
+func loadLiquidity(ctx context.Context, reader PriceReader, watchlist []domain.WatchlistEntry, now time.Time) []LiquidityEntry {
+	from := now.AddDate(0, 0, -liquidityLookbackDays)
+
+	var entries []LiquidityEntry
+	for _, entry := range watchlist {
+		snapshots, err := reader.FetchOrderBookSnapshots(ctx, entry.Symbol, from, now)
+		if err != nil {
+			slog.Warn("order book snapshots fetch skipped", "error", err, "symbol", entry.Symbol)
+			continue
+		}
+
+		liquidity, ok := ComputeLiquidity(snapshots)
+		if !ok {
+			continue
+		}
+		liquidity.Name = entry.Name
+		liquidity.Symbol = entry.Symbol
+		entries = append(entries, liquidity)
+	}
+
+	return entries
+}
+
+// loadRebalance fetches each configured holding's latest close price and
+// computes the Rebalance section. Returns nil when opts has no holdings
+// (rebalancing not configured for this run).
+func loadRebalance(
+	ctx context.Context,
+	reader PriceReader,
+	watchlist []domain.WatchlistEntry,
+	fxEntry *FXRateEntry,
+	now time.Time,
+	opts RebalanceOptions,
+) *RebalanceSummary {
+	if len(opts.Holdings) == 0 {
+		return nil
+	}
+
+	from := now.AddDate(0, 0, -rebalancePriceLookbackDays)
+
+	latestPrices := make(map[string]float64, len(opts.Holdings))
+	for _, h := range opts.Holdings {
+		prices, err := reader.FetchPriceHistory(ctx, h.Symbol, from, now)
+		if err != nil {
+			slog.Warn("rebalance price fetch skipped", "error", err, "symbol", h.Symbol)
+			continue
+		}
+		if len(prices) == 0 {
+			continue
+		}
+		latestPrices[h.Symbol] = prices[len(prices)-1].AdjClose
+	}
+
+	rebalance, skipped := ComputeRebalance(opts.Holdings, watchlist, latestPrices, fxEntry, opts)
+	if len(skipped) > 0 {
+		slog.Warn("rebalance symbols skipped", "symbols", skipped)
+	}
+	return &rebalance
+}
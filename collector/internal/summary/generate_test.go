@@ -13,10 +13,15 @@ import (
 )
 
 type stubPriceReader struct {
-	prices   map[string][]domain.DailyPrice
-	fxRates  map[string][]domain.FXRate
-	priceErr map[string]error
-	fxErr    map[string]error
+	prices      map[string][]domain.DailyPrice
+	fxRates     map[string][]domain.FXRate
+	priceErr    map[string]error
+	fxErr       map[string]error
+	loans       []domain.MarginLoan
+	interest    []domain.MarginInterest
+	repayments  []domain.MarginRepayment
+	orderBooks  map[string][]domain.OrderBookSnapshot
+	instruments map[string]domain.Instrument
 }
 
 func (s *stubPriceReader) FetchPriceHistory(_ context.Context, symbol string, _, _ time.Time) ([]domain.DailyPrice, error) {
@@ -37,6 +42,83 @@ func (s *stubPriceReader) FetchFXRates(_ context.Context, pair string, _, _ time
 	return s.fxRates[pair], nil
 }
 
+func (s *stubPriceReader) FetchOutstandingMarginLoans(_ context.Context, _ string) ([]domain.MarginLoan, error) {
+	return s.loans, nil
+}
+
+func (s *stubPriceReader) FetchMarginInterest(_ context.Context, _ string, _, _ time.Time) ([]domain.MarginInterest, error) {
+	return s.interest, nil
+}
+
+func (s *stubPriceReader) FetchMarginRepayments(_ context.Context, _ string, _, _ time.Time) ([]domain.MarginRepayment, error) {
+	return s.repayments, nil
+}
+
+func (s *stubPriceReader) FetchOrderBookSnapshots(_ context.Context, symbol string, _, _ time.Time) ([]domain.OrderBookSnapshot, error) {
+	return s.orderBooks[symbol], nil
+}
+
+func (s *stubPriceReader) FetchInstrument(_ context.Context, symbol string) (domain.Instrument, bool, error) {
+	inst, ok := s.instruments[symbol]
+	return inst, ok, nil
+}
+
+// stubRiskWriter records every metric batch passed to UpsertSummaryRisk, so
+// tests can assert on what GenerateSummary would persist.
+type stubRiskWriter struct {
+	metrics []domain.SummaryRiskMetric
+}
+
+func (s *stubRiskWriter) UpsertSummaryRisk(_ context.Context, metrics []domain.SummaryRiskMetric) (int64, error) {
+	s.metrics = append(s.metrics, metrics...)
+	return int64(len(metrics)), nil
+}
+
+func TestLoadLatestFXRate(t *testing.T) {
+	day1 := time.Date(2025, 2, 12, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 2, 13, 0, 0, 0, 0, time.UTC)
+	day3 := time.Date(2025, 2, 14, 0, 0, 0, 0, time.UTC)
+
+	t.Run("prefers the latest non-anomalous rate over a flagged latest date", func(t *testing.T) {
+		reader := &stubPriceReader{
+			fxRates: map[string][]domain.FXRate{
+				"USD/KRW": {
+					{Date: day1, Pair: "USD/KRW", Rate: 1345.50},
+					{Date: day2, Pair: "USD/KRW", Rate: 1346.00},
+					{Date: day3, Pair: "USD/KRW", Rate: 1900.00, IsAnomaly: true},
+				},
+			},
+		}
+
+		got := loadLatestFXRate(context.Background(), reader, day1, day3)
+		if got == nil {
+			t.Fatal("got nil, want an entry")
+		}
+		if got.Date != "2025-02-13" || got.Rate != 1346.00 {
+			t.Errorf("got %+v, want 2025-02-13/1346.00", got)
+		}
+	})
+
+	t.Run("falls back to the latest entry when every rate is anomalous", func(t *testing.T) {
+		reader := &stubPriceReader{
+			fxRates: map[string][]domain.FXRate{
+				"USD/KRW": {
+					{Date: day1, Pair: "USD/KRW", Rate: 1345.50, IsAnomaly: true},
+					{Date: day2, Pair: "USD/KRW", Rate: 1900.00, IsAnomaly: true},
+				},
+			},
+		}
+
+		got := loadLatestFXRate(context.Background(), reader, day1, day2)
+		if got == nil {
+			t.Fatal("got nil, want an entry")
+		}
+		if got.Date != "2025-02-13" || got.Rate != 1900.00 {
+			t.Errorf("got %+v, want 2025-02-13/1900.00", got)
+		}
+	})
+}
+
 func TestGenerateSummary(t *testing.T) {
 	t.Run("full pipeline renders all sections", func(t *testing.T) {
 		reader := &stubPriceReader{
@@ -61,7 +143,7 @@ func TestGenerateSummary(t *testing.T) {
 		outputDir := t.TempDir()
 		outputPath := filepath.Join(outputDir, "summary.md")
 
-		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath); err != nil {
+		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath, FormatMarkdown, "", DefaultSummaryOptions(), RebalanceOptions{}, nil); err != nil {
 			t.Fatalf("GenerateSummary failed: %v", err)
 		}
 
@@ -94,6 +176,49 @@ func TestGenerateSummary(t *testing.T) {
 		}
 	})
 
+	t.Run("renders Liquidity section from order book snapshots", func(t *testing.T) {
+		reader := &stubPriceReader{
+			prices: map[string][]domain.DailyPrice{
+				"069500": makePriceSeries(baseDate, repeatFloat(35000, 30), 500),
+			},
+			fxRates: map[string][]domain.FXRate{},
+			orderBooks: map[string][]domain.OrderBookSnapshot{
+				"069500": {
+					{
+						Asks:      []domain.OrderBookLevel{{Price: 35010, Quantity: 100}},
+						Bids:      []domain.OrderBookLevel{{Price: 35000, Quantity: 200}},
+						Symbol:    "069500",
+						Timestamp: time.Now(),
+					},
+				},
+			},
+		}
+
+		watchlist := []domain.WatchlistEntry{
+			{Symbol: "069500", Name: "KODEX 200", Market: domain.MarketKR, Type: domain.SecurityTypeETF},
+		}
+
+		outputDir := t.TempDir()
+		outputPath := filepath.Join(outputDir, "summary.md")
+
+		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath, FormatMarkdown, "", DefaultSummaryOptions(), RebalanceOptions{}, nil); err != nil {
+			t.Fatalf("GenerateSummary failed: %v", err)
+		}
+
+		got, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("read output: %v", err)
+		}
+
+		content := string(got)
+		if !strings.Contains(content, "## Liquidity") {
+			t.Error("missing Liquidity section")
+		}
+		if !strings.Contains(content, "| 069500 |") {
+			t.Error("missing 069500 liquidity row")
+		}
+	})
+
 	t.Run("skips symbols with no price data", func(t *testing.T) {
 		reader := &stubPriceReader{
 			prices: map[string][]domain.DailyPrice{
@@ -111,7 +236,7 @@ func TestGenerateSummary(t *testing.T) {
 		outputDir := t.TempDir()
 		outputPath := filepath.Join(outputDir, "summary.md")
 
-		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath); err != nil {
+		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath, FormatMarkdown, "", DefaultSummaryOptions(), RebalanceOptions{}, nil); err != nil {
 			t.Fatalf("GenerateSummary failed: %v", err)
 		}
 
@@ -147,7 +272,7 @@ func TestGenerateSummary(t *testing.T) {
 		outputDir := t.TempDir()
 		outputPath := filepath.Join(outputDir, "summary.md")
 
-		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath); err != nil {
+		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath, FormatMarkdown, "", DefaultSummaryOptions(), RebalanceOptions{}, nil); err != nil {
 			t.Fatalf("GenerateSummary failed: %v", err)
 		}
 
@@ -180,7 +305,7 @@ func TestGenerateSummary(t *testing.T) {
 		outputDir := t.TempDir()
 		outputPath := filepath.Join(outputDir, "summary.md")
 
-		err := GenerateSummary(context.Background(), reader, watchlist, outputPath)
+		err := GenerateSummary(context.Background(), reader, watchlist, outputPath, FormatMarkdown, "", DefaultSummaryOptions(), RebalanceOptions{}, nil)
 		if err == nil {
 			t.Fatal("expected error from benchmark fetch, got nil")
 		}
@@ -208,7 +333,7 @@ func TestGenerateSummary(t *testing.T) {
 		outputDir := t.TempDir()
 		outputPath := filepath.Join(outputDir, "summary.md")
 
-		err := GenerateSummary(context.Background(), reader, watchlist, outputPath)
+		err := GenerateSummary(context.Background(), reader, watchlist, outputPath, FormatMarkdown, "", DefaultSummaryOptions(), RebalanceOptions{}, nil)
 		if err == nil {
 			t.Fatal("expected error from symbol fetch, got nil")
 		}
@@ -232,7 +357,7 @@ func TestGenerateSummary(t *testing.T) {
 		outputDir := t.TempDir()
 		outputPath := filepath.Join(outputDir, "summary.md")
 
-		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath); err != nil {
+		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath, FormatMarkdown, "", DefaultSummaryOptions(), RebalanceOptions{}, nil); err != nil {
 			t.Fatalf("GenerateSummary failed: %v", err)
 		}
 
@@ -245,4 +370,183 @@ func TestGenerateSummary(t *testing.T) {
 			t.Error("Exchange Rate section should be absent when no FX data")
 		}
 	})
+
+	t.Run("format all emits md, json, and html alongside each other", func(t *testing.T) {
+		reader := &stubPriceReader{
+			prices: map[string][]domain.DailyPrice{
+				"QQQ": makePriceSeries(baseDate, repeatFloat(100, 30), 1000),
+			},
+		}
+
+		watchlist := []domain.WatchlistEntry{
+			{Symbol: "QQQ", Name: "Invesco QQQ Trust", Market: domain.MarketUS, Type: domain.SecurityTypeETF},
+		}
+
+		outputDir := t.TempDir()
+		outputPath := filepath.Join(outputDir, "summary.md")
+
+		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath, FormatAll, "", DefaultSummaryOptions(), RebalanceOptions{}, nil); err != nil {
+			t.Fatalf("GenerateSummary failed: %v", err)
+		}
+
+		for _, name := range []string{"summary.md", "summary.json", "summary.html"} {
+			if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+				t.Errorf("expected %s to exist: %v", name, err)
+			}
+		}
+	})
+
+	t.Run("account number populates the Margin section in JSON output", func(t *testing.T) {
+		reader := &stubPriceReader{
+			prices: map[string][]domain.DailyPrice{
+				"QQQ": makePriceSeries(baseDate, repeatFloat(100, 30), 1000),
+			},
+			loans: []domain.MarginLoan{
+				{AccountNo: "1234567801", IssuedAt: baseDate, LoanID: "L1", Principal: 5000000, Status: domain.MarginLoanOpen, Symbol: "NVDA"},
+			},
+		}
+
+		watchlist := []domain.WatchlistEntry{
+			{Symbol: "QQQ", Name: "Invesco QQQ Trust", Market: domain.MarketUS, Type: domain.SecurityTypeETF},
+		}
+
+		outputDir := t.TempDir()
+		outputPath := filepath.Join(outputDir, "summary.json")
+
+		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath, FormatJSON, "1234567801", DefaultSummaryOptions(), RebalanceOptions{}, nil); err != nil {
+			t.Fatalf("GenerateSummary failed: %v", err)
+		}
+
+		got, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("read output: %v", err)
+		}
+		if !strings.Contains(string(got), `"outstanding_principal": 5000000`) {
+			t.Errorf("expected outstanding_principal in output, got: %s", got)
+		}
+	})
+
+	t.Run("no account number omits the Margin section", func(t *testing.T) {
+		reader := &stubPriceReader{
+			prices: map[string][]domain.DailyPrice{
+				"QQQ": makePriceSeries(baseDate, repeatFloat(100, 30), 1000),
+			},
+		}
+
+		watchlist := []domain.WatchlistEntry{
+			{Symbol: "QQQ", Name: "Invesco QQQ Trust", Market: domain.MarketUS, Type: domain.SecurityTypeETF},
+		}
+
+		outputDir := t.TempDir()
+		outputPath := filepath.Join(outputDir, "summary.json")
+
+		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath, FormatJSON, "", DefaultSummaryOptions(), RebalanceOptions{}, nil); err != nil {
+			t.Fatalf("GenerateSummary failed: %v", err)
+		}
+
+		got, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("read output: %v", err)
+		}
+		if !strings.Contains(string(got), `"margin": null`) {
+			t.Errorf("expected margin: null in output, got: %s", got)
+		}
+	})
+
+	t.Run("holdings populate the Rebalance section in JSON output", func(t *testing.T) {
+		reader := &stubPriceReader{
+			prices: map[string][]domain.DailyPrice{
+				"069500": makePriceSeries(baseDate, repeatFloat(35000, 30), 500),
+			},
+		}
+
+		watchlist := []domain.WatchlistEntry{
+			{Symbol: "069500", Name: "KODEX 200", Market: domain.MarketKR, Type: domain.SecurityTypeETF, TargetWeight: 0.5},
+		}
+
+		outputDir := t.TempDir()
+		outputPath := filepath.Join(outputDir, "summary.json")
+
+		rebalanceOpts := RebalanceOptions{
+			DriftBandPct: 5,
+			Holdings:     []domain.Holding{{Symbol: "069500", Quantity: 40, Currency: "KRW"}},
+			LotSize:      1,
+		}
+		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath, FormatJSON, "", DefaultSummaryOptions(), rebalanceOpts, nil); err != nil {
+			t.Fatalf("GenerateSummary failed: %v", err)
+		}
+
+		got, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("read output: %v", err)
+		}
+		// A single holding at 100% of the (single-holding) portfolio against a 50%
+		// target drifts well past the 5pp band, so an action is expected.
+		if !strings.Contains(string(got), `"notional_krw"`) {
+			t.Errorf("expected a non-empty rebalance section in output, got: %s", got)
+		}
+	})
+
+	t.Run("no holdings omits the Rebalance section", func(t *testing.T) {
+		reader := &stubPriceReader{
+			prices: map[string][]domain.DailyPrice{
+				"QQQ": makePriceSeries(baseDate, repeatFloat(100, 30), 1000),
+			},
+		}
+
+		watchlist := []domain.WatchlistEntry{
+			{Symbol: "QQQ", Name: "Invesco QQQ Trust", Market: domain.MarketUS, Type: domain.SecurityTypeETF},
+		}
+
+		outputDir := t.TempDir()
+		outputPath := filepath.Join(outputDir, "summary.json")
+
+		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath, FormatJSON, "", DefaultSummaryOptions(), RebalanceOptions{}, nil); err != nil {
+			t.Fatalf("GenerateSummary failed: %v", err)
+		}
+
+		got, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("read output: %v", err)
+		}
+		if !strings.Contains(string(got), `"rebalance": null`) {
+			t.Errorf("expected rebalance: null in output, got: %s", got)
+		}
+	})
+
+	t.Run("non-nil riskWriter persists each symbol's risk metrics", func(t *testing.T) {
+		closes := make([]float64, 25)
+		for i := range closes {
+			closes[i] = 100 + float64(i)
+		}
+		reader := &stubPriceReader{
+			prices: map[string][]domain.DailyPrice{
+				"NVDA": makePriceSeries(baseDate, closes, 1000),
+			},
+		}
+
+		watchlist := []domain.WatchlistEntry{
+			{Symbol: "NVDA", Name: "NVIDIA", Market: domain.MarketUS, Type: domain.SecurityTypeStock},
+		}
+
+		outputDir := t.TempDir()
+		outputPath := filepath.Join(outputDir, "summary.json")
+
+		writer := &stubRiskWriter{}
+		opts := DefaultSummaryOptions()
+		opts.MinSamples = 20
+		if err := GenerateSummary(context.Background(), reader, watchlist, outputPath, FormatJSON, "", opts, RebalanceOptions{}, writer); err != nil {
+			t.Fatalf("GenerateSummary failed: %v", err)
+		}
+
+		if len(writer.metrics) != 1 {
+			t.Fatalf("got %d persisted metrics, want 1", len(writer.metrics))
+		}
+		if writer.metrics[0].Symbol != "NVDA" {
+			t.Errorf("Symbol = %q, want %q", writer.metrics[0].Symbol, "NVDA")
+		}
+		if writer.metrics[0].CAGR == nil {
+			t.Error("CAGR should not be nil")
+		}
+	})
 }
@@ -193,6 +193,87 @@ func HistoricalVolatility(prices []domain.DailyPrice, days int) *float64 {
 	return &hv
 }
 
+// RSI returns the Relative Strength Index over period using Wilder's smoothing:
+// the initial average gain/loss is the SMA of the first period deltas, then each
+// later delta folds in via avg = (prev*(period-1) + current) / period.
+// Returns nil if fewer than period+1 non-anomaly entries exist. A zero average
+// loss (the series never fell) reports RSI 100 rather than dividing by zero.
+func RSI(prices []domain.DailyPrice, period int) *float64 {
+	adjCloses := extractAdjCloses(prices)
+	if len(adjCloses) < period+1 {
+		return nil
+	}
+
+	var avgGain, avgLoss float64
+	for i := 1; i <= period; i++ {
+		delta := adjCloses[i] - adjCloses[i-1]
+		if delta > 0 {
+			avgGain += delta
+		} else {
+			avgLoss += -delta
+		}
+	}
+	avgGain /= float64(period)
+	avgLoss /= float64(period)
+
+	for i := period + 1; i < len(adjCloses); i++ {
+		delta := adjCloses[i] - adjCloses[i-1]
+		var gain, loss float64
+		if delta > 0 {
+			gain = delta
+		} else {
+			loss = -delta
+		}
+		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+	}
+
+	if avgLoss == 0 {
+		rsi := 100.0
+		return &rsi
+	}
+
+	rs := avgGain / avgLoss
+	rsi := 100 - 100/(1+rs)
+	return &rsi
+}
+
+// MACDResult holds the MACD line (fast EMA minus slow EMA), its signal line
+// (an EMA of the MACD line), and their difference (the histogram).
+type MACDResult struct {
+	Macd   float64
+	Signal float64
+	Hist   float64
+}
+
+// MACD returns the Moving Average Convergence/Divergence indicator: the
+// fastPeriod-EMA of adj_close minus the slowPeriod-EMA, a signalPeriod-EMA of
+// that difference, and their spread. Each EMA is seeded with the SMA of its
+// first N values, then carried forward with ema_t = alpha*close +
+// (1-alpha)*ema_{t-1}, alpha = 2/(N+1). Returns nil if fewer than
+// slowPeriod+signalPeriod non-anomaly entries exist.
+func MACD(prices []domain.DailyPrice, fastPeriod, slowPeriod, signalPeriod int) *MACDResult {
+	adjCloses := extractAdjCloses(prices)
+	if len(adjCloses) < slowPeriod+signalPeriod {
+		return nil
+	}
+
+	fastEMA := emaSeries(adjCloses, fastPeriod)
+	slowEMA := emaSeries(adjCloses, slowPeriod)
+
+	macdLine := make([]float64, len(adjCloses)-slowPeriod+1)
+	for i := range macdLine {
+		idx := slowPeriod - 1 + i
+		macdLine[i] = fastEMA[idx] - slowEMA[idx]
+	}
+
+	signalEMA := emaSeries(macdLine, signalPeriod)
+
+	macd := macdLine[len(macdLine)-1]
+	signal := signalEMA[len(signalEMA)-1]
+	return &MACDResult{Macd: macd, Signal: signal, Hist: macd - signal}
+}
+
 func extractAdjCloses(prices []domain.DailyPrice) []float64 {
 	result := make([]float64, 0, len(prices))
 	for _, p := range prices {
@@ -213,6 +294,31 @@ func extractVolumes(prices []domain.DailyPrice) []int64 {
 	return result
 }
 
+// emaSeries returns the exponential moving average of values, indexed the
+// same as values: entries before period-1 are left at zero (unseeded) since
+// callers only ever read from period-1 onward. The EMA is seeded with the SMA
+// of the first period values, then carried forward with ema_t = alpha*v +
+// (1-alpha)*ema_{t-1}, alpha = 2/(period+1).
+func emaSeries(values []float64, period int) []float64 {
+	ema := make([]float64, len(values))
+	if len(values) < period {
+		return ema
+	}
+
+	var seed float64
+	for i := 0; i < period; i++ {
+		seed += values[i]
+	}
+	ema[period-1] = seed / float64(period)
+
+	alpha := 2.0 / float64(period+1)
+	for i := period; i < len(values); i++ {
+		ema[i] = alpha*values[i] + (1-alpha)*ema[i-1]
+	}
+
+	return ema
+}
+
 func movingAverageAt(adjCloses []float64, endIdx int, days int) *float64 {
 	if endIdx < days-1 || endIdx < 0 || days <= 0 {
 		return nil
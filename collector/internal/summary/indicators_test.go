@@ -407,3 +407,82 @@ func TestHistoricalVolatility(t *testing.T) {
 		assertAlmostEqual(t, got, 0.0, 0.001, "HistoricalVolatility(2)")
 	})
 }
+
+func TestRSI(t *testing.T) {
+	t.Run("all gains returns 100", func(t *testing.T) {
+		adjCloses := make([]float64, 15)
+		for i := range adjCloses {
+			adjCloses[i] = 100 + float64(i)
+		}
+		prices := makePriceSeries(baseDate, adjCloses, 1000)
+
+		got := RSI(prices, 14)
+		assertAlmostEqual(t, got, 100.0, 0.001, "RSI(14)")
+	})
+
+	t.Run("all losses returns near zero", func(t *testing.T) {
+		adjCloses := make([]float64, 15)
+		for i := range adjCloses {
+			adjCloses[i] = 100 - float64(i)
+		}
+		prices := makePriceSeries(baseDate, adjCloses, 1000)
+
+		got := RSI(prices, 14)
+		assertAlmostEqual(t, got, 0.0, 0.001, "RSI(14)")
+	})
+
+	t.Run("constant prices returns 100 (zero avg loss)", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, repeatFloat(100, 15), 1000)
+
+		got := RSI(prices, 14)
+		assertAlmostEqual(t, got, 100.0, 0.001, "RSI(14)")
+	})
+
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, repeatFloat(100, 10), 1000)
+		assertNil(t, RSI(prices, 14), "RSI(14)")
+	})
+}
+
+func TestMACD(t *testing.T) {
+	t.Run("sufficient data returns non-nil macd/signal/hist", func(t *testing.T) {
+		// 40 flat days, then 20 days rising: the fast EMA pulls ahead of the slow
+		// EMA once the trend starts, and the signal line (an EMA of that gap)
+		// lags behind it, so both Macd and Hist should be positive.
+		adjCloses := repeatFloat(100, 40)
+		for i := 1; i <= 20; i++ {
+			adjCloses = append(adjCloses, 100+float64(i)*2.5)
+		}
+		prices := makePriceSeries(baseDate, adjCloses, 1000)
+
+		got := MACD(prices, 12, 26, 9)
+		if got == nil {
+			t.Fatal("MACD = nil, want non-nil")
+		}
+		if got.Macd <= 0 {
+			t.Errorf("Macd = %v, want positive once the uptrend starts", got.Macd)
+		}
+		if got.Hist <= 0 {
+			t.Errorf("Hist = %v, want positive once the uptrend starts", got.Hist)
+		}
+	})
+
+	t.Run("constant prices returns zero macd", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, repeatFloat(100, 40), 1000)
+
+		got := MACD(prices, 12, 26, 9)
+		if got == nil {
+			t.Fatal("MACD = nil, want non-nil")
+		}
+		assertAlmostEqual(t, &got.Macd, 0.0, 0.001, "MACD.Macd")
+		assertAlmostEqual(t, &got.Hist, 0.0, 0.001, "MACD.Hist")
+	})
+
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, repeatFloat(100, 20), 1000)
+		got := MACD(prices, 12, 26, 9)
+		if got != nil {
+			t.Errorf("MACD = %+v, want nil", got)
+		}
+	})
+}
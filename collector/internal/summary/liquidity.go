@@ -0,0 +1,61 @@
+package summary
+
+import "github.com/jusikbot/collector/internal/domain"
+
+// LiquidityEntry summarizes order book depth for one symbol averaged over the
+// lookback window: a wider spread and thinner top-of-book depth signal an
+// order is harder to fill without moving the price.
+type LiquidityEntry struct {
+	AvgSpreadBps float64 `json:"avg_spread_bps"`
+	Imbalance    float64 `json:"imbalance"`
+	Name         string  `json:"name"`
+	Symbol       string  `json:"symbol"`
+	TopDepth     int64   `json:"top_depth"`
+}
+
+// ComputeLiquidity averages spread (in bps of mid price), top-of-book depth
+// (best bid + best ask quantity), and order imbalance (bidVol / (bidVol+askVol))
+// across every snapshot in snapshots. Returns ok=false when no snapshot has both
+// a best bid and a best ask to compute a spread from.
+func ComputeLiquidity(snapshots []domain.OrderBookSnapshot) (LiquidityEntry, bool) {
+	var spreadBpsSum, topDepthSum, bidVolSum, askVolSum float64
+	var n int
+
+	for _, s := range snapshots {
+		if len(s.Bids) == 0 || len(s.Asks) == 0 {
+			continue
+		}
+
+		bestBid := s.Bids[0].Price
+		bestAsk := s.Asks[0].Price
+		mid := (bestBid + bestAsk) / 2
+		if mid <= 0 {
+			continue
+		}
+
+		spreadBpsSum += (bestAsk - bestBid) / mid * 10000
+		topDepthSum += float64(s.Bids[0].Quantity + s.Asks[0].Quantity)
+		n++
+
+		for _, b := range s.Bids {
+			bidVolSum += float64(b.Quantity)
+		}
+		for _, a := range s.Asks {
+			askVolSum += float64(a.Quantity)
+		}
+	}
+
+	if n == 0 {
+		return LiquidityEntry{}, false
+	}
+
+	entry := LiquidityEntry{
+		AvgSpreadBps: spreadBpsSum / float64(n),
+		TopDepth:     int64(topDepthSum / float64(n)),
+	}
+	if totalVol := bidVolSum + askVolSum; totalVol > 0 {
+		entry.Imbalance = bidVolSum / totalVol
+	}
+
+	return entry, true
+}
@@ -0,0 +1,67 @@
+package summary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func TestComputeLiquidity(t *testing.T) {
+	t.Run("averages spread, depth, and imbalance across snapshots", func(t *testing.T) {
+		snapshots := []domain.OrderBookSnapshot{
+			{
+				Asks:      []domain.OrderBookLevel{{Price: 101.0, Quantity: 100}},
+				Bids:      []domain.OrderBookLevel{{Price: 100.0, Quantity: 300}},
+				Symbol:    "005930",
+				Timestamp: baseDate,
+			},
+			{
+				Asks:      []domain.OrderBookLevel{{Price: 103.0, Quantity: 200}},
+				Bids:      []domain.OrderBookLevel{{Price: 101.0, Quantity: 100}},
+				Symbol:    "005930",
+				Timestamp: baseDate.Add(5 * time.Second),
+			},
+		}
+
+		got, ok := ComputeLiquidity(snapshots)
+		if !ok {
+			t.Fatal("ok = false, want true")
+		}
+
+		// Snapshot 1: spread=1, mid=100.5 -> 1/100.5*10000 ≈ 99.50 bps
+		// Snapshot 2: spread=2, mid=102 -> 2/102*10000 ≈ 196.08 bps
+		// avg ≈ 147.79 bps
+		assertAlmostEqual(t, &got.AvgSpreadBps, 147.79, 0.5, "AvgSpreadBps")
+
+		// TopDepth: snapshot 1 = 300+100=400, snapshot 2 = 100+200=300 -> avg 350
+		if got.TopDepth != 350 {
+			t.Errorf("TopDepth = %d, want 350", got.TopDepth)
+		}
+
+		// Imbalance: total bid = 300+100=400, total ask = 100+200=300, total=700
+		// imbalance = 400/700 ≈ 0.5714
+		wantImbalance := 0.5714
+		if diff := got.Imbalance - wantImbalance; diff < -0.001 || diff > 0.001 {
+			t.Errorf("Imbalance = %v, want ~%v", got.Imbalance, wantImbalance)
+		}
+	})
+
+	t.Run("empty snapshots returns ok=false", func(t *testing.T) {
+		_, ok := ComputeLiquidity(nil)
+		if ok {
+			t.Error("ok = true, want false for empty snapshots")
+		}
+	})
+
+	t.Run("snapshot missing a side is skipped", func(t *testing.T) {
+		snapshots := []domain.OrderBookSnapshot{
+			{Asks: nil, Bids: []domain.OrderBookLevel{{Price: 100, Quantity: 10}}, Symbol: "005930", Timestamp: baseDate},
+		}
+
+		_, ok := ComputeLiquidity(snapshots)
+		if ok {
+			t.Error("ok = true, want false when every snapshot lacks a best ask")
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package summary
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// Why 30: mirrors corporateActionWindowDays — a 30-day window keeps "recent
+// repayments" focused on activity still relevant to a reader checking today's
+// margin exposure, without listing the full repayment history.
+const marginRepaymentWindowDays = 30
+
+// MarginSummary aggregates an account's margin (신용거래) position for the
+// summary report: what's outstanding, what it's costing this month, and what
+// was recently paid down.
+type MarginSummary struct {
+	MTDInterest          float64                `json:"mtd_interest"`
+	OutstandingLoans     []MarginLoanEntry      `json:"outstanding_loans"`
+	OutstandingPrincipal float64                `json:"outstanding_principal"`
+	RecentRepayments     []MarginRepaymentEntry `json:"recent_repayments"`
+}
+
+// MarginLoanEntry is a single outstanding margin loan formatted for the summary report.
+type MarginLoanEntry struct {
+	IssuedAt  string  `json:"issued_at"`
+	Principal float64 `json:"principal"`
+	Symbol    string  `json:"symbol"`
+}
+
+// MarginRepaymentEntry is a single margin repayment formatted for the summary report.
+type MarginRepaymentEntry struct {
+	Amount float64 `json:"amount"`
+	Date   string  `json:"date"`
+	LoanID string  `json:"loan_id"`
+}
+
+// ComputeMarginSummary aggregates outstanding loan principal, month-to-date
+// interest, and repayments within marginRepaymentWindowDays, each sorted
+// ascending by date.
+func ComputeMarginSummary(
+	loans []domain.MarginLoan,
+	interest []domain.MarginInterest,
+	repayments []domain.MarginRepayment,
+	now time.Time,
+) MarginSummary {
+	var outstandingPrincipal float64
+	outstandingLoans := make([]MarginLoanEntry, 0, len(loans))
+	for _, l := range loans {
+		if l.Status != domain.MarginLoanOpen {
+			continue
+		}
+		outstandingPrincipal += l.Principal
+		outstandingLoans = append(outstandingLoans, MarginLoanEntry{
+			IssuedAt:  l.IssuedAt.Format("2006-01-02"),
+			Principal: l.Principal,
+			Symbol:    l.Symbol,
+		})
+	}
+	sort.Slice(outstandingLoans, func(i, j int) bool { return outstandingLoans[i].IssuedAt < outstandingLoans[j].IssuedAt })
+
+	var mtdInterest float64
+	for _, e := range interest {
+		if e.Date.Year() == now.Year() && e.Date.Month() == now.Month() {
+			mtdInterest += e.Amount
+		}
+	}
+
+	from := now.AddDate(0, 0, -marginRepaymentWindowDays)
+	recentRepayments := make([]MarginRepaymentEntry, 0, len(repayments))
+	for _, rp := range repayments {
+		if rp.Date.Before(from) || rp.Date.After(now) {
+			continue
+		}
+		recentRepayments = append(recentRepayments, MarginRepaymentEntry{
+			Amount: rp.Amount,
+			Date:   rp.Date.Format("2006-01-02"),
+			LoanID: rp.LoanID,
+		})
+	}
+	sort.Slice(recentRepayments, func(i, j int) bool { return recentRepayments[i].Date < recentRepayments[j].Date })
+
+	return MarginSummary{
+		MTDInterest:          mtdInterest,
+		OutstandingLoans:     outstandingLoans,
+		OutstandingPrincipal: outstandingPrincipal,
+		RecentRepayments:     recentRepayments,
+	}
+}
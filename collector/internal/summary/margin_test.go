@@ -0,0 +1,73 @@
+package summary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func TestComputeMarginSummary(t *testing.T) {
+	now := baseDate
+
+	t.Run("sums outstanding principal and excludes closed loans", func(t *testing.T) {
+		loans := []domain.MarginLoan{
+			{AccountNo: "1234567801", IssuedAt: now.AddDate(0, 0, -10), LoanID: "L1", Principal: 5000000, Status: domain.MarginLoanOpen, Symbol: "NVDA"},
+			{AccountNo: "1234567801", IssuedAt: now.AddDate(0, 0, -5), LoanID: "L2", Principal: 1000000, Status: domain.MarginLoanClosed, Symbol: "META"},
+		}
+
+		got := ComputeMarginSummary(loans, nil, nil, now)
+		if got.OutstandingPrincipal != 5000000 {
+			t.Errorf("OutstandingPrincipal = %v, want 5000000", got.OutstandingPrincipal)
+		}
+		if len(got.OutstandingLoans) != 1 {
+			t.Fatalf("len(OutstandingLoans) = %d, want 1", len(got.OutstandingLoans))
+		}
+		if got.OutstandingLoans[0].Symbol != "NVDA" {
+			t.Errorf("OutstandingLoans[0].Symbol = %q, want NVDA", got.OutstandingLoans[0].Symbol)
+		}
+	})
+
+	t.Run("sums interest within the current month only", func(t *testing.T) {
+		// Why not the package-level baseDate: it's the 1st of the month, so
+		// "yesterday" would fall in the prior month and break the "-1 day is
+		// still in range" case this test means to cover.
+		now := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+		interest := []domain.MarginInterest{
+			{AccountNo: "1234567801", Amount: 100, Date: now},
+			{AccountNo: "1234567801", Amount: 200, Date: now.AddDate(0, 0, -1)},
+			{AccountNo: "1234567801", Amount: 9999, Date: now.AddDate(0, -1, 0)},
+		}
+
+		got := ComputeMarginSummary(nil, interest, nil, now)
+		if got.MTDInterest != 300 {
+			t.Errorf("MTDInterest = %v, want 300", got.MTDInterest)
+		}
+	})
+
+	t.Run("keeps repayments within the window, sorted ascending", func(t *testing.T) {
+		repayments := []domain.MarginRepayment{
+			{AccountNo: "1234567801", Amount: 500000, Date: now.AddDate(0, 0, -5), LoanID: "L2"},
+			{AccountNo: "1234567801", Amount: 1000000, Date: now.AddDate(0, 0, -10), LoanID: "L1"},
+			{AccountNo: "1234567801", Amount: 2000000, Date: now.AddDate(0, 0, -60), LoanID: "L3"},
+		}
+
+		got := ComputeMarginSummary(nil, nil, repayments, now)
+		if len(got.RecentRepayments) != 2 {
+			t.Fatalf("len(RecentRepayments) = %d, want 2", len(got.RecentRepayments))
+		}
+		if got.RecentRepayments[0].LoanID != "L1" || got.RecentRepayments[1].LoanID != "L2" {
+			t.Errorf("repayments not sorted ascending by date: %+v", got.RecentRepayments)
+		}
+	})
+
+	t.Run("empty input returns zero-value summary", func(t *testing.T) {
+		got := ComputeMarginSummary(nil, nil, nil, now)
+		if got.OutstandingPrincipal != 0 || got.MTDInterest != 0 {
+			t.Errorf("expected zero-value summary, got %+v", got)
+		}
+		if len(got.OutstandingLoans) != 0 || len(got.RecentRepayments) != 0 {
+			t.Errorf("expected empty slices, got %+v", got)
+		}
+	})
+}
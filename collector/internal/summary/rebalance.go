@@ -0,0 +1,167 @@
+package summary
+
+import (
+	"math"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// RebalanceOptions configures the opt-in "## Rebalance" section. A nil/empty
+// Holdings disables the section entirely, so every existing GenerateSummary
+// caller is unaffected unless it opts in.
+type RebalanceOptions struct {
+	// DriftBandPct is the minimum |current weight - target weight|, in
+	// percentage points, before a symbol is flagged for rebalancing.
+	DriftBandPct float64
+	Holdings     []domain.Holding
+	// LotSize rounds suggested share counts down to the nearest multiple.
+	// Values <= 0 are treated as 1 (no rounding). Overridden per-symbol by
+	// LotSizes when present.
+	LotSize int64
+	// LotSizes overrides LotSize for specific symbols, e.g. with the real
+	// exchange-mandated lot size from kis.InstrumentCache. A symbol missing
+	// from this map falls back to LotSize.
+	LotSizes map[string]int64
+	// TickSizes snaps a symbol's suggested limit price to the exchange's
+	// minimum price increment, e.g. from kis.InstrumentCache. A symbol missing
+	// from this map (or a tick size <= 0) is left unsnapped.
+	TickSizes map[string]float64
+}
+
+// DefaultRebalanceOptions returns a 5 percentage point drift band and a lot
+// size of 1 (no rounding). Holdings is left nil, which disables the section.
+func DefaultRebalanceOptions() RebalanceOptions {
+	return RebalanceOptions{DriftBandPct: 5, LotSize: 1}
+}
+
+// RebalanceAction suggests a buy or sell to close one symbol's drift from its
+// target weight.
+type RebalanceAction struct {
+	CurrentWeight float64 `json:"current_weight"`
+	Drift         float64 `json:"drift"`
+	// LimitPriceKRW is the holding's latest KRW price, snapped to the
+	// symbol's tick size when opts.TickSizes has an entry for it.
+	LimitPriceKRW float64 `json:"limit_price_krw"`
+	Name          string  `json:"name"`
+	NotionalKRW   float64 `json:"notional_krw"`
+	Shares        int64   `json:"shares"`
+	Side          string  `json:"side"`
+	Symbol        string  `json:"symbol"`
+	TargetWeight  float64 `json:"target_weight"`
+}
+
+// RebalanceSummary is the computed Rebalance section: one action per symbol
+// whose drift exceeds the configured band, plus the total cash needed to
+// execute all of them.
+type RebalanceSummary struct {
+	Actions           []RebalanceAction `json:"actions"`
+	TotalCashDeltaKRW float64           `json:"total_cash_delta_krw"`
+}
+
+// ComputeRebalance converts holdings into KRW-normalized market values using
+// latestPrices (keyed by symbol, in the holding's own currency) and fxRate
+// (for non-KRW holdings), weighs each symbol against its watchlist entry's
+// TargetWeight, and returns a suggested buy/sell action for every symbol whose
+// drift exceeds opts.DriftBandPct. A holding is skipped (and returned in
+// skipped) when its latest price is unavailable, or when it's priced in a
+// non-KRW currency and fxRate is nil.
+func ComputeRebalance(
+	holdings []domain.Holding,
+	watchlist []domain.WatchlistEntry,
+	latestPrices map[string]float64,
+	fxRate *FXRateEntry,
+	opts RebalanceOptions,
+) (summary RebalanceSummary, skipped []string) {
+	targets := make(map[string]domain.WatchlistEntry, len(watchlist))
+	for _, e := range watchlist {
+		targets[e.Symbol] = e
+	}
+
+	type valuedHolding struct {
+		holding     domain.Holding
+		marketValue float64
+		priceKRW    float64
+	}
+
+	valued := make([]valuedHolding, 0, len(holdings))
+	var total float64
+	for _, h := range holdings {
+		price, ok := latestPrices[h.Symbol]
+		if !ok {
+			skipped = append(skipped, h.Symbol)
+			continue
+		}
+
+		priceKRW := price
+		if h.Currency != "KRW" {
+			if fxRate == nil {
+				skipped = append(skipped, h.Symbol)
+				continue
+			}
+			priceKRW *= fxRate.Rate
+		}
+
+		marketValue := h.Quantity * priceKRW
+		valued = append(valued, valuedHolding{holding: h, marketValue: marketValue, priceKRW: priceKRW})
+		total += marketValue
+	}
+
+	if total == 0 {
+		return summary, skipped
+	}
+
+	defaultLotSize := opts.LotSize
+	if defaultLotSize <= 0 {
+		defaultLotSize = 1
+	}
+
+	for _, v := range valued {
+		target, ok := targets[v.holding.Symbol]
+		if !ok {
+			continue
+		}
+
+		currentWeight := v.marketValue / total
+		drift := currentWeight - target.TargetWeight
+		if math.Abs(drift)*100 <= opts.DriftBandPct {
+			continue
+		}
+
+		lotSize := defaultLotSize
+		if override, ok := opts.LotSizes[v.holding.Symbol]; ok && override > 0 {
+			lotSize = override
+		}
+
+		notional := -drift * total
+		shares := int64(math.Abs(notional)/v.priceKRW/float64(lotSize)) * lotSize
+
+		side := "buy"
+		if notional < 0 {
+			side = "sell"
+		}
+
+		summary.Actions = append(summary.Actions, RebalanceAction{
+			CurrentWeight: currentWeight,
+			Drift:         drift,
+			LimitPriceKRW: snapToTick(v.priceKRW, opts.TickSizes[v.holding.Symbol]),
+			Name:          target.Name,
+			NotionalKRW:   notional,
+			Shares:        shares,
+			Side:          side,
+			Symbol:        v.holding.Symbol,
+			TargetWeight:  target.TargetWeight,
+		})
+		summary.TotalCashDeltaKRW += notional
+	}
+
+	return summary, skipped
+}
+
+// snapToTick rounds price to the nearest multiple of tickSize. A tickSize <= 0
+// (no known tick size for this symbol) returns price unchanged.
+func snapToTick(price, tickSize float64) float64 {
+	if tickSize <= 0 {
+		return price
+	}
+	return math.Round(price/tickSize) * tickSize
+}
@@ -0,0 +1,170 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// actionFor returns the action for symbol, failing the test if none exists.
+// With only two watchlist symbols summing to 100% target weight, a drift on
+// one necessarily drifts the other in the opposite direction, so tests that
+// push one symbol out of band must expect both actions back, not just one.
+func actionFor(t *testing.T, actions []RebalanceAction, symbol string) RebalanceAction {
+	t.Helper()
+	for _, a := range actions {
+		if a.Symbol == symbol {
+			return a
+		}
+	}
+	t.Fatalf("no action for %s in %+v", symbol, actions)
+	return RebalanceAction{}
+}
+
+func TestComputeRebalance(t *testing.T) {
+	watchlist := []domain.WatchlistEntry{
+		{Symbol: "NVDA", Name: "NVIDIA", Market: domain.MarketUS, TargetWeight: 0.5},
+		{Symbol: "069500", Name: "KODEX 200", Market: domain.MarketKR, TargetWeight: 0.5},
+	}
+	fxRate := &FXRateEntry{Pair: "USD/KRW", Rate: 1400}
+
+	t.Run("no drift", func(t *testing.T) {
+		// NVDA: 10 * 100 * 1400 = 1,400,000 KRW. 069500: 40 * 35000 = 1,400,000 KRW.
+		// Both sit exactly at their 50% target, so no action is suggested.
+		holdings := []domain.Holding{
+			{Symbol: "NVDA", Quantity: 10, Currency: "USD"},
+			{Symbol: "069500", Quantity: 40, Currency: "KRW"},
+		}
+		latestPrices := map[string]float64{"NVDA": 100, "069500": 35000}
+
+		summary, skipped := ComputeRebalance(holdings, watchlist, latestPrices, fxRate, DefaultRebalanceOptions())
+
+		if len(skipped) != 0 {
+			t.Fatalf("skipped = %v, want none", skipped)
+		}
+		if len(summary.Actions) != 0 {
+			t.Fatalf("Actions = %v, want none", summary.Actions)
+		}
+	})
+
+	t.Run("above-band drift triggers a sell", func(t *testing.T) {
+		// NVDA is overweight: 15 * 100 * 1400 = 2,100,000 vs 069500's 1,400,000,
+		// for a 60%/40% split against a 50%/50% target (10pp drift > 5pp band).
+		holdings := []domain.Holding{
+			{Symbol: "NVDA", Quantity: 15, Currency: "USD"},
+			{Symbol: "069500", Quantity: 40, Currency: "KRW"},
+		}
+		latestPrices := map[string]float64{"NVDA": 100, "069500": 35000}
+
+		summary, skipped := ComputeRebalance(holdings, watchlist, latestPrices, fxRate, DefaultRebalanceOptions())
+
+		if len(skipped) != 0 {
+			t.Fatalf("skipped = %v, want none", skipped)
+		}
+		// Both symbols drift past the band: NVDA overweight, 069500 underweight
+		// by the same amount, since they're the only two entries and sum to 100%.
+		if len(summary.Actions) != 2 {
+			t.Fatalf("len(Actions) = %d, want 2", len(summary.Actions))
+		}
+		action := actionFor(t, summary.Actions, "NVDA")
+		if action.Side != "sell" {
+			t.Errorf("Side = %q, want sell", action.Side)
+		}
+		if action.NotionalKRW >= 0 {
+			t.Errorf("NotionalKRW = %v, want negative (sell)", action.NotionalKRW)
+		}
+	})
+
+	t.Run("above-band drift triggers a buy", func(t *testing.T) {
+		// NVDA is underweight: 5 * 100 * 1400 = 700,000 vs 069500's 1,400,000,
+		// for a 33%/67% split against a 50%/50% target.
+		holdings := []domain.Holding{
+			{Symbol: "NVDA", Quantity: 5, Currency: "USD"},
+			{Symbol: "069500", Quantity: 40, Currency: "KRW"},
+		}
+		latestPrices := map[string]float64{"NVDA": 100, "069500": 35000}
+
+		summary, skipped := ComputeRebalance(holdings, watchlist, latestPrices, fxRate, DefaultRebalanceOptions())
+
+		if len(skipped) != 0 {
+			t.Fatalf("skipped = %v, want none", skipped)
+		}
+		// Both symbols drift past the band: NVDA underweight, 069500 overweight
+		// by the same amount, since they're the only two entries and sum to 100%.
+		if len(summary.Actions) != 2 {
+			t.Fatalf("len(Actions) = %d, want 2", len(summary.Actions))
+		}
+		action := actionFor(t, summary.Actions, "NVDA")
+		if action.Side != "buy" {
+			t.Errorf("Side = %q, want buy", action.Side)
+		}
+		if action.NotionalKRW <= 0 {
+			t.Errorf("NotionalKRW = %v, want positive (buy)", action.NotionalKRW)
+		}
+		if action.Shares <= 0 {
+			t.Errorf("Shares = %d, want > 0", action.Shares)
+		}
+	})
+
+	t.Run("fx missing skips non-KRW holdings", func(t *testing.T) {
+		holdings := []domain.Holding{
+			{Symbol: "NVDA", Quantity: 15, Currency: "USD"},
+			{Symbol: "069500", Quantity: 40, Currency: "KRW"},
+		}
+		latestPrices := map[string]float64{"NVDA": 100, "069500": 35000}
+
+		summary, skipped := ComputeRebalance(holdings, watchlist, latestPrices, nil, DefaultRebalanceOptions())
+
+		if len(skipped) != 1 || skipped[0] != "NVDA" {
+			t.Fatalf("skipped = %v, want [NVDA]", skipped)
+		}
+		// Only 069500 could be valued, and as the sole holding it's 100% of the
+		// (reduced) total, i.e. still above its 50% target.
+		if len(summary.Actions) != 1 || summary.Actions[0].Symbol != "069500" {
+			t.Fatalf("Actions = %v, want one action for 069500", summary.Actions)
+		}
+	})
+
+	t.Run("LotSizes overrides LotSize for a specific symbol", func(t *testing.T) {
+		holdings := []domain.Holding{
+			{Symbol: "NVDA", Quantity: 15, Currency: "USD"},
+			{Symbol: "069500", Quantity: 40, Currency: "KRW"},
+		}
+		latestPrices := map[string]float64{"NVDA": 100, "069500": 35000}
+
+		opts := DefaultRebalanceOptions()
+		opts.LotSizes = map[string]int64{"NVDA": 10}
+
+		summary, _ := ComputeRebalance(holdings, watchlist, latestPrices, fxRate, opts)
+
+		// Both symbols drift past the band (see "above-band drift triggers a sell").
+		if len(summary.Actions) != 2 {
+			t.Fatalf("len(Actions) = %d, want 2", len(summary.Actions))
+		}
+		if shares := actionFor(t, summary.Actions, "NVDA").Shares; shares%10 != 0 {
+			t.Errorf("Shares = %d, want a multiple of the overridden lot size 10", shares)
+		}
+	})
+
+	t.Run("TickSizes snaps the suggested limit price", func(t *testing.T) {
+		holdings := []domain.Holding{
+			{Symbol: "NVDA", Quantity: 15, Currency: "USD"},
+			{Symbol: "069500", Quantity: 40, Currency: "KRW"},
+		}
+		latestPrices := map[string]float64{"NVDA": 100, "069500": 35050}
+
+		opts := DefaultRebalanceOptions()
+		opts.TickSizes = map[string]float64{"069500": 100}
+
+		summary, _ := ComputeRebalance(holdings, watchlist, latestPrices, fxRate, opts)
+
+		// Both symbols drift past the band (see "above-band drift triggers a sell").
+		if len(summary.Actions) != 2 {
+			t.Fatalf("len(Actions) = %d, want 2", len(summary.Actions))
+		}
+		// 35050/100 = 350.5, a tie that rounds away from zero, snapping up to 35100.
+		if got := actionFor(t, summary.Actions, "069500").LimitPriceKRW; got != 35100 {
+			t.Errorf("LimitPriceKRW = %v, want 35100 (snapped to the 100 tick)", got)
+		}
+	})
+}
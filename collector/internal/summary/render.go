@@ -13,31 +13,47 @@ import (
 //go:embed templates/summary.md.tmpl
 var summaryTemplate string
 
-// SummaryData holds all data for rendering the summary markdown.
+// SummaryData holds all data for rendering the summary. Fields carry json tags
+// because JSONRenderer serializes this struct directly as the daily-digest schema.
 type SummaryData struct {
-	FXRate              *FXRateEntry
-	GeneratedAt         string
-	InsufficientSymbols []string
-	KRRows              []SymbolRow
-	USRows              []SymbolRow
+	FXRate              *FXRateEntry      `json:"fx_rate"`
+	GeneratedAt         string            `json:"generated_at"`
+	InsufficientSymbols []string          `json:"insufficient_symbols"`
+	KRAggregate         MarketAggregate   `json:"kr_aggregate"`
+	KRRows              []SymbolRow       `json:"kr_rows"`
+	Liquidity           []LiquidityEntry  `json:"liquidity"`
+	Margin              *MarginSummary    `json:"margin"`
+	Rebalance           *RebalanceSummary `json:"rebalance"`
+	USAggregate         MarketAggregate   `json:"us_aggregate"`
+	USRows              []SymbolRow       `json:"us_rows"`
 }
 
 // SymbolRow pairs a watchlist entry with its computed indicators.
 type SymbolRow struct {
-	Indicators SymbolIndicators
-	Name       string
-	Symbol     string
+	Indicators SymbolIndicators `json:"indicators"`
+	Name       string           `json:"name"`
+	Symbol     string           `json:"symbol"`
 }
 
 // FXRateEntry holds the latest exchange rate for display.
 type FXRateEntry struct {
-	Date string
-	Pair string
-	Rate float64
+	Date string  `json:"date"`
+	Pair string  `json:"pair"`
+	Rate float64 `json:"rate"`
 }
 
-// RenderSummary executes the template with data and writes to outputPath atomically.
-func RenderSummary(data SummaryData, outputPath string) error {
+// Renderer writes SummaryData to outputPath in a specific output format.
+// All implementations write through atomicWriteFile so partial writes never
+// reach outputPath.
+type Renderer interface {
+	Render(data SummaryData, outputPath string) error
+}
+
+// MarkdownRenderer renders summary data using the embedded Markdown template.
+type MarkdownRenderer struct{}
+
+// Render executes the Markdown template with data and writes to outputPath atomically.
+func (MarkdownRenderer) Render(data SummaryData, outputPath string) error {
 	tmpl, err := template.New("summary").Funcs(templateFuncMap()).Parse(summaryTemplate)
 	if err != nil {
 		return fmt.Errorf("parse summary template: %w", err)
@@ -51,6 +67,12 @@ func RenderSummary(data SummaryData, outputPath string) error {
 	return atomicWriteFile(outputPath, buf.Bytes())
 }
 
+// RenderSummary renders data as Markdown and writes to outputPath atomically.
+// Kept as a shorthand for MarkdownRenderer{}.Render.
+func RenderSummary(data SummaryData, outputPath string) error {
+	return MarkdownRenderer{}.Render(data, outputPath)
+}
+
 // atomicWriteFile writes data to a temp file then renames to prevent partial writes.
 func atomicWriteFile(path string, data []byte) error {
 	dir := filepath.Dir(path)
@@ -74,14 +96,33 @@ func atomicWriteFile(path string, data []byte) error {
 
 func templateFuncMap() template.FuncMap {
 	return template.FuncMap{
-		"fmtCross":    formatMACross,
-		"fmtFXRate":   formatFXRate,
-		"fmtOptPrice": formatOptionalPrice,
-		"fmtPct":      formatOptionalPct,
-		"fmtPos":      formatPosition,
-		"fmtPrice":    formatPrice,
-		"fmtRatio":    formatRatio,
+		"fmtCross":     formatMACross,
+		"fmtFXRate":    formatFXRate,
+		"fmtImbalance": formatImbalance,
+		"fmtInt":       formatInt,
+		"fmtNum":       formatOptionalNumber,
+		"fmtNumber":    formatNumber,
+		"fmtOptPrice":  formatOptionalPrice,
+		"fmtPct":       formatOptionalPct,
+		"fmtPos":       formatPosition,
+		"fmtPrice":     formatPrice,
+		"fmtRatio":     formatRatio,
+	}
+}
+
+// formatOptionalNumber formats a plain (non-percent, non-ratio) statistic such as
+// Sharpe or Sortino to two decimal places, or "-" when unavailable.
+func formatOptionalNumber(v *float64) string {
+	if v == nil {
+		return "-"
 	}
+	return fmt.Sprintf("%.2f", *v)
+}
+
+// formatNumber is formatOptionalNumber's non-pointer counterpart, for fields
+// like LiquidityEntry.AvgSpreadBps that are always computed when present.
+func formatNumber(v float64) string {
+	return fmt.Sprintf("%.2f", v)
 }
 
 func formatOptionalPct(v *float64) string {
@@ -113,6 +154,16 @@ func formatPosition(v *float64) string {
 	return fmt.Sprintf("%.0f%%", *v*100)
 }
 
+// formatImbalance converts a bidVol/(bidVol+askVol) ratio (0.0-1.0) to a 0-100%
+// display string, same convention as formatPosition.
+func formatImbalance(v float64) string {
+	return fmt.Sprintf("%.0f%%", v*100)
+}
+
+func formatInt(v int64) string {
+	return fmt.Sprintf("%d", v)
+}
+
 func formatMACross(v *string) string {
 	if v == nil {
 		return "-"
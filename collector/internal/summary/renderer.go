@@ -0,0 +1,64 @@
+package summary
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+)
+
+//go:embed templates/summary.html.tmpl
+var summaryHTMLTemplate string
+
+// Format identifies a summary output format.
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatJSON     Format = "json"
+	FormatHTML     Format = "html"
+	FormatAll      Format = "all"
+)
+
+// renderers maps each concrete Format to the Renderer that produces it.
+// FormatAll is handled separately by expanding to every entry here.
+var renderers = map[Format]Renderer{
+	FormatMarkdown: MarkdownRenderer{},
+	FormatJSON:     JSONRenderer{},
+	FormatHTML:     HTMLRenderer{},
+}
+
+// JSONRenderer renders summary data as indented JSON. nil *float64 fields serialize
+// as null rather than being omitted, so downstream consumers (Slack bots, dashboards,
+// notebooks) can tell "no data" apart from a zero value without parsing Markdown.
+type JSONRenderer struct{}
+
+// Render marshals data as indented JSON and writes to outputPath atomically.
+func (JSONRenderer) Render(data SummaryData, outputPath string) error {
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal summary json: %w", err)
+	}
+
+	return atomicWriteFile(outputPath, out)
+}
+
+// HTMLRenderer renders summary data as a self-contained HTML page (inline CSS,
+// no external assets) suitable for emailing.
+type HTMLRenderer struct{}
+
+// Render executes the HTML template with data and writes to outputPath atomically.
+func (HTMLRenderer) Render(data SummaryData, outputPath string) error {
+	tmpl, err := htmltemplate.New("summary").Funcs(htmltemplate.FuncMap(templateFuncMap())).Parse(summaryHTMLTemplate)
+	if err != nil {
+		return fmt.Errorf("parse summary html template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("render summary html template: %w", err)
+	}
+
+	return atomicWriteFile(outputPath, buf.Bytes())
+}
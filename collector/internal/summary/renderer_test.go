@@ -0,0 +1,166 @@
+package summary
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJSONRendererRender(t *testing.T) {
+	t.Run("nil float fields serialize as null, not omitted", func(t *testing.T) {
+		data := SummaryData{
+			GeneratedAt: "2025-01-01 00:00 UTC",
+			USRows: []SymbolRow{
+				{
+					Symbol:     "NEW",
+					Name:       "New Stock",
+					Indicators: SymbolIndicators{AdjClose: 50.00},
+				},
+			},
+		}
+
+		outputPath := filepath.Join(t.TempDir(), "summary.json")
+		if err := (JSONRenderer{}).Render(data, outputPath); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+
+		got, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("read output: %v", err)
+		}
+
+		var decoded map[string]any
+		if err := json.Unmarshal(got, &decoded); err != nil {
+			t.Fatalf("output is not valid JSON: %v", err)
+		}
+
+		usRows, ok := decoded["us_rows"].([]any)
+		if !ok || len(usRows) != 1 {
+			t.Fatalf("expected one us_rows entry, got %v", decoded["us_rows"])
+		}
+		indicators, ok := usRows[0].(map[string]any)["indicators"].(map[string]any)
+		if !ok {
+			t.Fatalf("expected indicators object, got %v", usRows[0])
+		}
+
+		rawValue, present := indicators["change_5d"]
+		if !present {
+			t.Fatal("change_5d key missing entirely; nil fields must not be omitted")
+		}
+		if rawValue != nil {
+			t.Errorf("change_5d = %v, want null", rawValue)
+		}
+	})
+
+	t.Run("round-trips populated fields", func(t *testing.T) {
+		data := SummaryData{
+			GeneratedAt: "2025-01-01 00:00 UTC",
+			FXRate:      &FXRateEntry{Pair: "USD/KRW", Rate: 1345.50, Date: "2025-01-01"},
+			USRows: []SymbolRow{
+				{
+					Symbol: "NVDA",
+					Name:   "NVIDIA",
+					Indicators: SymbolIndicators{
+						AdjClose:  875.28,
+						Change5D:  ptrFloat(2.34),
+						Change20D: ptrFloat(15.67),
+					},
+				},
+			},
+		}
+
+		outputPath := filepath.Join(t.TempDir(), "summary.json")
+		if err := (JSONRenderer{}).Render(data, outputPath); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+
+		got, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("read output: %v", err)
+		}
+
+		var decoded SummaryData
+		if err := json.Unmarshal(got, &decoded); err != nil {
+			t.Fatalf("unmarshal output: %v", err)
+		}
+
+		if decoded.FXRate == nil || decoded.FXRate.Rate != 1345.50 {
+			t.Errorf("FXRate round-trip mismatch: %+v", decoded.FXRate)
+		}
+		if len(decoded.USRows) != 1 || decoded.USRows[0].Indicators.AdjClose != 875.28 {
+			t.Errorf("USRows round-trip mismatch: %+v", decoded.USRows)
+		}
+	})
+}
+
+func TestHTMLRendererRender(t *testing.T) {
+	t.Run("renders a self-contained page with inline CSS", func(t *testing.T) {
+		data := SummaryData{
+			GeneratedAt: "2025-02-15 14:30 UTC",
+			USRows: []SymbolRow{
+				{
+					Symbol:     "NVDA",
+					Name:       "NVIDIA",
+					Indicators: SymbolIndicators{AdjClose: 875.28, Change5D: ptrFloat(2.34)},
+				},
+			},
+			FXRate: &FXRateEntry{Pair: "USD/KRW", Rate: 1345.50, Date: "2025-02-14"},
+		}
+
+		outputPath := filepath.Join(t.TempDir(), "summary.html")
+		if err := (HTMLRenderer{}).Render(data, outputPath); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+
+		got, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("read output: %v", err)
+		}
+		content := string(got)
+
+		if !strings.Contains(content, "<style>") {
+			t.Error("expected inline <style> block for a self-contained page")
+		}
+		if strings.Contains(content, "<link rel=\"stylesheet\"") {
+			t.Error("expected no external stylesheet links")
+		}
+		if !strings.Contains(content, "NVDA") {
+			t.Error("missing NVDA row")
+		}
+		if !strings.Contains(content, "USD/KRW") {
+			t.Error("missing FX rate section")
+		}
+	})
+
+	t.Run("html-escapes untrusted content", func(t *testing.T) {
+		data := SummaryData{
+			GeneratedAt: "2025-01-01 00:00 UTC",
+			USRows: []SymbolRow{
+				{Symbol: "X", Name: "<script>alert(1)</script>", Indicators: SymbolIndicators{AdjClose: 1}},
+			},
+		}
+
+		outputPath := filepath.Join(t.TempDir(), "summary.html")
+		if err := (HTMLRenderer{}).Render(data, outputPath); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+
+		got, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("read output: %v", err)
+		}
+		if strings.Contains(string(got), "<script>alert(1)</script>") {
+			t.Error("expected the watchlist name to be HTML-escaped")
+		}
+	})
+}
+
+func TestRenderersMapCoversEveryConcreteFormat(t *testing.T) {
+	for _, f := range []Format{FormatMarkdown, FormatJSON, FormatHTML} {
+		if _, ok := renderers[f]; !ok {
+			t.Errorf("no Renderer registered for format %q", f)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package summary
+
+import (
+	"math"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+// SharpeRatio returns the annualized Sharpe ratio over the trailing window of daily
+// log returns (non-anomaly). riskFreeAnnual is converted to a daily rate (/252).
+// Returns nil if fewer than window+1 non-anomaly entries exist or stdev is zero.
+func SharpeRatio(prices []domain.DailyPrice, window int, riskFreeAnnual float64) *float64 {
+	returns := trailingLogReturns(prices, window)
+	if returns == nil {
+		return nil
+	}
+
+	rfDaily := riskFreeAnnual / tradingDaysPerYear
+	mean := meanOf(returns)
+	stdev := stdevOf(returns, mean)
+	if stdev == 0 {
+		return nil
+	}
+
+	sharpe := (mean - rfDaily) / stdev * math.Sqrt(tradingDaysPerYear)
+	return &sharpe
+}
+
+// SortinoRatio returns the annualized Sortino ratio, using downside deviation against
+// a 0 target return in place of Sharpe's stdev. Returns nil if fewer than window+1
+// non-anomaly entries exist or downside deviation is zero.
+func SortinoRatio(prices []domain.DailyPrice, window int, riskFreeAnnual float64) *float64 {
+	returns := trailingLogReturns(prices, window)
+	if returns == nil {
+		return nil
+	}
+
+	rfDaily := riskFreeAnnual / tradingDaysPerYear
+	mean := meanOf(returns)
+
+	var sumSqDownside float64
+	for _, r := range returns {
+		if r < 0 {
+			sumSqDownside += r * r
+		}
+	}
+	downsideDev := math.Sqrt(sumSqDownside / float64(len(returns)))
+	if downsideDev == 0 {
+		return nil
+	}
+
+	sortino := (mean - rfDaily) / downsideDev * math.Sqrt(tradingDaysPerYear)
+	return &sortino
+}
+
+// AnnualizedVolatility returns the annualized standard deviation of daily log
+// returns over the trailing window, as a percent (e.g. 23.5) — the same
+// denominator Sharpe divides by, exposed directly as its own stat. Returns nil
+// under the same conditions as SharpeRatio.
+func AnnualizedVolatility(prices []domain.DailyPrice, window int) *float64 {
+	returns := trailingLogReturns(prices, window)
+	if returns == nil {
+		return nil
+	}
+
+	stdev := stdevOf(returns, meanOf(returns))
+	if stdev == 0 {
+		return nil
+	}
+
+	vol := stdev * math.Sqrt(tradingDaysPerYear) * 100
+	return &vol
+}
+
+// MaxDrawdown walks the non-anomaly adj_close series tracking the running peak and
+// returns the largest peak-to-trough decline as a negative percent (e.g. -23.5).
+// Returns nil if fewer than 2 non-anomaly entries exist.
+func MaxDrawdown(prices []domain.DailyPrice) *float64 {
+	adjCloses := extractAdjCloses(prices)
+	if len(adjCloses) < 2 {
+		return nil
+	}
+
+	peak := adjCloses[0]
+	var maxDD float64
+	for _, p := range adjCloses {
+		if p > peak {
+			peak = p
+		}
+		if peak == 0 {
+			continue
+		}
+		dd := (p - peak) / peak
+		if dd < maxDD {
+			maxDD = dd
+		}
+	}
+
+	pct := maxDD * 100
+	return &pct
+}
+
+// DrawdownDetail is MaxDrawdown's result plus the dates marking the peak and
+// the trough, for callers (e.g. persisted summary_risk rows) that need more
+// than the raw percentage.
+type DrawdownDetail struct {
+	ValuePct   float64
+	PeakDate   time.Time
+	TroughDate time.Time
+}
+
+// MaxDrawdownDetail walks the non-anomaly price series the same way MaxDrawdown
+// does, but also records the date of the running peak and of the trough where
+// the largest decline bottomed out. Returns nil under the same conditions as
+// MaxDrawdown.
+func MaxDrawdownDetail(prices []domain.DailyPrice) *DrawdownDetail {
+	clean := make([]domain.DailyPrice, 0, len(prices))
+	for _, p := range prices {
+		if !p.IsAnomaly {
+			clean = append(clean, p)
+		}
+	}
+	if len(clean) < 2 {
+		return nil
+	}
+
+	peak := clean[0].AdjClose
+	peakDate := clean[0].Date
+	var worst DrawdownDetail
+	for _, p := range clean {
+		if p.AdjClose > peak {
+			peak = p.AdjClose
+			peakDate = p.Date
+		}
+		if peak == 0 {
+			continue
+		}
+		dd := (p.AdjClose - peak) / peak
+		if dd < worst.ValuePct/100 {
+			worst = DrawdownDetail{
+				ValuePct:   dd * 100,
+				PeakDate:   peakDate,
+				TroughDate: p.Date,
+			}
+		}
+	}
+
+	return &worst
+}
+
+// CAGR returns the compound annual growth rate over the full non-anomaly adj_close
+// series, as a percent (e.g. 23.5), assuming tradingDaysPerYear trading days per year.
+// Returns nil if fewer than 2 non-anomaly entries exist or the first value isn't
+// strictly positive.
+func CAGR(prices []domain.DailyPrice) *float64 {
+	adjCloses := extractAdjCloses(prices)
+	if len(adjCloses) < 2 || adjCloses[0] <= 0 {
+		return nil
+	}
+
+	years := float64(len(adjCloses)-1) / tradingDaysPerYear
+	cagr := (math.Pow(adjCloses[len(adjCloses)-1]/adjCloses[0], 1/years) - 1) * 100
+	return &cagr
+}
+
+// CalmarRatio returns the annualized return over the trailing window divided by the
+// absolute max drawdown over that same window. Returns nil when MaxDrawdown is nil,
+// zero, or fewer than window+1 non-anomaly entries exist.
+func CalmarRatio(prices []domain.DailyPrice, window int) *float64 {
+	adjCloses := extractAdjCloses(prices)
+	if len(adjCloses) < window+1 {
+		return nil
+	}
+
+	windowed := adjCloses[len(adjCloses)-window-1:]
+	dd := MaxDrawdown(windowStub(windowed))
+	if dd == nil || *dd == 0 {
+		return nil
+	}
+
+	first, last := windowed[0], windowed[len(windowed)-1]
+	if first == 0 {
+		return nil
+	}
+
+	annualizedReturn := (last/first - 1) * (tradingDaysPerYear / float64(window)) * 100
+	calmar := annualizedReturn / math.Abs(*dd)
+	return &calmar
+}
+
+// Beta returns the covariance of the symbol's daily log returns with the
+// benchmark's, divided by the benchmark's return variance, over the trailing
+// window of dates present in both (non-anomaly) series. Returns nil if fewer
+// than window+1 dates align between prices and benchPrices, or the
+// benchmark's variance is zero.
+func Beta(prices, benchPrices []domain.DailyPrice, window int) *float64 {
+	stockReturns, benchReturns := alignedTrailingLogReturns(prices, benchPrices, window)
+	if stockReturns == nil {
+		return nil
+	}
+
+	stockMean := meanOf(stockReturns)
+	benchMean := meanOf(benchReturns)
+
+	var cov, benchVar float64
+	for i := range stockReturns {
+		sd := stockReturns[i] - stockMean
+		bd := benchReturns[i] - benchMean
+		cov += sd * bd
+		benchVar += bd * bd
+	}
+	if benchVar == 0 {
+		return nil
+	}
+
+	beta := cov / benchVar
+	return &beta
+}
+
+// alignedTrailingLogReturns returns the trailing window of daily log returns
+// for prices and benchPrices, restricted to the dates that appear (non-anomaly)
+// in both series and paired index-for-index by date. Returns (nil, nil) if
+// fewer than window+1 aligned dates exist, or any aligned price is zero.
+func alignedTrailingLogReturns(prices, benchPrices []domain.DailyPrice, window int) ([]float64, []float64) {
+	benchByDate := make(map[time.Time]float64, len(benchPrices))
+	for _, p := range benchPrices {
+		if !p.IsAnomaly {
+			benchByDate[p.Date] = p.AdjClose
+		}
+	}
+
+	var stockAdj, benchAdj []float64
+	for _, p := range prices {
+		if p.IsAnomaly {
+			continue
+		}
+		if adj, ok := benchByDate[p.Date]; ok {
+			stockAdj = append(stockAdj, p.AdjClose)
+			benchAdj = append(benchAdj, adj)
+		}
+	}
+	if len(stockAdj) < window+1 {
+		return nil, nil
+	}
+
+	start := len(stockAdj) - window - 1
+	stockReturns := make([]float64, window)
+	benchReturns := make([]float64, window)
+	for i := range window {
+		if stockAdj[start+i] == 0 || benchAdj[start+i] == 0 {
+			return nil, nil
+		}
+		stockReturns[i] = math.Log(stockAdj[start+i+1] / stockAdj[start+i])
+		benchReturns[i] = math.Log(benchAdj[start+i+1] / benchAdj[start+i])
+	}
+	return stockReturns, benchReturns
+}
+
+// ProfitFactor returns the ratio of summed positive daily log returns to the absolute
+// value of summed negative daily log returns over the trailing window.
+// Returns nil if fewer than window+1 non-anomaly entries exist or there are no losses.
+func ProfitFactor(prices []domain.DailyPrice, window int) *float64 {
+	returns := trailingLogReturns(prices, window)
+	if returns == nil {
+		return nil
+	}
+
+	var gains, losses float64
+	for _, r := range returns {
+		if r > 0 {
+			gains += r
+		} else if r < 0 {
+			losses += -r
+		}
+	}
+	if losses == 0 {
+		return nil
+	}
+
+	pf := gains / losses
+	return &pf
+}
+
+// WinningRatio returns the fraction of trailing-window days with a positive daily
+// log return. Returns nil if fewer than window+1 non-anomaly entries exist.
+func WinningRatio(prices []domain.DailyPrice, window int) *float64 {
+	returns := trailingLogReturns(prices, window)
+	if returns == nil {
+		return nil
+	}
+
+	var wins int
+	for _, r := range returns {
+		if r > 0 {
+			wins++
+		}
+	}
+
+	ratio := float64(wins) / float64(len(returns))
+	return &ratio
+}
+
+// trailingLogReturns computes daily log returns over the trailing window of non-anomaly
+// adj_close entries. Returns nil if fewer than window+1 entries exist or any price is zero.
+func trailingLogReturns(prices []domain.DailyPrice, window int) []float64 {
+	adjCloses := extractAdjCloses(prices)
+	if len(adjCloses) < window+1 {
+		return nil
+	}
+
+	start := len(adjCloses) - window - 1
+	returns := make([]float64, window)
+	for i := range window {
+		if adjCloses[start+i] == 0 {
+			return nil
+		}
+		returns[i] = math.Log(adjCloses[start+i+1] / adjCloses[start+i])
+	}
+	return returns
+}
+
+func meanOf(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// stdevOf returns the sample standard deviation (N-1 denominator).
+func stdevOf(values []float64, mean float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	var sumSqDev float64
+	for _, v := range values {
+		dev := v - mean
+		sumSqDev += dev * dev
+	}
+	return math.Sqrt(sumSqDev / float64(len(values)-1))
+}
+
+// windowStub wraps a raw adj_close slice as prices so MaxDrawdown can be reused
+// for a sub-window without duplicating its peak-tracking logic.
+func windowStub(adjCloses []float64) []domain.DailyPrice {
+	prices := make([]domain.DailyPrice, len(adjCloses))
+	for i, c := range adjCloses {
+		prices[i].AdjClose = c
+	}
+	return prices
+}
@@ -0,0 +1,258 @@
+package summary
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func TestSharpeRatio(t *testing.T) {
+	t.Run("known computation", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 105, 103, 108, 110}, 1000)
+
+		got := SharpeRatio(prices, 4, 0.0)
+
+		// Same returns as TestHistoricalVolatility: mean=0.02382, stdev=0.03195
+		// sharpe = (0.02382 - 0) / 0.03195 * sqrt(252) ≈ 11.83
+		assertAlmostEqual(t, got, 11.83, 0.5, "SharpeRatio(4, 0.0)")
+	})
+
+	t.Run("constant prices returns nil (zero stdev)", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, repeatFloat(100, 25), 1000)
+		assertNil(t, SharpeRatio(prices, 20, 0.0), "SharpeRatio(20, 0.0)")
+	})
+
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 105}, 1000)
+		assertNil(t, SharpeRatio(prices, 20, 0.0), "SharpeRatio(20, 0.0)")
+	})
+}
+
+func TestSortinoRatio(t *testing.T) {
+	t.Run("no downside returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 101, 102, 103, 104}, 1000)
+		assertNil(t, SortinoRatio(prices, 4, 0.0), "SortinoRatio(4, 0.0)")
+	})
+
+	t.Run("mixed returns computes downside deviation", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 105, 103, 108, 110}, 1000)
+		got := SortinoRatio(prices, 4, 0.0)
+		if got == nil {
+			t.Fatal("expected non-nil Sortino ratio")
+		}
+	})
+
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 105}, 1000)
+		assertNil(t, SortinoRatio(prices, 20, 0.0), "SortinoRatio(20, 0.0)")
+	})
+}
+
+func TestBeta(t *testing.T) {
+	t.Run("identical series has beta of 1", func(t *testing.T) {
+		closes := []float64{100, 105, 103, 108, 110}
+		prices := makePriceSeries(baseDate, closes, 1000)
+		benchPrices := makePriceSeries(baseDate, closes, 1000)
+
+		got := Beta(prices, benchPrices, 4)
+		assertAlmostEqual(t, got, 1.0, 0.001, "Beta")
+	})
+
+	t.Run("amplified series has beta greater than 1", func(t *testing.T) {
+		benchCloses := []float64{100, 110, 100, 110, 100}
+		stockCloses := []float64{100, 120, 100, 120, 100}
+		prices := makePriceSeries(baseDate, stockCloses, 1000)
+		benchPrices := makePriceSeries(baseDate, benchCloses, 1000)
+
+		// Log returns aren't linear in price amplitude, so a stock moving twice
+		// the benchmark's percentage swing doesn't land on exactly beta=2.
+		got := Beta(prices, benchPrices, 4)
+		assertAlmostEqual(t, got, 1.913, 0.01, "Beta")
+	})
+
+	t.Run("constant benchmark returns nil (zero variance)", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 105, 103, 108, 110}, 1000)
+		benchPrices := makePriceSeries(baseDate, repeatFloat(100, 5), 1000)
+		assertNil(t, Beta(prices, benchPrices, 4), "Beta")
+	})
+
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 105}, 1000)
+		benchPrices := makePriceSeries(baseDate, []float64{100, 105}, 1000)
+		assertNil(t, Beta(prices, benchPrices, 20), "Beta")
+	})
+
+	t.Run("misaligned dates only use overlapping days", func(t *testing.T) {
+		stockCloses := []float64{100, 110, 100, 110, 100}
+		benchCloses := []float64{200, 220, 200, 220, 200}
+		prices := makePriceSeries(baseDate, stockCloses, 1000)
+		// Shift the benchmark's dates by one day so only 4 days overlap.
+		benchPrices := makePriceSeries(baseDate.AddDate(0, 0, 1), benchCloses, 1000)
+
+		got := Beta(prices, benchPrices, 3)
+		if got == nil {
+			t.Fatal("expected non-nil Beta from overlapping dates")
+		}
+	})
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	t.Run("known drawdown", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 120, 90, 95, 110}, 1000)
+		// peak 120 -> trough 90: (90-120)/120 = -25%
+		got := MaxDrawdown(prices)
+		assertAlmostEqual(t, got, -25.0, 0.001, "MaxDrawdown")
+	})
+
+	t.Run("monotonically increasing has zero drawdown", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 101, 102, 103}, 1000)
+		got := MaxDrawdown(prices)
+		assertAlmostEqual(t, got, 0.0, 0.001, "MaxDrawdown")
+	})
+
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100}, 1000)
+		assertNil(t, MaxDrawdown(prices), "MaxDrawdown")
+	})
+}
+
+func TestAnnualizedVolatility(t *testing.T) {
+	t.Run("known computation", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 105, 103, 108, 110}, 1000)
+
+		got := AnnualizedVolatility(prices, 4)
+
+		// Same daily log returns as TestHistoricalVolatility/TestSharpeRatio: stdev=0.03195.
+		assertAlmostEqual(t, got, 0.03195*math.Sqrt(252)*100, 0.5, "AnnualizedVolatility(4)")
+	})
+
+	t.Run("constant prices returns nil (zero stdev)", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, repeatFloat(100, 25), 1000)
+		assertNil(t, AnnualizedVolatility(prices, 20), "AnnualizedVolatility(20)")
+	})
+
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 105}, 1000)
+		assertNil(t, AnnualizedVolatility(prices, 20), "AnnualizedVolatility(20)")
+	})
+}
+
+func TestMaxDrawdownDetail(t *testing.T) {
+	t.Run("known drawdown reports peak and trough dates", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 120, 90, 95, 110}, 1000)
+		// peak 120 on day 1 -> trough 90 on day 2: (90-120)/120 = -25%
+		got := MaxDrawdownDetail(prices)
+		if got == nil {
+			t.Fatal("expected non-nil drawdown detail")
+		}
+		if math.Abs(got.ValuePct-(-25.0)) > 0.001 {
+			t.Errorf("ValuePct = %v, want -25.0", got.ValuePct)
+		}
+		wantPeak := baseDate.AddDate(0, 0, 1)
+		wantTrough := baseDate.AddDate(0, 0, 2)
+		if !got.PeakDate.Equal(wantPeak) {
+			t.Errorf("PeakDate = %v, want %v", got.PeakDate, wantPeak)
+		}
+		if !got.TroughDate.Equal(wantTrough) {
+			t.Errorf("TroughDate = %v, want %v", got.TroughDate, wantTrough)
+		}
+	})
+
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100}, 1000)
+		if got := MaxDrawdownDetail(prices); got != nil {
+			t.Errorf("got %+v, want nil", got)
+		}
+	})
+}
+
+func TestCAGR(t *testing.T) {
+	t.Run("known computation over one year", func(t *testing.T) {
+		closes := make([]float64, 253)
+		for i := range closes {
+			closes[i] = 100
+		}
+		closes[252] = 200
+		prices := makePriceSeries(baseDate, closes, 1000)
+
+		// 252 trading days (253 points) from 100 to 200 is exactly 1 year: CAGR = 100%.
+		got := CAGR(prices)
+		assertAlmostEqual(t, got, 100.0, 0.01, "CAGR")
+	})
+
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100}, 1000)
+		assertNil(t, CAGR(prices), "CAGR")
+	})
+
+	t.Run("non-positive first value returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{0, 100}, 1000)
+		assertNil(t, CAGR(prices), "CAGR")
+	})
+}
+
+func TestCalmarRatio(t *testing.T) {
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 105}, 1000)
+		assertNil(t, CalmarRatio(prices, 20), "CalmarRatio(20)")
+	})
+
+	t.Run("computes ratio of annualized return to drawdown", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 120, 90, 95, 130}, 1000)
+		got := CalmarRatio(prices, 4)
+		if got == nil {
+			t.Fatal("expected non-nil Calmar ratio")
+		}
+	})
+}
+
+func TestProfitFactor(t *testing.T) {
+	t.Run("no losses returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 101, 102, 103, 104}, 1000)
+		assertNil(t, ProfitFactor(prices, 4), "ProfitFactor(4)")
+	})
+
+	t.Run("mixed gains and losses", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 110, 99, 109, 98}, 1000)
+		got := ProfitFactor(prices, 4)
+		if got == nil {
+			t.Fatal("expected non-nil profit factor")
+		}
+	})
+
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 105}, 1000)
+		assertNil(t, ProfitFactor(prices, 20), "ProfitFactor(20)")
+	})
+}
+
+func TestWinningRatio(t *testing.T) {
+	t.Run("all winning days", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 101, 102, 103}, 1000)
+		got := WinningRatio(prices, 3)
+		assertAlmostEqual(t, got, 1.0, 0.001, "WinningRatio(3)")
+	})
+
+	t.Run("mixed days", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 110, 100, 110}, 1000)
+		got := WinningRatio(prices, 3)
+		assertAlmostEqual(t, got, 2.0/3.0, 0.001, "WinningRatio(3)")
+	})
+
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		prices := makePriceSeries(baseDate, []float64{100, 105}, 1000)
+		assertNil(t, WinningRatio(prices, 20), "WinningRatio(20)")
+	})
+
+	t.Run("excludes anomaly entries", func(t *testing.T) {
+		prices := []domain.DailyPrice{
+			makePrice(baseDate, 100, 1000),
+			makePriceAnomaly(baseDate.AddDate(0, 0, 1), 999, 1000),
+			makePrice(baseDate.AddDate(0, 0, 2), 105, 1000),
+			makePrice(baseDate.AddDate(0, 0, 3), 110, 1000),
+		}
+		got := WinningRatio(prices, 2)
+		assertAlmostEqual(t, got, 1.0, 0.001, "WinningRatio(2)")
+	})
+}
@@ -0,0 +1,188 @@
+package summary
+
+import (
+	"math"
+	"slices"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+const (
+	bollingerDefaultWindow   = 20
+	bollingerDefaultStdDev   = 2.0
+	volRegimeQuantileWindow  = 90
+	volRegimeSqueezeQuantile = 0.25
+	volRegimeExpandQuantile  = 0.75
+)
+
+// BollingerResult holds the Bollinger Band values for a single day.
+type BollingerResult struct {
+	BandwidthPct float64
+	Lower        float64
+	Middle       float64
+	PercentB     float64
+	Upper        float64
+}
+
+type ohlcBar struct {
+	Close float64
+	High  float64
+	Low   float64
+}
+
+// AverageTrueRange returns Wilder's smoothed ATR over the trailing window of
+// non-anomaly entries. The first ATR is the simple mean of the first `window` true
+// ranges; subsequent days smooth via ATR_i = (ATR_{i-1}*(window-1) + TR_i) / window.
+// Anomaly rows are skipped, carrying the previous clean close forward.
+// Returns nil if fewer than window+1 non-anomaly entries exist.
+func AverageTrueRange(prices []domain.DailyPrice, window int) *float64 {
+	bars := extractOHLC(prices)
+	if len(bars) < window+1 {
+		return nil
+	}
+
+	trueRanges := make([]float64, len(bars)-1)
+	for i := 1; i < len(bars); i++ {
+		trueRanges[i-1] = trueRange(bars[i], bars[i-1].Close)
+	}
+
+	var sum float64
+	for i := range window {
+		sum += trueRanges[i]
+	}
+	atr := sum / float64(window)
+
+	for i := window; i < len(trueRanges); i++ {
+		atr = (atr*float64(window-1) + trueRanges[i]) / float64(window)
+	}
+
+	return &atr
+}
+
+// BollingerBands computes the middle (SMA of close), upper/lower bands
+// (middle ± stddevMult * sample stddev of close), bandwidth, and %B for the
+// trailing window of non-anomaly entries. Returns nil if fewer than window
+// non-anomaly entries exist.
+func BollingerBands(prices []domain.DailyPrice, window int, stddevMult float64) *BollingerResult {
+	closes := extractCloses(prices)
+	if len(closes) < window {
+		return nil
+	}
+
+	trailing := closes[len(closes)-window:]
+	mean := meanOf(trailing)
+	sd := stdevOf(trailing, mean)
+
+	upper := mean + stddevMult*sd
+	lower := mean - stddevMult*sd
+
+	var bandwidth float64
+	if mean != 0 {
+		bandwidth = (upper - lower) / mean * 100
+	}
+
+	var percentB float64
+	if upper != lower {
+		percentB = (trailing[len(trailing)-1] - lower) / (upper - lower)
+	}
+
+	return &BollingerResult{
+		BandwidthPct: bandwidth,
+		Lower:        lower,
+		Middle:       mean,
+		PercentB:     percentB,
+		Upper:        upper,
+	}
+}
+
+// VolatilityRegime classifies the latest Bollinger bandwidth against its own
+// trailing 90-day distribution: "squeeze" (<=25th percentile), "expansion"
+// (>=75th percentile), or "normal" otherwise.
+// Returns nil if fewer than bollingerDefaultWindow+volRegimeQuantileWindow
+// non-anomaly entries exist.
+func VolatilityRegime(prices []domain.DailyPrice) *string {
+	closes := extractCloses(prices)
+	if len(closes) < bollingerDefaultWindow+volRegimeQuantileWindow {
+		return nil
+	}
+
+	bandwidths := make([]float64, volRegimeQuantileWindow)
+	for i := range volRegimeQuantileWindow {
+		end := len(closes) - volRegimeQuantileWindow + i + 1
+		window := closes[end-bollingerDefaultWindow : end]
+		mean := meanOf(window)
+		sd := stdevOf(window, mean)
+		upper := mean + bollingerDefaultStdDev*sd
+		lower := mean - bollingerDefaultStdDev*sd
+
+		var bw float64
+		if mean != 0 {
+			bw = (upper - lower) / mean * 100
+		}
+		bandwidths[i] = bw
+	}
+
+	latest := bandwidths[len(bandwidths)-1]
+	sorted := slices.Clone(bandwidths)
+	slices.Sort(sorted)
+
+	q25 := quantile(sorted, volRegimeSqueezeQuantile)
+	q75 := quantile(sorted, volRegimeExpandQuantile)
+
+	var regime string
+	switch {
+	case latest <= q25:
+		regime = "squeeze"
+	case latest >= q75:
+		regime = "expansion"
+	default:
+		regime = "normal"
+	}
+
+	return &regime
+}
+
+// quantile returns the p-th quantile (0.0-1.0) of an already-sorted slice using
+// linear interpolation between closest ranks.
+func quantile(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	pos := p * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	if lower == upper {
+		return sorted[lower]
+	}
+
+	frac := pos - float64(lower)
+	return sorted[lower] + (sorted[upper]-sorted[lower])*frac
+}
+
+func trueRange(bar ohlcBar, prevClose float64) float64 {
+	hl := bar.High - bar.Low
+	hc := math.Abs(bar.High - prevClose)
+	lc := math.Abs(bar.Low - prevClose)
+	return math.Max(hl, math.Max(hc, lc))
+}
+
+func extractCloses(prices []domain.DailyPrice) []float64 {
+	result := make([]float64, 0, len(prices))
+	for _, p := range prices {
+		if !p.IsAnomaly {
+			result = append(result, p.Close)
+		}
+	}
+	return result
+}
+
+func extractOHLC(prices []domain.DailyPrice) []ohlcBar {
+	result := make([]ohlcBar, 0, len(prices))
+	for _, p := range prices {
+		if !p.IsAnomaly {
+			result = append(result, ohlcBar{Close: p.Close, High: p.High, Low: p.Low})
+		}
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package summary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func makeBar(date time.Time, high, low, close_ float64) domain.DailyPrice {
+	return domain.DailyPrice{
+		AdjClose: close_,
+		Close:    close_,
+		Date:     date,
+		High:     high,
+		Low:      low,
+		Open:     close_,
+		Source:   "test",
+		Symbol:   "TEST",
+		Volume:   1000,
+	}
+}
+
+func TestAverageTrueRange(t *testing.T) {
+	t.Run("known computation", func(t *testing.T) {
+		bars := []domain.DailyPrice{
+			makeBar(baseDate, 48, 46, 47),
+			makeBar(baseDate.AddDate(0, 0, 1), 49, 47, 48),
+			makeBar(baseDate.AddDate(0, 0, 2), 50, 48, 49),
+			makeBar(baseDate.AddDate(0, 0, 3), 51, 49, 50),
+		}
+
+		got := AverageTrueRange(bars, 3)
+		if got == nil {
+			t.Fatal("expected non-nil ATR")
+		}
+		// TRs: each of day1..day3 is max(H-L=2, |H-prevC|=2, |L-prevC|=0)=2 -> ATR = 2
+		assertAlmostEqual(t, got, 2.0, 0.01, "AverageTrueRange(3)")
+	})
+
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		bars := []domain.DailyPrice{makeBar(baseDate, 48, 46, 47)}
+		if got := AverageTrueRange(bars, 3); got != nil {
+			t.Errorf("AverageTrueRange(3) = %v, want nil", *got)
+		}
+	})
+
+	t.Run("skips anomaly rows carrying previous close forward", func(t *testing.T) {
+		anomaly := makeBar(baseDate.AddDate(0, 0, 1), 500, 1, 200)
+		anomaly.IsAnomaly = true
+
+		bars := []domain.DailyPrice{
+			makeBar(baseDate, 48, 46, 47),
+			anomaly,
+			makeBar(baseDate.AddDate(0, 0, 2), 49, 47, 48),
+			makeBar(baseDate.AddDate(0, 0, 3), 50, 48, 49),
+		}
+
+		got := AverageTrueRange(bars, 2)
+		if got == nil {
+			t.Fatal("expected non-nil ATR")
+		}
+		assertAlmostEqual(t, got, 2.0, 0.01, "AverageTrueRange(2)")
+	})
+}
+
+func TestBollingerBands(t *testing.T) {
+	t.Run("constant prices yields zero-width bands", func(t *testing.T) {
+		bars := makePriceSeries(baseDate, repeatFloat(100, 20), 1000)
+		got := BollingerBands(bars, 20, 2.0)
+		if got == nil {
+			t.Fatal("expected non-nil BollingerResult")
+		}
+		if got.Middle != 100 || got.Upper != 100 || got.Lower != 100 {
+			t.Errorf("got %+v, want all bands = 100", *got)
+		}
+	})
+
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		bars := makePriceSeries(baseDate, []float64{100, 101}, 1000)
+		if got := BollingerBands(bars, 20, 2.0); got != nil {
+			t.Errorf("BollingerBands(20) = %+v, want nil", *got)
+		}
+	})
+}
+
+func TestVolatilityRegime(t *testing.T) {
+	t.Run("insufficient data returns nil", func(t *testing.T) {
+		bars := makePriceSeries(baseDate, repeatFloat(100, 30), 1000)
+		if got := VolatilityRegime(bars); got != nil {
+			t.Errorf("VolatilityRegime() = %q, want nil", *got)
+		}
+	})
+
+	t.Run("low but nonzero variance classifies relative to its own history", func(t *testing.T) {
+		closes := make([]float64, bollingerDefaultWindow+volRegimeQuantileWindow+5)
+		for i := range closes {
+			closes[i] = 100 + float64(i%5)
+		}
+		bars := makePriceSeries(baseDate, closes, 1000)
+
+		got := VolatilityRegime(bars)
+		if got == nil {
+			t.Fatal("expected non-nil regime")
+		}
+		switch *got {
+		case "squeeze", "normal", "expansion":
+		default:
+			t.Errorf("unexpected regime %q", *got)
+		}
+	})
+}
@@ -11,10 +11,16 @@ import (
 
 	"github.com/jusikbot/collector/internal/domain"
 	"github.com/jusikbot/collector/internal/httpclient"
+	"github.com/jusikbot/collector/internal/ratelimit"
 )
 
 const sourceName = "tiingo"
 
+// maxRateLimitBodyLength bounds how much of a rate-limit response body gets
+// copied into the synthetic APIError.Body fetchPrices constructs, matching
+// httpclient's own APIError.Body truncation for safe log inclusion.
+const maxRateLimitBodyLength = 512
+
 // ErrTickerInvalid signals that the requested symbol does not exist on Tiingo.
 var ErrTickerInvalid = errors.New("ticker not found on tiingo")
 
@@ -63,7 +69,18 @@ func (c *Client) fetchPrices(ctx context.Context, symbol string, from, to time.T
 
 	trimmed := bytes.TrimLeftFunc(body, unicode.IsSpace)
 	if len(trimmed) == 0 || trimmed[0] != '[' {
-		return nil, fmt.Errorf("symbol %s: unexpected response body: %w", symbol, httpclient.ErrRateLimited)
+		// Tiingo signals rate limiting with HTTP 200 and a non-JSON (often
+		// plain-text or empty) body rather than a real 429, so it's surfaced
+		// here as a synthetic APIError carrying the same StatusCode/IsRetryable
+		// shape a real 429 would, letting it flow through the same
+		// ratelimit.HTTPRetryClassifier path as every other retryable failure.
+		return nil, fmt.Errorf("fetch prices for %s: %w", symbol, &httpclient.APIError{
+			Body:        truncateForLog(trimmed),
+			IsRetryable: true,
+			Method:      "GET",
+			StatusCode:  429,
+			URL:         path,
+		})
 	}
 
 	var prices []tiingoPrice
@@ -78,14 +95,14 @@ func (c *Client) fetchPrices(ctx context.Context, symbol string, from, to time.T
 // Retryable: rate limiting (HTTP 429 or body-level), server errors (5xx).
 // Non-retryable: invalid ticker (404), parse errors.
 func IsRetryable(err error) bool {
-	if errors.Is(err, httpclient.ErrRateLimited) {
-		return true
-	}
-	var apiErr *httpclient.APIError
-	if errors.As(err, &apiErr) {
-		return apiErr.IsRetryable
+	return ratelimit.DefaultIsRetryable(err)
+}
+
+func truncateForLog(body []byte) string {
+	if len(body) <= maxRateLimitBodyLength {
+		return string(body)
 	}
-	return false
+	return string(body[:maxRateLimitBodyLength]) + "..."
 }
 
 func toDailyPrice(r tiingoPrice, symbol string) (domain.DailyPrice, error) {
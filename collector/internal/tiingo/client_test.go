@@ -107,6 +107,14 @@ func TestFetchPrices(t *testing.T) {
 		if !errors.Is(err, httpclient.ErrRateLimited) {
 			t.Errorf("error should wrap ErrRateLimited, got: %v", err)
 		}
+
+		var apiErr *httpclient.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatal("error should wrap a *httpclient.APIError")
+		}
+		if apiErr.StatusCode != 429 || !apiErr.IsRetryable {
+			t.Errorf("APIError = %+v, want StatusCode=429 IsRetryable=true", apiErr)
+		}
 	})
 
 	t.Run("rate limit empty body", func(t *testing.T) {
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/jusikbot/collector/internal/domain"
@@ -15,65 +17,185 @@ import (
 
 const defaultLookbackDays = 365
 
-// Collector orchestrates sequential symbol collection with rate limiting and anomaly detection.
+// corporateActionCurrency is the settlement currency for Tiingo's US-listed symbols;
+// Tiingo does not report a currency field alongside divCash.
+const corporateActionCurrency = "USD"
+
+// defaultWorkers bounds how many symbols CollectAll fetches concurrently when
+// Collector.Workers is left unset.
+const defaultWorkers = 4
+
+// SymbolError pairs a per-symbol collection failure with the symbol that produced it.
+type SymbolError struct {
+	Err    error
+	Symbol string
+}
+
+func (e SymbolError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Symbol, e.Err)
+}
+
+// CollectResult holds the prices and corporate actions collected across a watchlist,
+// plus one SymbolError per symbol that failed. A failure for one symbol does not
+// prevent the others from being collected.
+type CollectResult struct {
+	Actions []domain.CorporateAction
+	Errors  []SymbolError
+	Prices  []domain.DailyPrice
+}
+
+// Collector orchestrates concurrent symbol collection with rate limiting and anomaly detection.
 type Collector struct {
+	breaker  *ratelimit.CircuitBreaker
 	client   *Client
 	limiter  *rate.Limiter
 	retryCfg ratelimit.RetryConfig
+
+	// Workers bounds how many symbols are fetched concurrently. The shared
+	// rate.Limiter still gates the underlying HTTP calls, so raising this does
+	// not bypass Tiingo's rate limit; it only lets idle workers overlap latency.
+	// Defaults to defaultWorkers when left at zero.
+	Workers int
+
+	// Why injectable: enables deterministic testing without time-dependent flakiness.
+	now func() time.Time
 }
 
-func NewCollector(client *Client, limiter *rate.Limiter, retryCfg ratelimit.RetryConfig) *Collector {
+func NewCollector(client *Client, limiter *rate.Limiter, retryCfg ratelimit.RetryConfig, breaker *ratelimit.CircuitBreaker) *Collector {
 	return &Collector{
+		breaker:  breaker,
 		client:   client,
 		limiter:  limiter,
+		now:      time.Now,
 		retryCfg: retryCfg,
+		Workers:  defaultWorkers,
 	}
 }
 
-// CollectAll fetches daily prices for all entries sequentially with rate limiting.
-// Invalid tickers are skipped with a warning. Returns partial results on error.
+// CollectAll fetches daily prices for all entries over a bounded worker pool,
+// rate limited by the shared *rate.Limiter. Invalid tickers are skipped with a
+// warning. Per-symbol failures are aggregated into the result rather than
+// aborting the batch; the returned error is non-nil only on context cancellation.
+// Prices are sorted by (Symbol, Date) before returning, since workers complete
+// out of order. The circuit breaker is shared across all workers, so once
+// sustained failures trip it, every symbol still queued fails fast on
+// ErrCircuitOpen instead of retrying against a source that's fully down.
 func (c *Collector) CollectAll(
 	ctx context.Context,
 	entries []domain.WatchlistEntry,
 	gaps map[string]time.Time,
-) ([]domain.DailyPrice, error) {
-	var allPrices []domain.DailyPrice
-	to := time.Now().Truncate(24 * time.Hour)
+) (CollectResult, error) {
+	to := c.now().Truncate(24 * time.Hour)
+
+	workers := c.Workers
+	if workers < 1 {
+		workers = defaultWorkers
+	}
 
-	for _, entry := range entries {
-		if ctx.Err() != nil {
-			return allPrices, ctx.Err()
+	jobs := make(chan domain.WatchlistEntry)
+	outcomes := make(chan symbolOutcome)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range jobs {
+				outcomes <- c.collectSymbol(ctx, entry, to, gaps)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, entry := range entries {
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- entry:
+			}
 		}
+	}()
 
-		from := computeStartDate(to, gaps, entry.Symbol)
-		if !from.Before(to) {
-			slog.Info("already up to date", "symbol", entry.Symbol)
-			continue
+	go func() {
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	var result CollectResult
+	for outcome := range outcomes {
+		switch {
+		case outcome.skipped:
+		case outcome.err != nil:
+			result.Errors = append(result.Errors, SymbolError{Err: outcome.err, Symbol: outcome.symbol})
+		default:
+			result.Prices = append(result.Prices, outcome.prices...)
+			result.Actions = append(result.Actions, outcome.actions...)
 		}
+	}
 
-		raw, err := ratelimit.FetchWithRateLimit(ctx, c.limiter, c.retryCfg, IsRetryable,
-			func(ctx context.Context) ([]tiingoPrice, error) {
-				return c.client.fetchPrices(ctx, entry.Symbol, from, to)
-			},
-		)
-		if err != nil {
-			if errors.Is(err, ErrTickerInvalid) {
-				slog.Warn("skipping invalid ticker", "symbol", entry.Symbol)
-				continue
-			}
-			return allPrices, fmt.Errorf("collect %s: %w", entry.Symbol, err)
+	sort.Slice(result.Prices, func(i, j int) bool {
+		if result.Prices[i].Symbol != result.Prices[j].Symbol {
+			return result.Prices[i].Symbol < result.Prices[j].Symbol
 		}
+		return result.Prices[i].Date.Before(result.Prices[j].Date)
+	})
 
-		prices, err := markAnomalies(raw, entry)
-		if err != nil {
-			return allPrices, fmt.Errorf("validate %s: %w", entry.Symbol, err)
+	if ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+
+	return result, nil
+}
+
+// symbolOutcome is what one worker produces for one watchlist entry.
+type symbolOutcome struct {
+	actions []domain.CorporateAction
+	err     error
+	prices  []domain.DailyPrice
+	skipped bool
+	symbol  string
+}
+
+// collectSymbol fetches, validates, and extracts corporate actions for a single
+// symbol. Extracted from CollectAll so it can run inside a worker goroutine.
+func (c *Collector) collectSymbol(
+	ctx context.Context,
+	entry domain.WatchlistEntry,
+	to time.Time,
+	gaps map[string]time.Time,
+) symbolOutcome {
+	from := computeStartDate(to, gaps, entry.Symbol)
+	if !from.Before(to) {
+		slog.Info("already up to date", "symbol", entry.Symbol)
+		return symbolOutcome{skipped: true, symbol: entry.Symbol}
+	}
+
+	raw, err := ratelimit.FetchWithRateLimitCB(ctx, c.limiter, c.breaker, c.retryCfg, IsRetryable,
+		func(ctx context.Context) ([]tiingoPrice, error) {
+			return c.client.fetchPrices(ctx, entry.Symbol, from, to)
+		},
+	)
+	if err != nil {
+		if errors.Is(err, ErrTickerInvalid) {
+			slog.Warn("skipping invalid ticker", "symbol", entry.Symbol)
+			return symbolOutcome{skipped: true, symbol: entry.Symbol}
 		}
+		return symbolOutcome{err: fmt.Errorf("collect %s: %w", entry.Symbol, err), symbol: entry.Symbol}
+	}
 
-		allPrices = append(allPrices, prices...)
-		slog.Info("collected", "rows", len(prices), "symbol", entry.Symbol)
+	prices, err := markAnomalies(raw, entry)
+	if err != nil {
+		return symbolOutcome{err: fmt.Errorf("validate %s: %w", entry.Symbol, err), symbol: entry.Symbol}
 	}
 
-	return allPrices, nil
+	slog.Info("collected", "rows", len(prices), "symbol", entry.Symbol)
+
+	return symbolOutcome{
+		actions: corporateActions(raw, entry.Symbol),
+		prices:  prices,
+		symbol:  entry.Symbol,
+	}
 }
 
 // markAnomalies converts raw Tiingo data to domain prices with anomaly detection.
@@ -104,8 +226,43 @@ func markAnomalies(raw []tiingoPrice, entry domain.WatchlistEntry) ([]domain.Dai
 	return prices, nil
 }
 
+// corporateActions extracts splits and dividends from raw Tiingo rows that would
+// otherwise be discarded by toDailyPrice. Rows are skipped on date parse failure,
+// since markAnomalies already surfaces that as a hard error for the batch.
+func corporateActions(raw []tiingoPrice, symbol string) []domain.CorporateAction {
+	var actions []domain.CorporateAction
+
+	for _, r := range raw {
+		exDate, err := parseTiingoDate(r.Date)
+		if err != nil {
+			continue
+		}
+
+		if r.SplitFactor != 1.0 {
+			actions = append(actions, domain.CorporateAction{
+				ExDate:     exDate,
+				Kind:       domain.CorporateActionSplit,
+				SplitRatio: r.SplitFactor,
+				Symbol:     symbol,
+			})
+		}
+
+		if r.DivCash != 0 {
+			actions = append(actions, domain.CorporateAction{
+				CashAmount: r.DivCash,
+				Currency:   corporateActionCurrency,
+				ExDate:     exDate,
+				Kind:       domain.CorporateActionDividend,
+				Symbol:     symbol,
+			})
+		}
+	}
+
+	return actions
+}
+
 func isConfirmedAnomaly(r tiingoPrice, prevAdjClose float64, entry domain.WatchlistEntry) bool {
-	return validate.IsPriceAnomaly(r.AdjClose, prevAdjClose, entry.Market, entry.Type) &&
+	return validate.IsPriceAnomaly(r.AdjClose, prevAdjClose, domain.MarketAdapterFor(entry.Market), entry.Type) &&
 		validate.CrossValidateAdjClose(r.SplitFactor, r.DivCash)
 }
 
@@ -163,6 +163,51 @@ func TestMarkAnomalies(t *testing.T) {
 	})
 }
 
+func TestCorporateActions(t *testing.T) {
+	t.Run("extracts split and dividend rows, skips normal rows", func(t *testing.T) {
+		raw := []tiingoPrice{
+			{AdjClose: 100.0, Close: 100.0, Date: "2024-01-15T00:00:00+00:00", SplitFactor: 1.0, Volume: 1000},
+			{AdjClose: 30.0, Close: 30.0, Date: "2024-01-16T00:00:00+00:00", SplitFactor: 3.0, Volume: 5000},
+			{AdjClose: 29.5, Close: 30.0, Date: "2024-01-17T00:00:00+00:00", DivCash: 0.5, SplitFactor: 1.0, Volume: 4000},
+		}
+
+		actions := corporateActions(raw, "TEST")
+		if len(actions) != 2 {
+			t.Fatalf("len(actions) = %d, want 2", len(actions))
+		}
+
+		split := actions[0]
+		if split.Kind != domain.CorporateActionSplit || split.SplitRatio != 3.0 || split.Symbol != "TEST" {
+			t.Errorf("split action = %+v, want split 3.0 for TEST", split)
+		}
+
+		dividend := actions[1]
+		if dividend.Kind != domain.CorporateActionDividend || dividend.CashAmount != 0.5 || dividend.Currency != "USD" {
+			t.Errorf("dividend action = %+v, want 0.5 USD dividend", dividend)
+		}
+	})
+
+	t.Run("no corporate actions returns empty slice", func(t *testing.T) {
+		raw := []tiingoPrice{
+			{AdjClose: 100.0, Close: 100.0, Date: "2024-01-15T00:00:00+00:00", SplitFactor: 1.0, Volume: 1000},
+		}
+
+		if actions := corporateActions(raw, "TEST"); len(actions) != 0 {
+			t.Errorf("len(actions) = %d, want 0", len(actions))
+		}
+	})
+
+	t.Run("unparseable date is skipped", func(t *testing.T) {
+		raw := []tiingoPrice{
+			{AdjClose: 30.0, Close: 30.0, Date: "bad-date", SplitFactor: 2.0},
+		}
+
+		if actions := corporateActions(raw, "TEST"); len(actions) != 0 {
+			t.Errorf("len(actions) = %d, want 0", len(actions))
+		}
+	})
+}
+
 func TestIsConfirmedAnomaly(t *testing.T) {
 	entry := domain.WatchlistEntry{
 		Market: domain.MarketUS,
@@ -0,0 +1,204 @@
+package tiingo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/ratelimit"
+	"golang.org/x/time/rate"
+)
+
+// perSymbolServer routes each request to the handler registered for the
+// requested symbol, so tests can give individual symbols distinct behavior
+// (success, 404, 500) within a single fake server.
+func perSymbolServer(t testing.TB, handlers map[string]http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		symbol := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/tiingo/daily/"), "/prices")
+		h, ok := handlers[symbol]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		h(w, r)
+	}))
+}
+
+func pricesHandler(adjClose float64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `[{"adjClose":%f,"close":%f,"date":"2024-01-15T00:00:00+00:00","splitFactor":1.0,"volume":1000}]`,
+			adjClose, adjClose)
+	}
+}
+
+func notFoundHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func serverErrorHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+func testCollector(srv *httptest.Server) *Collector {
+	client := newTestClient(srv)
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	retryCfg := ratelimit.RetryConfig{InitialBackoff: time.Millisecond, MaxAttempts: 1, MaxBackoff: time.Millisecond}
+	return NewCollector(client, limiter, retryCfg, ratelimit.NewCircuitBreaker(ratelimit.DefaultCircuitBreakerConfig()))
+}
+
+func watchlistEntries(symbols ...string) []domain.WatchlistEntry {
+	entries := make([]domain.WatchlistEntry, len(symbols))
+	for i, s := range symbols {
+		entries[i] = domain.WatchlistEntry{Market: domain.MarketUS, Symbol: s, Type: domain.SecurityTypeStock}
+	}
+	return entries
+}
+
+func TestCollectAll(t *testing.T) {
+	t.Run("collects prices across symbols, sorted by symbol then date", func(t *testing.T) {
+		srv := perSymbolServer(t, map[string]http.HandlerFunc{
+			"AAPL": pricesHandler(150),
+			"MSFT": pricesHandler(300),
+			"NVDA": pricesHandler(900),
+		})
+		defer srv.Close()
+
+		collector := testCollector(srv)
+		result, err := collector.CollectAll(context.Background(), watchlistEntries("NVDA", "AAPL", "MSFT"), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Errors) != 0 {
+			t.Fatalf("Errors = %+v, want none", result.Errors)
+		}
+		if len(result.Prices) != 3 {
+			t.Fatalf("len(Prices) = %d, want 3", len(result.Prices))
+		}
+
+		gotSymbols := []string{result.Prices[0].Symbol, result.Prices[1].Symbol, result.Prices[2].Symbol}
+		wantSymbols := []string{"AAPL", "MSFT", "NVDA"}
+		for i := range wantSymbols {
+			if gotSymbols[i] != wantSymbols[i] {
+				t.Errorf("Prices[%d].Symbol = %q, want %q (order %v)", i, gotSymbols[i], wantSymbols[i], gotSymbols)
+			}
+		}
+	})
+
+	t.Run("aggregates per-symbol errors without aborting the batch", func(t *testing.T) {
+		srv := perSymbolServer(t, map[string]http.HandlerFunc{
+			"AAPL": pricesHandler(150),
+			"BAD":  serverErrorHandler,
+		})
+		defer srv.Close()
+
+		collector := testCollector(srv)
+		result, err := collector.CollectAll(context.Background(), watchlistEntries("AAPL", "BAD"), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Prices) != 1 || result.Prices[0].Symbol != "AAPL" {
+			t.Fatalf("Prices = %+v, want 1 row for AAPL", result.Prices)
+		}
+		if len(result.Errors) != 1 || result.Errors[0].Symbol != "BAD" {
+			t.Fatalf("Errors = %+v, want 1 entry for BAD", result.Errors)
+		}
+	})
+
+	t.Run("skips invalid tickers without recording an error", func(t *testing.T) {
+		srv := perSymbolServer(t, map[string]http.HandlerFunc{
+			"AAPL":    pricesHandler(150),
+			"INVALID": notFoundHandler,
+		})
+		defer srv.Close()
+
+		collector := testCollector(srv)
+		result, err := collector.CollectAll(context.Background(), watchlistEntries("AAPL", "INVALID"), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Prices) != 1 {
+			t.Fatalf("len(Prices) = %d, want 1", len(result.Prices))
+		}
+		if len(result.Errors) != 0 {
+			t.Fatalf("Errors = %+v, want none (invalid ticker is a skip, not an error)", result.Errors)
+		}
+	})
+
+	t.Run("symbols already up to date are skipped", func(t *testing.T) {
+		srv := perSymbolServer(t, map[string]http.HandlerFunc{
+			"AAPL": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("fetchPrices should not be called for an up-to-date symbol")
+			}),
+		})
+		defer srv.Close()
+
+		collector := testCollector(srv)
+		gaps := map[string]time.Time{"AAPL": time.Now()}
+		result, err := collector.CollectAll(context.Background(), watchlistEntries("AAPL"), gaps)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Prices) != 0 {
+			t.Fatalf("len(Prices) = %d, want 0", len(result.Prices))
+		}
+	})
+}
+
+// BenchmarkCollectAll demonstrates the speedup from fanning symbols out over a
+// worker pool instead of fetching them strictly sequentially. Each fake request
+// sleeps to stand in for real network latency.
+func BenchmarkCollectAll(b *testing.B) {
+	const (
+		numSymbols   = 20
+		simulatedRTT = 5 * time.Millisecond
+	)
+
+	handlers := make(map[string]http.HandlerFunc, numSymbols)
+	symbols := make([]string, numSymbols)
+	for i := range numSymbols {
+		symbol := fmt.Sprintf("SYM%d", i)
+		symbols[i] = symbol
+		handlers[symbol] = func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(simulatedRTT)
+			pricesHandler(100).ServeHTTP(w, r)
+		}
+	}
+	srv := perSymbolServer(b, handlers)
+	defer srv.Close()
+
+	entries := watchlistEntries(symbols...)
+	retryCfg := ratelimit.RetryConfig{InitialBackoff: time.Millisecond, MaxAttempts: 1, MaxBackoff: time.Millisecond}
+
+	b.Run("sequential", func(b *testing.B) {
+		client := newTestClient(srv)
+		limiter := rate.NewLimiter(rate.Inf, 0)
+		collector := NewCollector(client, limiter, retryCfg, ratelimit.NewCircuitBreaker(ratelimit.DefaultCircuitBreakerConfig()))
+		collector.Workers = 1
+
+		for range b.N {
+			if _, err := collector.CollectAll(context.Background(), entries, nil); err != nil {
+				b.Fatalf("CollectAll: %v", err)
+			}
+		}
+	})
+
+	b.Run("parallel", func(b *testing.B) {
+		client := newTestClient(srv)
+		limiter := rate.NewLimiter(rate.Inf, 0)
+		collector := NewCollector(client, limiter, retryCfg, ratelimit.NewCircuitBreaker(ratelimit.DefaultCircuitBreakerConfig()))
+		collector.Workers = 8
+
+		for range b.N {
+			if _, err := collector.CollectAll(context.Background(), entries, nil); err != nil {
+				b.Fatalf("CollectAll: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package tiingo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/httpclient"
+)
+
+// defaultUSEquityPriceTickSize is the standard minimum price increment for
+// US equities and ETFs (Reg NMS Rule 612); Tiingo's search endpoint doesn't
+// return a tick size, so this is used for every symbol it resolves.
+const defaultUSEquityPriceTickSize = 0.01
+
+// tiingoSearchResult represents one row of the Tiingo ticker-search response.
+type tiingoSearchResult struct {
+	ExchCode string `json:"exchCode"`
+	Ticker   string `json:"ticker"`
+}
+
+// FetchInstrument returns symbol's exchange-level contract specification via
+// Tiingo's ticker-search endpoint. US equities/ETFs settle T+1 (effective May
+// 2024) and trade in whole shares with no futures-style multiplier or
+// delivery date, so those fields are fixed here rather than parsed from the
+// response.
+func (c *Client) FetchInstrument(ctx context.Context, symbol string) (domain.Instrument, error) {
+	body, _, err := c.http.Get(ctx, "/tiingo/utilities/search",
+		httpclient.WithQueryParam("query", symbol),
+	)
+	if err != nil {
+		return domain.Instrument{}, fmt.Errorf("search instrument for %s: %w", symbol, err)
+	}
+
+	var results []tiingoSearchResult
+	if err := json.Unmarshal(body, &results); err != nil {
+		return domain.Instrument{}, fmt.Errorf("parse tiingo search response for %s: %w", symbol, err)
+	}
+
+	for _, r := range results {
+		if r.Ticker == symbol {
+			return domain.Instrument{
+				AmountTickSize:     1,
+				ContractMultiplier: 1,
+				Exchange:           r.ExchCode,
+				PriceTickSize:      defaultUSEquityPriceTickSize,
+				QuoteCurrency:      "USD",
+				SettlementType:     domain.SettlementTPlus1,
+				Symbol:             symbol,
+			}, nil
+		}
+	}
+
+	return domain.Instrument{}, fmt.Errorf("symbol %s: %w", symbol, ErrTickerInvalid)
+}
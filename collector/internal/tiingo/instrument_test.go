@@ -0,0 +1,66 @@
+package tiingo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jusikbot/collector/internal/domain"
+)
+
+func TestFetchInstrument(t *testing.T) {
+	t.Run("matching ticker", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/tiingo/utilities/search" {
+				t.Errorf("path = %q, want /tiingo/utilities/search", r.URL.Path)
+			}
+			if got := r.URL.Query().Get("query"); got != "AAPL" {
+				t.Errorf("query = %q, want AAPL", got)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[
+				{"ticker": "AAPL", "exchCode": "NASDAQ"},
+				{"ticker": "AAPLW", "exchCode": "NASDAQ"}
+			]`))
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		inst, err := client.FetchInstrument(context.Background(), "AAPL")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if inst.Symbol != "AAPL" {
+			t.Errorf("Symbol = %q, want AAPL", inst.Symbol)
+		}
+		if inst.Exchange != "NASDAQ" {
+			t.Errorf("Exchange = %q, want NASDAQ", inst.Exchange)
+		}
+		if inst.QuoteCurrency != "USD" {
+			t.Errorf("QuoteCurrency = %q, want USD", inst.QuoteCurrency)
+		}
+		if inst.PriceTickSize != defaultUSEquityPriceTickSize {
+			t.Errorf("PriceTickSize = %v, want %v", inst.PriceTickSize, defaultUSEquityPriceTickSize)
+		}
+		if inst.SettlementType != domain.SettlementTPlus1 {
+			t.Errorf("SettlementType = %q, want T+1", inst.SettlementType)
+		}
+	})
+
+	t.Run("no matching ticker", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"ticker": "MSFT", "exchCode": "NASDAQ"}]`))
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		_, err := client.FetchInstrument(context.Background(), "AAPL")
+		if err == nil {
+			t.Fatal("expected error for unmatched symbol")
+		}
+	})
+}
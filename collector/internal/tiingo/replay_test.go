@@ -0,0 +1,51 @@
+package tiingo
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/httpclient"
+	"github.com/jusikbot/collector/internal/httpclient/replay"
+	"github.com/jusikbot/collector/internal/ratelimit"
+	"golang.org/x/time/rate"
+)
+
+// TestCollectAll_ReplayRetryOn5xxAndGapDetection drives CollectAll entirely
+// from a recorded vector fixture (testdata/vectors/tiingo/retry_then_success.json)
+// instead of a bespoke httptest.Server, giving deterministic coverage of the
+// retry-on-5xx path alongside gap-adjusted date range computation.
+func TestCollectAll_ReplayRetryOn5xxAndGapDetection(t *testing.T) {
+	player, err := replay.NewPlayer("tiingo", "retry_then_success")
+	if err != nil {
+		t.Fatalf("load vectors: %v", err)
+	}
+
+	hc := httpclient.NewClient(
+		"https://api.tiingo.com",
+		map[string]string{"Authorization": "Token " + testAPIKey},
+		&http.Client{Transport: player},
+		0,
+	)
+	client := NewClient(hc)
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	retryCfg := ratelimit.RetryConfig{InitialBackoff: time.Millisecond, MaxAttempts: 2, MaxBackoff: time.Millisecond}
+	collector := NewCollector(client, limiter, retryCfg, ratelimit.NewCircuitBreaker(ratelimit.DefaultCircuitBreakerConfig()))
+	collector.now = func() time.Time { return time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC) }
+
+	gaps := map[string]time.Time{"AAPL": time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC)}
+	result, err := collector.CollectAll(context.Background(), watchlistEntries("AAPL"), gaps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("result.Errors = %v, want none (the 500 should have been retried against the vector's second entry)", result.Errors)
+	}
+	if len(result.Prices) != 1 {
+		t.Fatalf("len(result.Prices) = %d, want 1", len(result.Prices))
+	}
+	if result.Prices[0].AdjClose != 150.25 {
+		t.Errorf("AdjClose = %v, want 150.25", result.Prices[0].AdjClose)
+	}
+}
@@ -2,32 +2,61 @@ package validate
 
 import (
 	"math"
+	"sort"
+	"time"
 
 	"github.com/jusikbot/collector/internal/domain"
 )
 
-// Anomaly detection thresholds per market and security type.
-// Why these values: KR 30% matches KRX daily price limit band,
-// US ETF 15% reflects lower expected volatility of diversified funds,
-// US stock 50% accommodates high-volatility individual equities.
+// Modified z-score thresholds (k) per market and security type, used by
+// DetectAnomalies once enough trailing history exists. Why these values: KR 3.5
+// is tighter since KRX's daily limit band already caps legitimate single-day
+// moves, US ETF 4 reflects lower expected volatility of diversified funds, US
+// stock 5 accommodates high-volatility individual equities.
 const (
-	ThresholdKR      = 0.30
-	ThresholdUSETF   = 0.15
-	ThresholdUSStock = 0.50
+	ModifiedZKR      = 3.5
+	ModifiedZUSETF   = 4.0
+	ModifiedZUSStock = 5.0
 )
 
+// anomalyWindow is the number of trailing log-return observations DetectAnomalies
+// requires before switching from the fixed-threshold fallback (IsPriceAnomaly) to
+// the modified z-score.
+const anomalyWindow = 60
+
+// madScaleFactor converts MAD (median absolute deviation) to an estimate of the
+// standard deviation for a normal distribution, making the modified z-score
+// comparable across series with different volatility (Iglewicz & Hoaglin 1993).
+const madScaleFactor = 1.4826
+
 // IsPriceAnomaly returns true when the adj_close percentage change between
-// consecutive trading days exceeds the market+type-specific threshold.
-// Returns false for the first data point (previous == 0).
-func IsPriceAnomaly(current, previous float64, market domain.Market, secType domain.SecurityType) bool {
+// consecutive trading days exceeds adapter's threshold for secType. Returns
+// false for the first data point (previous == 0).
+func IsPriceAnomaly(current, previous float64, adapter domain.MarketAdapter, secType domain.SecurityType) bool {
 	if previous == 0 {
 		return false
 	}
 
 	changeRatio := math.Abs(current-previous) / previous
-	threshold := resolveThreshold(market, secType)
 
-	return changeRatio > threshold
+	return changeRatio > adapter.PriceLimit(secType)
+}
+
+// FXRateAnomalyThreshold is the fixed day-over-day percentage change (as a
+// fraction) above which a merged FX rate is flagged as an anomaly. Unlike
+// IsPriceAnomaly's equity thresholds, this isn't exchange-specific: FX rates
+// have no MarketAdapter to consult, and a currency pair moving more than 5%
+// in a single day is unusual enough across the board to warrant a flag.
+const FXRateAnomalyThreshold = 0.05
+
+// IsFXRateAnomaly returns true when the percentage change between two
+// consecutive days' FX rates exceeds FXRateAnomalyThreshold. Returns false
+// for the first observation (previous == 0), matching IsPriceAnomaly.
+func IsFXRateAnomaly(current, previous float64) bool {
+	if previous == 0 {
+		return false
+	}
+	return math.Abs(current-previous)/previous > FXRateAnomalyThreshold
 }
 
 // CrossValidateAdjClose returns true (confirmed anomaly) when no corporate
@@ -40,14 +69,130 @@ func CrossValidateAdjClose(splitFactor, divCash float64) bool {
 	return !isSplit && !isDividend
 }
 
-func resolveThreshold(market domain.Market, secType domain.SecurityType) float64 {
-	if market == domain.MarketKR {
-		return ThresholdKR
+// DetectAnomalies flags each entry in prices (ascending by Date) as an anomaly.
+// Once anomalyWindow trailing log-returns are available, day t is flagged when its
+// return deviates from the trailing window's median by more than k modified
+// z-scores (Iglewicz & Hoaglin); before that much history exists, or when the
+// window's returns are all identical (MAD of 0 would make any deviation
+// infinite), it falls back to IsPriceAnomaly's fixed threshold.
+func DetectAnomalies(prices []domain.DailyPrice, market domain.Market, secType domain.SecurityType) []bool {
+	flags := make([]bool, len(prices))
+	k := resolveModifiedZThreshold(market, secType)
+	adapter := domain.MarketAdapterFor(market)
+
+	for t := 1; t < len(prices); t++ {
+		if t < anomalyWindow+1 {
+			flags[t] = IsPriceAnomaly(prices[t].AdjClose, prices[t-1].AdjClose, adapter, secType)
+			continue
+		}
+
+		window := make([]float64, anomalyWindow)
+		for i := range window {
+			idx := t - anomalyWindow + i
+			window[i] = logReturn(prices[idx-1].AdjClose, prices[idx].AdjClose)
+		}
+
+		med := median(window)
+		mad := medianAbsoluteDeviation(window, med)
+		if mad == 0 {
+			flags[t] = IsPriceAnomaly(prices[t].AdjClose, prices[t-1].AdjClose, adapter, secType)
+			continue
+		}
+
+		rt := logReturn(prices[t-1].AdjClose, prices[t].AdjClose)
+		modifiedZ := math.Abs(rt-med) / (madScaleFactor * mad)
+		flags[t] = modifiedZ > k
 	}
 
-	if secType == domain.SecurityTypeETF {
-		return ThresholdUSETF
+	return flags
+}
+
+// CorporateAction describes a split or dividend event on a given date. Callers
+// that have access to source-specific fields (e.g. Tiingo's splitFactor/divCash)
+// build these to feed CrossValidateAdjCloseNear.
+type CorporateAction struct {
+	Date        time.Time
+	DivCash     float64
+	SplitFactor float64
+}
+
+// CrossValidateAdjCloseNear is CrossValidateAdjClose extended with a 1-trading-day
+// tolerance: DetectAnomalies' statistical window has no way to tell a real split or
+// dividend apart from a data error, so a flagged day at index t is also cleared
+// when a corporate action lands on prices[t-1], prices[t], or prices[t+1].
+func CrossValidateAdjCloseNear(prices []domain.DailyPrice, t int, actions []CorporateAction) bool {
+	for _, a := range actions {
+		if CrossValidateAdjClose(a.SplitFactor, a.DivCash) {
+			continue
+		}
+		for _, i := range [3]int{t - 1, t, t + 1} {
+			if i < 0 || i >= len(prices) {
+				continue
+			}
+			if prices[i].Date.Equal(a.Date) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// CorporateActionsFromDomain converts domain.CorporateAction rows — e.g. the
+// result of a domain.MarketDataFetcher's FetchSplitsAndDividends — into the
+// CorporateAction shape CrossValidateAdjCloseNear expects, so any source
+// implementing the richer interface can cross-validate anomalies the same way
+// Tiingo's raw splitFactor/divCash fields already do.
+func CorporateActionsFromDomain(actions []domain.CorporateAction) []CorporateAction {
+	out := make([]CorporateAction, 0, len(actions))
+	for _, a := range actions {
+		converted := CorporateAction{Date: a.ExDate, SplitFactor: 1.0}
+		switch a.Kind {
+		case domain.CorporateActionSplit:
+			converted.SplitFactor = a.SplitRatio
+		case domain.CorporateActionDividend:
+			converted.DivCash = a.CashAmount
+		}
+		out = append(out, converted)
+	}
+	return out
+}
+
+func logReturn(prev, curr float64) float64 {
+	if prev <= 0 || curr <= 0 {
+		return 0
 	}
+	return math.Log(curr / prev)
+}
+
+func median(xs []float64) float64 {
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
 
-	return ThresholdUSStock
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+func medianAbsoluteDeviation(xs []float64, med float64) float64 {
+	deviations := make([]float64, len(xs))
+	for i, x := range xs {
+		deviations[i] = math.Abs(x - med)
+	}
+	return median(deviations)
+}
+
+func resolveModifiedZThreshold(market domain.Market, secType domain.SecurityType) float64 {
+	if market == domain.MarketKR {
+		return ModifiedZKR
+	}
+	if secType == domain.SecurityTypeETF {
+		return ModifiedZUSETF
+	}
+	return ModifiedZUSStock
 }
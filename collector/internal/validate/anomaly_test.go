@@ -3,6 +3,7 @@ package validate
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/jusikbot/collector/internal/domain"
 )
@@ -13,7 +14,6 @@ func TestIsPriceAnomaly(t *testing.T) {
 			name        string
 			current     float64
 			previous    float64
-			market      domain.Market
 			secType     domain.SecurityType
 			wantAnomaly bool
 		}{
@@ -21,7 +21,6 @@ func TestIsPriceAnomaly(t *testing.T) {
 				name:        "exactly at 30% threshold is not anomaly",
 				current:     130,
 				previous:    100,
-				market:      domain.MarketKR,
 				secType:     domain.SecurityTypeStock,
 				wantAnomaly: false,
 			},
@@ -29,7 +28,6 @@ func TestIsPriceAnomaly(t *testing.T) {
 				name:        "just above 30% threshold is anomaly",
 				current:     130.01,
 				previous:    100,
-				market:      domain.MarketKR,
 				secType:     domain.SecurityTypeStock,
 				wantAnomaly: true,
 			},
@@ -37,7 +35,6 @@ func TestIsPriceAnomaly(t *testing.T) {
 				name:        "negative change exactly at -30% threshold is not anomaly",
 				current:     70,
 				previous:    100,
-				market:      domain.MarketKR,
 				secType:     domain.SecurityTypeStock,
 				wantAnomaly: false,
 			},
@@ -45,7 +42,6 @@ func TestIsPriceAnomaly(t *testing.T) {
 				name:        "negative change just beyond -30% threshold is anomaly",
 				current:     69.99,
 				previous:    100,
-				market:      domain.MarketKR,
 				secType:     domain.SecurityTypeStock,
 				wantAnomaly: true,
 			},
@@ -53,7 +49,6 @@ func TestIsPriceAnomaly(t *testing.T) {
 				name:        "KR ETF uses same 30% threshold",
 				current:     130.01,
 				previous:    100,
-				market:      domain.MarketKR,
 				secType:     domain.SecurityTypeETF,
 				wantAnomaly: true,
 			},
@@ -61,7 +56,6 @@ func TestIsPriceAnomaly(t *testing.T) {
 				name:        "normal daily move is not anomaly",
 				current:     102,
 				previous:    100,
-				market:      domain.MarketKR,
 				secType:     domain.SecurityTypeStock,
 				wantAnomaly: false,
 			},
@@ -69,11 +63,11 @@ func TestIsPriceAnomaly(t *testing.T) {
 
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
-				got := IsPriceAnomaly(tt.current, tt.previous, tt.market, tt.secType)
+				got := IsPriceAnomaly(tt.current, tt.previous, domain.MarketAdapterFor(domain.MarketKR), tt.secType)
 				if got != tt.wantAnomaly {
 					change := math.Abs(tt.current-tt.previous) / tt.previous * 100
-					t.Errorf("IsPriceAnomaly(%.2f, %.2f, %s, %s) = %v, want %v (change=%.4f%%)",
-						tt.current, tt.previous, tt.market, tt.secType, got, tt.wantAnomaly, change)
+					t.Errorf("IsPriceAnomaly(%.2f, %.2f, KR, %s) = %v, want %v (change=%.4f%%)",
+						tt.current, tt.previous, tt.secType, got, tt.wantAnomaly, change)
 				}
 			})
 		}
@@ -114,7 +108,7 @@ func TestIsPriceAnomaly(t *testing.T) {
 
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
-				got := IsPriceAnomaly(tt.current, tt.previous, domain.MarketUS, domain.SecurityTypeETF)
+				got := IsPriceAnomaly(tt.current, tt.previous, domain.MarketAdapterFor(domain.MarketUS), domain.SecurityTypeETF)
 				if got != tt.wantAnomaly {
 					change := math.Abs(tt.current-tt.previous) / tt.previous * 100
 					t.Errorf("IsPriceAnomaly(%.2f, %.2f, US, etf) = %v, want %v (change=%.4f%%)",
@@ -165,7 +159,7 @@ func TestIsPriceAnomaly(t *testing.T) {
 
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
-				got := IsPriceAnomaly(tt.current, tt.previous, domain.MarketUS, domain.SecurityTypeStock)
+				got := IsPriceAnomaly(tt.current, tt.previous, domain.MarketAdapterFor(domain.MarketUS), domain.SecurityTypeStock)
 				if got != tt.wantAnomaly {
 					change := math.Abs(tt.current-tt.previous) / tt.previous * 100
 					t.Errorf("IsPriceAnomaly(%.2f, %.2f, US, stock) = %v, want %v (change=%.4f%%)",
@@ -175,15 +169,47 @@ func TestIsPriceAnomaly(t *testing.T) {
 		}
 	})
 
+	t.Run("JP and HK markets use their own adapter thresholds", func(t *testing.T) {
+		if !IsPriceAnomaly(121, 100, domain.MarketAdapterFor(domain.MarketJP), domain.SecurityTypeStock) {
+			t.Error("expected +21% to exceed TSE's 20% threshold")
+		}
+		if IsPriceAnomaly(119, 100, domain.MarketAdapterFor(domain.MarketJP), domain.SecurityTypeStock) {
+			t.Error("expected +19% to stay within TSE's 20% threshold")
+		}
+		if IsPriceAnomaly(140, 100, domain.MarketAdapterFor(domain.MarketHK), domain.SecurityTypeStock) {
+			t.Error("expected +40% to stay within HKEX's 50% fallback threshold")
+		}
+	})
+
 	t.Run("first data point skipped", func(t *testing.T) {
 		// Why zero previous: first data point has no prior day to compare against.
-		got := IsPriceAnomaly(100, 0, domain.MarketKR, domain.SecurityTypeStock)
+		got := IsPriceAnomaly(100, 0, domain.MarketAdapterFor(domain.MarketKR), domain.SecurityTypeStock)
 		if got {
 			t.Error("IsPriceAnomaly should return false when previous is zero (first data point)")
 		}
 	})
 }
 
+func TestIsFXRateAnomaly(t *testing.T) {
+	t.Run("jump past threshold is flagged", func(t *testing.T) {
+		if !IsFXRateAnomaly(1450.00, 1380.00) {
+			t.Error("expected +5.07% to exceed FXRateAnomalyThreshold")
+		}
+	})
+
+	t.Run("move within threshold is not flagged", func(t *testing.T) {
+		if IsFXRateAnomaly(1410.00, 1380.00) {
+			t.Error("expected +2.17% to stay within FXRateAnomalyThreshold")
+		}
+	})
+
+	t.Run("first data point skipped", func(t *testing.T) {
+		if IsFXRateAnomaly(1380.00, 0) {
+			t.Error("IsFXRateAnomaly should return false when previous is zero (first data point)")
+		}
+	})
+}
+
 func TestCrossValidateAdjClose(t *testing.T) {
 	t.Run("stock split day", func(t *testing.T) {
 		tests := []struct {
@@ -293,3 +319,135 @@ func TestCrossValidateAdjClose(t *testing.T) {
 		}
 	})
 }
+
+func TestDetectAnomalies(t *testing.T) {
+	t.Run("8-sigma spike flagged once the trailing window is full", func(t *testing.T) {
+		base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		prices := make([]domain.DailyPrice, 62)
+		prices[0] = domain.DailyPrice{AdjClose: 100, Date: base}
+
+		// Quiet alternating +/-0.1% daily returns: median 0, MAD 0.001.
+		for i := 1; i <= 60; i++ {
+			ret := 0.001
+			if i%2 == 0 {
+				ret = -0.001
+			}
+			prices[i] = domain.DailyPrice{
+				AdjClose: prices[i-1].AdjClose * math.Exp(ret),
+				Date:     base.AddDate(0, 0, i),
+			}
+		}
+
+		// 8 modified z-scores above that window's median/MAD.
+		spikeReturn := 8 * madScaleFactor * 0.001
+		prices[61] = domain.DailyPrice{
+			AdjClose: prices[60].AdjClose * math.Exp(spikeReturn),
+			Date:     base.AddDate(0, 0, 61),
+		}
+
+		flags := DetectAnomalies(prices, domain.MarketUS, domain.SecurityTypeStock)
+		if len(flags) != len(prices) {
+			t.Fatalf("len(flags) = %d, want %d", len(flags), len(prices))
+		}
+		if !flags[61] {
+			t.Error("flags[61] = false, want true (injected 8-sigma spike)")
+		}
+		for i := 1; i < 61; i++ {
+			if flags[i] {
+				t.Errorf("flags[%d] = true, want false (quiet trailing window)", i)
+			}
+		}
+	})
+
+	t.Run("falls back to the fixed threshold before the window fills", func(t *testing.T) {
+		base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		prices := []domain.DailyPrice{
+			{AdjClose: 100, Date: base},
+			{AdjClose: 131, Date: base.AddDate(0, 0, 1)}, // +31%, above the 30% KR fallback threshold
+		}
+
+		flags := DetectAnomalies(prices, domain.MarketKR, domain.SecurityTypeStock)
+		if !flags[1] {
+			t.Error("flags[1] = false, want true (fixed-threshold fallback with no trailing history)")
+		}
+	})
+}
+
+func TestCrossValidateAdjCloseNear(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	prices := []domain.DailyPrice{
+		{AdjClose: 100, Date: base},
+		{AdjClose: 50, Date: base.AddDate(0, 0, 1)},
+		{AdjClose: 51, Date: base.AddDate(0, 0, 2)},
+	}
+
+	t.Run("split dated on the flagged day suppresses it", func(t *testing.T) {
+		actions := []CorporateAction{{Date: prices[1].Date, SplitFactor: 2.0}}
+		if CrossValidateAdjCloseNear(prices, 1, actions) {
+			t.Error("CrossValidateAdjCloseNear = true, want false (split explains the deviation)")
+		}
+	})
+
+	t.Run("split one trading day off still suppresses it", func(t *testing.T) {
+		actions := []CorporateAction{{Date: prices[0].Date, SplitFactor: 2.0}}
+		if CrossValidateAdjCloseNear(prices, 1, actions) {
+			t.Error("CrossValidateAdjCloseNear = true, want false (split lands within 1 trading day)")
+		}
+	})
+
+	t.Run("split more than 1 trading day away does not suppress it", func(t *testing.T) {
+		actions := []CorporateAction{{Date: prices[2].Date.AddDate(0, 0, 5), SplitFactor: 2.0}}
+		if !CrossValidateAdjCloseNear(prices, 0, actions) {
+			t.Error("CrossValidateAdjCloseNear = false, want true (no nearby corporate action)")
+		}
+	})
+
+	t.Run("no corporate actions leaves the flag confirmed", func(t *testing.T) {
+		if !CrossValidateAdjCloseNear(prices, 1, nil) {
+			t.Error("CrossValidateAdjCloseNear = false, want true with no actions at all")
+		}
+	})
+}
+
+func TestCorporateActionsFromDomain(t *testing.T) {
+	exDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("split converts to a non-1.0 SplitFactor with zero DivCash", func(t *testing.T) {
+		actions := CorporateActionsFromDomain([]domain.CorporateAction{
+			{ExDate: exDate, Kind: domain.CorporateActionSplit, SplitRatio: 2.0, Symbol: "AAPL"},
+		})
+		if len(actions) != 1 {
+			t.Fatalf("len(actions) = %d, want 1", len(actions))
+		}
+		if actions[0].SplitFactor != 2.0 {
+			t.Errorf("SplitFactor = %v, want 2.0", actions[0].SplitFactor)
+		}
+		if actions[0].DivCash != 0 {
+			t.Errorf("DivCash = %v, want 0", actions[0].DivCash)
+		}
+		if !actions[0].Date.Equal(exDate) {
+			t.Errorf("Date = %v, want %v", actions[0].Date, exDate)
+		}
+	})
+
+	t.Run("dividend converts to DivCash with SplitFactor 1.0", func(t *testing.T) {
+		actions := CorporateActionsFromDomain([]domain.CorporateAction{
+			{CashAmount: 0.24, ExDate: exDate, Kind: domain.CorporateActionDividend, Symbol: "AAPL"},
+		})
+		if len(actions) != 1 {
+			t.Fatalf("len(actions) = %d, want 1", len(actions))
+		}
+		if actions[0].DivCash != 0.24 {
+			t.Errorf("DivCash = %v, want 0.24", actions[0].DivCash)
+		}
+		if actions[0].SplitFactor != 1.0 {
+			t.Errorf("SplitFactor = %v, want 1.0", actions[0].SplitFactor)
+		}
+	})
+
+	t.Run("empty input returns empty slice", func(t *testing.T) {
+		if actions := CorporateActionsFromDomain(nil); len(actions) != 0 {
+			t.Errorf("len(actions) = %d, want 0", len(actions))
+		}
+	})
+}
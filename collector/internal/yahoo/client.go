@@ -0,0 +1,141 @@
+package yahoo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jusikbot/collector/internal/domain"
+	"github.com/jusikbot/collector/internal/httpclient"
+	"github.com/jusikbot/collector/internal/ratelimit"
+)
+
+const sourceName = "yahoo"
+
+// ErrTickerInvalid signals that the requested symbol does not exist on Yahoo Finance.
+var ErrTickerInvalid = errors.New("ticker not found on yahoo")
+
+// chartResponse is the envelope Yahoo's /v8/finance/chart endpoint wraps results in.
+// A symbol Yahoo doesn't recognize comes back with Result empty and Error set,
+// rather than as an HTTP error status.
+type chartResponse struct {
+	Chart struct {
+		Result []chartResult `json:"result"`
+		Error  *chartError   `json:"error"`
+	} `json:"chart"`
+}
+
+type chartError struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+type chartResult struct {
+	Timestamp  []int64 `json:"timestamp"`
+	Indicators struct {
+		Quote []struct {
+			Close  []float64 `json:"close"`
+			High   []float64 `json:"high"`
+			Low    []float64 `json:"low"`
+			Open   []float64 `json:"open"`
+			Volume []float64 `json:"volume"`
+		} `json:"quote"`
+		AdjClose []struct {
+			AdjClose []float64 `json:"adjclose"`
+		} `json:"adjclose"`
+	} `json:"indicators"`
+}
+
+// Client wraps an httpclient.Client configured for the Yahoo Finance chart API.
+type Client struct {
+	http *httpclient.Client
+}
+
+// NewClient creates a Yahoo Finance client. The httpClient must be pre-configured
+// with Yahoo's chart API base URL; Yahoo's public chart endpoint needs no auth header.
+func NewClient(httpClient *httpclient.Client) *Client {
+	return &Client{http: httpClient}
+}
+
+// FetchDailyPrices calls the Yahoo Finance chart API and converts the response to
+// domain.DailyPrice. Implements domain.StockDataFetcher.
+func (c *Client) FetchDailyPrices(ctx context.Context, symbol string, from, to time.Time) ([]domain.DailyPrice, error) {
+	path := fmt.Sprintf("/v8/finance/chart/%s", symbol)
+
+	body, _, err := c.http.Get(ctx, path,
+		httpclient.WithQueryParam("period1", strconv.FormatInt(from.Unix(), 10)),
+		httpclient.WithQueryParam("period2", strconv.FormatInt(to.Unix(), 10)),
+		httpclient.WithQueryParam("interval", "1d"),
+	)
+	if err != nil {
+		var apiErr *httpclient.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == 404 {
+			return nil, fmt.Errorf("symbol %s: %w", symbol, ErrTickerInvalid)
+		}
+		return nil, fmt.Errorf("fetch chart for %s: %w", symbol, err)
+	}
+
+	var parsed chartResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("parse yahoo response for %s: %w", symbol, err)
+	}
+	if parsed.Chart.Error != nil {
+		return nil, fmt.Errorf("symbol %s: %s: %w", symbol, parsed.Chart.Error.Description, ErrTickerInvalid)
+	}
+	if len(parsed.Chart.Result) == 0 {
+		return nil, fmt.Errorf("symbol %s: empty chart result", symbol)
+	}
+
+	return toDailyPrices(parsed.Chart.Result[0], symbol)
+}
+
+// toDailyPrices converts one chart result into domain prices, skipping entries
+// Yahoo pads with a zero close for days the market was closed within the requested
+// range (e.g. holidays).
+func toDailyPrices(result chartResult, symbol string) ([]domain.DailyPrice, error) {
+	if len(result.Indicators.Quote) == 0 {
+		return nil, fmt.Errorf("symbol %s: missing quote indicators", symbol)
+	}
+	quote := result.Indicators.Quote[0]
+
+	var adjClose []float64
+	if len(result.Indicators.AdjClose) > 0 {
+		adjClose = result.Indicators.AdjClose[0].AdjClose
+	}
+
+	prices := make([]domain.DailyPrice, 0, len(result.Timestamp))
+	for i, ts := range result.Timestamp {
+		if i >= len(quote.Close) || quote.Close[i] == 0 {
+			continue
+		}
+
+		adj := quote.Close[i]
+		if i < len(adjClose) {
+			adj = adjClose[i]
+		}
+
+		prices = append(prices, domain.DailyPrice{
+			AdjClose: adj,
+			Close:    quote.Close[i],
+			Date:     time.Unix(ts, 0).UTC().Truncate(24 * time.Hour),
+			High:     quote.High[i],
+			Low:      quote.Low[i],
+			Open:     quote.Open[i],
+			Source:   sourceName,
+			Symbol:   symbol,
+			Volume:   int64(quote.Volume[i]),
+		})
+	}
+
+	return prices, nil
+}
+
+// IsRetryable determines whether an error from the Yahoo client warrants retry.
+// Retryable: rate limiting (HTTP 429), server errors (5xx).
+// Non-retryable: invalid ticker (404), parse errors.
+func IsRetryable(err error) bool {
+	return ratelimit.DefaultIsRetryable(err)
+}
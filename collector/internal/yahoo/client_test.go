@@ -0,0 +1,157 @@
+package yahoo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jusikbot/collector/internal/httpclient"
+)
+
+func newTestClient(srv *httptest.Server) *Client {
+	hc := httpclient.NewClient(srv.URL, nil, srv.Client(), 0)
+	return NewClient(hc)
+}
+
+func TestFetchDailyPrices(t *testing.T) {
+	t.Run("normal JSON response", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v8/finance/chart/AAPL" {
+				t.Errorf("path = %q, want /v8/finance/chart/AAPL", r.URL.Path)
+			}
+			if got := r.URL.Query().Get("interval"); got != "1d" {
+				t.Errorf("interval = %q, want 1d", got)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{
+				"chart": {
+					"result": [{
+						"timestamp": [1705276800, 1705363200],
+						"indicators": {
+							"quote": [{
+								"close": [150.25, 155.50],
+								"high": [151.0, 156.0],
+								"low": [149.5, 154.0],
+								"open": [150.0, 155.0],
+								"volume": [55000000, 48000000]
+							}],
+							"adjclose": [{"adjclose": [149.80, 155.00]}]
+						}
+					}],
+					"error": null
+				}
+			}`))
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+		prices, err := client.FetchDailyPrices(context.Background(), "AAPL", from, to)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(prices) != 2 {
+			t.Fatalf("len(prices) = %d, want 2", len(prices))
+		}
+		if prices[0].Close != 150.25 {
+			t.Errorf("prices[0].Close = %v, want 150.25", prices[0].Close)
+		}
+		if prices[0].AdjClose != 149.80 {
+			t.Errorf("prices[0].AdjClose = %v, want 149.80", prices[0].AdjClose)
+		}
+		if prices[0].Source != sourceName {
+			t.Errorf("prices[0].Source = %q, want %q", prices[0].Source, sourceName)
+		}
+	})
+
+	t.Run("skips market-closed padding", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{
+				"chart": {
+					"result": [{
+						"timestamp": [1705276800, 1705363200],
+						"indicators": {
+							"quote": [{
+								"close": [150.25, 0],
+								"high": [151.0, 0],
+								"low": [149.5, 0],
+								"open": [150.0, 0],
+								"volume": [55000000, 0]
+							}],
+							"adjclose": [{"adjclose": [149.80, 0]}]
+						}
+					}],
+					"error": null
+				}
+			}`))
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+		prices, err := client.FetchDailyPrices(context.Background(), "AAPL", from, to)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(prices) != 1 {
+			t.Fatalf("len(prices) = %d, want 1", len(prices))
+		}
+	})
+
+	t.Run("chart error body returns ErrTickerInvalid", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"chart":{"result":null,"error":{"code":"Not Found","description":"No data found, symbol may be delisted"}}}`))
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		_, err := client.FetchDailyPrices(context.Background(), "INVALID", time.Now(), time.Now())
+		if !errors.Is(err, ErrTickerInvalid) {
+			t.Errorf("err = %v, want ErrTickerInvalid", err)
+		}
+	})
+
+	t.Run("404 status returns ErrTickerInvalid", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer srv.Close()
+
+		client := newTestClient(srv)
+		_, err := client.FetchDailyPrices(context.Background(), "INVALID", time.Now(), time.Now())
+		if !errors.Is(err, ErrTickerInvalid) {
+			t.Errorf("err = %v, want ErrTickerInvalid", err)
+		}
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limited", httpclient.ErrRateLimited, true},
+		{"server error", &httpclient.APIError{IsRetryable: true, StatusCode: 500}, true},
+		{"invalid ticker", ErrTickerInvalid, false},
+		{"other error", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}